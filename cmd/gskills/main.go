@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/pkg/cmd"
 	"github.com/spf13/viper"
 )
@@ -18,15 +18,15 @@ func main() {
 func initViper() {
 	viper.SetDefault("github_token", "")
 	viper.SetDefault("proxy", "")
+	viper.SetDefault("github_api_url", "")
 
-	home, err := os.UserHomeDir()
+	configDir, err := paths.ConfigDir()
 	if err != nil {
-		fmt.Printf("Error getting home directory: %v\n", err)
+		fmt.Printf("Error getting config directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	configDir := filepath.Join(home, ".gskills")
-	configPath := filepath.Join(configDir, "config.json")
+	configPath := paths.ConfigPath(configDir)
 
 	viper.SetConfigName("config")
 	viper.SetConfigType("json")
@@ -36,8 +36,9 @@ func initViper() {
 		os.MkdirAll(configDir, 0755)
 
 		defaultConfig := map[string]interface{}{
-			"github_token": "",
-			"proxy":        "",
+			"github_token":   "",
+			"proxy":          "",
+			"github_api_url": "",
 		}
 
 		data, err := json.MarshalIndent(defaultConfig, "", "  ")
@@ -0,0 +1,68 @@
+package add
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// tokenScopeHint explains which permissions github_token needs to read a
+// private repository.
+const tokenScopeHint = "fine-grained tokens and GitHub App installations need read access to \"Contents\" on the repository; " +
+	"classic tokens need the \"repo\" scope for private repositories"
+
+var (
+	// ErrNoAccess is wrapped by the error returned when the repository exists
+	// but its contents cannot be read with the configured token.
+	ErrNoAccess = errors.New("no access to repository contents")
+	// ErrRepoNotFound is wrapped by the error returned when the repository
+	// does not exist or is not visible to the configured token at all.
+	ErrRepoNotFound = errors.New("repository not found")
+)
+
+// diagnoseNotFound explains a 404 returned to an authenticated request for
+// the contents of repoInfo. GitHub answers 404 both for missing paths and for
+// repositories the token may not read, so the repository itself is probed:
+// if it is visible the token most likely lacks contents permission, if not
+// the repository does not exist. It returns nil when the client is not
+// authenticated or the probe is inconclusive, leaving the 404 as is.
+func (c *Client) diagnoseNotFound(ctx context.Context, repoInfo *GitHubRepoInfo) error {
+	if c.token == "" {
+		return nil
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, repoInfo.Owner, repoInfo.Repo)
+	resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+	if err != nil {
+		c.logger.Debug("Repository probe failed", "repo", repoInfo.Owner+"/"+repoInfo.Repo, "error", err)
+		return nil
+	}
+
+	switch resp.StatusCode() {
+	case 200:
+		return fmt.Errorf("%w: %s/%s exists but '%s' at '%s' was not found; if the path and ref are correct, the token lacks permission (%s)",
+			ErrNoAccess, repoInfo.Owner, repoInfo.Repo, repoInfo.Path, repoInfo.Branch, tokenScopeHint)
+	case 404:
+		return fmt.Errorf("%w: %s/%s does not exist or is not visible to the configured token",
+			ErrRepoNotFound, repoInfo.Owner, repoInfo.Repo)
+	default:
+		return nil
+	}
+}
+
+// accessError wraps err in a DownloadError whose type tells a missing
+// repository and a missing permission apart from other API failures.
+func accessError(message string, err error) *DownloadError {
+	errType := ErrorTypeAPI
+	switch {
+	case errors.Is(err, ErrNoAccess):
+		errType = ErrorTypeAccess
+	case errors.Is(err, ErrRepoNotFound):
+		errType = ErrorTypeNotFound
+	}
+	return &DownloadError{
+		Type:    errType,
+		Message: message,
+		Err:     err,
+	}
+}
@@ -0,0 +1,76 @@
+package add
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDownload_DiagnosesAuthenticated404(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		probeStatus int
+		wantType    ErrorType
+		wantErr     error
+		wantProbe   bool
+	}{
+		{
+			name:        "repository visible means missing permission",
+			token:       "token",
+			probeStatus: http.StatusOK,
+			wantType:    ErrorTypeAccess,
+			wantErr:     ErrNoAccess,
+			wantProbe:   true,
+		},
+		{
+			name:        "repository missing means not found",
+			token:       "token",
+			probeStatus: http.StatusNotFound,
+			wantType:    ErrorTypeNotFound,
+			wantErr:     ErrRepoNotFound,
+			wantProbe:   true,
+		},
+		{
+			name:        "unauthenticated keeps the SKILL.md error",
+			probeStatus: http.StatusOK,
+			wantType:    ErrorTypeValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+			ts.SetHandler("/repos/owner/private", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.probeStatus)
+				w.Write([]byte(`{"default_branch":"main"}`))
+			})
+
+			client := NewClient(tt.token)
+			client.SetBaseURL(ts.URL())
+			client.SetDataDir(t.TempDir())
+
+			_, err := client.Download(context.Background(), "https://github.com/owner/private/tree/main/skill")
+
+			var downloadErr *DownloadError
+			if !errors.As(err, &downloadErr) {
+				t.Fatalf("Download() error = %v, want *DownloadError", err)
+			}
+			if downloadErr.Type != tt.wantType {
+				t.Errorf("error type = %v, want %v (error: %v)", downloadErr.Type, tt.wantType, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error should wrap %v: %v", tt.wantErr, err)
+			}
+			if tt.wantType == ErrorTypeAccess && !strings.Contains(err.Error(), "Contents") {
+				t.Errorf("permission error should include the scope hint: %v", err)
+			}
+			if probed := ts.GetCallCount("/repos/owner/private") > 0; probed != tt.wantProbe {
+				t.Errorf("repository probed = %v, want %v", probed, tt.wantProbe)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package add
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Batch records the progress of adding several skills found in one
+// repository, so that an add interrupted by a failure can be resumed with
+// only the skills not yet installed. Only the most recent batch is kept.
+type Batch struct {
+	// Source is the repository URL the batch was started from.
+	Source string `json:"source"`
+	// Skills are the skill URLs of the batch, in install order.
+	Skills []string `json:"skills"`
+	// Done are the skill URLs already installed.
+	Done      []string  `json:"done,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// NewBatch starts a batch installing skills from source.
+func NewBatch(source string, skills []string) *Batch {
+	return &Batch{Source: source, Skills: skills, StartedAt: time.Now()}
+}
+
+// LoadBatch reads the batch state at path. It returns nil and no error when
+// there is no batch or the recorded batch was started from another source.
+func LoadBatch(path, source string) (*Batch, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch state: %w", err)
+	}
+
+	var batch Batch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse batch state: %w", err)
+	}
+	if batch.Source != source {
+		return nil, nil
+	}
+	return &batch, nil
+}
+
+// Save writes the batch state to path.
+func (b *Batch) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create batch state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write batch state: %w", err)
+	}
+	return nil
+}
+
+// MarkDone records that skill was installed.
+func (b *Batch) MarkDone(skill string) {
+	if !slices.Contains(b.Done, skill) {
+		b.Done = append(b.Done, skill)
+	}
+}
+
+// Remaining returns the skills of the batch not installed yet, in order.
+func (b *Batch) Remaining() []string {
+	var remaining []string
+	for _, skill := range b.Skills {
+		if !slices.Contains(b.Done, skill) {
+			remaining = append(remaining, skill)
+		}
+	}
+	return remaining
+}
+
+// ClearBatch removes the batch state at path. A missing file is not an error.
+func ClearBatch(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove batch state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package add
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checksumPrefix is the algorithm prefix of a checksum as printed and recorded.
+const checksumPrefix = "sha256:"
+
+// ErrChecksumMismatch is wrapped by the error returned when a downloaded
+// skill does not match the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Checksum returns the aggregate checksum of the skill directory dir. It is
+// the SHA-256 of a manifest listing the SHA-256 and slash-separated relative
+// path of every regular file, sorted by path, so it only depends on the file
+// names and contents:
+//
+//	<sha256 hex>  <path>\n
+//
+// The result has the form "sha256:<hex>".
+func Checksum(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list skill files: %w", err)
+	}
+	sort.Strings(files)
+
+	manifest := sha256.New()
+	for _, rel := range files {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(manifest, "%s  %s\n", sum, rel)
+	}
+	return checksumPrefix + hex.EncodeToString(manifest.Sum(nil)), nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// NormalizeChecksum validates an expected checksum given as 64 hex digits,
+// optionally prefixed with "sha256:", and returns it in the form produced by
+// Checksum.
+func NormalizeChecksum(s string) (string, error) {
+	hexSum := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), checksumPrefix))
+	if len(hexSum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid checksum %q: want 64 hex digits", s)
+	}
+	if _, err := hex.DecodeString(hexSum); err != nil {
+		return "", fmt.Errorf("invalid checksum %q: want 64 hex digits", s)
+	}
+	return checksumPrefix + hexSum, nil
+}
+
+// verifyChecksum returns an error wrapping ErrChecksumMismatch unless dir
+// matches the expected checksum.
+func verifyChecksum(dir, expected string) (string, error) {
+	actual, err := Checksum(dir)
+	if err != nil {
+		return "", err
+	}
+	if actual != expected {
+		return actual, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+	return actual, nil
+}
@@ -0,0 +1,141 @@
+package add
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+)
+
+func TestChecksum_DependsOnNamesAndContents(t *testing.T) {
+	write := func(dir, name, content string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := t.TempDir()
+	write(a, "SKILL.md", "# Skill")
+	write(a, "scripts/run.sh", "echo hi")
+	b := t.TempDir()
+	write(b, "scripts/run.sh", "echo hi")
+	write(b, "SKILL.md", "# Skill")
+
+	sumA, err := Checksum(a)
+	if err != nil {
+		t.Fatalf("Checksum() error = %v", err)
+	}
+	sumB, err := Checksum(b)
+	if err != nil {
+		t.Fatalf("Checksum() error = %v", err)
+	}
+	if sumA != sumB {
+		t.Errorf("identical trees have different checksums: %s != %s", sumA, sumB)
+	}
+	if !strings.HasPrefix(sumA, "sha256:") || len(sumA) != len("sha256:")+64 {
+		t.Errorf("Checksum() = %q, want sha256:<64 hex digits>", sumA)
+	}
+
+	write(b, "scripts/run.sh", "echo bye")
+	if sumC, _ := Checksum(b); sumC == sumA {
+		t.Error("changing a file should change the checksum")
+	}
+}
+
+func TestNormalizeChecksum(t *testing.T) {
+	hexSum := strings.Repeat("ab", 32)
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: hexSum, want: "sha256:" + hexSum},
+		{input: "sha256:" + strings.ToUpper(hexSum), want: "sha256:" + hexSum},
+		{input: "sha256:abc", wantErr: true},
+		{input: strings.Repeat("zz", 32), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeChecksum(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeChecksum(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeChecksum(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDownload_VerifiesChecksum(t *testing.T) {
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "main", "abc123")
+	gh.SetFile("owner", "repo", "skill/SKILL.md", "# Skill")
+	gh.SetFile("owner", "repo", "skill/notes.txt", "notes")
+
+	// The expected checksum is that of an identical local tree.
+	reference := t.TempDir()
+	os.WriteFile(filepath.Join(reference, "SKILL.md"), []byte("# Skill"), 0644)
+	os.WriteFile(filepath.Join(reference, "notes.txt"), []byte("notes"), 0644)
+	want, err := Checksum(reference)
+	if err != nil {
+		t.Fatalf("Checksum() error = %v", err)
+	}
+
+	const skillURL = "https://github.com/owner/repo/tree/main/skill"
+	newClient := func(dataDir, checksum string) *Client {
+		client := NewClient("")
+		client.SetBaseURL(gh.URL())
+		client.SetDataDir(dataDir)
+		client.SetConfirmOverwrite(nil)
+		client.SetChecksum(checksum)
+		return client
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		dataDir := t.TempDir()
+		_, err := newClient(dataDir, "sha256:"+strings.Repeat("0", 64)).Download(context.Background(), skillURL)
+		var downloadErr *DownloadError
+		if !errors.As(err, &downloadErr) || downloadErr.Type != ErrorTypeChecksum {
+			t.Fatalf("Download() error = %v, want ErrorTypeChecksum", err)
+		}
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("error should wrap ErrChecksumMismatch: %v", err)
+		}
+		entries, _ := os.ReadDir(filepath.Join(dataDir, "skills"))
+		if len(entries) != 0 {
+			t.Errorf("mismatching download left %d entries in the store", len(entries))
+		}
+		if _, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "skill"); err == nil {
+			t.Error("mismatching skill should not be registered")
+		}
+	})
+
+	t.Run("match", func(t *testing.T) {
+		dataDir := t.TempDir()
+		stats, err := newClient(dataDir, strings.TrimPrefix(want, "sha256:")).Download(context.Background(), skillURL)
+		if err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+		if stats.Checksum != want {
+			t.Errorf("stats.Checksum = %q, want %q", stats.Checksum, want)
+		}
+		skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "skill")
+		if err != nil {
+			t.Fatalf("skill not registered: %v", err)
+		}
+		if skill.Checksum != want {
+			t.Errorf("recorded Checksum = %q, want %q", skill.Checksum, want)
+		}
+	})
+}
@@ -2,25 +2,55 @@ package add
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/sourceurl"
 	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/version"
+)
+
+// MaxConcurrency caps the number of parallel requests of one download set
+// with SetConcurrency.
+const MaxConcurrency = 16
+
+// Bounds of the retry settings accepted by SetRetries.
+const (
+	MaxRetries   = 10
+	MinRetryWait = 100 * time.Millisecond
+	MaxRetryWait = time.Minute
+)
+
+// Defaults used until SetRetries or SetDownloadTimeout change them.
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryWait       = 2 * time.Second
+	DefaultDownloadTimeout = 5 * time.Minute
 )
 
 const (
 	defaultTimeout         = 30 * time.Second
-	maxRetries             = 3
-	retryWaitTime          = 2 * time.Second
 	maxConcurrentDownloads = 3
-	downloadTimeout        = 5 * time.Minute
 	maxRetryAttempts       = 5
+	// rateLimitRetryWait is the first wait before retrying a rate-limited request.
+	rateLimitRetryWait = time.Second
+	// defaultFileTimeout bounds one file download. It is far longer than
+	// defaultTimeout, which suits API calls but would cut off a large file
+	// still arriving; the download context is the intended bound.
+	defaultFileTimeout = 10 * time.Minute
+	// fewFilesThreshold is the file count at or below which a skill is
+	// reported as having few files: a lone manifest.
+	fewFilesThreshold = 1
 )
 
 // DownloadStats contains statistics about download operation.
@@ -28,42 +58,372 @@ type DownloadStats struct {
 	FilesDownloaded int
 	DirsCreated     int
 	BytesDownloaded int64
+	// StorePath is the final location of the skill; set by Download and AddLocal.
+	StorePath string
+	// UpToDate is set by Download when the same source was already installed
+	// at the same commit, so nothing was downloaded.
+	UpToDate bool
+	// Checksum is the checksum the skill was verified against; empty when
+	// no checksum was set with SetChecksum.
+	Checksum string
+	// Ignored counts the files and directories skipped because of the
+	// skill's .gskillsignore.
+	Ignored int
+	// Hidden counts the top-level dotfiles and dot-directories skipped
+	// because hidden entries were not included.
+	Hidden int
+	// FewFiles is set when the skill has no more than fewFilesThreshold
+	// files, e.g. only its SKILL.md, which often means the URL points at the
+	// wrong directory.
+	FewFiles bool
 }
 
 // Client is a GitHub API client for downloading skill packages.
 type Client struct {
-	restyClient *resty.Client
-	token       string
-	baseURL     string
-	logger      Logger
+	restyClient      *resty.Client
+	fileClient       *resty.Client
+	token            string
+	server           Server
+	baseURL          string
+	logger           Logger
+	downloadTimeout  time.Duration
+	dataDir          string
+	storeDir         string
+	tagPattern       string
+	updatePolicy     string
+	skipSkillCheck   bool
+	allowEmpty       bool
+	force            bool
+	includeHidden    bool
+	checksum         string
+	concurrency      int
+	retryAttempts    int
+	retryWait        time.Duration
+	limits           DownloadLimits
+	confirmOverwrite func() (bool, error)
+	onProgress       func(ProgressEvent)
+
+	rateMu    sync.Mutex
+	rateLimit *RateLimit
 }
 
 // NewClient creates a new GitHub API client with the given authentication token.
-// The token can be empty for public repositories.
-// The client is configured with a 30-second timeout, 3 retries, and 2-second retry wait time.
+// The token can be empty for public repositories. Requests go to github.com
+// unless another server is set with SetServer.
+// The client is configured with a 30-second timeout for API calls, 3 retries,
+// and 2-second retry wait time. File downloads share the API calls' transport
+// but have a 10-minute timeout of their own.
 func NewClient(token string) *Client {
-	client := resty.New()
-
+	client := newRestyClient(resty.New(), token)
 	client.SetTimeout(defaultTimeout)
-	client.SetRetryCount(maxRetries)
-	client.SetRetryWaitTime(retryWaitTime)
+
+	// Sharing the transport makes a proxy set with SetProxy apply to both.
+	fileClient := newRestyClient(resty.NewWithClient(&http.Client{Transport: client.GetClient().Transport}), token)
+	fileClient.SetTimeout(defaultFileTimeout)
+
+	c := &Client{
+		restyClient:     client,
+		fileClient:      fileClient,
+		token:           token,
+		server:          GitHubDotCom,
+		baseURL:         GitHubDotCom.APIURL,
+		logger:          NoOpLogger{},
+		downloadTimeout: DefaultDownloadTimeout,
+		concurrency:     maxConcurrentDownloads,
+		retryAttempts:   maxRetryAttempts,
+		retryWait:       rateLimitRetryWait,
+		limits:          DefaultDownloadLimits(),
+		confirmOverwrite: func() (bool, error) {
+			return promptOverwrite()
+		},
+	}
+	for _, rc := range []*resty.Client{client, fileClient} {
+		rc.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			c.recordRateLimit(resp.Header())
+			return nil
+		})
+	}
+	return c
+}
+
+// newRestyClient sets the retries and headers shared by the API and file
+// download clients on client.
+func newRestyClient(client *resty.Client, token string) *resty.Client {
+	client.SetRetryCount(DefaultMaxRetries)
+	client.SetRetryWaitTime(DefaultRetryWait)
 
 	if token != "" {
 		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 
-	client.SetHeader("User-Agent", "gskills-cli/1.0")
+	client.SetHeader("User-Agent", version.UserAgent())
+	return client
+}
+
+// SetLogger sets the logger used by the client.
+func (c *Client) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// SetDataDir sets the gskills data directory in which skills are stored and
+// registered. An empty dir selects the default ~/.gskills.
+func (c *Client) SetDataDir(dir string) {
+	c.dataDir = dir
+}
+
+// SetStoreDir places added skills in dir instead of the skills store of the
+// data directory. The skills are still registered in the data directory's
+// registry with their StorePath under dir. An empty dir restores the default.
+func (c *Client) SetStoreDir(dir string) {
+	c.storeDir = dir
+}
+
+// SetTagPattern makes skills downloaded from a tag follow the newest release
+// tag matching pattern, a glob such as "v*", when updated. Downloading from
+// a branch with a pattern set fails. An empty pattern keeps tags frozen.
+func (c *Client) SetTagPattern(pattern string) {
+	c.tagPattern = pattern
+}
+
+// SetUpdatePolicy records policy, one of UpdatePolicies, as the update
+// policy of downloaded skills. Downloading from a branch with the
+// latest-tag policy fails. An empty policy keeps the policy of a skill
+// already installed under the same name.
+func (c *Client) SetUpdatePolicy(policy string) {
+	c.updatePolicy = policy
+}
+
+// SetSkipSkillCheck makes Download fetch the target directory even when it
+// has no SKILL.md. Skills downloaded this way are registered as Unverified.
+func (c *Client) SetSkipSkillCheck(skip bool) {
+	c.skipSkillCheck = skip
+}
+
+// SetAllowEmpty makes Download install a skill directory that has no files,
+// e.g. one added with SetSkipSkillCheck, instead of failing with
+// ErrEmptySkill. Local and release skills always have their SKILL.md.
+func (c *Client) SetAllowEmpty(allow bool) {
+	c.allowEmpty = allow
+}
+
+// checkFileCount fails with ErrEmptySkill when stats counts no files, unless
+// SetAllowEmpty was called, and sets stats.FewFiles when the skill has
+// suspiciously few.
+func (c *Client) checkFileCount(stats *DownloadStats) error {
+	if stats.FilesDownloaded == 0 && !c.allowEmpty {
+		return &DownloadError{
+			Type:    ErrorTypeValidation,
+			Message: "the skill directory is empty",
+			Err:     ErrEmptySkill,
+		}
+	}
+	stats.FewFiles = stats.FilesDownloaded <= fewFilesThreshold
+	if stats.FewFiles {
+		c.logger.Warn("Skill has few files", "files", stats.FilesDownloaded)
+	}
+	return nil
+}
+
+// SetForce makes Download fetch a skill again even when the same source is
+// already installed at the remote commit.
+func (c *Client) SetForce(force bool) {
+	c.force = force
+}
+
+// SetIncludeHidden makes Download keep the top-level dotfiles and
+// dot-directories of a skill, which are skipped by default (see IsHidden).
+func (c *Client) SetIncludeHidden(include bool) {
+	c.includeHidden = include
+}
+
+// SetChecksum makes Download and AddLocal verify the skill against checksum,
+// an aggregate checksum as computed by Checksum. A mismatching skill is not
+// installed. An empty checksum disables the verification.
+func (c *Client) SetChecksum(checksum string) {
+	c.checksum = checksum
+}
+
+// SetConcurrency sets how many requests a download makes in parallel.
+// Non-positive values keep the default and values above MaxConcurrency are
+// capped at MaxConcurrency.
+func (c *Client) SetConcurrency(n int) {
+	switch {
+	case n <= 0:
+		return
+	case n > MaxConcurrency:
+		c.concurrency = MaxConcurrency
+	default:
+		c.concurrency = n
+	}
+}
+
+// SetRetries sets how often a failed request is retried and the wait
+// before the first retry, for both the HTTP client and the backoff loops of
+// rate-limited requests, whose waits double from retryWait on. A negative
+// maxRetries or non-positive retryWait keeps the default (3 HTTP retries,
+// 4 rate-limit retries starting at 1s); other values are clamped to
+// MaxRetries and to MinRetryWait..MaxRetryWait.
+func (c *Client) SetRetries(maxRetries int, retryWait time.Duration) {
+	if maxRetries >= 0 {
+		maxRetries = min(maxRetries, MaxRetries)
+		c.restyClient.SetRetryCount(maxRetries)
+		c.fileClient.SetRetryCount(maxRetries)
+		c.retryAttempts = maxRetries + 1
+	}
+	if retryWait > 0 {
+		retryWait = min(max(retryWait, MinRetryWait), MaxRetryWait)
+		for _, rc := range []*resty.Client{c.restyClient, c.fileClient} {
+			rc.SetRetryWaitTime(retryWait)
+			rc.SetRetryMaxWaitTime(max(maxRateLimitBackoff, retryWait))
+		}
+		c.retryWait = retryWait
+	}
+}
+
+// Retries returns how often a failed request is retried by the backoff
+// loops and the wait before the first retry.
+func (c *Client) Retries() (maxRetries int, retryWait time.Duration) {
+	return c.retryAttempts - 1, c.retryWait
+}
+
+// Concurrency returns how many requests a download makes in parallel.
+func (c *Client) Concurrency() int {
+	return c.concurrency
+}
+
+// SetDownloadLimits bounds the number of files and total bytes of a single
+// download. Non-positive values keep the defaults.
+func (c *Client) SetDownloadLimits(maxFiles int, maxTotalBytes int64) {
+	if maxFiles > 0 {
+		c.limits.MaxFiles = maxFiles
+	}
+	if maxTotalBytes > 0 {
+		c.limits.MaxTotalBytes = maxTotalBytes
+	}
+}
+
+// DownloadLimits returns the limits applied to a single download.
+func (c *Client) DownloadLimits() DownloadLimits {
+	return c.limits
+}
+
+// SetConfirmOverwrite sets the callback asked before an existing skill is
+// replaced. The default prompts on stdin; nil overwrites without asking.
+func (c *Client) SetConfirmOverwrite(confirm func() (bool, error)) {
+	c.confirmOverwrite = confirm
+}
+
+// skillStorePath returns the directory in which the skill skillName is
+// stored: under the store directory if one is set, otherwise in the skills
+// store of dataDir.
+func (c *Client) skillStorePath(dataDir, skillName string) string {
+	if c.storeDir != "" {
+		return filepath.Join(c.storeDir, skillName)
+	}
+	return filepath.Join(paths.SkillsDir(dataDir), skillName)
+}
+
+// registerSkill records skill in the registry of dataDir. Links recorded for
+// an existing entry with the same ID are carried over so re-adding a skill
+// does not lose them; user tags are kept for any entry with the same name.
+// A missing description is taken from the skill manifest when it has one.
+func (c *Client) registerSkill(dataDir string, skill *types.SkillMetadata) error {
+	registryPath := paths.RegistryPath(dataDir)
+
+	if skill.Description == "" {
+		if manifest, err := ReadManifest(skill.StorePath); err == nil && manifest != nil {
+			skill.Description = manifest.Description
+		}
+	}
+
+	if existing, err := registry.FindSkillByNameWithPath(registryPath, skill.Name); err == nil {
+		skill.Tags = existing.Tags
+		if skill.UpdatePolicy == "" {
+			skill.UpdatePolicy = existing.UpdatePolicy
+		}
+		if existing.ID == skill.ID {
+			skill.LinkedProjects = existing.LinkedProjects
+		}
+	}
+
+	if err := registry.AddOrUpdateSkillWithPath(registryPath, skill); err != nil {
+		c.logger.Error("Failed to update skills registry", err, "skill", skill.Name)
+		return &DownloadError{
+			Type:    ErrorTypeRegistry,
+			Message: "skill stored but failed to update skills registry",
+			Err:     err,
+		}
+	}
+	return nil
+}
 
-	return &Client{
-		restyClient: client,
-		token:       token,
-		baseURL:     "https://api.github.com",
-		logger:      NoOpLogger{},
+// isInstalled reports whether the registry of dataDir already has skillName
+// from rawURL, which must be canonical, at commitSHA, stored at localPath with the same tag pattern
+// and, when one is set, the same update policy, and its store directory still exists. A non-empty checksum must also match
+// the checksum the installed skill was verified against.
+func (c *Client) isInstalled(dataDir, skillName, rawURL, commitSHA, localPath, checksum string) bool {
+	existing, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), skillName)
+	if err != nil {
+		return false
 	}
+	if sourceurl.Canonicalize(existing.SourceURL) != rawURL || existing.CommitSHA != commitSHA ||
+		existing.StorePath != localPath || existing.TagPattern != c.tagPattern ||
+		(c.updatePolicy != "" && existing.UpdatePolicy != c.updatePolicy) ||
+		(checksum != "" && existing.Checksum != checksum) {
+		return false
+	}
+	exists, err := checkPathExists(localPath)
+	return err == nil && exists
 }
 
-// SetBaseURL sets the base URL for GitHub API requests.
-// This method is intended for testing purposes only and should not be used in production code.
+// SetTimeout overrides both the per-request HTTP timeout of API calls and the
+// overall download timeout with d. File downloads keep their own, longer
+// timeout and are bounded by the download timeout instead. Non-positive
+// values are ignored.
+func (c *Client) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.restyClient.SetTimeout(d)
+	c.downloadTimeout = d
+}
+
+// SetDownloadTimeout overrides the time allowed for one Download, from the
+// SKILL.md check to the last file (default 5m). Unlike SetTimeout it leaves
+// the per-request HTTP timeout alone. Non-positive values are ignored.
+func (c *Client) SetDownloadTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.downloadTimeout = d
+}
+
+// SetProxy routes all requests through the given proxy URL. An empty proxy
+// leaves the client unchanged.
+func (c *Client) SetProxy(proxy string) {
+	if proxy == "" {
+		return
+	}
+	c.restyClient.SetProxy(proxy)
+}
+
+// SetServer makes the client add skills from server, e.g. a GitHub
+// Enterprise server: skill URLs must use its host and API requests go to its
+// API URL.
+func (c *Client) SetServer(server Server) {
+	c.server = server
+	c.baseURL = server.APIURL
+}
+
+// Server returns the GitHub server the client adds skills from.
+func (c *Client) Server() Server {
+	return c.server
+}
+
+// SetBaseURL sets the base URL for GitHub API requests made by this client,
+// overriding the API URL of its server. Every request of Download, from the
+// SKILL.md check to the recursive walk, goes to this URL, so tests can point
+// it at a mock server.
 func (c *Client) SetBaseURL(url string) {
 	c.baseURL = url
 }
@@ -72,18 +432,29 @@ func (c *Client) SetBaseURL(url string) {
 // The URL must be in format: https://github.com/owner/repo/tree/branch/path
 //
 // The function performs the following steps:
-// 1. Parses and validates the GitHub URL
-// 2. Checks that SKILL.md exists in the target directory
-// 3. Prompts the user for confirmation if the download directory already exists
-// 4. Downloads all files and directories recursively to a temporary location
-// 5. Atomically moves the download to the final location
-// 6. Displays download statistics
+//  1. Parses and validates the GitHub URL
+//  2. Checks that SKILL.md exists in the target directory, unless disabled
+//     with SetSkipSkillCheck
+//  3. Prompts the user for confirmation if the download directory already exists
+//  4. Downloads all files and directories recursively to a temporary location
+//  5. Atomically moves the download to the final location
+//  6. Registers the skill and returns the download statistics
 //
-// Returns an error if any step fails, nil on success.
-func (c *Client) Download(rawURL string) error {
-	repoInfo, err := ParseGitHubURL(rawURL)
+// The download is bound to ctx; if ctx is cancelled (e.g. on SIGINT) the
+// temporary download directory is removed and the existing skill, if any,
+// is left in place.
+//
+// Returns the download statistics on success. If the user declines to
+// overwrite an existing skill, both the stats and the error are nil. If the
+// skill was stored but the registry could not be updated, the stats are
+// returned together with an ErrorTypeRegistry error. If the same source is
+// already installed at the remote commit, nothing is downloaded and the
+// stats have UpToDate set, unless SetForce was called.
+func (c *Client) Download(ctx context.Context, rawURL string) (*DownloadStats, error) {
+	rawURL = sourceurl.Canonicalize(rawURL)
+	repoInfo, err := c.server.ParseGitHubURL(rawURL)
 	if err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeInvalidURL,
 			Message: "failed to parse URL",
 			Err:     err,
@@ -92,86 +463,109 @@ func (c *Client) Download(rawURL string) error {
 
 	c.logger.Debug("Parsed GitHub URL", "owner", repoInfo.Owner, "repo", repoInfo.Repo, "branch", repoInfo.Branch, "path", repoInfo.Path)
 
-	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	expectedChecksum, err := c.expectedChecksum()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.downloadTimeout)
 	defer cancel()
 
-	hasSkillMD, err := c.checkSKILLExists(ctx, repoInfo)
-	if err != nil {
-		return &DownloadError{
-			Type:    ErrorTypeAPI,
-			Message: "failed to check SKILL.md",
-			Err:     err,
+	if c.skipSkillCheck {
+		c.logger.Warn("Skipping SKILL.md check", "path", repoInfo.Path)
+	} else {
+		hasSkillMD, err := c.checkSKILLExists(ctx, repoInfo)
+		if err != nil {
+			return nil, accessError("failed to check SKILL.md", err)
 		}
-	}
-	if !hasSkillMD {
-		return &DownloadError{
-			Type:    ErrorTypeValidation,
-			Message: "SKILL.md not found in the target directory. This is not a valid skill package.",
+		if !hasSkillMD {
+			return nil, &DownloadError{
+				Type:    ErrorTypeValidation,
+				Message: "SKILL.md not found in the target directory",
+				Err:     ErrSkillManifestMissing,
+			}
 		}
 	}
 
 	commitSHA, err := c.GetBranchCommitSHA(ctx, repoInfo)
 	if err != nil {
-		return &DownloadError{
-			Type:    ErrorTypeAPI,
-			Message: "failed to get commit SHA",
-			Err:     err,
+		return nil, accessError("failed to get commit SHA", err)
+	}
+
+	// The ref type only decides how update treats the skill, so failing to
+	// resolve it leaves the skill tracking its ref like a branch.
+	refType, err := c.GetRefType(ctx, repoInfo)
+	if err != nil {
+		c.logger.Warn("Failed to resolve ref type", "ref", repoInfo.Branch, "error", err)
+	}
+	if c.tagPattern != "" {
+		if _, err := path.Match(c.tagPattern, ""); err != nil {
+			return nil, &DownloadError{
+				Type:    ErrorTypeValidation,
+				Message: fmt.Sprintf("invalid tag pattern '%s'", c.tagPattern),
+				Err:     err,
+			}
+		}
+		if refType != RefTypeTag {
+			return nil, &DownloadError{
+				Type:    ErrorTypeValidation,
+				Message: fmt.Sprintf("a tag pattern requires a tag URL, but '%s' is not a tag", repoInfo.Branch),
+			}
+		}
+	}
+	if c.updatePolicy == UpdatePolicyLatestTag && refType != RefTypeTag {
+		return nil, &DownloadError{
+			Type:    ErrorTypeValidation,
+			Message: fmt.Sprintf("the %s policy requires a tag URL, but '%s' is not a tag", UpdatePolicyLatestTag, repoInfo.Branch),
 		}
 	}
 
-	homeDir, err := os.UserHomeDir()
+	dataDir, err := paths.ResolveDataDir(c.dataDir)
 	if err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeFilesystem,
-			Message: "failed to get home directory",
+			Message: "failed to resolve data directory",
 			Err:     err,
 		}
 	}
 
 	skillName := filepath.Base(repoInfo.Path)
 	if skillName == "." || skillName == "" {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeInvalidURL,
 			Message: fmt.Sprintf("invalid skill path: %s", repoInfo.Path),
 		}
 	}
-	localPath := filepath.Join(homeDir, ".gskills", "skills", skillName)
+	localPath := c.skillStorePath(dataDir, skillName)
+
+	if !c.force && c.isInstalled(dataDir, skillName, rawURL, commitSHA, localPath, expectedChecksum) {
+		c.logger.Info("Skill already up to date", "skill", skillName, "sha", commitSHA)
+		return &DownloadStats{StorePath: localPath, UpToDate: true, Checksum: expectedChecksum}, nil
+	}
 
 	exists, err := checkPathExists(localPath)
 	if err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to check path existence",
 			Err:     err,
 		}
 	}
 
-	if exists {
-		overwrite, err := promptOverwrite()
+	if exists && c.confirmOverwrite != nil {
+		overwrite, err := c.confirmOverwriteOf(localPath)
 		if err != nil {
-			return &DownloadError{
-				Type:    ErrorTypeFilesystem,
-				Message: "failed to read user input",
-				Err:     err,
-			}
+			return nil, err
 		}
 		if !overwrite {
-			fmt.Println("Download cancelled.")
 			c.logger.Info("Download cancelled by user")
-			return nil
-		}
-		if err := os.RemoveAll(localPath); err != nil {
-			return &DownloadError{
-				Type:    ErrorTypeFilesystem,
-				Message: "failed to remove existing directory",
-				Err:     err,
-			}
+			return nil, nil
 		}
 	}
 
 	tmpDir := filepath.Join(filepath.Dir(localPath), ".tmp."+filepath.Base(localPath)+fmt.Sprintf(".%d", time.Now().UnixNano()))
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to create temporary directory",
 			Err:     err,
@@ -180,27 +574,54 @@ func (c *Client) Download(rawURL string) error {
 
 	c.logger.Debug("Created temporary directory", "path", tmpDir)
 
+	committed := false
 	defer func() {
-		if err != nil {
-			c.logger.Error("Cleaning up temporary directory", err, "path", tmpDir)
+		if !committed {
+			c.logger.Warn("Cleaning up temporary directory", "path", tmpDir)
 			os.RemoveAll(tmpDir)
 		}
 	}()
 
 	c.logger.Info("Starting download", "url", rawURL, "target", tmpDir)
-	fmt.Printf("Downloading skill from %s...\n", rawURL)
 
-	stats, err := c.downloadRecursive(ctx, repoInfo, tmpDir, repoInfo.Path)
+	ignore, err := c.FetchIgnoreRules(ctx, repoInfo)
 	if err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
+			Type:    ErrorTypeAPI,
+			Message: "failed to read " + IgnoreFileName,
+			Err:     err,
+		}
+	}
+
+	stats, err := c.downloadRecursive(ctx, repoInfo, tmpDir, repoInfo.Path, ignore)
+	if errors.Is(err, ErrDownloadLimit) {
+		return nil, &DownloadError{
+			Type:    ErrorTypeLimit,
+			Message: "download aborted",
+			Err:     err,
+		}
+	}
+	if err != nil {
+		return nil, &DownloadError{
 			Type:    ErrorTypeAPI,
 			Message: "failed to download",
 			Err:     err,
 		}
 	}
 
+	if err := c.checkFileCount(stats); err != nil {
+		return nil, err
+	}
+
+	if expectedChecksum != "" {
+		if _, err := verifyChecksum(tmpDir, expectedChecksum); err != nil {
+			return nil, checksumError(err)
+		}
+		c.logger.Info("Checksum verified", "checksum", expectedChecksum)
+	}
+
 	if err := os.RemoveAll(localPath); err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to remove existing directory for atomic move",
 			Err:     err,
@@ -208,38 +629,73 @@ func (c *Client) Download(rawURL string) error {
 	}
 
 	if err := os.Rename(tmpDir, localPath); err != nil {
-		return &DownloadError{
+		return nil, &DownloadError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to move download to final location",
 			Err:     err,
 		}
 	}
+	committed = true
 
 	c.logger.Info("Download complete", "files", stats.FilesDownloaded, "bytes", stats.BytesDownloaded)
 
-	fmt.Printf("\nDownload complete!\n")
-	fmt.Printf("  Files downloaded: %d\n", stats.FilesDownloaded)
-	fmt.Printf("  Directories created: %d\n", stats.DirsCreated)
-	fmt.Printf("  Total size: %d bytes\n", stats.BytesDownloaded)
-	fmt.Printf("  Location: %s\n", localPath)
+	stats.StorePath = localPath
+	stats.Checksum = expectedChecksum
 
 	skillMetadata := &types.SkillMetadata{
-		ID:        fmt.Sprintf("%s@%s", skillName, repoInfo.Branch),
-		Name:      skillName,
-		Version:   repoInfo.Branch,
-		CommitSHA: commitSHA,
-		SourceURL: rawURL,
-		StorePath: localPath,
-		UpdatedAt: time.Now(),
+		ID:            fmt.Sprintf("%s@%s", skillName, repoInfo.Branch),
+		Name:          skillName,
+		Version:       repoInfo.Branch,
+		CommitSHA:     commitSHA,
+		RefType:       refType,
+		TagPattern:    c.tagPattern,
+		UpdatePolicy:  c.updatePolicy,
+		Unverified:    c.skipSkillCheck,
+		Checksum:      expectedChecksum,
+		IgnoreApplied: ignore != nil,
+		IncludeHidden: c.includeHidden,
+		SourceURL:     rawURL,
+		StorePath:     localPath,
+		UpdatedAt:     time.Now(),
 	}
-	if err := registry.AddOrUpdateSkill(skillMetadata); err != nil {
-		c.logger.Error("Failed to update skills registry", err, "skill", skillName)
-		fmt.Printf("Warning: Failed to update skills registry: %v\n", err)
-		fmt.Println("The skill was downloaded successfully, but may not appear in 'gskills list'.")
-		fmt.Println("You may need to manually clean up ~/.gskills/skills.json if this persists.")
+	if err := c.registerSkill(dataDir, skillMetadata); err != nil {
+		return stats, err
 	}
 
-	return nil
+	return stats, nil
+}
+
+// expectedChecksum returns the normalized checksum set with SetChecksum, or
+// an empty string if none is set.
+func (c *Client) expectedChecksum() (string, error) {
+	if c.checksum == "" {
+		return "", nil
+	}
+	checksum, err := NormalizeChecksum(c.checksum)
+	if err != nil {
+		return "", &DownloadError{
+			Type:    ErrorTypeValidation,
+			Message: "invalid checksum",
+			Err:     err,
+		}
+	}
+	return checksum, nil
+}
+
+// checksumError wraps a failed checksum verification in a DownloadError.
+func checksumError(err error) error {
+	if errors.Is(err, ErrChecksumMismatch) {
+		return &DownloadError{
+			Type:    ErrorTypeChecksum,
+			Message: "downloaded skill does not match the expected checksum",
+			Err:     err,
+		}
+	}
+	return &DownloadError{
+		Type:    ErrorTypeFilesystem,
+		Message: "failed to compute checksum",
+		Err:     err,
+	}
 }
 
 type downloadTask struct {
@@ -247,23 +703,68 @@ type downloadTask struct {
 	localPath  string
 }
 
-func (c *Client) downloadRecursive(ctx context.Context, repoInfo *GitHubRepoInfo, localPath string, downloadPath string) (*DownloadStats, error) {
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+// downloadRecursive downloads the directory downloadPath of the repository
+// into localPath in two passes: the whole tree is listed first, then the
+// files are downloaded, each pass with up to c.concurrency parallel
+// requests. The start of each pass is reported through the progress
+// callback. Entries matched by ignore, and hidden entries unless included,
+// are skipped. The download is aborted once it exceeds the client's download
+// limits.
+func (c *Client) downloadRecursive(ctx context.Context, repoInfo *GitHubRepoInfo, localPath string, downloadPath string, ignore *IgnoreRules) (*DownloadStats, error) {
+	stats := &DownloadStats{}
+
+	c.reportProgress(ProgressEvent{Phase: PhaseListing})
+	files, dirs, err := c.listTree(ctx, repoInfo, localPath, downloadPath, ignore, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		stats.DirsCreated++
+	}
+
+	var listedBytes int64
+	for _, file := range files {
+		listedBytes += int64(file.item.Size)
+	}
+	c.reportProgress(ProgressEvent{Phase: PhaseDownloading, Files: len(files), Bytes: listedBytes})
 
-	stats := &DownloadStats{
-		FilesDownloaded: 0,
-		DirsCreated:     0,
-		BytesDownloaded: 0,
+	if err := c.downloadFiles(ctx, files, stats); err != nil {
+		return nil, err
 	}
+	return stats, nil
+}
+
+// remoteFile is a file found by listTree and the local path it is
+// downloaded to.
+type remoteFile struct {
+	item      types.GitHubContent
+	localPath string
+}
 
-	sem := make(chan struct{}, maxConcurrentDownloads)
+// listTree lists the directory downloadPath of the repository and its
+// subdirectories with up to c.concurrency parallel requests, without
+// downloading anything. It returns the files to download and the local
+// directories to create under localPath, parents before children. Hidden and
+// ignored entries are skipped and counted in stats. The walk is aborted once
+// the listed files exceed the client's download limits.
+func (c *Client) listTree(ctx context.Context, repoInfo *GitHubRepoInfo, localPath string, downloadPath string, ignore *IgnoreRules, stats *DownloadStats) ([]remoteFile, []string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, c.concurrency)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	var downloadErr error
+	var listErr error
+	var files []remoteFile
+	var dirs []string
+	var listedBytes int64
 
-	var downloadTask func(string, string)
-	downloadTask = func(remotePath, localTarget string) {
+	var listTask func(string, string)
+	listTask = func(remotePath, localTarget string) {
 		defer wg.Done()
 
 		select {
@@ -276,64 +777,136 @@ func (c *Client) downloadRecursive(ctx context.Context, repoInfo *GitHubRepoInfo
 		contents, err := c.GetGitHubContents(ctx, repoInfo, remotePath)
 		if err != nil {
 			mu.Lock()
-			downloadErr = fmt.Errorf("failed to get contents for %s: %w", remotePath, err)
+			if listErr == nil {
+				listErr = fmt.Errorf("failed to get contents for %s: %w", remotePath, err)
+			}
 			mu.Unlock()
 			cancel()
 			return
 		}
 
+		mu.Lock()
+		defer mu.Unlock()
 		for _, item := range contents {
 			itemLocalPath := filepath.Join(localTarget, item.Name)
 
+			relPath := relativeTo(downloadPath, path.Join(remotePath, item.Name))
+			if !c.includeHidden && IsHidden(relPath) {
+				stats.Hidden++
+				continue
+			}
+			if ignore.Match(relPath, item.Type == "dir") {
+				stats.Ignored++
+				continue
+			}
+
 			switch item.Type {
 			case "dir":
-				if err := os.MkdirAll(itemLocalPath, 0755); err != nil {
-					mu.Lock()
-					downloadErr = fmt.Errorf("failed to create directory %s: %w", itemLocalPath, err)
-					mu.Unlock()
-					cancel()
-					return
-				}
-
-				mu.Lock()
-				stats.DirsCreated++
-				mu.Unlock()
-
+				dirs = append(dirs, itemLocalPath)
 				wg.Add(1)
-				go downloadTask(path.Join(remotePath, item.Name), itemLocalPath)
+				go listTask(path.Join(remotePath, item.Name), itemLocalPath)
 			case "file":
-				data, err := c.DownloadFile(ctx, item.DownloadURL)
-				if err != nil {
-					mu.Lock()
-					downloadErr = fmt.Errorf("failed to download file %s: %w", item.Name, err)
-					mu.Unlock()
+				if err := c.limits.Check(len(files)+1, listedBytes+int64(item.Size)); err != nil {
+					if listErr == nil {
+						listErr = err
+					}
 					cancel()
 					return
 				}
+				files = append(files, remoteFile{item: item, localPath: itemLocalPath})
+				listedBytes += int64(item.Size)
+			}
+		}
+	}
 
-				if err := os.WriteFile(itemLocalPath, data, 0644); err != nil {
-					mu.Lock()
-					downloadErr = fmt.Errorf("failed to write file %s: %w", itemLocalPath, err)
-					mu.Unlock()
-					cancel()
-					return
-				}
+	wg.Add(1)
+	go listTask(downloadPath, localPath)
+	wg.Wait()
 
-				mu.Lock()
+	if listErr != nil {
+		return nil, nil, listErr
+	}
+
+	// Workers waiting on the semaphore return silently when ctx is cancelled,
+	// so a cancelled walk must be reported here to avoid treating a partial
+	// listing as complete.
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Subdirectories are listed concurrently; sorting puts every parent
+	// before its children.
+	slices.Sort(dirs)
+	return files, dirs, nil
+}
+
+// downloadFiles downloads files with up to c.concurrency parallel requests
+// and writes them to their local paths, whose directories must exist. The
+// downloaded files and bytes are counted in stats. The download is aborted
+// once the actual content exceeds the client's download limits.
+func (c *Client) downloadFiles(ctx context.Context, files []remoteFile, stats *DownloadStats) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var downloadErr error
+
+	// fail records the first error and stops the other downloads, whose own
+	// errors are then only the cancellation.
+	fail := func(err error) {
+		mu.Lock()
+		if downloadErr == nil {
+			downloadErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for _, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case <-ctx.Done():
+				return
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			}
+
+			data, err := c.DownloadFile(ctx, file.item.DownloadURL)
+			if err != nil {
+				fail(fmt.Errorf("failed to download file %s: %w", file.item.Name, err))
+				return
+			}
+
+			// The listed size may be missing or wrong, so the actual
+			// content is checked again before it is written.
+			mu.Lock()
+			err = c.limits.Check(stats.FilesDownloaded+1, stats.BytesDownloaded+int64(len(data)))
+			if err == nil {
 				stats.FilesDownloaded++
 				stats.BytesDownloaded += int64(len(data))
-				mu.Unlock()
 			}
-		}
-	}
+			mu.Unlock()
+			if err != nil {
+				fail(err)
+				return
+			}
 
-	wg.Add(1)
-	go downloadTask(downloadPath, localPath)
+			if err := os.WriteFile(file.localPath, data, 0644); err != nil {
+				fail(fmt.Errorf("failed to write file %s: %w", file.localPath, err))
+			}
+		}()
+	}
 	wg.Wait()
 
 	if downloadErr != nil {
-		return nil, downloadErr
+		return downloadErr
 	}
 
-	return stats, nil
+	// As in listTree, a cancelled download must not pass for a complete one.
+	return ctx.Err()
 }
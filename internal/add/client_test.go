@@ -3,18 +3,23 @@ package add
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/version"
 )
 
 type LogCall struct {
@@ -202,14 +207,14 @@ func TestNewClient(t *testing.T) {
 			token:         "",
 			wantToken:     false,
 			wantBaseURL:   "https://api.github.com",
-			wantUserAgent: "gskills-cli/1.0",
+			wantUserAgent: version.UserAgent(),
 		},
 		{
 			name:          "client with token",
 			token:         "test-token",
 			wantToken:     true,
 			wantBaseURL:   "https://api.github.com",
-			wantUserAgent: "gskills-cli/1.0",
+			wantUserAgent: version.UserAgent(),
 		},
 	}
 
@@ -245,6 +250,32 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClient_UserAgentReflectsVersion(t *testing.T) {
+	oldVersion := version.Version
+	version.Version = "v9.8.7"
+	defer func() { version.Version = oldVersion }()
+
+	var gotUserAgent string
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	repoInfo := &GitHubRepoInfo{Owner: "owner", Repo: "repo", Branch: "main"}
+	if _, err := client.GetBranchCommitSHA(context.Background(), repoInfo); err != nil {
+		t.Fatalf("GetBranchCommitSHA() error = %v", err)
+	}
+
+	if gotUserAgent != "gskills-cli/v9.8.7" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "gskills-cli/v9.8.7")
+	}
+}
+
 func TestCheckSKILLExists(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -256,16 +287,56 @@ func TestCheckSKILLExists(t *testing.T) {
 		{
 			name:       "SKILL.md exists",
 			statusCode: http.StatusOK,
-			response:   `{"name":"SKILL.md","path":"SKILL.md","type":"file"}`,
+			response:   `[{"name":"SKILL.md","path":"skills/test/SKILL.md","type":"file"}]`,
+			wantExists: true,
+		},
+		{
+			name:       "lowercase skill.md",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"skill.md","path":"skills/test/skill.md","type":"file"}]`,
+			wantExists: true,
+		},
+		{
+			name:       "mixed case Skill.md",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"docs","path":"skills/test/docs","type":"dir"},{"name":"Skill.md","path":"skills/test/Skill.md","type":"file"}]`,
 			wantExists: true,
-			wantErr:    false,
 		},
 		{
-			name:       "SKILL.md not found",
+			name:       "SKILL.yaml manifest",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"SKILL.yaml","path":"skills/test/SKILL.yaml","type":"file"}]`,
+			wantExists: true,
+		},
+		{
+			name:       "SKILL.json manifest",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"SKILL.json","path":"skills/test/SKILL.json","type":"file"}]`,
+			wantExists: true,
+		},
+		{
+			name:       "README.md only is not a skill",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"README.md","path":"skills/test/README.md","type":"file"}]`,
+			wantExists: false,
+		},
+		{
+			name:       "SKILL.md directory is not a manifest",
+			statusCode: http.StatusOK,
+			response:   `[{"name":"SKILL.md","path":"skills/test/SKILL.md","type":"dir"}]`,
+			wantExists: false,
+		},
+		{
+			name:       "path is a file",
+			statusCode: http.StatusOK,
+			response:   `{"name":"test","path":"skills/test","type":"file"}`,
+			wantExists: false,
+		},
+		{
+			name:       "directory not found",
 			statusCode: http.StatusNotFound,
 			response:   `{"message":"Not Found"}`,
 			wantExists: false,
-			wantErr:    false,
 		},
 		{
 			name:       "API error",
@@ -281,14 +352,14 @@ func TestCheckSKILLExists(t *testing.T) {
 			ts := NewTestServer()
 			defer ts.Close()
 
-			path := "/repos/owner/repo/contents/skills/test/SKILL.md"
+			path := "/repos/owner/repo/contents/skills/test"
 			ts.SetHandler(path, func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				w.Write([]byte(tt.response))
 			})
 
 			client := NewClient("")
-			client.baseURL = ts.URL()
+			client.SetBaseURL(ts.URL())
 
 			repoInfo := &GitHubRepoInfo{
 				Owner:  "owner",
@@ -316,6 +387,116 @@ func TestCheckSKILLExists(t *testing.T) {
 	}
 }
 
+func TestCheckSKILLExists_RetriesRateLimit(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusForbidden} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+
+			// The first request is rate limited, the retry succeeds.
+			path := "/repos/owner/repo/contents/skills/test"
+			ts.SetHandler(path, func(w http.ResponseWriter, r *http.Request) {
+				if ts.GetCallCount(path) == 1 {
+					w.WriteHeader(status)
+					w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+					return
+				}
+				w.Write([]byte(`[{"name":"SKILL.md","path":"skills/test/SKILL.md","type":"file"}]`))
+			})
+
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+
+			repoInfo := &GitHubRepoInfo{Owner: "owner", Repo: "repo", Branch: "main", Path: "skills/test"}
+			exists, err := client.checkSKILLExists(context.Background(), repoInfo)
+			if err != nil {
+				t.Fatalf("checkSKILLExists() error = %v, want the rate limit retried", err)
+			}
+			if !exists {
+				t.Error("checkSKILLExists() = false, want true after the retry")
+			}
+			if got := ts.GetCallCount(path); got != 2 {
+				t.Errorf("checkSKILLExists() called test server %d times, want 2", got)
+			}
+		})
+	}
+}
+
+func TestSetRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   int
+		wantErr    bool
+		wantCalls  int
+	}{
+		{name: "no retries", maxRetries: 0, failures: 1, wantErr: true, wantCalls: 1},
+		{name: "one retry recovers", maxRetries: 1, failures: 1, wantCalls: 2},
+		{name: "one retry exhausted", maxRetries: 1, failures: 2, wantErr: true, wantCalls: 2},
+		{name: "more retries than the default", maxRetries: 6, failures: 5, wantCalls: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+
+			// The first tt.failures requests are rate limited.
+			path := "/repos/owner/repo/commits/main"
+			ts.SetHandler(path, func(w http.ResponseWriter, r *http.Request) {
+				if ts.GetCallCount(path) <= tt.failures {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte(`{"sha":"abc123"}`))
+			})
+
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+			client.SetRetries(tt.maxRetries, MinRetryWait)
+			if client.restyClient.RetryCount != tt.maxRetries {
+				t.Errorf("HTTP retry count = %d, want %d", client.restyClient.RetryCount, tt.maxRetries)
+			}
+			// Keep the backoff short; the count is what is under test.
+			client.retryWait = time.Millisecond
+
+			repoInfo := &GitHubRepoInfo{Owner: "owner", Repo: "repo", Branch: "main"}
+			sha, err := client.GetBranchCommitSHA(context.Background(), repoInfo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBranchCommitSHA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && sha != "abc123" {
+				t.Errorf("GetBranchCommitSHA() = %q, want abc123", sha)
+			}
+			if got := ts.GetCallCount(path); got != tt.wantCalls {
+				t.Errorf("GetBranchCommitSHA() called test server %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSetRetries_Bounds(t *testing.T) {
+	client := NewClient("")
+
+	client.SetRetries(-1, 0)
+	if client.retryAttempts != maxRetryAttempts || client.retryWait != rateLimitRetryWait {
+		t.Errorf("defaults changed to %d attempts, %v wait", client.retryAttempts, client.retryWait)
+	}
+
+	client.SetRetries(MaxRetries+5, time.Millisecond)
+	if client.retryAttempts != MaxRetries+1 || client.restyClient.RetryCount != MaxRetries {
+		t.Errorf("retries = %d attempts, %d HTTP retries, want capped at %d", client.retryAttempts, client.restyClient.RetryCount, MaxRetries)
+	}
+	if client.retryWait != MinRetryWait {
+		t.Errorf("retry wait = %v, want raised to %v", client.retryWait, MinRetryWait)
+	}
+
+	client.SetRetries(-1, time.Hour)
+	if client.retryWait != MaxRetryWait || client.restyClient.RetryWaitTime != MaxRetryWait {
+		t.Errorf("retry wait = %v, want capped at %v", client.retryWait, MaxRetryWait)
+	}
+}
+
 func TestGetBranchCommitSHA(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -323,6 +504,7 @@ func TestGetBranchCommitSHA(t *testing.T) {
 		response   string
 		wantSHA    string
 		wantErr    bool
+		wantCalls  int
 	}{
 		{
 			name:       "successful fetch",
@@ -337,6 +519,14 @@ func TestGetBranchCommitSHA(t *testing.T) {
 			response:   `{"message":"Not Found"}`,
 			wantSHA:    "",
 			wantErr:    true,
+			wantCalls:  1,
+		},
+		{
+			name:       "rate limited once",
+			statusCode: http.StatusTooManyRequests,
+			response:   `{"sha":"abc123def456"}`,
+			wantSHA:    "abc123def456",
+			wantCalls:  2,
 		},
 		{
 			name:       "missing sha in response",
@@ -361,12 +551,17 @@ func TestGetBranchCommitSHA(t *testing.T) {
 
 			path := "/repos/owner/repo/commits/main"
 			ts.SetHandler(path, func(w http.ResponseWriter, r *http.Request) {
-				w.WriteHeader(tt.statusCode)
+				// A rate limit is only answered to the first request.
+				if tt.statusCode == http.StatusTooManyRequests && ts.GetCallCount(path) > 1 {
+					w.WriteHeader(http.StatusOK)
+				} else {
+					w.WriteHeader(tt.statusCode)
+				}
 				w.Write([]byte(tt.response))
 			})
 
 			client := NewClient("")
-			client.baseURL = ts.URL()
+			client.SetBaseURL(ts.URL())
 
 			repoInfo := &GitHubRepoInfo{
 				Owner:  "owner",
@@ -384,11 +579,15 @@ func TestGetBranchCommitSHA(t *testing.T) {
 			}
 
 			if sha != tt.wantSHA {
-				t.Errorf("getBranchCommitSHA() = %v, want %v", sha, tt.wantSHA)
+				t.Errorf("GetBranchCommitSHA() = %v, want %v", sha, tt.wantSHA)
 			}
 
-			if !tt.wantErr && ts.GetCallCount(path) != 1 {
-				t.Errorf("getBranchCommitSHA() called test server %d times, want 1", ts.GetCallCount(path))
+			wantCalls := tt.wantCalls
+			if wantCalls == 0 && !tt.wantErr {
+				wantCalls = 1
+			}
+			if wantCalls != 0 && ts.GetCallCount(path) != wantCalls {
+				t.Errorf("GetBranchCommitSHA() called test server %d times, want %d", ts.GetCallCount(path), wantCalls)
 			}
 		})
 	}
@@ -439,7 +638,7 @@ func TestDownloadFile(t *testing.T) {
 			})
 
 			client := NewClient("")
-			client.baseURL = ts.URL()
+			client.SetBaseURL(ts.URL())
 			client.logger = &MockLogger{}
 
 			ctx := context.Background()
@@ -511,7 +710,7 @@ func TestGetGitHubContents(t *testing.T) {
 			})
 
 			client := NewClient("")
-			client.baseURL = ts.URL()
+			client.SetBaseURL(ts.URL())
 
 			repoInfo := &GitHubRepoInfo{
 				Owner:  "owner",
@@ -535,6 +734,50 @@ func TestGetGitHubContents(t *testing.T) {
 	}
 }
 
+func TestDownloadFile_OutlastsRequestTimeout(t *testing.T) {
+	// The API timeout stands in for the 30s default: the file takes longer
+	// to arrive than an API call is allowed, but well within the context.
+	const apiTimeout = 300 * time.Millisecond
+	const chunks, chunkSize = 8, 64 << 10
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/raw/large.bin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(chunks*chunkSize))
+		chunk := []byte(strings.Repeat("x", chunkSize))
+		for range chunks {
+			w.Write(chunk)
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	})
+	ts.SetHandler("/repos/owner/repo/contents/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * apiTimeout)
+		w.Write([]byte("[]"))
+	})
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetRetries(0, 0)
+	client.restyClient.SetTimeout(apiTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data, err := client.DownloadFile(ctx, ts.URL()+"/raw/large.bin")
+	if err != nil {
+		t.Fatalf("DownloadFile() error = %v, want the slow file to complete", err)
+	}
+	if len(data) != chunks*chunkSize {
+		t.Errorf("DownloadFile() returned %d bytes, want %d", len(data), chunks*chunkSize)
+	}
+
+	repoInfo := &GitHubRepoInfo{Owner: "owner", Repo: "repo", Branch: "main", Path: "slow"}
+	if _, err := client.checkSKILLExists(ctx, repoInfo); err == nil {
+		t.Error("checkSKILLExists() should still fail once the API timeout passes")
+	}
+}
+
 func TestDownloadRecursive(t *testing.T) {
 	t.Run("successful directory download", func(t *testing.T) {
 		ts := NewTestServer()
@@ -586,7 +829,7 @@ func TestDownloadRecursive(t *testing.T) {
 		})
 
 		client := NewClient("")
-		client.baseURL = ts.URL()
+		client.SetBaseURL(ts.URL())
 		mockLogger := &MockLogger{}
 		client.logger = mockLogger
 
@@ -600,7 +843,7 @@ func TestDownloadRecursive(t *testing.T) {
 		tmpDir := t.TempDir()
 		ctx := context.Background()
 
-		stats, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill")
+		stats, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill", nil)
 
 		if err != nil {
 			t.Fatalf("downloadRecursive() error = %v", err)
@@ -659,7 +902,7 @@ func TestDownloadRecursive(t *testing.T) {
 		})
 
 		client := NewClient("")
-		client.baseURL = ts.URL()
+		client.SetBaseURL(ts.URL())
 
 		repoInfo := &GitHubRepoInfo{
 			Owner:  "owner",
@@ -672,7 +915,7 @@ func TestDownloadRecursive(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
 		defer cancel()
 
-		_, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill")
+		_, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill", nil)
 
 		if err == nil {
 			t.Error("downloadRecursive() expected error on timeout, got nil")
@@ -697,14 +940,6 @@ func TestDownload(t *testing.T) {
 			name: "successful download",
 			url:  "https://github.com/owner/repo/tree/main/skill",
 			setupServer: func(ts *TestServer) {
-				ts.SetHandler("/repos/owner/repo/contents/skill/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusOK)
-					json.NewEncoder(w).Encode(map[string]interface{}{
-						"name": "SKILL.md",
-						"type": "file",
-					})
-				})
-
 				ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusOK)
 					json.NewEncoder(w).Encode(map[string]interface{}{
@@ -784,7 +1019,7 @@ func TestDownload(t *testing.T) {
 			name: "SKILL.md not found",
 			url:  "https://github.com/owner/repo/tree/main/noskill",
 			setupServer: func(ts *TestServer) {
-				ts.SetHandler("/repos/owner/repo/contents/noskill/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+				ts.SetHandler("/repos/owner/repo/contents/noskill", func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusNotFound)
 				})
 			},
@@ -795,11 +1030,10 @@ func TestDownload(t *testing.T) {
 			name: "commit SHA fetch fails",
 			url:  "https://github.com/owner/repo/tree/main/nocommit",
 			setupServer: func(ts *TestServer) {
-				ts.SetHandler("/repos/owner/repo/contents/nocommit/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+				ts.SetHandler("/repos/owner/repo/contents/nocommit", func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusOK)
-					json.NewEncoder(w).Encode(map[string]interface{}{
-						"name": "SKILL.md",
-						"type": "file",
+					json.NewEncoder(w).Encode([]types.GitHubContent{
+						{Type: "file", Name: "SKILL.md", Path: "nocommit/SKILL.md"},
 					})
 				})
 
@@ -818,14 +1052,6 @@ func TestDownload(t *testing.T) {
 				skillPath := filepath.Join(homeDir, ".gskills", "skills", "skill2")
 				os.MkdirAll(skillPath, 0755)
 
-				ts.SetHandler("/repos/owner/repo/contents/skill2/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusOK)
-					json.NewEncoder(w).Encode(map[string]interface{}{
-						"name": "SKILL.md",
-						"type": "file",
-					})
-				})
-
 				ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusOK)
 					json.NewEncoder(w).Encode(map[string]interface{}{
@@ -838,6 +1064,12 @@ func TestDownload(t *testing.T) {
 
 				ts.SetHandler("/repos/owner/repo/contents/skill2", func(w http.ResponseWriter, r *http.Request) {
 					contents := []types.GitHubContent{
+						{
+							Type:        "file",
+							Name:        "SKILL.md",
+							Path:        "skill2/SKILL.md",
+							DownloadURL: ts.URL() + "/file",
+						},
 						{
 							Type:        "file",
 							Name:        "file.txt",
@@ -872,11 +1104,10 @@ func TestDownload(t *testing.T) {
 				skillPath := filepath.Join(homeDir, ".gskills", "skills", "skill3")
 				os.MkdirAll(skillPath, 0755)
 
-				ts.SetHandler("/repos/owner/repo/contents/skill3/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+				ts.SetHandler("/repos/owner/repo/contents/skill3", func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusOK)
-					json.NewEncoder(w).Encode(map[string]interface{}{
-						"name": "SKILL.md",
-						"type": "file",
+					json.NewEncoder(w).Encode([]types.GitHubContent{
+						{Type: "file", Name: "SKILL.md", Path: "skill3/SKILL.md"},
 					})
 				})
 
@@ -903,7 +1134,7 @@ func TestDownload(t *testing.T) {
 			tt.setupServer(ts)
 
 			client := NewClient("")
-			client.baseURL = ts.URL()
+			client.SetBaseURL(ts.URL())
 
 			if tt.mockPromptOverwrite != nil {
 				oldPromptOverwrite := promptOverwrite
@@ -913,7 +1144,7 @@ func TestDownload(t *testing.T) {
 
 			homeDir, _ := os.UserHomeDir()
 
-			err := client.Download(tt.url)
+			_, err := client.Download(context.Background(), tt.url)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Download() error = %v, wantErr %v", err, tt.wantErr)
@@ -966,7 +1197,7 @@ func TestDownloadRecursive_Race(t *testing.T) {
 	}
 
 	client := NewClient("")
-	client.baseURL = ts.URL()
+	client.SetBaseURL(ts.URL())
 
 	repoInfo := &GitHubRepoInfo{
 		Owner:  "owner",
@@ -978,7 +1209,7 @@ func TestDownloadRecursive_Race(t *testing.T) {
 	tmpDir := t.TempDir()
 	ctx := context.Background()
 
-	stats, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill")
+	stats, err := client.downloadRecursive(ctx, repoInfo, tmpDir, "skill", nil)
 
 	if err != nil {
 		t.Fatalf("downloadRecursive() error = %v", err)
@@ -1055,3 +1286,688 @@ func TestDownloadStats(t *testing.T) {
 		t.Errorf("BytesDownloaded = %d, want 1024", stats.BytesDownloaded)
 	}
 }
+
+func TestDownload_CancelCleansUpTempDir(t *testing.T) {
+	homeDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills")
+	existing := filepath.Join(skillsDir, "skill")
+	if err := os.MkdirAll(existing, 0755); err != nil {
+		t.Fatalf("failed to create existing skill: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(existing, "SKILL.md"), []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write existing SKILL.md: %v", err)
+	}
+
+	oldPromptOverwrite := promptOverwrite
+	promptOverwrite = func() (bool, error) { return true, nil }
+	defer func() { promptOverwrite = oldPromptOverwrite }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	var once sync.Once
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", DownloadURL: ts.URL() + "/slow"},
+		})
+	})
+	ts.SetHandler("/slow", func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(started) })
+		<-r.Context().Done()
+	})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	if _, err := client.Download(ctx, "https://github.com/owner/repo/tree/main/skill"); err == nil {
+		t.Fatal("Download() expected error after cancellation, got nil")
+	}
+
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		t.Fatalf("failed to read skills directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp.") {
+			t.Errorf("temporary directory %s was not cleaned up", entry.Name())
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(existing, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("existing skill was removed: %v", err)
+	}
+	if string(content) != "old" {
+		t.Errorf("existing SKILL.md = %s, want 'old'", string(content))
+	}
+}
+
+func TestClient_SetTimeout(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	})
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetTimeout(100 * time.Millisecond)
+
+	if client.downloadTimeout != 100*time.Millisecond {
+		t.Errorf("downloadTimeout = %v, want 100ms", client.downloadTimeout)
+	}
+
+	start := time.Now()
+	_, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill")
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Download() expected timeout error against slow server, got nil")
+	}
+	if !err.(*DownloadError).Is(&DownloadError{Type: ErrorTypeAPI}) {
+		t.Errorf("Download() error = %v, want API error", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("Download() took %v, SetTimeout did not override the default timeouts", elapsed)
+	}
+
+	client.SetTimeout(-time.Second)
+	if client.downloadTimeout != 100*time.Millisecond {
+		t.Errorf("SetTimeout with non-positive value changed downloadTimeout to %v", client.downloadTimeout)
+	}
+}
+
+func TestDownload_ReturnsStats(t *testing.T) {
+	homeDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	files := map[string]string{
+		"/file/SKILL.md":       "# Stats Skill",
+		"/file/docs/guide.md":  "guide content",
+		"/file/docs/extra.txt": "x",
+	}
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/stats-skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "stats-skill/SKILL.md", DownloadURL: ts.URL() + "/file/SKILL.md"},
+			{Type: "dir", Name: "docs", Path: "stats-skill/docs"},
+		})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/stats-skill/docs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "guide.md", Path: "stats-skill/docs/guide.md", DownloadURL: ts.URL() + "/file/docs/guide.md"},
+			{Type: "file", Name: "extra.txt", Path: "stats-skill/docs/extra.txt", DownloadURL: ts.URL() + "/file/docs/extra.txt"},
+		})
+	})
+	for p, content := range files {
+		content := content
+		ts.SetHandler(p, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(content))
+		})
+	}
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	stats, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/stats-skill")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "stats-skill")
+	if stats.StorePath != storePath {
+		t.Errorf("stats.StorePath = %s, want %s", stats.StorePath, storePath)
+	}
+
+	var wantFiles, wantDirs int
+	var wantBytes int64
+	err = filepath.Walk(storePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == storePath {
+			return nil
+		}
+		if info.IsDir() {
+			wantDirs++
+		} else {
+			wantFiles++
+			wantBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk store path: %v", err)
+	}
+
+	if stats.FilesDownloaded != wantFiles {
+		t.Errorf("stats.FilesDownloaded = %d, want %d", stats.FilesDownloaded, wantFiles)
+	}
+	if stats.DirsCreated != wantDirs {
+		t.Errorf("stats.DirsCreated = %d, want %d", stats.DirsCreated, wantDirs)
+	}
+	if stats.BytesDownloaded != wantBytes {
+		t.Errorf("stats.BytesDownloaded = %d, want %d", stats.BytesDownloaded, wantBytes)
+	}
+	if wantFiles != 3 {
+		t.Errorf("expected 3 files on disk, got %d", wantFiles)
+	}
+}
+
+func TestDownload_RetriesRateLimitedRequests(t *testing.T) {
+	homeDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	// rateLimitOnce answers 429 to the first request and delegates afterwards.
+	rateLimitOnce := func(next http.HandlerFunc) http.HandlerFunc {
+		var mu sync.Mutex
+		limited := false
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			first := !limited
+			limited = true
+			mu.Unlock()
+			if first {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+	// The SKILL.md check is the first request to the directory listing.
+	ts.SetHandler("/repos/owner/repo/contents/limited", rateLimitOnce(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "limited/SKILL.md", DownloadURL: ts.URL() + "/file/SKILL.md"},
+		})
+	}))
+	ts.SetHandler("/file/SKILL.md", rateLimitOnce(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Limited"))
+	}))
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	if _, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/limited"); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(homeDir, ".gskills", "skills", "limited", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to read SKILL.md: %v", err)
+	}
+	if string(content) != "# Limited" {
+		t.Errorf("SKILL.md = %q, want %q", string(content), "# Limited")
+	}
+
+	// Listing: rate-limited check, retried check, download walk.
+	if got := ts.GetCallCount("/repos/owner/repo/contents/limited"); got != 3 {
+		t.Errorf("directory listing called %d times, want 3", got)
+	}
+	if got := ts.GetCallCount("/file/SKILL.md"); got != 2 {
+		t.Errorf("file download called %d times, want 2", got)
+	}
+}
+
+func TestDownload_EndToEnd(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "e2e0sha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skills/e2e", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "skills/e2e/SKILL.md", DownloadURL: ts.URL() + "/raw/SKILL.md"},
+			{Type: "dir", Name: "scripts", Path: "skills/e2e/scripts"},
+		})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skills/e2e/scripts", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "run.sh", Path: "skills/e2e/scripts/run.sh", DownloadURL: ts.URL() + "/raw/scripts/run.sh"},
+		})
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# E2E Skill"))
+	})
+	ts.SetHandler("/raw/scripts/run.sh", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("echo ok"))
+	})
+
+	dataDir := t.TempDir()
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetDataDir(dataDir)
+
+	const skillURL = "https://github.com/owner/repo/tree/main/skills/e2e"
+	stats, err := client.Download(context.Background(), skillURL)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	// The skill directory is listed once for the SKILL.md check and once by
+	// the recursive walk.
+	if got := ts.GetCallCount("/repos/owner/repo/contents/skills/e2e"); got != 2 {
+		t.Errorf("skill directory listed %d times, want 2", got)
+	}
+
+	storePath := filepath.Join(paths.SkillsDir(dataDir), "e2e")
+	if stats.StorePath != storePath || stats.FilesDownloaded != 2 || stats.DirsCreated != 1 {
+		t.Errorf("stats = %+v, want 2 files and 1 dir in %s", stats, storePath)
+	}
+	for file, want := range map[string]string{"SKILL.md": "# E2E Skill", "scripts/run.sh": "echo ok"} {
+		content, err := os.ReadFile(filepath.Join(storePath, file))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, want %q", file, content, want)
+		}
+	}
+
+	skills, err := registry.LoadRegistryWithPath(paths.RegistryPath(dataDir))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if len(skills) != 1 {
+		t.Fatalf("registry has %d skills, want 1", len(skills))
+	}
+	got := skills[0]
+	if got.ID != "e2e@main" || got.Name != "e2e" || got.Version != "main" || got.CommitSHA != "e2e0sha" ||
+		got.SourceURL != skillURL || got.StorePath != storePath {
+		t.Errorf("registry entry = %+v", got)
+	}
+}
+
+func TestDownload_SkipSkillCheck(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "devsha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/wip", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "README.md", Path: "wip/README.md", DownloadURL: ts.URL() + "/raw/README.md"},
+		})
+	})
+	ts.SetHandler("/raw/README.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Work in progress"))
+	})
+
+	const skillURL = "https://github.com/owner/repo/tree/main/wip"
+
+	t.Run("rejected by default", func(t *testing.T) {
+		client := NewClient("")
+		client.SetBaseURL(ts.URL())
+		client.SetDataDir(t.TempDir())
+
+		_, err := client.Download(context.Background(), skillURL)
+		if !errors.Is(err, &DownloadError{Type: ErrorTypeValidation}) {
+			t.Errorf("Download() error = %v, want a validation error", err)
+		}
+	})
+
+	t.Run("downloaded and marked unverified when skipped", func(t *testing.T) {
+		dataDir := t.TempDir()
+		client := NewClient("")
+		client.SetBaseURL(ts.URL())
+		client.SetDataDir(dataDir)
+		client.SetSkipSkillCheck(true)
+
+		stats, err := client.Download(context.Background(), skillURL)
+		if err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(stats.StorePath, "README.md")); err != nil {
+			t.Errorf("directory not downloaded: %v", err)
+		}
+
+		skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "wip")
+		if err != nil {
+			t.Fatalf("skill not registered: %v", err)
+		}
+		if !skill.Unverified {
+			t.Error("skill downloaded without the SKILL.md check should be marked unverified")
+		}
+	})
+}
+
+func TestDownload_EmptySkill(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "emptysha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+
+	const skillURL = "https://github.com/owner/repo/tree/main/empty"
+
+	t.Run("aborted by default", func(t *testing.T) {
+		dataDir := t.TempDir()
+		client := NewClient("")
+		client.SetBaseURL(ts.URL())
+		client.SetDataDir(dataDir)
+		client.SetSkipSkillCheck(true)
+
+		_, err := client.Download(context.Background(), skillURL)
+		if !errors.Is(err, ErrEmptySkill) {
+			t.Fatalf("Download() error = %v, want ErrEmptySkill", err)
+		}
+		if !errors.Is(err, &DownloadError{Type: ErrorTypeValidation}) {
+			t.Errorf("Download() error = %v, want a validation error", err)
+		}
+		entries, _ := os.ReadDir(paths.SkillsDir(dataDir))
+		if len(entries) != 0 {
+			t.Errorf("aborted download left %d entries in the store", len(entries))
+		}
+		if _, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "empty"); err == nil {
+			t.Error("empty skill should not be registered")
+		}
+	})
+
+	t.Run("added with SetAllowEmpty", func(t *testing.T) {
+		client := NewClient("")
+		client.SetBaseURL(ts.URL())
+		client.SetDataDir(t.TempDir())
+		client.SetSkipSkillCheck(true)
+		client.SetAllowEmpty(true)
+
+		stats, err := client.Download(context.Background(), skillURL)
+		if err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+		if stats.FilesDownloaded != 0 || !stats.FewFiles {
+			t.Errorf("stats = %+v, want no files flagged as few", stats)
+		}
+	})
+}
+
+func TestDownload_ReportsPhases(t *testing.T) {
+	// listings counts the listings of the subdirectory, which only the walk
+	// makes; the skill root is also listed by the SKILL.md check.
+	var listings, downloads atomic.Int32
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "phasesha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", Size: 7, DownloadURL: ts.URL() + "/raw/SKILL.md"},
+			{Type: "dir", Name: "scripts", Path: "skill/scripts"},
+		})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill/scripts", func(w http.ResponseWriter, r *http.Request) {
+		listings.Add(1)
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "run.sh", Path: "skill/scripts/run.sh", Size: 7, DownloadURL: ts.URL() + "/raw/run.sh"},
+		})
+	})
+	for _, name := range []string{"SKILL.md", "run.sh"} {
+		ts.SetHandler("/raw/"+name, func(w http.ResponseWriter, r *http.Request) {
+			downloads.Add(1)
+			w.Write([]byte("content"))
+		})
+	}
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetDataDir(t.TempDir())
+
+	var events []ProgressEvent
+	client.SetOnProgress(func(event ProgressEvent) {
+		if event.Phase == PhaseDownloading && (listings.Load() != 1 || downloads.Load() != 0) {
+			t.Errorf("downloading phase started after %d listings and %d downloads, want 1 and 0", listings.Load(), downloads.Load())
+		}
+		events = append(events, event)
+	})
+
+	if _, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill"); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	want := []ProgressEvent{{Phase: PhaseListing}, {Phase: PhaseDownloading, Files: 2, Bytes: 14}}
+	if !slices.Equal(events, want) {
+		t.Errorf("progress events = %+v, want %+v", events, want)
+	}
+}
+
+func TestDownload_SkipsUpToDateSkill(t *testing.T) {
+	var downloads atomic.Int32
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "samesha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", DownloadURL: ts.URL() + "/raw/SKILL.md"},
+		})
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.Write([]byte("# Skill"))
+	})
+
+	const skillURL = "https://github.com/owner/repo/tree/main/skill"
+	dataDir := t.TempDir()
+	newClient := func() *Client {
+		client := NewClient("")
+		client.SetBaseURL(ts.URL())
+		client.SetDataDir(dataDir)
+		client.SetConfirmOverwrite(func() (bool, error) {
+			t.Error("an up-to-date skill should not ask to overwrite")
+			return false, nil
+		})
+		return client
+	}
+
+	first, err := newClient().Download(context.Background(), skillURL)
+	if err != nil {
+		t.Fatalf("first Download() error = %v", err)
+	}
+	if first.UpToDate {
+		t.Error("first Download() should not report up to date")
+	}
+	if got := downloads.Load(); got != 1 {
+		t.Fatalf("first Download() fetched %d files, want 1", got)
+	}
+
+	second, err := newClient().Download(context.Background(), skillURL)
+	if err != nil {
+		t.Fatalf("second Download() error = %v", err)
+	}
+	if !second.UpToDate {
+		t.Error("second Download() should report up to date")
+	}
+	if second.StorePath != first.StorePath {
+		t.Errorf("StorePath = %s, want %s", second.StorePath, first.StorePath)
+	}
+	if got := downloads.Load(); got != 1 {
+		t.Errorf("second Download() fetched files again: %d downloads in total, want 1", got)
+	}
+
+	forced := newClient()
+	forced.SetForce(true)
+	forced.SetConfirmOverwrite(nil)
+	stats, err := forced.Download(context.Background(), skillURL)
+	if err != nil {
+		t.Fatalf("forced Download() error = %v", err)
+	}
+	if stats.UpToDate {
+		t.Error("forced Download() should not report up to date")
+	}
+	if got := downloads.Load(); got != 2 {
+		t.Errorf("forced Download() made %d downloads in total, want 2", got)
+	}
+}
+
+func TestDownload_AbortsWhenLimitsExceeded(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/contents/big/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(types.GitHubContent{Type: "file", Name: "SKILL.md", Path: "big/SKILL.md"})
+	})
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "bigsha"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/big", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "big/SKILL.md", Size: 7, DownloadURL: ts.URL() + "/raw/SKILL.md"},
+			{Type: "dir", Name: "data", Path: "big/data"},
+		})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/big/data", func(w http.ResponseWriter, r *http.Request) {
+		var contents []types.GitHubContent
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprintf("blob-%d.bin", i)
+			contents = append(contents, types.GitHubContent{Type: "file", Name: name, Path: "big/data/" + name, DownloadURL: ts.URL() + "/raw/blob"})
+		}
+		json.NewEncoder(w).Encode(contents)
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Skill"))
+	})
+	// Blobs are listed without a size, so only their content reveals it.
+	ts.SetHandler("/raw/blob", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	})
+
+	tests := []struct {
+		name          string
+		maxFiles      int
+		maxTotalBytes int64
+	}{
+		{name: "too many files", maxFiles: 3},
+		{name: "too many bytes", maxTotalBytes: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := t.TempDir()
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+			client.SetDataDir(dataDir)
+			client.SetDownloadLimits(tt.maxFiles, tt.maxTotalBytes)
+
+			stats, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/big")
+			if stats != nil {
+				t.Errorf("Download() stats = %+v, want nil", stats)
+			}
+			if !errors.Is(err, &DownloadError{Type: ErrorTypeLimit}) {
+				t.Fatalf("Download() error = %v, want a limit error", err)
+			}
+			if !errors.Is(err, ErrDownloadLimit) {
+				t.Errorf("Download() error = %v, want it to wrap ErrDownloadLimit", err)
+			}
+
+			entries, err := os.ReadDir(paths.SkillsDir(dataDir))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatalf("failed to read skills store: %v", err)
+			}
+			for _, entry := range entries {
+				t.Errorf("aborted download left %s in the skills store", entry.Name())
+			}
+			if _, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "big"); err == nil {
+				t.Error("aborted download should not be registered")
+			}
+		})
+	}
+}
+
+func TestDownload_ConcurrencyBoundsParallelRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+	dirs := []types.GitHubContent{{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", DownloadURL: ts.URL() + "/raw/SKILL.md"}}
+	for i := range 6 {
+		name := fmt.Sprintf("part%d", i)
+		dirs = append(dirs, types.GitHubContent{Type: "dir", Name: name, Path: "skill/" + name})
+		ts.SetHandler("/repos/owner/repo/contents/skill/"+name, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "data.txt", Path: "skill/" + name + "/data.txt", DownloadURL: ts.URL() + "/raw/" + name},
+			})
+		})
+		ts.SetHandler("/raw/"+name, func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(30 * time.Millisecond)
+			w.Write([]byte("data"))
+		})
+	}
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(dirs)
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Skill"))
+	})
+
+	tests := []struct {
+		name        string
+		concurrency int
+		want        int
+	}{
+		{name: "override", concurrency: 2, want: 2},
+		{name: "capped", concurrency: MaxConcurrency + 10, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxInFlight.Store(0)
+
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+			client.SetDataDir(t.TempDir())
+			client.SetConcurrency(tt.concurrency)
+			if tt.concurrency > MaxConcurrency && client.Concurrency() != MaxConcurrency {
+				t.Errorf("Concurrency() = %d, want it capped at %d", client.Concurrency(), MaxConcurrency)
+			}
+
+			if _, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill"); err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			if got := int(maxInFlight.Load()); got != tt.want {
+				t.Errorf("max parallel downloads = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
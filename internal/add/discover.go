@@ -0,0 +1,125 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	pathpkg "path"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// skillsDirName is the conventional directory holding several skills in a repository.
+const skillsDirName = "skills"
+
+// GetDefaultBranch returns the default branch of owner/repo.
+func (c *Client) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return "", err
+		}
+
+		if resp.StatusCode() != 200 {
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return "", err
+				}
+				continue
+			}
+			if resp.StatusCode() == 404 {
+				return "", fmt.Errorf("repository %s/%s not found", owner, repo)
+			}
+			return "", fmt.Errorf("GitHub API returned status %d for repository %s/%s", resp.StatusCode(), owner, repo)
+		}
+
+		var result struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := json.Unmarshal(resp.Body(), &result); err != nil {
+			return "", fmt.Errorf("failed to unmarshal repository response: %w", err)
+		}
+		if result.DefaultBranch == "" {
+			return "", fmt.Errorf("default branch not found in response")
+		}
+
+		return result.DefaultBranch, nil
+	}
+
+	return "", fmt.Errorf("failed to get default branch: retries exhausted")
+}
+
+// FindSkillDirs lists the skill directories of the repository described by
+// repoInfo. When the repository has a top-level skills/ directory, its
+// subdirectories are inspected; otherwise the top-level directories are.
+// Hidden directories are ignored. If repoInfo.Branch is empty it is set to the
+// repository's default branch. The returned paths are relative to the
+// repository root.
+func (c *Client) FindSkillDirs(ctx context.Context, repoInfo *GitHubRepoInfo) ([]string, error) {
+	if repoInfo.Branch == "" {
+		branch, err := c.GetDefaultBranch(ctx, repoInfo.Owner, repoInfo.Repo)
+		if err != nil {
+			return nil, err
+		}
+		repoInfo.Branch = branch
+	}
+
+	root, err := c.GetGitHubContents(ctx, repoInfo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	parent := ""
+	candidates := root
+	for _, item := range root {
+		if item.Type == "dir" && item.Name == skillsDirName {
+			parent = skillsDirName
+			candidates, err = c.GetGitHubContents(ctx, repoInfo, skillsDirName)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	return c.skillDirsAmong(ctx, repoInfo, parent, candidates)
+}
+
+// FindNestedSkillDirs lists the immediate subdirectories of the directory
+// described by repoInfo that contain a SKILL.md, for a URL pointing at a
+// folder of skills rather than at one skill. Hidden directories are
+// ignored. The returned paths are relative to the repository root.
+func (c *Client) FindNestedSkillDirs(ctx context.Context, repoInfo *GitHubRepoInfo) ([]string, error) {
+	items, err := c.GetGitHubContents(ctx, repoInfo, repoInfo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return c.skillDirsAmong(ctx, repoInfo, repoInfo.Path, items)
+}
+
+// skillDirsAmong returns the directories among items, the contents of
+// parent, that contain a SKILL.md.
+func (c *Client) skillDirsAmong(ctx context.Context, repoInfo *GitHubRepoInfo, parent string, items []types.GitHubContent) ([]string, error) {
+	var skillDirs []string
+	for _, item := range items {
+		if item.Type != "dir" || strings.HasPrefix(item.Name, ".") {
+			continue
+		}
+
+		dirPath := pathpkg.Join(parent, item.Name)
+		probe := *repoInfo
+		probe.Path = dirPath
+		found, err := c.checkSKILLExists(ctx, &probe)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			skillDirs = append(skillDirs, dirPath)
+		}
+	}
+
+	return skillDirs, nil
+}
@@ -0,0 +1,163 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestParseRepoRootURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantOK     bool
+		wantBranch string
+	}{
+		{name: "bare repo", url: "https://github.com/owner/repo", wantOK: true},
+		{name: "trailing slash", url: "https://github.com/owner/repo/", wantOK: true},
+		{name: "git suffix", url: "https://github.com/owner/repo.git", wantOK: true},
+		{name: "branch root", url: "https://github.com/owner/repo/tree/dev", wantOK: true, wantBranch: "dev"},
+		{name: "skill path", url: "https://github.com/owner/repo/tree/main/skills/a", wantOK: false},
+		{name: "non-github", url: "https://gitlab.com/owner/repo", wantOK: false},
+		{name: "owner only", url: "https://github.com/owner", wantOK: false},
+		{name: "local path", url: "./skills/a", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := ParseRepoRootURL(tt.url)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRepoRootURL(%q) ok = %v, want %v", tt.url, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if info.Owner != "owner" || info.Repo != "repo" {
+				t.Errorf("owner/repo = %s/%s, want owner/repo", info.Owner, info.Repo)
+			}
+			if info.Branch != tt.wantBranch {
+				t.Errorf("Branch = %q, want %q", info.Branch, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestParseBranchlessURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		wantOK   bool
+		wantPath string
+	}{
+		{name: "skill path", url: "https://github.com/owner/repo/skills/a", wantOK: true, wantPath: "skills/a"},
+		{name: "trailing slash", url: "https://github.com/owner/repo/skills/a/", wantOK: true, wantPath: "skills/a"},
+		{name: "single segment", url: "https://github.com/owner/repo/demo", wantOK: true, wantPath: "demo"},
+		{name: "tree URL", url: "https://github.com/owner/repo/tree/main/skills/a", wantOK: false},
+		{name: "blob URL", url: "https://github.com/owner/repo/blob/main/skills/a/SKILL.md", wantOK: false},
+		{name: "release asset", url: "https://github.com/owner/repo/releases/download/v1/a.zip", wantOK: false},
+		{name: "repo root", url: "https://github.com/owner/repo", wantOK: false},
+		{name: "non-github", url: "https://gitlab.com/owner/repo/skills/a", wantOK: false},
+		{name: "local path", url: "./skills/a", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := ParseBranchlessURL(tt.url)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseBranchlessURL(%q) ok = %v, want %v", tt.url, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if info.Owner != "owner" || info.Repo != "repo" || info.Branch != "" {
+				t.Errorf("info = %+v, want owner/repo without a branch", info)
+			}
+			if info.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", info.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
+// setupSkillsRepo serves owner/repo with default branch main and a skills/
+// directory holding two skills and one directory without SKILL.md.
+func setupSkillsRepo(ts *TestServer) {
+	ts.SetHandler("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "README.md", Path: "README.md"},
+			{Type: "dir", Name: "skills", Path: "skills"},
+		})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skills", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "dir", Name: "alpha", Path: "skills/alpha"},
+			{Type: "dir", Name: "beta", Path: "skills/beta"},
+			{Type: "dir", Name: "docs", Path: "skills/docs"},
+			{Type: "dir", Name: ".github", Path: "skills/.github"},
+		})
+	})
+	for _, name := range []string{"alpha", "beta"} {
+		ts.SetHandler("/repos/owner/repo/contents/skills/"+name, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]types.GitHubContent{{Type: "file", Name: "SKILL.md"}})
+		})
+	}
+	ts.SetHandler("/repos/owner/repo/contents/skills/docs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{{Type: "file", Name: "README.md"}})
+	})
+}
+
+func TestFindSkillDirs(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	setupSkillsRepo(ts)
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	repoInfo := &GitHubRepoInfo{Owner: "owner", Repo: "repo"}
+	dirs, err := client.FindSkillDirs(context.Background(), repoInfo)
+	if err != nil {
+		t.Fatalf("FindSkillDirs() error = %v", err)
+	}
+
+	want := []string{"skills/alpha", "skills/beta"}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("FindSkillDirs() = %v, want %v", dirs, want)
+	}
+	if repoInfo.Branch != "main" {
+		t.Errorf("Branch = %q, want default branch main", repoInfo.Branch)
+	}
+	if got := repoInfo.SkillURL(dirs[0]); got != "https://github.com/owner/repo/tree/main/skills/alpha" {
+		t.Errorf("SkillURL() = %s", got)
+	}
+}
+
+func TestFindNestedSkillDirs(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	setupSkillsRepo(ts)
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	repoInfo, err := ParseGitHubURL("https://github.com/owner/repo/tree/main/skills")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs, err := client.FindNestedSkillDirs(context.Background(), repoInfo)
+	if err != nil {
+		t.Fatalf("FindNestedSkillDirs() error = %v", err)
+	}
+
+	want := []string{"skills/alpha", "skills/beta"}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("FindNestedSkillDirs() = %v, want %v", dirs, want)
+	}
+}
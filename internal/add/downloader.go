@@ -22,41 +22,60 @@ func isRateLimitError(err error) bool {
 	return strings.Contains(errStr, "403") || strings.Contains(errStr, "429") || strings.Contains(errStr, "rate limit exceeded")
 }
 
+// maxRateLimitBackoff caps the exponential backoff between rate-limited attempts.
+const maxRateLimitBackoff = 16 * time.Second
+
+// waitForRetry sleeps before the attempt following a rate-limited one. The
+// wait doubles with every attempt, starting at the retry wait (one second by
+// default, see SetRetries). It returns ctx.Err() if ctx is done first.
+func (c *Client) waitForRetry(ctx context.Context, attempt int) error {
+	backoff := min(c.retryWait<<uint(attempt), max(maxRateLimitBackoff, c.retryWait))
+
+	c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
+
+	select {
+	case <-time.After(backoff):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetBranchCommitSHA returns the commit SHA that repoInfo.Branch points to.
+// It is the only commit lookup, shared by add and update. Rate-limited
+// requests (403, 429) are retried with exponential backoff; other failures
+// are retried up to the client's retry attempts (see SetRetries). A 404 is returned right away,
+// after being diagnosed with diagnoseNotFound when authenticated.
 func (c *Client) GetBranchCommitSHA(ctx context.Context, repoInfo *GitHubRepoInfo) (string, error) {
 	apiURL := fmt.Sprintf("%s/repos/%s/%s/commits/%s", c.baseURL, repoInfo.Owner, repoInfo.Repo, repoInfo.Branch)
 
 	var lastErr error
-	for attempt := range maxRetryAttempts {
+	for attempt := range c.retryAttempts {
 		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
 		if err != nil {
-			if isRateLimitError(err) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return "", ctx.Err()
+			if isRateLimitError(err) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return "", err
 				}
+				continue
 			}
 			lastErr = err
 			continue
 		}
 
-		if resp.StatusCode() != 200 {
-			if isRateLimitResponse(resp.StatusCode()) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
+		if resp.StatusCode() == 404 {
+			if err := c.diagnoseNotFound(ctx, repoInfo); err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("ref '%s' not found in %s/%s", repoInfo.Branch, repoInfo.Owner, repoInfo.Repo)
+		}
 
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return "", ctx.Err()
+		if resp.StatusCode() != 200 {
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return "", err
 				}
+				continue
 			}
 			lastErr = fmt.Errorf("GitHub API returned status %d for commit SHA", resp.StatusCode())
 			continue
@@ -82,37 +101,25 @@ func (c *Client) GetGitHubContents(ctx context.Context, repoInfo *GitHubRepoInfo
 	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", c.baseURL, repoInfo.Owner, repoInfo.Repo, path, repoInfo.Branch)
 
 	var lastErr error
-	for attempt := range maxRetryAttempts {
+	for attempt := range c.retryAttempts {
 		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
 		if err != nil {
-			if isRateLimitError(err) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
+			if isRateLimitError(err) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
 				}
+				continue
 			}
 			lastErr = err
 			continue
 		}
 
 		if resp.StatusCode() != 200 {
-			if isRateLimitResponse(resp.StatusCode()) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
 				}
+				continue
 			}
 			lastErr = fmt.Errorf("GitHub API returned status %d for path %s", resp.StatusCode(), path)
 			continue
@@ -129,39 +136,30 @@ func (c *Client) GetGitHubContents(ctx context.Context, repoInfo *GitHubRepoInfo
 	return nil, lastErr
 }
 
+// DownloadFile returns the contents of the file at downloadURL. Unlike API
+// calls it is not held to the short per-request timeout: a large file may
+// take as long as ctx allows, up to defaultFileTimeout.
 func (c *Client) DownloadFile(ctx context.Context, downloadURL string) ([]byte, error) {
 	var lastErr error
-	for attempt := range maxRetryAttempts {
-		resp, err := c.restyClient.R().SetContext(ctx).Get(downloadURL)
+	for attempt := range c.retryAttempts {
+		resp, err := c.fileClient.R().SetContext(ctx).Get(downloadURL)
 		if err != nil {
-			if isRateLimitError(err) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
+			if isRateLimitError(err) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
 				}
+				continue
 			}
 			lastErr = err
 			continue
 		}
 
 		if resp.StatusCode() != 200 {
-			if isRateLimitResponse(resp.StatusCode()) && attempt < maxRetryAttempts-1 {
-				backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-
-				c.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-				select {
-				case <-time.After(backoff):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
 				}
+				continue
 			}
 			lastErr = fmt.Errorf("download failed with status %d", resp.StatusCode())
 			continue
@@ -172,3 +170,48 @@ func (c *Client) DownloadFile(ctx context.Context, downloadURL string) ([]byte,
 
 	return nil, lastErr
 }
+
+// GetLatestRelease returns the latest published release of owner/repo.
+// Only rate-limited responses are retried; any other failure is returned
+// right away so that callers can degrade gracefully when offline.
+func (c *Client) GetLatestRelease(ctx context.Context, owner, repo string) (*types.GitHubRelease, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases/latest", c.baseURL, owner, repo)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			if isRateLimitError(err) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		if resp.StatusCode() != 200 {
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if resp.StatusCode() == 404 {
+				return nil, fmt.Errorf("no release found for %s/%s", owner, repo)
+			}
+			return nil, fmt.Errorf("GitHub API returned status %d for latest release", resp.StatusCode())
+		}
+
+		var release types.GitHubRelease
+		if err := json.Unmarshal(resp.Body(), &release); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal release response: %w", err)
+		}
+		if release.TagName == "" {
+			return nil, fmt.Errorf("release tag not found in response")
+		}
+
+		return &release, nil
+	}
+
+	return nil, fmt.Errorf("rate limit retries exhausted for latest release")
+}
@@ -1,6 +1,7 @@
 package add
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -12,8 +13,40 @@ const (
 	ErrorTypeFilesystem
 	ErrorTypeValidation
 	ErrorTypeRateLimit
+	ErrorTypeRegistry
+	// ErrorTypeExists means the skill is already installed and overwriting it
+	// was refused.
+	ErrorTypeExists
+	// ErrorTypeLimit means the download was aborted because it exceeded the
+	// file-count or size limit.
+	ErrorTypeLimit
+	// ErrorTypeChecksum means the skill did not match the checksum given
+	// with --checksum and was not installed.
+	ErrorTypeChecksum
+	// ErrorTypeAccess means the repository exists but the token may not read
+	// its contents.
+	ErrorTypeAccess
+	// ErrorTypeNotFound means the repository does not exist or is not
+	// visible to the token.
+	ErrorTypeNotFound
 )
 
+// ErrSkillExists is returned by an overwrite confirmation that refuses to
+// replace an installed skill.
+var ErrSkillExists = errors.New("skill already exists")
+
+// ErrSkillManifestMissing is wrapped by the error returned when a GitHub
+// skill directory has no SKILL.md.
+var ErrSkillManifestMissing = errors.New("not a valid skill package")
+
+// ErrNoUpdater is returned by Manager.Update when no updater was set with
+// SetUpdater.
+var ErrNoUpdater = errors.New("no updater set; use SetUpdater")
+
+// ErrEmptySkill is wrapped by the error returned when a skill has no files
+// and SetAllowEmpty was not called.
+var ErrEmptySkill = errors.New("skill has no files")
+
 type DownloadError struct {
 	Type    ErrorType
 	Message string
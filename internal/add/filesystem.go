@@ -1,10 +1,11 @@
 package add
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"os"
-	"strings"
+
+	"github.com/smy-101/gskills/internal/prompt"
 )
 
 func checkPathExists(localPath string) (bool, error) {
@@ -18,18 +19,40 @@ func checkPathExists(localPath string) (bool, error) {
 	return true, nil
 }
 
-var promptOverwrite = func() (bool, error) {
-	fmt.Print("Target path already exists. Overwrite? [y/N]: ")
+// RefuseOverwrite is an overwrite confirmation that never replaces an
+// installed skill; the add fails with an ErrorTypeExists error instead.
+func RefuseOverwrite() (bool, error) {
+	return false, ErrSkillExists
+}
 
-	var response string
-	_, err := fmt.Scanln(&response)
+// confirmOverwriteOf asks the client's overwrite confirmation whether the
+// skill at localPath may be replaced. A confirmation returning ErrSkillExists
+// yields an ErrorTypeExists error.
+func (c *Client) confirmOverwriteOf(localPath string) (bool, error) {
+	overwrite, err := c.confirmOverwrite()
+	if errors.Is(err, ErrSkillExists) {
+		return false, &DownloadError{
+			Type:    ErrorTypeExists,
+			Message: fmt.Sprintf("target path already exists: %s", localPath),
+			Err:     err,
+		}
+	}
 	if err != nil {
-		if err == io.EOF {
-			return false, nil
+		return false, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to read user input",
+			Err:     err,
 		}
-		return false, nil
 	}
+	return overwrite, nil
+}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes", nil
+// PromptOverwrite asks on stdin whether an existing skill should be
+// overwritten. It is the default overwrite confirmation of a Client.
+func PromptOverwrite() (bool, error) {
+	return promptOverwrite()
+}
+
+var promptOverwrite = func() (bool, error) {
+	return prompt.Confirm("Target path already exists. Overwrite?")
 }
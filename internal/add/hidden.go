@@ -0,0 +1,16 @@
+package add
+
+import "strings"
+
+// hiddenAllowlist are the top-level dotfiles a skill keeps even when hidden
+// entries are skipped.
+var hiddenAllowlist = map[string]bool{IgnoreFileName: true}
+
+// IsHidden reports whether relPath, a slash-separated path relative to the
+// skill root, is a top-level dotfile or dot-directory such as .github or
+// .gitignore. Such entries are repository housekeeping rather than part of
+// the skill, so they are skipped unless SetIncludeHidden(true) is called.
+// Dotfiles in subdirectories and the files in hiddenAllowlist are kept.
+func IsHidden(relPath string) bool {
+	return strings.HasPrefix(relPath, ".") && !strings.Contains(relPath, "/") && !hiddenAllowlist[relPath]
+}
@@ -0,0 +1,88 @@
+package add
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+)
+
+func TestIsHidden(t *testing.T) {
+	tests := []struct {
+		relPath string
+		want    bool
+	}{
+		{relPath: ".github", want: true},
+		{relPath: ".gitignore", want: true},
+		{relPath: IgnoreFileName, want: false},
+		{relPath: "docs/.keep", want: false},
+		{relPath: "SKILL.md", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsHidden(tt.relPath); got != tt.want {
+			t.Errorf("IsHidden(%q) = %v, want %v", tt.relPath, got, tt.want)
+		}
+	}
+}
+
+func TestDownload_SkipsHiddenUnlessIncluded(t *testing.T) {
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "main", "abc123")
+	gh.SetFile("owner", "repo", "skill/SKILL.md", "# Skill")
+	gh.SetFile("owner", "repo", "skill/.github/workflows/ci.yml", "on: push")
+	gh.SetFile("owner", "repo", "skill/.gitignore", "*.tmp")
+	gh.SetFile("owner", "repo", "skill/"+IgnoreFileName, "# nothing ignored")
+	gh.SetFile("owner", "repo", "skill/docs/.keep", "")
+
+	tests := []struct {
+		name          string
+		includeHidden bool
+		wantHidden    int
+	}{
+		{name: "default skips top-level dotfiles", wantHidden: 2},
+		{name: "include hidden", includeHidden: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := t.TempDir()
+			client := NewClient("")
+			client.SetBaseURL(gh.URL())
+			client.SetDataDir(dataDir)
+			client.SetIncludeHidden(tt.includeHidden)
+
+			stats, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill")
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			if stats.Hidden != tt.wantHidden {
+				t.Errorf("stats.Hidden = %d, want %d", stats.Hidden, tt.wantHidden)
+			}
+
+			storePath := filepath.Join(paths.SkillsDir(dataDir), "skill")
+			for name, hidden := range map[string]bool{
+				".github/workflows/ci.yml": true,
+				".gitignore":               true,
+				IgnoreFileName:             false,
+				"docs/.keep":               false,
+			} {
+				_, err := os.Stat(filepath.Join(storePath, name))
+				if want := !hidden || tt.includeHidden; (err == nil) != want {
+					t.Errorf("%s present = %v, want %v", name, err == nil, want)
+				}
+			}
+
+			skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "skill")
+			if err != nil {
+				t.Fatalf("skill not registered: %v", err)
+			}
+			if skill.IncludeHidden != tt.includeHidden {
+				t.Errorf("IncludeHidden = %v, want %v", skill.IncludeHidden, tt.includeHidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package add
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API used when no enterprise server is configured.
+	DefaultAPIURL = "https://api.github.com"
+	// defaultWebHost is the host of skill URLs on github.com.
+	defaultWebHost = "github.com"
+)
+
+// Server is a GitHub server skills are added from: github.com, or a GitHub
+// Enterprise server. Skill URLs are parsed against the server's host and
+// API requests go to its API URL.
+type Server struct {
+	// APIURL is the base URL of the REST API, without a trailing slash.
+	APIURL string
+	// Host is the host of the server's web URLs, e.g. github.mycorp.com.
+	Host string
+}
+
+// GitHubDotCom is the github.com server, used unless another is configured.
+var GitHubDotCom = Server{APIURL: DefaultAPIURL, Host: defaultWebHost}
+
+// NewServer returns the server whose API is at apiURL, e.g.
+// https://github.mycorp.com/api/v3. An empty apiURL selects github.com.
+func NewServer(apiURL string) (Server, error) {
+	if apiURL == "" {
+		return GitHubDotCom, nil
+	}
+
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return Server{}, fmt.Errorf("invalid GitHub API URL: %w", err)
+	}
+	if (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return Server{}, fmt.Errorf("invalid GitHub API URL '%s': must be an http(s) URL such as https://github.mycorp.com/api/v3", apiURL)
+	}
+
+	server := Server{APIURL: strings.TrimRight(apiURL, "/"), Host: parsed.Host}
+	if server.Host == "api.github.com" {
+		server.Host = defaultWebHost
+	}
+	return server, nil
+}
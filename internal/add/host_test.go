@@ -0,0 +1,126 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestNewServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		wantErr  bool
+		wantAPI  string
+		wantHost string
+	}{
+		{name: "empty selects github.com", rawURL: "", wantAPI: DefaultAPIURL, wantHost: "github.com"},
+		{name: "github.com API", rawURL: "https://api.github.com/", wantAPI: DefaultAPIURL, wantHost: "github.com"},
+		{name: "enterprise server", rawURL: "https://github.mycorp.com/api/v3/", wantAPI: "https://github.mycorp.com/api/v3", wantHost: "github.mycorp.com"},
+		{name: "enterprise server with port", rawURL: "http://ghe.local:8080/api/v3", wantAPI: "http://ghe.local:8080/api/v3", wantHost: "ghe.local:8080"},
+		{name: "missing scheme", rawURL: "github.mycorp.com/api/v3", wantErr: true},
+		{name: "unsupported scheme", rawURL: "ftp://github.mycorp.com/api/v3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(tt.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if server.APIURL != tt.wantAPI {
+				t.Errorf("APIURL = %s, want %s", server.APIURL, tt.wantAPI)
+			}
+			if server.Host != tt.wantHost {
+				t.Errorf("Host = %s, want %s", server.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestParseGitHubURL_Enterprise(t *testing.T) {
+	server, err := NewServer("https://github.mycorp.com/api/v3")
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	info, err := server.ParseGitHubURL("https://github.mycorp.com/team/skills/tree/main/my-skill")
+	if err != nil {
+		t.Fatalf("ParseGitHubURL() error = %v", err)
+	}
+	if info.Owner != "team" || info.Repo != "skills" || info.Branch != "main" || info.Path != "my-skill" {
+		t.Errorf("ParseGitHubURL() = %+v", info)
+	}
+
+	if _, err := server.ParseGitHubURL("https://github.com/owner/repo/tree/main/skill"); err == nil {
+		t.Error("ParseGitHubURL() should reject github.com URLs on an enterprise server")
+	}
+	if _, err := ParseGitHubURL("https://github.mycorp.com/team/skills/tree/main/my-skill"); err == nil {
+		t.Error("package-level ParseGitHubURL() should only accept github.com URLs")
+	}
+
+	repoInfo, ok := server.ParseRepoRootURL("https://github.mycorp.com/team/skills")
+	if !ok {
+		t.Fatal("ParseRepoRootURL() should accept enterprise repository URLs")
+	}
+	repoInfo.Branch = "main"
+	if got, want := repoInfo.SkillURL("skills/a"), "https://github.mycorp.com/team/skills/tree/main/skills/a"; got != want {
+		t.Errorf("SkillURL() = %s, want %s", got, want)
+	}
+}
+
+func TestDownload_Enterprise(t *testing.T) {
+	homeDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/api/v3/repos/team/skills/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "abc123"})
+	})
+	ts.SetHandler("/api/v3/repos/team/skills/contents/ghe-skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "ghe-skill/SKILL.md", DownloadURL: ts.URL() + "/raw/SKILL.md"},
+		})
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Enterprise Skill"))
+	})
+
+	server, err := NewServer(ts.URL() + "/api/v3")
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	client := NewClient("")
+	client.SetServer(server)
+	if client.baseURL != ts.URL()+"/api/v3" {
+		t.Fatalf("SetServer() baseURL = %s, want the server's API URL", client.baseURL)
+	}
+
+	skillURL := "http://" + server.Host + "/team/skills/tree/main/ghe-skill"
+	if _, err := client.Download(context.Background(), skillURL); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(homeDir, ".gskills", "skills", "ghe-skill", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to read downloaded SKILL.md: %v", err)
+	}
+	if string(content) != "# Enterprise Skill" {
+		t.Errorf("SKILL.md = %q", content)
+	}
+}
+
+func TestParseGitHubURL_AcceptsEquivalentForms(t *testing.T) {
+	if _, err := ParseGitHubURL("http://www.github.com/owner/repo/tree/main/skills/my-skill/"); err != nil {
+		t.Errorf("ParseGitHubURL() should accept an equivalent URL form: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// IgnoreFileName is the file at the root of a skill that lists, in gitignore
+// syntax, the files and directories not to download.
+const IgnoreFileName = ".gskillsignore"
+
+// IgnoreRules is a parsed .gskillsignore file. It supports the commonly used
+// subset of gitignore: blank lines and # comments, ! negation, a trailing /
+// for directories only, a leading or inner / to anchor a pattern at the
+// skill root, and the *, ?, [...] and ** wildcards. As in git, a path inside
+// an ignored directory cannot be re-included. A nil *IgnoreRules ignores
+// nothing.
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ParseIgnore parses the contents of a .gskillsignore file. Invalid patterns
+// are skipped.
+func ParseIgnore(data []byte) *IgnoreRules {
+	ignore := &IgnoreRules{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		expr := globToRegexp(line)
+		if anchored {
+			expr = "^" + expr + "$"
+		} else {
+			expr = "^(?:.*/)?" + expr + "$"
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			continue
+		}
+		rule.re = re
+		ignore.rules = append(ignore.rules, rule)
+	}
+	return ignore
+}
+
+// globToRegexp translates a gitignore glob into a regular expression body.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether relPath, a slash-separated path relative to the
+// skill root, is ignored. isDir tells whether the path is a directory. The
+// last matching rule decides.
+func (r *IgnoreRules) Match(relPath string, isDir bool) bool {
+	if r == nil {
+		return false
+	}
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(relPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// relativeTo returns remotePath relative to the skill root.
+func relativeTo(root, remotePath string) string {
+	if root == "" {
+		return remotePath
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(remotePath, root), "/")
+}
+
+// FetchIgnoreRules downloads the .gskillsignore file at the root of the skill
+// described by repoInfo. It returns nil rules and no error when the skill has
+// no such file.
+func (c *Client) FetchIgnoreRules(ctx context.Context, repoInfo *GitHubRepoInfo) (*IgnoreRules, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", c.baseURL, repoInfo.Owner, repoInfo.Repo,
+		path.Join(repoInfo.Path, IgnoreFileName), repoInfo.Branch)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", IgnoreFileName, err)
+		}
+
+		switch {
+		case resp.StatusCode() == 404:
+			return nil, nil
+		case isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1:
+			if err := c.waitForRetry(ctx, attempt); err != nil {
+				return nil, err
+			}
+			continue
+		case resp.StatusCode() != 200:
+			return nil, fmt.Errorf("GitHub API returned status %d for %s", resp.StatusCode(), IgnoreFileName)
+		}
+
+		var file types.GitHubContent
+		if err := json.Unmarshal(resp.Body(), &file); err != nil || file.Type != "file" {
+			// A directory of that name is not an ignore file.
+			return nil, nil
+		}
+
+		data, err := c.DownloadFile(ctx, file.DownloadURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", IgnoreFileName, err)
+		}
+		return ParseIgnore(data), nil
+	}
+
+	return nil, fmt.Errorf("failed to look up %s: retries exhausted", IgnoreFileName)
+}
@@ -0,0 +1,93 @@
+package add
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+)
+
+func TestIgnoreRules_Match(t *testing.T) {
+	rules := ParseIgnore([]byte(`# large fixtures
+examples/
+*.bin
+!keep.bin
+/data
+docs/**/*.png
+`))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "examples", isDir: true, want: true},
+		{path: "scripts/examples", isDir: true, want: true},
+		{path: "examples", isDir: false, want: false},
+		{path: "model.bin", want: true},
+		{path: "scripts/model.bin", want: true},
+		{path: "keep.bin", want: false},
+		{path: "data", isDir: true, want: true},
+		{path: "scripts/data", isDir: true, want: false},
+		{path: "docs/a/b/c.png", want: true},
+		{path: "docs/c.png", want: true},
+		{path: "SKILL.md", want: false},
+	}
+	for _, tt := range tests {
+		if got := rules.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	var none *IgnoreRules
+	if none.Match("anything", false) {
+		t.Error("nil rules should ignore nothing")
+	}
+}
+
+func TestDownload_AppliesIgnoreFile(t *testing.T) {
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "main", "abc123")
+	gh.SetFile("owner", "repo", "skill/SKILL.md", "# Skill")
+	gh.SetFile("owner", "repo", "skill/.gskillsignore", "examples/\n")
+	gh.SetFile("owner", "repo", "skill/scripts/run.sh", "echo hi")
+	gh.SetFile("owner", "repo", "skill/examples/big/data.csv", "1,2,3")
+	gh.SetFile("owner", "repo", "skill/examples/readme.txt", "examples")
+
+	dataDir := t.TempDir()
+	client := NewClient("")
+	client.SetBaseURL(gh.URL())
+	client.SetDataDir(dataDir)
+
+	stats, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(stats.StorePath, "examples")); !os.IsNotExist(err) {
+		t.Errorf("ignored examples/ directory was downloaded")
+	}
+	if gh.Requests("/raw/owner/repo/skill/examples/readme.txt") != 0 {
+		t.Errorf("files in an ignored directory should not be requested")
+	}
+	for _, want := range []string{"SKILL.md", ".gskillsignore", "scripts/run.sh"} {
+		if _, err := os.Stat(filepath.Join(stats.StorePath, want)); err != nil {
+			t.Errorf("%s should be downloaded: %v", want, err)
+		}
+	}
+	if stats.Ignored != 1 {
+		t.Errorf("stats.Ignored = %d, want 1", stats.Ignored)
+	}
+
+	skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "skill")
+	if err != nil {
+		t.Fatalf("skill not registered: %v", err)
+	}
+	if !skill.IgnoreApplied {
+		t.Error("registry should record that ignore rules were applied")
+	}
+}
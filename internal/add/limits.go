@@ -0,0 +1,41 @@
+package add
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultMaxFiles is the default limit on the number of files in one skill.
+	DefaultMaxFiles = 5000
+	// DefaultMaxTotalBytes is the default limit on the total size of one skill.
+	DefaultMaxTotalBytes int64 = 500 << 20
+)
+
+// ErrDownloadLimit is wrapped by the error returned when a download exceeds
+// its DownloadLimits.
+var ErrDownloadLimit = errors.New("download limit exceeded")
+
+// DownloadLimits bounds a single skill download so that a huge or
+// misidentified repository cannot fill the disk.
+type DownloadLimits struct {
+	MaxFiles      int
+	MaxTotalBytes int64
+}
+
+// DefaultDownloadLimits returns the limits used unless configured otherwise.
+func DefaultDownloadLimits() DownloadLimits {
+	return DownloadLimits{MaxFiles: DefaultMaxFiles, MaxTotalBytes: DefaultMaxTotalBytes}
+}
+
+// Check returns an error wrapping ErrDownloadLimit if files or totalBytes
+// exceed the limits.
+func (l DownloadLimits) Check(files int, totalBytes int64) error {
+	if files > l.MaxFiles {
+		return fmt.Errorf("%w: more than %d files", ErrDownloadLimit, l.MaxFiles)
+	}
+	if totalBytes > l.MaxTotalBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrDownloadLimit, l.MaxTotalBytes)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package add
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/fsutil"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+const (
+	// localScheme prefixes the SourceURL of skills added from a local directory.
+	localScheme = "file://"
+	// LocalVersion is the version recorded for skills added from a local directory.
+	LocalVersion = "local"
+	// UntrackedSource is the SourceURL of a skill that was found in the store
+	// without a registry entry and registered again by 'gskills doctor --fix'.
+	// Its origin is unknown, so it cannot be updated.
+	UntrackedSource = "local"
+)
+
+// IsLocalSource reports whether source refers to a local directory rather than
+// a GitHub URL. Sources prefixed with file://, explicit relative or absolute
+// paths, and bare names of existing directories are treated as local.
+func IsLocalSource(source string) bool {
+	if strings.HasPrefix(source, localScheme) {
+		return true
+	}
+	if strings.Contains(source, "://") {
+		return false
+	}
+	if filepath.IsAbs(source) || source == "." || source == ".." ||
+		strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return true
+	}
+	info, err := os.Stat(source)
+	return err == nil && info.IsDir()
+}
+
+// LocalSourcePath returns the absolute directory path referred to by a local source.
+func LocalSourcePath(source string) (string, error) {
+	p := strings.TrimPrefix(source, localScheme)
+	if p == "" {
+		return "", fmt.Errorf("local source path cannot be empty")
+	}
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve local path: %w", err)
+	}
+	return absPath, nil
+}
+
+// validateLocalSkillDir checks that dir is a directory containing a skill
+// manifest (SKILL.md, matched case-insensitively).
+func validateLocalSkillDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("directory '%s' does not exist", dir)
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", dir)
+	}
+
+	isSkill, err := IsSkillDirectory(dir)
+	if err != nil {
+		return err
+	}
+	if !isSkill {
+		return fmt.Errorf("SKILL.md not found in '%s'. This is not a valid skill package", dir)
+	}
+	return nil
+}
+
+// InstallLocal copies the skill directory at srcPath to storePath, replacing
+// any existing content. The copy is made to a temporary sibling directory
+// first and then atomically moved into place.
+func InstallLocal(srcPath, storePath string) (*DownloadStats, error) {
+	if err := validateLocalSkillDir(srcPath); err != nil {
+		return nil, err
+	}
+	if filepath.Clean(srcPath) == filepath.Clean(storePath) {
+		return nil, fmt.Errorf("source directory is already the store directory")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(storePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	tmpDir := filepath.Join(filepath.Dir(storePath), ".tmp."+filepath.Base(storePath)+fmt.Sprintf(".%d", time.Now().UnixNano()))
+	if err := fsutil.CopyDir(srcPath, tmpDir); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to copy skill directory: %w", err)
+	}
+
+	stats, err := collectStats(tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	stats.StorePath = storePath
+
+	if err := os.RemoveAll(storePath); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to remove existing directory: %w", err)
+	}
+
+	if err := os.Rename(tmpDir, storePath); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to move copy to final location: %w", err)
+	}
+
+	return stats, nil
+}
+
+// collectStats walks dir and counts its files, subdirectories and total size.
+func collectStats(dir string) (*DownloadStats, error) {
+	stats := &DownloadStats{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if info.IsDir() {
+			stats.DirsCreated++
+		} else if info.Mode().IsRegular() {
+			stats.FilesDownloaded++
+			stats.BytesDownloaded += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect copy statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// AddLocal adds a skill from a local directory. source may be a plain path or
+// a file:// URL. The directory must contain SKILL.md; it is copied into
+// ~/.gskills/skills/<name> and registered with a file:// source URL so that
+// later updates can re-copy it from the same location.
+//
+// Returns the copy statistics on success. If the user declines to overwrite
+// an existing skill, both the stats and the error are nil. A registry failure
+// is reported like in Download.
+func (c *Client) AddLocal(source string) (*DownloadStats, error) {
+	srcPath, err := LocalSourcePath(source)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeInvalidURL,
+			Message: "invalid local path",
+			Err:     err,
+		}
+	}
+
+	if err := validateLocalSkillDir(srcPath); err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeValidation,
+			Message: "invalid local skill directory",
+			Err:     err,
+		}
+	}
+
+	expectedChecksum, err := c.expectedChecksum()
+	if err != nil {
+		return nil, err
+	}
+	if expectedChecksum != "" {
+		if _, err := verifyChecksum(srcPath, expectedChecksum); err != nil {
+			return nil, checksumError(err)
+		}
+	}
+
+	dataDir, err := paths.ResolveDataDir(c.dataDir)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to resolve data directory",
+			Err:     err,
+		}
+	}
+
+	skillName := filepath.Base(srcPath)
+	localPath := c.skillStorePath(dataDir, skillName)
+
+	exists, err := checkPathExists(localPath)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to check path existence",
+			Err:     err,
+		}
+	}
+
+	if exists && c.confirmOverwrite != nil {
+		overwrite, err := c.confirmOverwriteOf(localPath)
+		if err != nil {
+			return nil, err
+		}
+		if !overwrite {
+			c.logger.Info("Local add cancelled by user")
+			return nil, nil
+		}
+	}
+
+	c.logger.Info("Copying local skill", "source", srcPath, "target", localPath)
+
+	stats, err := InstallLocal(srcPath, localPath)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to copy local skill",
+			Err:     err,
+		}
+	}
+
+	stats.Checksum = expectedChecksum
+	stats.FewFiles = stats.FilesDownloaded <= fewFilesThreshold
+
+	skillMetadata := &types.SkillMetadata{
+		ID:        fmt.Sprintf("%s@%s", skillName, LocalVersion),
+		Name:      skillName,
+		Version:   LocalVersion,
+		CommitSHA: LocalVersion,
+		Checksum:  expectedChecksum,
+		SourceURL: localScheme + srcPath,
+		StorePath: localPath,
+		UpdatedAt: time.Now(),
+	}
+	if err := c.registerSkill(dataDir, skillMetadata); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
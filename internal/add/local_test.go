@@ -0,0 +1,168 @@
+package add
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/registry"
+)
+
+func TestIsLocalSource(t *testing.T) {
+	existingDir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{name: "file URL", source: "file:///tmp/skill", want: true},
+		{name: "relative dot path", source: "./skills/my-skill", want: true},
+		{name: "parent path", source: "../my-skill", want: true},
+		{name: "absolute path", source: "/home/user/my-skill", want: true},
+		{name: "existing directory", source: existingDir, want: true},
+		{name: "GitHub URL", source: "https://github.com/owner/repo/tree/main/skill", want: false},
+		{name: "non-existent bare name", source: "not-a-dir", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsLocalSource(tt.source); got != tt.want {
+				t.Errorf("IsLocalSource(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddLocal(t *testing.T) {
+	homeDir, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	srcDir := filepath.Join(t.TempDir(), "local-skill")
+	if err := os.MkdirAll(filepath.Join(srcDir, "scripts"), 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Local Skill"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "scripts", "run.sh"), []byte("echo hi"), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	client := NewClient("")
+	stats, err := client.AddLocal("file://" + srcDir)
+	if err != nil {
+		t.Fatalf("AddLocal() error = %v", err)
+	}
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "local-skill")
+	if stats.FilesDownloaded != 2 || stats.DirsCreated != 1 || stats.StorePath != storePath {
+		t.Errorf("AddLocal() stats = %+v, want 2 files, 1 dir at %s", stats, storePath)
+	}
+
+	info, err := os.Stat(filepath.Join(storePath, "scripts", "run.sh"))
+	if err != nil {
+		t.Fatalf("copied script not found: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("script mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	skill, err := registry.FindSkillByName("local-skill")
+	if err != nil {
+		t.Fatalf("local skill not found in registry: %v", err)
+	}
+	if skill.SourceURL != "file://"+srcDir {
+		t.Errorf("SourceURL = %s, want file://%s", skill.SourceURL, srcDir)
+	}
+	if skill.Version != LocalVersion {
+		t.Errorf("Version = %s, want %s", skill.Version, LocalVersion)
+	}
+	if skill.StorePath != storePath {
+		t.Errorf("StorePath = %s, want %s", skill.StorePath, storePath)
+	}
+
+	t.Run("missing SKILL.md", func(t *testing.T) {
+		emptyDir := t.TempDir()
+		_, err := client.AddLocal(emptyDir)
+		if err == nil {
+			t.Fatal("AddLocal() should fail without SKILL.md")
+		}
+		if !err.(*DownloadError).Is(&DownloadError{Type: ErrorTypeValidation}) {
+			t.Errorf("AddLocal() error type = %v, want validation error", err)
+		}
+	})
+}
+
+func TestIsSkillDirectory(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  bool
+	}{
+		{name: "SKILL.md", files: []string{"SKILL.md"}, want: true},
+		{name: "lowercase skill.md", files: []string{"skill.md", "docs.md"}, want: true},
+		{name: "mixed case Skill.md", files: []string{"Skill.md"}, want: true},
+		{name: "SKILL.yaml", files: []string{"SKILL.yaml"}, want: true},
+		{name: "lowercase skill.json", files: []string{"skill.json"}, want: true},
+		{name: "SKILL.yml is not a manifest", files: []string{"SKILL.yml"}, want: false},
+		{name: "README.md only", files: []string{"README.md"}, want: false},
+		{name: "empty directory", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("x"), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", f, err)
+				}
+			}
+
+			got, err := IsSkillDirectory(dir)
+			if err != nil {
+				t.Fatalf("IsSkillDirectory() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsSkillDirectory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddLocal_StructuredManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{name: "json", file: "SKILL.json", contents: `{"name":"structured","description":"A structured skill","version":"1.0.0"}`},
+		{name: "yaml", file: "SKILL.yaml", contents: "name: structured\ndescription: A structured skill\nversion: 1.0.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTestEnv(t)
+			defer cleanup()
+
+			srcDir := filepath.Join(t.TempDir(), "structured")
+			if err := os.MkdirAll(srcDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(srcDir, tt.file), []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewClient("").AddLocal(srcDir); err != nil {
+				t.Fatalf("AddLocal() error = %v", err)
+			}
+			skill, err := registry.FindSkillByName("structured")
+			if err != nil {
+				t.Fatalf("skill not registered: %v", err)
+			}
+			if skill.Description != "A structured skill" {
+				t.Errorf("Description = %q, want it read from %s", skill.Description, tt.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package add
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/smy-101/gskills/internal/history"
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// Manager is a facade over adding, removing, listing, linking and updating
+// skills for programs that embed gskills as a library. All operations work on
+// an explicit data directory, never prompt and never print; results are
+// returned as values and errors. Adds, removes, links and updates are
+// recorded in the history log of the data directory.
+type Manager struct {
+	dataDir       string
+	linkTargetDir string
+	linkCopy      bool
+	client        *Client
+	updater       SkillUpdater
+	logger        Logger
+}
+
+// SkillUpdater updates one installed skill in place. It is implemented by
+// update.Updater, which builds on this package and so cannot be used by the
+// Manager directly.
+type SkillUpdater interface {
+	UpdateSkill(ctx context.Context, skill *types.SkillMetadata) error
+}
+
+// NewManager creates a Manager that stores skills and the registry in
+// dataDir. An empty dataDir selects the default ~/.gskills. The token can be
+// empty for public repositories and a nil logger disables logging.
+//
+// Existing skills are overwritten without confirmation.
+func NewManager(dataDir, token string, logger Logger) *Manager {
+	if logger == nil {
+		logger = NoOpLogger{}
+	}
+
+	client := NewClient(token)
+	client.SetDataDir(dataDir)
+	client.SetLogger(logger)
+	client.SetConfirmOverwrite(nil)
+
+	return &Manager{
+		dataDir: dataDir,
+		client:  client,
+		logger:  logger,
+	}
+}
+
+// Client returns the underlying GitHub client, e.g. to adjust its timeout
+// or base URL.
+func (m *Manager) Client() *Client {
+	return m.client
+}
+
+// SetLinkTargetDir sets the skills directory, relative to the project, that
+// Link creates symlinks in. An empty dir detects it from the project's agent
+// framework.
+func (m *Manager) SetLinkTargetDir(dir string) {
+	m.linkTargetDir = dir
+}
+
+// SetLinkCopy makes Link copy skills into projects instead of symlinking
+// them; see link.Linker.SetCopy.
+func (m *Manager) SetLinkCopy(copyMode bool) {
+	m.linkCopy = copyMode
+}
+
+// SetUpdater sets the updater Update delegates to, normally an
+// update.Updater. The updater must work on the Manager's data directory and
+// records the update in the history log itself. Without an updater, Update
+// fails.
+func (m *Manager) SetUpdater(updater SkillUpdater) {
+	m.updater = updater
+}
+
+// newLinker returns a linker working on the manager's data directory.
+func (m *Manager) newLinker() *link.Linker {
+	linker := link.NewLinker()
+	linker.SetDataDir(m.dataDir)
+	linker.SetLogger(m.logger)
+	linker.SetTargetDir(m.linkTargetDir)
+	linker.SetCopy(m.linkCopy)
+	return linker
+}
+
+// registryPath returns the path of the registry of the Manager's data directory.
+func (m *Manager) registryPath() (string, error) {
+	dataDir, err := paths.ResolveDataDir(m.dataDir)
+	if err != nil {
+		return "", err
+	}
+	return paths.RegistryPath(dataDir), nil
+}
+
+// recordHistory appends op on skill to the history log. The operation has
+// already happened, so a failure to write the log is only logged.
+func (m *Manager) recordHistory(op history.Operation, skill string, opErr error) {
+	dataDir, err := paths.ResolveDataDir(m.dataDir)
+	if err == nil {
+		err = history.Record(dataDir, op, skill, opErr)
+	}
+	if err != nil {
+		m.logger.Warn("Failed to record history", "operation", op, "skill", skill, "error", err)
+	}
+}
+
+// Add adds a skill from a GitHub URL, a release asset or a local directory
+// and returns the download or copy statistics. The add is recorded in the
+// history log unless the user declined to overwrite an existing skill or it
+// was already up to date.
+func (m *Manager) Add(ctx context.Context, source string) (*DownloadStats, error) {
+	var stats *DownloadStats
+	var err error
+	switch {
+	case IsLocalSource(source):
+		stats, err = m.client.AddLocal(source)
+	case m.client.server.IsReleaseAssetURL(source):
+		stats, err = m.client.AddReleaseAsset(ctx, source)
+	default:
+		stats, err = m.client.Download(ctx, source)
+	}
+
+	if (stats != nil && !stats.UpToDate) || err != nil {
+		skillName, nameErr := m.client.server.SkillNameFromSource(source)
+		if nameErr != nil {
+			skillName = source
+		}
+		m.recordHistory(history.OpAdd, skillName, err)
+	}
+	return stats, err
+}
+
+// Remove deletes the named skill: its project symlinks, its store directory
+// and its registry entry. Symlinks that are already gone are ignored.
+func (m *Manager) Remove(name string) error {
+	err := m.remove(name)
+	m.recordHistory(history.OpRemove, name, err)
+	return err
+}
+
+func (m *Manager) remove(name string) error {
+	registryPath, err := m.registryPath()
+	if err != nil {
+		return err
+	}
+
+	skill, err := registry.FindSkillByNameWithPath(registryPath, name)
+	if err != nil {
+		return err
+	}
+
+	for projectPath, linkInfo := range skill.LinkedProjects {
+		if err := link.RemoveLink(linkInfo); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove symlink for project '%s': %w", projectPath, err)
+		}
+	}
+
+	if err := os.RemoveAll(skill.StorePath); err != nil {
+		return fmt.Errorf("failed to remove skill directory '%s': %w", skill.StorePath, err)
+	}
+
+	if err := registry.RemoveSkillWithPath(registryPath, skill.ID); err != nil {
+		return fmt.Errorf("failed to remove skill from registry: %w", err)
+	}
+
+	m.logger.Info("Removed skill", "skill", name)
+	return nil
+}
+
+// Replace moves the project links of the skill oldName to the skill newName
+// and then removes oldName, e.g. after the same skill was added again from
+// another source. Each link keeps its path and is re-pointed at the store
+// directory of newName (copies are re-copied); a link whose name differs
+// from newName records it as its alias. Projects already linked to newName
+// keep that link and lose the old one. The links are moved and the entry of
+// oldName is dropped in one registry transaction, so other commands never
+// see a half-moved skill; if a link cannot be re-pointed, the links moved so
+// far are saved and oldName is kept. Returns the number of links moved.
+func (m *Manager) Replace(oldName, newName string) (int, error) {
+	moved, err := m.replace(oldName, newName)
+	m.recordHistory(history.OpRemove, oldName, err)
+	return moved, err
+}
+
+func (m *Manager) replace(oldName, newName string) (int, error) {
+	if oldName == newName {
+		return 0, fmt.Errorf("cannot replace skill '%s' with itself", oldName)
+	}
+
+	registryPath, err := m.registryPath()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	// replaceErr is a failure after which the links moved so far are still
+	// saved, so the registry matches where they point.
+	var replaceErr error
+	err = registry.UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		oldIdx, newIdx := -1, -1
+		for i := range skills {
+			switch skills[i].Name {
+			case oldName:
+				oldIdx = i
+			case newName:
+				newIdx = i
+			}
+		}
+		if oldIdx < 0 {
+			return nil, fmt.Errorf("%w: %s", registry.ErrSkillNotFound, oldName)
+		}
+		if newIdx < 0 {
+			return nil, fmt.Errorf("%w: %s", registry.ErrSkillNotFound, newName)
+		}
+		oldSkill, newSkill := &skills[oldIdx], &skills[newIdx]
+
+		projects := make([]string, 0, len(oldSkill.LinkedProjects))
+		for projectPath := range oldSkill.LinkedProjects {
+			projects = append(projects, projectPath)
+		}
+		sort.Strings(projects)
+
+		for _, projectPath := range projects {
+			linkInfo := oldSkill.LinkedProjects[projectPath]
+			if _, linked := newSkill.LinkedProjects[projectPath]; linked {
+				if err := link.RemoveLink(linkInfo); err != nil && !os.IsNotExist(err) {
+					replaceErr = fmt.Errorf("failed to remove symlink for project '%s': %w", projectPath, err)
+					return skills, nil
+				}
+				delete(oldSkill.LinkedProjects, projectPath)
+				continue
+			}
+
+			if err := link.Repoint(linkInfo, newSkill.StorePath); err != nil {
+				replaceErr = fmt.Errorf("failed to re-point link in project '%s': %w", projectPath, err)
+				return skills, nil
+			}
+
+			linkInfo.LinkedAt = time.Now()
+			linkInfo.Version = ""
+			linkInfo.CommitSHA = ""
+			linkInfo.Alias = ""
+			if linkName := filepath.Base(linkInfo.SymlinkPath); linkName != newName {
+				linkInfo.Alias = linkName
+			}
+			if newSkill.LinkedProjects == nil {
+				newSkill.LinkedProjects = make(map[string]types.LinkedProjectInfo)
+			}
+			newSkill.LinkedProjects[projectPath] = linkInfo
+			delete(oldSkill.LinkedProjects, projectPath)
+			moved++
+		}
+
+		if err := os.RemoveAll(oldSkill.StorePath); err != nil {
+			replaceErr = fmt.Errorf("failed to remove skill directory '%s': %w", oldSkill.StorePath, err)
+			return skills, nil
+		}
+		return append(skills[:oldIdx], skills[oldIdx+1:]...), nil
+	})
+	if err != nil {
+		return moved, err
+	}
+	if replaceErr != nil {
+		return moved, replaceErr
+	}
+
+	m.logger.Info("Replaced skill", "old", oldName, "new", newName, "links", moved)
+	return moved, nil
+}
+
+// Find returns the registry entry of the named skill.
+func (m *Manager) Find(name string) (*types.SkillMetadata, error) {
+	registryPath, err := m.registryPath()
+	if err != nil {
+		return nil, err
+	}
+	return registry.FindSkillByNameWithPath(registryPath, name)
+}
+
+// List returns all skills recorded in the registry.
+func (m *Manager) List() ([]types.SkillMetadata, error) {
+	registryPath, err := m.registryPath()
+	if err != nil {
+		return nil, err
+	}
+	return registry.LoadRegistryWithPath(registryPath)
+}
+
+// Link symlinks the named skill into the skills directory of projectPath: the
+// one set with SetLinkTargetDir, or else the one link.DetectTargetDir finds.
+func (m *Manager) Link(ctx context.Context, name, projectPath string) error {
+	return m.LinkAs(ctx, name, projectPath, "")
+}
+
+// LinkAs is like Link but names the symlink alias; an empty alias uses the
+// skill name.
+func (m *Manager) LinkAs(ctx context.Context, name, projectPath, alias string) error {
+	err := m.newLinker().LinkSkillAs(ctx, name, projectPath, alias)
+	m.recordHistory(history.OpLink, name, err)
+	return err
+}
+
+// PlanLink reports the symlink LinkAs would create for the named skill in
+// projectPath, without creating it or changing the registry.
+func (m *Manager) PlanLink(name, projectPath, alias string) (*link.LinkPlan, error) {
+	return m.newLinker().PlanLink(name, projectPath, alias)
+}
+
+// Update brings the named skill up to date with its source through the
+// updater set with SetUpdater and reports whether it changed. It fails with
+// ErrNoUpdater when no updater is set: the update rules (store location,
+// update policy and manifest checks) live in update.Updater only.
+func (m *Manager) Update(ctx context.Context, name string) (bool, error) {
+	if m.updater == nil {
+		return false, ErrNoUpdater
+	}
+	return m.updateWithUpdater(ctx, name)
+}
+
+// updateWithUpdater is Update through the updater set with SetUpdater. The
+// skill changed if the updater touched its registry entry.
+func (m *Manager) updateWithUpdater(ctx context.Context, name string) (bool, error) {
+	skill, err := m.Find(name)
+	if err != nil {
+		return false, err
+	}
+	updatedAt := skill.UpdatedAt
+	if err := m.updater.UpdateSkill(ctx, skill); err != nil {
+		return false, err
+	}
+	skill, err = m.Find(name)
+	if err != nil {
+		return false, err
+	}
+	return !skill.UpdatedAt.Equal(updatedAt), nil
+}
@@ -0,0 +1,276 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// setupManagerServer serves a single-file skill at owner/repo/tree/main/skill
+// whose branch head is the value returned by sha.
+func setupManagerServer(ts *TestServer, sha func() string) {
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": sha()})
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", DownloadURL: ts.URL() + "/skillmd"},
+		})
+	})
+	ts.SetHandler("/skillmd", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Skill " + sha()))
+	})
+}
+
+// isolateHome points HOME at an empty directory and returns it, so tests can
+// verify the Manager never touches ~/.gskills.
+func isolateHome(t *testing.T) string {
+	t.Helper()
+	homeDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	t.Cleanup(func() { os.Setenv("HOME", oldHome) })
+	return homeDir
+}
+
+func TestManager_AddListLinkRemove(t *testing.T) {
+	homeDir := isolateHome(t)
+	dataDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	ts := NewTestServer()
+	defer ts.Close()
+	setupManagerServer(ts, func() string { return "sha1" })
+
+	manager := NewManager(dataDir, "", nil)
+	manager.Client().SetBaseURL(ts.URL())
+	ctx := context.Background()
+
+	stats, err := manager.Add(ctx, "https://github.com/owner/repo/tree/main/skill")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	wantStore := filepath.Join(dataDir, "skills", "skill")
+	if stats.StorePath != wantStore {
+		t.Errorf("StorePath = %s, want %s", stats.StorePath, wantStore)
+	}
+
+	// Adding again overwrites without prompting.
+	if _, err := manager.Add(ctx, "https://github.com/owner/repo/tree/main/skill"); err != nil {
+		t.Fatalf("second Add() error = %v", err)
+	}
+
+	skills, err := manager.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(skills) != 1 || skills[0].Name != "skill" || skills[0].CommitSHA != "sha1" {
+		t.Fatalf("List() = %+v, want one skill 'skill' at sha1", skills)
+	}
+
+	if err := manager.Link(ctx, "skill", projectDir); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	symlinkPath := filepath.Join(projectDir, ".opencode", "skills", "skill")
+	if target, err := os.Readlink(symlinkPath); err != nil || target != wantStore {
+		t.Errorf("symlink target = %q (err %v), want %s", target, err, wantStore)
+	}
+
+	if err := manager.Remove("skill"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Lstat(symlinkPath); !os.IsNotExist(err) {
+		t.Error("symlink should be removed")
+	}
+	if _, err := os.Stat(wantStore); !os.IsNotExist(err) {
+		t.Error("store directory should be removed")
+	}
+	if skills, _ := manager.List(); len(skills) != 0 {
+		t.Errorf("List() after Remove() = %d skills, want 0", len(skills))
+	}
+
+	if _, err := os.Stat(filepath.Join(homeDir, ".gskills")); !os.IsNotExist(err) {
+		t.Error("Manager must not touch ~/.gskills when a data dir is set")
+	}
+}
+
+func TestManager_UpdateWithoutUpdater(t *testing.T) {
+	isolateHome(t)
+	dataDir := t.TempDir()
+
+	ts := NewTestServer()
+	defer ts.Close()
+	setupManagerServer(ts, func() string { return "sha1" })
+
+	manager := NewManager(dataDir, "", nil)
+	manager.Client().SetBaseURL(ts.URL())
+	ctx := context.Background()
+	if _, err := manager.Add(ctx, "https://github.com/owner/repo/tree/main/skill"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	updated, err := manager.Update(ctx, "skill")
+	if !errors.Is(err, ErrNoUpdater) || updated {
+		t.Errorf("Update() without an updater = %v, %v, want false, ErrNoUpdater", updated, err)
+	}
+}
+
+func TestManager_LocalSkill(t *testing.T) {
+	isolateHome(t)
+	dataDir := t.TempDir()
+
+	srcDir := filepath.Join(t.TempDir(), "local-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("v1"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	manager := NewManager(dataDir, "", nil)
+	if _, err := manager.Add(context.Background(), srcDir); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dataDir, "skills", "local-skill", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to read SKILL.md: %v", err)
+	}
+	if string(content) != "v1" {
+		t.Errorf("SKILL.md = %q, want v1", string(content))
+	}
+}
+
+func TestManager_Replace(t *testing.T) {
+	isolateHome(t)
+	dataDir := t.TempDir()
+	projectDir := t.TempDir()
+	otherProject := t.TempDir()
+
+	newSkillDir := func(name, content string) string {
+		dir := filepath.Join(t.TempDir(), name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create source directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write SKILL.md: %v", err)
+		}
+		return dir
+	}
+
+	manager := NewManager(dataDir, "", nil)
+	ctx := context.Background()
+
+	if _, err := manager.Add(ctx, newSkillDir("old-skill", "old")); err != nil {
+		t.Fatalf("Add() old error = %v", err)
+	}
+	if err := manager.Link(ctx, "old-skill", projectDir); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if err := manager.Link(ctx, "old-skill", otherProject); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if _, err := manager.Add(ctx, newSkillDir("new-skill", "new")); err != nil {
+		t.Fatalf("Add() new error = %v", err)
+	}
+
+	if _, err := manager.Replace("new-skill", "new-skill"); err == nil {
+		t.Error("Replace() of a skill with itself should fail")
+	}
+
+	moved, err := manager.Replace("old-skill", "new-skill")
+	if err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Replace() moved %d links, want 2", moved)
+	}
+
+	newStore := filepath.Join(dataDir, "skills", "new-skill")
+	for _, project := range []string{projectDir, otherProject} {
+		linkPath := filepath.Join(project, ".opencode", "skills", "old-skill")
+		if target, err := os.Readlink(linkPath); err != nil || target != newStore {
+			t.Errorf("link %s -> %s (%v), want -> %s", linkPath, target, err, newStore)
+		}
+		if content, _ := os.ReadFile(filepath.Join(linkPath, "SKILL.md")); string(content) != "new" {
+			t.Errorf("link %s resolves to SKILL.md %q, want the new skill", linkPath, content)
+		}
+	}
+
+	skills, err := manager.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(skills) != 1 || skills[0].Name != "new-skill" {
+		t.Fatalf("List() = %+v, want only new-skill", skills)
+	}
+	linkInfo, linked := skills[0].LinkedProjects[projectDir]
+	if !linked || linkInfo.Alias != "old-skill" {
+		t.Errorf("new-skill link = %+v (linked %v), want it recorded under alias old-skill", linkInfo, linked)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "skills", "old-skill")); !os.IsNotExist(err) {
+		t.Errorf("old store directory should be removed: %v", err)
+	}
+}
+
+// fakeUpdater records the skills it is asked to update and touches their
+// registry entry when bump is set.
+type fakeUpdater struct {
+	registryPath string
+	bump         bool
+	updated      []string
+}
+
+func (u *fakeUpdater) UpdateSkill(ctx context.Context, skill *types.SkillMetadata) error {
+	u.updated = append(u.updated, skill.Name)
+	if !u.bump {
+		return nil
+	}
+	skill.UpdatedAt = skill.UpdatedAt.Add(time.Second)
+	return registry.UpdateSkillWithPath(u.registryPath, skill)
+}
+
+func TestManager_UpdateWithUpdater(t *testing.T) {
+	isolateHome(t)
+	dataDir := t.TempDir()
+
+	ts := NewTestServer()
+	defer ts.Close()
+	setupManagerServer(ts, func() string { return "sha1" })
+
+	manager := NewManager(dataDir, "", nil)
+	manager.Client().SetBaseURL(ts.URL())
+	ctx := context.Background()
+	if _, err := manager.Add(ctx, "https://github.com/owner/repo/tree/main/skill"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	updater := &fakeUpdater{registryPath: filepath.Join(dataDir, "skills.json")}
+	manager.SetUpdater(updater)
+
+	for _, bump := range []bool{false, true} {
+		updater.bump = bump
+		updated, err := manager.Update(ctx, "skill")
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if updated != bump {
+			t.Errorf("Update() = %v, want %v", updated, bump)
+		}
+	}
+	if len(updater.updated) != 2 {
+		t.Errorf("updater called for %v, want the skill twice", updater.updated)
+	}
+
+	if _, err := manager.Update(ctx, "missing"); err == nil {
+		t.Error("Update() of a missing skill should fail")
+	}
+}
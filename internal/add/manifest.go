@@ -0,0 +1,114 @@
+package add
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest holds the fields gskills reads from a skill manifest: the front
+// matter of a SKILL.md, or the whole of a SKILL.yaml or SKILL.json.
+type Manifest struct {
+	Name         string   `json:"name" yaml:"name"`
+	Description  string   `json:"description" yaml:"description"`
+	Version      string   `json:"version" yaml:"version"`
+	Dependencies []string `json:"dependencies" yaml:"dependencies"`
+	// PostInstall is the command to run in the store directory after the
+	// skill is installed.
+	PostInstall string `json:"postInstall" yaml:"postInstall"`
+}
+
+// ReadManifest finds the skill manifest in the local directory dir with
+// FindSkillManifest and parses it. It returns nil and no error when dir has
+// no manifest.
+func ReadManifest(dir string) (*Manifest, error) {
+	manifest, err := FindSkillManifest(dir)
+	if err != nil || manifest == "" {
+		return nil, err
+	}
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifest(filepath.Base(manifest), data)
+}
+
+// ParseManifest parses data, the contents of the manifest file name. The
+// format is chosen from the file extension. A markdown manifest without
+// front matter yields an empty Manifest.
+func ParseManifest(name string, data []byte) (*Manifest, error) {
+	var m Manifest
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	case ".yaml":
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	default:
+		front := frontMatter(data)
+		// Hand-written front matter is often not valid YAML, e.g. an
+		// unquoted description containing ": ". Fall back to reading the
+		// single-line fields so those manifests still work.
+		if yaml.Unmarshal(front, &m) != nil {
+			m = parseFrontMatterLines(front)
+		}
+	}
+	return &m, nil
+}
+
+// frontMatter returns the lines between a leading "---" line and the next
+// "---" line, or to the end of data when it is not closed. It returns nil
+// when data does not start with front matter.
+func frontMatter(data []byte) []byte {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return nil
+	}
+	var front bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		front.WriteString(line)
+		front.WriteByte('\n')
+	}
+	return front.Bytes()
+}
+
+// parseFrontMatterLines reads the single-line "key: value" fields of front
+// matter that is not valid YAML. Surrounding quotes are removed from values.
+// Dependencies are not read, as they need a YAML list.
+func parseFrontMatterLines(front []byte) Manifest {
+	var m Manifest
+	for _, line := range strings.Split(string(front), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			m.Name = value
+		case "description":
+			m.Description = value
+		case "version":
+			m.Version = value
+		case "postInstall":
+			m.PostInstall = value
+		}
+	}
+	return m
+}
@@ -0,0 +1,107 @@
+package add
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		want     Manifest
+	}{
+		{
+			name: "json manifest",
+			file: "SKILL.json",
+			contents: `{"name":"api-helper","description":"Calls the API","version":"1.2.0",
+"dependencies":["http-basics","json-tools"],"postInstall":"npm ci"}`,
+			want: Manifest{Name: "api-helper", Description: "Calls the API", Version: "1.2.0",
+				Dependencies: []string{"http-basics", "json-tools"}, PostInstall: "npm ci"},
+		},
+		{
+			name: "yaml manifest",
+			file: "SKILL.yaml",
+			contents: "name: api-helper\ndescription: Calls the API\nversion: 1.2.0\n" +
+				"dependencies:\n  - http-basics\n  - json-tools\n",
+			want: Manifest{Name: "api-helper", Description: "Calls the API", Version: "1.2.0",
+				Dependencies: []string{"http-basics", "json-tools"}},
+		},
+		{
+			name:     "markdown front matter",
+			file:     "SKILL.md",
+			contents: "---\nname: api-helper\ndescription: Calls the API\ndependencies: [http-basics]\n---\n# API helper",
+			want:     Manifest{Name: "api-helper", Description: "Calls the API", Dependencies: []string{"http-basics"}},
+		},
+		{
+			name:     "markdown front matter that is not valid yaml",
+			file:     "SKILL.md",
+			contents: "---\nname: api-helper\ndescription: Use when: calling the API\n---\n",
+			want:     Manifest{Name: "api-helper", Description: "Use when: calling the API"},
+		},
+		{
+			name:     "markdown without front matter",
+			file:     "SKILL.md",
+			contents: "# API helper",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadManifest(dir)
+			if err != nil {
+				t.Fatalf("ReadManifest() error = %v", err)
+			}
+			if got.Name != tt.want.Name || got.Description != tt.want.Description || got.Version != tt.want.Version ||
+				got.PostInstall != tt.want.PostInstall || !slices.Equal(got.Dependencies, tt.want.Dependencies) {
+				t.Errorf("ReadManifest() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadManifest_Invalid(t *testing.T) {
+	for _, file := range []string{"SKILL.json", "SKILL.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, file), []byte("{name: [unclosed"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadManifest(dir); err == nil {
+				t.Errorf("ReadManifest() should fail for an invalid %s", file)
+			}
+		})
+	}
+}
+
+func TestFindSkillManifest_PrefersMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"SKILL.json", "SKILL.yaml", "SKILL.md"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := FindSkillManifest(dir)
+	if err != nil {
+		t.Fatalf("FindSkillManifest() error = %v", err)
+	}
+	if filepath.Base(got) != "SKILL.md" {
+		t.Errorf("FindSkillManifest() = %s, want SKILL.md", filepath.Base(got))
+	}
+}
+
+func TestReadManifest_NoManifest(t *testing.T) {
+	got, err := ReadManifest(t.TempDir())
+	if err != nil || got != nil {
+		t.Errorf("ReadManifest() = %v, %v, want nil, nil", got, err)
+	}
+}
@@ -4,25 +4,39 @@ import (
 	"fmt"
 	"net/url"
 	pathpkg "path"
+	"path/filepath"
 	"strings"
+
+	"github.com/smy-101/gskills/internal/sourceurl"
 )
 
 // GitHubRepoInfo contains parsed information from a GitHub repository URL.
 type GitHubRepoInfo struct {
+	// Host is the web host of the GitHub server; empty means github.com.
+	Host   string
 	Owner  string
 	Repo   string
 	Branch string
 	Path   string
 }
 
+// ParseGitHubURL parses a skill URL on github.com; see Server.ParseGitHubURL.
 func ParseGitHubURL(rawURL string) (*GitHubRepoInfo, error) {
-	parsedURL, err := url.Parse(rawURL)
+	return GitHubDotCom.ParseGitHubURL(rawURL)
+}
+
+// ParseGitHubURL parses a skill URL of the form
+// https://<host>/owner/repo/tree/branch/path on the server. Equivalent forms
+// accepted by sourceurl.Canonicalize, such as www.github.com or a trailing slash,
+// are accepted too.
+func (s Server) ParseGitHubURL(rawURL string) (*GitHubRepoInfo, error) {
+	parsedURL, err := url.Parse(sourceurl.Canonicalize(rawURL))
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	if parsedURL.Host != "github.com" {
-		return nil, fmt.Errorf("only GitHub URLs are supported")
+	if parsedURL.Host != s.Host {
+		return nil, fmt.Errorf("only GitHub URLs on %s are supported", s.Host)
 	}
 
 	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
@@ -61,9 +75,120 @@ func ParseGitHubURL(rawURL string) (*GitHubRepoInfo, error) {
 	}
 
 	return &GitHubRepoInfo{
+		Host:   s.Host,
 		Owner:  owner,
 		Repo:   repo,
 		Branch: branch,
 		Path:   path,
 	}, nil
 }
+
+// SkillNameFromSource returns the name under which a skill from github.com
+// is stored; see Server.SkillNameFromSource.
+func SkillNameFromSource(source string) (string, error) {
+	return GitHubDotCom.SkillNameFromSource(source)
+}
+
+// SkillNameFromSource returns the name under which the skill referred to by
+// source (a GitHub URL on the server, a release asset URL or a local path)
+// is stored.
+func (s Server) SkillNameFromSource(source string) (string, error) {
+	if asset, err := s.ParseReleaseAssetURL(source); err == nil {
+		return asset.SkillName(), nil
+	}
+	if IsLocalSource(source) {
+		srcPath, err := LocalSourcePath(source)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Base(srcPath), nil
+	}
+
+	repoInfo, err := s.ParseGitHubURL(source)
+	if err != nil {
+		return "", err
+	}
+	return pathpkg.Base(repoInfo.Path), nil
+}
+
+// ParseRepoRootURL is Server.ParseRepoRootURL on github.com.
+func ParseRepoRootURL(rawURL string) (*GitHubRepoInfo, bool) {
+	return GitHubDotCom.ParseRepoRootURL(rawURL)
+}
+
+// ParseRepoRootURL reports whether rawURL points at the root of a repository
+// on the server (https://<host>/owner/repo or .../tree/<branch>) rather than
+// at a skill directory. The returned Branch is empty when the URL names none.
+func (s Server) ParseRepoRootURL(rawURL string) (*GitHubRepoInfo, bool) {
+	parsedURL, err := url.Parse(sourceurl.Canonicalize(rawURL))
+	if err != nil || parsedURL.Host != s.Host {
+		return nil, false
+	}
+
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	switch {
+	case len(pathParts) == 2:
+	case len(pathParts) == 4 && pathParts[2] == "tree" && pathParts[3] != "":
+	default:
+		return nil, false
+	}
+	if pathParts[0] == "" || pathParts[1] == "" {
+		return nil, false
+	}
+
+	info := &GitHubRepoInfo{
+		Host:  s.Host,
+		Owner: pathParts[0],
+		Repo:  strings.TrimSuffix(pathParts[1], ".git"),
+	}
+	if len(pathParts) == 4 {
+		info.Branch = pathParts[3]
+	}
+	return info, true
+}
+
+// ParseBranchlessURL is Server.ParseBranchlessURL on github.com.
+func ParseBranchlessURL(rawURL string) (*GitHubRepoInfo, bool) {
+	return GitHubDotCom.ParseBranchlessURL(rawURL)
+}
+
+// ParseBranchlessURL reports whether rawURL points at a path inside a
+// repository on the server without naming a branch
+// (https://<host>/owner/repo/path). The returned Branch is empty; resolve it
+// with GetDefaultBranch and build the skill URL with SkillURL.
+func (s Server) ParseBranchlessURL(rawURL string) (*GitHubRepoInfo, bool) {
+	parsedURL, err := url.Parse(sourceurl.Canonicalize(rawURL))
+	if err != nil || parsedURL.Host != s.Host {
+		return nil, false
+	}
+
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(pathParts) < 3 || pathParts[0] == "" || pathParts[1] == "" {
+		return nil, false
+	}
+	switch pathParts[2] {
+	case "tree", "blob", "releases":
+		return nil, false
+	}
+
+	return &GitHubRepoInfo{
+		Host:  s.Host,
+		Owner: pathParts[0],
+		Repo:  strings.TrimSuffix(pathParts[1], ".git"),
+		Path:  pathpkg.Join(pathParts[2:]...),
+	}, true
+}
+
+// SkillURL returns the GitHub tree URL of the skill at path inside the repository.
+func (r *GitHubRepoInfo) SkillURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s/tree/%s/%s", r.WebURL(), r.Owner, r.Repo, r.Branch, path)
+}
+
+// WebURL returns the https URL of the repository's GitHub server.
+func (r *GitHubRepoInfo) WebURL() string {
+	host := r.Host
+	if host == "" {
+		host = defaultWebHost
+	}
+	return "https://" + host
+}
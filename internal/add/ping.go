@@ -0,0 +1,115 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrGitHubUnavailable is wrapped by the error Ping returns when GitHub
+// cannot be used at all, so that bulk operations can stop before failing
+// once per skill.
+var ErrGitHubUnavailable = errors.New("GitHub is not available")
+
+// RateLimit is the core API quota reported by GitHub.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Ping checks that GitHub is reachable and accepts the configured token by
+// requesting /rate_limit, which does not count against the quota. It returns
+// the remaining quota, or nil without an error when the server has rate
+// limiting disabled (as GitHub Enterprise may). The returned error wraps
+// ErrGitHubUnavailable when the network is down, the token is rejected or
+// the quota is used up.
+func (c *Client) Ping(ctx context.Context) (*RateLimit, error) {
+	resp, err := c.restyClient.R().SetContext(ctx).Get(c.baseURL + "/rate_limit")
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeAPI,
+			Message: "cannot reach GitHub; check the network connection and proxy",
+			Err:     fmt.Errorf("%w: %v", ErrGitHubUnavailable, err),
+		}
+	}
+
+	switch resp.StatusCode() {
+	case 200:
+	case 404:
+		return nil, nil
+	case 401:
+		return nil, &DownloadError{
+			Type:    ErrorTypeAccess,
+			Message: "GitHub rejected the token; check github_token",
+			Err:     fmt.Errorf("%w: status 401", ErrGitHubUnavailable),
+		}
+	default:
+		return nil, &DownloadError{
+			Type:    ErrorTypeAPI,
+			Message: "GitHub health check failed",
+			Err:     fmt.Errorf("%w: status %d", ErrGitHubUnavailable, resp.StatusCode()),
+		}
+	}
+
+	var body struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(resp.Body(), &body); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rate limit response: %w", err)
+	}
+	core := body.Resources.Core
+	limit := &RateLimit{Limit: core.Limit, Remaining: core.Remaining, Reset: time.Unix(core.Reset, 0)}
+
+	if limit.Limit > 0 && limit.Remaining == 0 {
+		return limit, &DownloadError{
+			Type:    ErrorTypeRateLimit,
+			Message: fmt.Sprintf("GitHub rate limit exhausted until %s; set github_token to raise it", limit.Reset.Format("15:04:05")),
+			Err:     fmt.Errorf("%w: rate limit exceeded", ErrGitHubUnavailable),
+		}
+	}
+
+	c.setRateLimit(limit)
+	c.logger.Info("GitHub rate limit", "remaining", limit.Remaining, "limit", limit.Limit, "reset", limit.Reset.Format(time.RFC3339))
+	return limit, nil
+}
+
+// RateLimit returns the quota reported by the most recent GitHub response
+// carrying rate limit headers, or by Ping. It is nil until one was seen.
+func (c *Client) RateLimit() *RateLimit {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	if c.rateLimit == nil {
+		return nil
+	}
+	limit := *c.rateLimit
+	return &limit
+}
+
+func (c *Client) setRateLimit(limit *RateLimit) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	c.rateLimit = limit
+}
+
+// recordRateLimit remembers the quota from the X-RateLimit-* headers of a
+// response. Responses without them, such as raw file downloads, are ignored.
+func (c *Client) recordRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	c.setRateLimit(&RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)})
+}
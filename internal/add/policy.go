@@ -0,0 +1,35 @@
+package add
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Update policies recorded in SkillMetadata.UpdatePolicy.
+const (
+	// UpdatePolicyAuto updates the skill like any other; it is the default.
+	UpdatePolicyAuto = "auto"
+	// UpdatePolicyManual leaves the skill out of updates of all skills; it
+	// is only updated when named explicitly.
+	UpdatePolicyManual = "manual"
+	// UpdatePolicyPinned never updates the skill.
+	UpdatePolicyPinned = "pinned"
+	// UpdatePolicyLatestTag moves a skill added from a tag to the newest
+	// release tag, matching its tag pattern when it has one.
+	UpdatePolicyLatestTag = "latest-tag"
+)
+
+// UpdatePolicies lists the valid update policies.
+var UpdatePolicies = []string{UpdatePolicyAuto, UpdatePolicyManual, UpdatePolicyPinned, UpdatePolicyLatestTag}
+
+// ParseUpdatePolicy validates policy, ignoring case and surrounding spaces,
+// and returns it in canonical form.
+func ParseUpdatePolicy(policy string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(policy))
+	for _, valid := range UpdatePolicies {
+		if p == valid {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid update policy '%s' (valid policies: %s)", policy, strings.Join(UpdatePolicies, ", "))
+}
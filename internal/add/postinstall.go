@@ -0,0 +1,39 @@
+package add
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+)
+
+// ReadPostInstall returns the postInstall command declared in the skill
+// manifest in storePath: the front matter of SKILL.md, or a field of
+// SKILL.yaml or SKILL.json. It returns "" when the skill declares none.
+func ReadPostInstall(storePath string) (string, error) {
+	manifest, err := ReadManifest(storePath)
+	if err != nil || manifest == nil {
+		return "", err
+	}
+	return manifest.PostInstall, nil
+}
+
+// RunPostInstall runs command through the shell (sh -c, cmd /C on Windows)
+// with storePath as the working directory. The command's output goes to
+// stdout and stderr.
+func RunPostInstall(ctx context.Context, storePath, command string, stdout, stderr io.Writer) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.CommandContext(ctx, shell, flag, command)
+	cmd.Dir = storePath
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post-install command %q failed: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package add
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadPostInstall(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{name: "plain", manifest: "---\nname: demo\npostInstall: npm install\n---\n# Demo", want: "npm install"},
+		{name: "quoted", manifest: "---\npostInstall: \"cd helper && npm ci\"\n---\n", want: "cd helper && npm ci"},
+		{name: "none", manifest: "---\nname: demo\n---\npostInstall: not front matter", want: ""},
+		{name: "no front matter", manifest: "# Demo\npostInstall: ignored", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.manifest), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadPostInstall(dir)
+			if err != nil {
+				t.Fatalf("ReadPostInstall() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadPostInstall() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPostInstall(t *testing.T) {
+	storePath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "setup.sh"), []byte("pwd > installed.txt\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunPostInstall(context.Background(), storePath, "sh setup.sh", io.Discard, io.Discard); err != nil {
+		t.Fatalf("RunPostInstall() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(storePath, "installed.txt"))
+	if err != nil {
+		t.Fatalf("hook did not run in the store directory: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != storePath {
+		t.Errorf("hook ran in %q, want %q", got, storePath)
+	}
+
+	if err := RunPostInstall(context.Background(), storePath, "exit 3", io.Discard, io.Discard); err == nil {
+		t.Error("RunPostInstall() should report a failing command")
+	}
+}
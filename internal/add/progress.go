@@ -0,0 +1,36 @@
+package add
+
+// Phase is a stage of a GitHub download reported through SetOnProgress.
+type Phase int
+
+const (
+	// PhaseListing starts when the skill's directories are listed, before
+	// anything is downloaded. Large skills can spend a while in it.
+	PhaseListing Phase = iota
+	// PhaseDownloading starts once every directory has been listed, before
+	// the first file is downloaded.
+	PhaseDownloading
+)
+
+// ProgressEvent reports that a download entered a new phase.
+type ProgressEvent struct {
+	Phase Phase
+	// Files and Bytes are the number and listed size of the files about to
+	// be downloaded. They are set for PhaseDownloading only.
+	Files int
+	Bytes int64
+}
+
+// SetOnProgress makes Download call fn as it enters each phase, so callers
+// can tell a long listing from a stalled download. fn is called from the
+// downloading goroutine. A nil fn reports nothing.
+func (c *Client) SetOnProgress(fn func(ProgressEvent)) {
+	c.onProgress = fn
+}
+
+// reportProgress passes event to the progress callback, if one is set.
+func (c *Client) reportProgress(event ProgressEvent) {
+	if c.onProgress != nil {
+		c.onProgress(event)
+	}
+}
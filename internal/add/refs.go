@@ -0,0 +1,99 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Ref types recorded in SkillMetadata.RefType.
+const (
+	// RefTypeBranch marks a skill that tracks the head of a branch.
+	RefTypeBranch = "branch"
+	// RefTypeTag marks a skill frozen at a tag.
+	RefTypeTag = "tag"
+)
+
+// GetRefType reports whether repoInfo.Branch names a branch or a tag of the
+// repository, returning RefTypeBranch or RefTypeTag. A ref that is neither,
+// such as a commit SHA, yields an empty string.
+func (c *Client) GetRefType(ctx context.Context, repoInfo *GitHubRepoInfo) (string, error) {
+	for _, candidate := range []struct{ refType, namespace string }{
+		{RefTypeBranch, "heads"},
+		{RefTypeTag, "tags"},
+	} {
+		exists, err := c.refExists(ctx, repoInfo, candidate.namespace)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return candidate.refType, nil
+		}
+	}
+	return "", nil
+}
+
+// refExists reports whether refs/<namespace>/<repoInfo.Branch> exists.
+func (c *Client) refExists(ctx context.Context, repoInfo *GitHubRepoInfo, namespace string) (bool, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/git/ref/%s/%s", c.baseURL, repoInfo.Owner, repoInfo.Repo, namespace, repoInfo.Branch)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up ref %s: %w", repoInfo.Branch, err)
+		}
+
+		switch {
+		case resp.StatusCode() == 200:
+			return true, nil
+		case resp.StatusCode() == 404:
+			return false, nil
+		case isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1:
+			if err := c.waitForRetry(ctx, attempt); err != nil {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("GitHub API returned status %d for ref %s", resp.StatusCode(), repoInfo.Branch)
+		}
+	}
+
+	return false, fmt.Errorf("failed to look up ref %s: retries exhausted", repoInfo.Branch)
+}
+
+// ListTags returns the names of the tags of owner/repo, newest first as
+// reported by GitHub. Only the first 100 tags are returned.
+func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/tags?per_page=100", c.baseURL, owner, repo)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, fmt.Errorf("GitHub API returned status %d for tags of %s/%s", resp.StatusCode(), owner, repo)
+		}
+
+		var tags []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(resp.Body(), &tags); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tags response: %w", err)
+		}
+
+		names := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		return names, nil
+	}
+
+	return nil, fmt.Errorf("failed to list tags: retries exhausted")
+}
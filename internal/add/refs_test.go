@@ -0,0 +1,117 @@
+package add
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestGetRefType(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ref": "refs/heads/main"}`))
+	})
+	ts.SetHandler("/repos/owner/repo/git/ref/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ref": "refs/tags/v1.0.0"}`))
+	})
+	ts.SetHandler("/repos/owner/repo/git/ref/heads/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "main", want: RefTypeBranch},
+		{ref: "v1.0.0", want: RefTypeTag},
+		{ref: "abc123", want: ""},
+		{ref: "broken", wantErr: true},
+	}
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := client.GetRefType(context.Background(), &GitHubRepoInfo{Owner: "owner", Repo: "repo", Branch: tt.ref})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRefType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetRefType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownload_RecordsRefType(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "tagsha"})
+	})
+	ts.SetHandler("/repos/owner/repo/git/ref/tags/v2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ref": "refs/tags/v2.0.0"}`))
+	})
+	ts.SetHandler("/repos/owner/repo/contents/pinned", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{
+			{Type: "file", Name: "SKILL.md", Path: "pinned/SKILL.md", DownloadURL: ts.URL() + "/raw/SKILL.md"},
+		})
+	})
+	ts.SetHandler("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# Pinned"))
+	})
+
+	dataDir := t.TempDir()
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetDataDir(dataDir)
+
+	if _, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/v2.0.0/pinned"); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "pinned")
+	if err != nil {
+		t.Fatalf("skill not registered: %v", err)
+	}
+	if skill.RefType != RefTypeTag {
+		t.Errorf("RefType = %q, want %q", skill.RefType, RefTypeTag)
+	}
+	if skill.StorePath != filepath.Join(paths.SkillsDir(dataDir), "pinned") {
+		t.Errorf("StorePath = %s", skill.StorePath)
+	}
+}
+
+func TestDownload_TagPatternRequiresTag(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	ts.SetHandler("/repos/owner/repo/commits/main", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"sha": "mainsha"})
+	})
+	ts.SetHandler("/repos/owner/repo/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ref": "refs/heads/main"}`))
+	})
+	ts.SetHandler("/repos/owner/repo/contents/skill", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]types.GitHubContent{{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md"}})
+	})
+
+	client := NewClient("")
+	client.SetBaseURL(ts.URL())
+	client.SetDataDir(t.TempDir())
+	client.SetTagPattern("v*")
+
+	_, err := client.Download(context.Background(), "https://github.com/owner/repo/tree/main/skill")
+	if !errors.Is(err, &DownloadError{Type: ErrorTypeValidation}) {
+		t.Errorf("Download() error = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,488 @@
+package add
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/sourceurl"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// releaseArchiveExtensions are the archive formats accepted as release
+// assets, longest first so that ".tar.gz" wins over ".gz".
+var releaseArchiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+// ErrUnsafeArchivePath is wrapped by the error returned when an archive
+// entry would be extracted outside the skill directory (zip-slip).
+var ErrUnsafeArchivePath = errors.New("archive entry escapes the skill directory")
+
+// ReleaseAsset identifies an archive attached to a GitHub release.
+type ReleaseAsset struct {
+	Owner string
+	Repo  string
+	Tag   string
+	Name  string
+}
+
+// ParseReleaseAssetURL is Server.ParseReleaseAssetURL on github.com.
+func ParseReleaseAssetURL(rawURL string) (*ReleaseAsset, error) {
+	return GitHubDotCom.ParseReleaseAssetURL(rawURL)
+}
+
+// ParseReleaseAssetURL parses a release asset URL of the form
+// https://<host>/owner/repo/releases/download/<tag>/<asset> on the server,
+// where the asset is a .zip, .tar.gz or .tgz archive.
+func (s Server) ParseReleaseAssetURL(rawURL string) (*ReleaseAsset, error) {
+	parsedURL, err := url.Parse(sourceurl.Canonicalize(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsedURL.Host != s.Host {
+		return nil, fmt.Errorf("only GitHub URLs on %s are supported", s.Host)
+	}
+
+	parts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(parts) != 6 || parts[2] != "releases" || parts[3] != "download" {
+		return nil, fmt.Errorf("not a release asset URL (use format: https://github.com/owner/repo/releases/download/tag/asset.zip)")
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid release asset URL: %s", rawURL)
+		}
+	}
+	if archiveExtension(parts[5]) == "" {
+		return nil, fmt.Errorf("unsupported release asset '%s' (supported: %s)", parts[5], strings.Join(releaseArchiveExtensions, ", "))
+	}
+
+	return &ReleaseAsset{Owner: parts[0], Repo: parts[1], Tag: parts[4], Name: parts[5]}, nil
+}
+
+// IsReleaseAssetURL reports whether source is a release asset URL on github.com.
+func IsReleaseAssetURL(source string) bool {
+	return GitHubDotCom.IsReleaseAssetURL(source)
+}
+
+// IsReleaseAssetURL reports whether source is a release asset URL on the server.
+func (s Server) IsReleaseAssetURL(source string) bool {
+	_, err := s.ParseReleaseAssetURL(source)
+	return err == nil
+}
+
+// SkillName returns the name under which the asset's skill is stored: the
+// asset file name without its archive extension.
+func (a *ReleaseAsset) SkillName() string {
+	return strings.TrimSuffix(a.Name, archiveExtension(a.Name))
+}
+
+// archiveExtension returns the supported archive extension of name, or an
+// empty string if it has none.
+func archiveExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range releaseArchiveExtensions {
+		if strings.HasSuffix(lower, ext) && len(name) > len(ext) {
+			return name[len(name)-len(ext):]
+		}
+	}
+	return ""
+}
+
+// getReleaseAsset looks up asset in the release of its tag.
+func (c *Client) getReleaseAsset(ctx context.Context, asset *ReleaseAsset) (*types.GitHubReleaseAsset, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.baseURL, asset.Owner, asset.Repo, url.PathEscape(asset.Tag))
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1:
+			if err := c.waitForRetry(ctx, attempt); err != nil {
+				return nil, err
+			}
+			continue
+		case resp.StatusCode() == 404:
+			return nil, fmt.Errorf("release '%s' not found in %s/%s", asset.Tag, asset.Owner, asset.Repo)
+		case resp.StatusCode() != 200:
+			return nil, fmt.Errorf("GitHub API returned status %d for release '%s'", resp.StatusCode(), asset.Tag)
+		}
+
+		var release types.GitHubRelease
+		if err := json.Unmarshal(resp.Body(), &release); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal release response: %w", err)
+		}
+		for i := range release.Assets {
+			if release.Assets[i].Name == asset.Name {
+				return &release.Assets[i], nil
+			}
+		}
+		return nil, fmt.Errorf("release '%s' has no asset named '%s'", asset.Tag, asset.Name)
+	}
+
+	return nil, fmt.Errorf("rate limit retries exhausted for release '%s'", asset.Tag)
+}
+
+// downloadReleaseAsset downloads the contents of asset. The API URL is
+// preferred because, unlike the browser URL, it accepts the token for
+// assets of private repositories.
+func (c *Client) downloadReleaseAsset(ctx context.Context, asset *types.GitHubReleaseAsset) ([]byte, error) {
+	if asset.URL == "" {
+		return c.DownloadFile(ctx, asset.BrowserDownloadURL)
+	}
+
+	resp, err := c.fileClient.R().SetContext(ctx).
+		SetHeader("Accept", "application/octet-stream").
+		Get(asset.URL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode())
+	}
+	return resp.Body(), nil
+}
+
+// extractArchive extracts the archive data, named name, into dir. Entries
+// that would land outside dir are rejected with ErrUnsafeArchivePath, links
+// are skipped, and extraction stops with ErrDownloadLimit once the limits
+// are exceeded.
+func extractArchive(data []byte, name, dir string, limits DownloadLimits) error {
+	ext := strings.ToLower(archiveExtension(name))
+	if ext == ".zip" {
+		return extractZip(data, dir, limits)
+	}
+	return extractTarGz(data, dir, limits)
+}
+
+func extractZip(data []byte, dir string, limits DownloadLimits) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if errors.Is(err, zip.ErrInsecurePath) {
+		return fmt.Errorf("%w: %v", ErrUnsafeArchivePath, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+
+	x := &extractor{dir: dir, limits: limits}
+	for _, file := range reader.File {
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			if err := x.mkdir(file.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open '%s': %w", file.Name, err)
+			}
+			err = x.writeFile(file.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func extractTarGz(data []byte, dir string, limits DownloadLimits) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to read gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	x := &extractor{dir: dir, limits: limits}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := x.mkdir(header.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := x.writeFile(header.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractor writes archive entries below dir while enforcing the download
+// limits on the extracted, not the compressed, size.
+type extractor struct {
+	dir    string
+	limits DownloadLimits
+	files  int
+	bytes  int64
+}
+
+// target returns the path of the entry name below x.dir, rejecting absolute
+// names and names that climb out of it.
+func (x *extractor) target(name string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || filepath.VolumeName(cleaned) != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeArchivePath, name)
+	}
+	return filepath.Join(x.dir, filepath.FromSlash(cleaned)), nil
+}
+
+func (x *extractor) mkdir(name string) error {
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0755)
+}
+
+func (x *extractor) writeFile(name string, r io.Reader) error {
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	x.files++
+	if err := x.limits.Check(x.files, x.bytes); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	// Read one byte past the remaining budget so an oversized entry is
+	// detected without extracting all of it.
+	n, err := io.Copy(f, io.LimitReader(r, x.limits.MaxTotalBytes-x.bytes+1))
+	closeErr := f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to extract '%s': %w", name, err)
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	x.bytes += n
+	return x.limits.Check(x.files, x.bytes)
+}
+
+// archiveSkillRoot returns the directory of the extracted archive that holds
+// SKILL.md: dir itself, or its only subdirectory when the archive wraps the
+// skill in a top-level folder.
+func archiveSkillRoot(dir string) (string, error) {
+	if isSkill, err := IsSkillDirectory(dir); err != nil || isSkill {
+		return dir, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub := filepath.Join(dir, entries[0].Name())
+		if isSkill, err := IsSkillDirectory(sub); err != nil || isSkill {
+			return sub, err
+		}
+	}
+	return "", fmt.Errorf("SKILL.md not found in the archive. This is not a valid skill package")
+}
+
+// AddReleaseAsset adds a skill distributed as a release archive. rawURL must
+// be a release asset URL on the client's server (see SetServer). The archive is
+// downloaded and extracted to a temporary directory, checked for SKILL.md
+// (at its root or inside a single top-level folder) and moved into the store.
+// The release tag is recorded as the version and the skill is treated as
+// pinned to it.
+//
+// Return values follow Download: nil stats and error when the user declines
+// to overwrite, stats with UpToDate set when the same asset is installed.
+func (c *Client) AddReleaseAsset(ctx context.Context, rawURL string) (*DownloadStats, error) {
+	rawURL = sourceurl.Canonicalize(rawURL)
+	asset, err := c.server.ParseReleaseAssetURL(rawURL)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeInvalidURL,
+			Message: "failed to parse URL",
+			Err:     err,
+		}
+	}
+
+	expectedChecksum, err := c.expectedChecksum()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.downloadTimeout)
+	defer cancel()
+
+	dataDir, err := paths.ResolveDataDir(c.dataDir)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to resolve data directory",
+			Err:     err,
+		}
+	}
+
+	skillName := asset.SkillName()
+	localPath := c.skillStorePath(dataDir, skillName)
+
+	if !c.force && c.isInstalled(dataDir, skillName, rawURL, asset.Tag, localPath, expectedChecksum) {
+		c.logger.Info("Skill already up to date", "skill", skillName, "tag", asset.Tag)
+		return &DownloadStats{StorePath: localPath, UpToDate: true, Checksum: expectedChecksum}, nil
+	}
+
+	exists, err := checkPathExists(localPath)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to check path existence",
+			Err:     err,
+		}
+	}
+	if exists && c.confirmOverwrite != nil {
+		overwrite, err := c.confirmOverwriteOf(localPath)
+		if err != nil {
+			return nil, err
+		}
+		if !overwrite {
+			c.logger.Info("Download cancelled by user")
+			return nil, nil
+		}
+	}
+
+	releaseAsset, err := c.getReleaseAsset(ctx, asset)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeAPI,
+			Message: "failed to look up release asset",
+			Err:     err,
+		}
+	}
+	if releaseAsset.Size > c.limits.MaxTotalBytes {
+		return nil, &DownloadError{
+			Type:    ErrorTypeLimit,
+			Message: "download aborted",
+			Err:     fmt.Errorf("%w: more than %d bytes", ErrDownloadLimit, c.limits.MaxTotalBytes),
+		}
+	}
+
+	c.logger.Info("Downloading release asset", "asset", asset.Name, "tag", asset.Tag)
+	data, err := c.downloadReleaseAsset(ctx, releaseAsset)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeAPI,
+			Message: "failed to download release asset",
+			Err:     err,
+		}
+	}
+
+	tmpDir := filepath.Join(filepath.Dir(localPath), ".tmp."+filepath.Base(localPath)+fmt.Sprintf(".%d", time.Now().UnixNano()))
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to create temporary directory",
+			Err:     err,
+		}
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractArchive(data, asset.Name, tmpDir, c.limits); err != nil {
+		errType := ErrorTypeFilesystem
+		switch {
+		case errors.Is(err, ErrDownloadLimit):
+			errType = ErrorTypeLimit
+		case errors.Is(err, ErrUnsafeArchivePath):
+			errType = ErrorTypeValidation
+		}
+		return nil, &DownloadError{
+			Type:    errType,
+			Message: "failed to extract release asset",
+			Err:     err,
+		}
+	}
+
+	skillRoot, err := archiveSkillRoot(tmpDir)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeValidation,
+			Message: "invalid release asset",
+			Err:     err,
+		}
+	}
+
+	if expectedChecksum != "" {
+		if _, err := verifyChecksum(skillRoot, expectedChecksum); err != nil {
+			return nil, checksumError(err)
+		}
+	}
+
+	stats, err := collectStats(skillRoot)
+	if err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to inspect extracted skill",
+			Err:     err,
+		}
+	}
+
+	if err := os.RemoveAll(localPath); err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to remove existing directory for atomic move",
+			Err:     err,
+		}
+	}
+	if err := os.Rename(skillRoot, localPath); err != nil {
+		return nil, &DownloadError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to move extracted skill to final location",
+			Err:     err,
+		}
+	}
+
+	stats.StorePath = localPath
+	stats.Checksum = expectedChecksum
+	stats.FewFiles = stats.FilesDownloaded <= fewFilesThreshold
+
+	skillMetadata := &types.SkillMetadata{
+		ID:        fmt.Sprintf("%s@%s", skillName, asset.Tag),
+		Name:      skillName,
+		Version:   asset.Tag,
+		CommitSHA: asset.Tag,
+		RefType:   RefTypeTag,
+		Checksum:  expectedChecksum,
+		SourceURL: rawURL,
+		StorePath: localPath,
+		UpdatedAt: time.Now(),
+	}
+	if err := c.registerSkill(dataDir, skillMetadata); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
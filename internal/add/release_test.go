@@ -0,0 +1,211 @@
+package add
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+)
+
+func TestParseReleaseAssetURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantSkill string
+		wantTag   string
+		wantErr   bool
+	}{
+		{url: "https://github.com/owner/repo/releases/download/v1.0.0/my-skill.zip", wantSkill: "my-skill", wantTag: "v1.0.0"},
+		{url: "https://github.com/owner/repo/releases/download/v2/my-skill.tar.gz", wantSkill: "my-skill", wantTag: "v2"},
+		{url: "https://github.com/owner/repo/releases/download/v2/My-Skill.TGZ", wantSkill: "My-Skill", wantTag: "v2"},
+		{url: "https://github.com/owner/repo/releases/download/v1.0.0/my-skill.exe", wantErr: true},
+		{url: "https://github.com/owner/repo/tree/main/skills/my-skill", wantErr: true},
+		{url: "https://example.com/owner/repo/releases/download/v1.0.0/my-skill.zip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		asset, err := ParseReleaseAssetURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReleaseAssetURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if asset.SkillName() != tt.wantSkill || asset.Tag != tt.wantTag {
+			t.Errorf("ParseReleaseAssetURL(%q) = skill %q tag %q, want %q %q", tt.url, asset.SkillName(), asset.Tag, tt.wantSkill, tt.wantTag)
+		}
+	}
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// serveReleaseAsset makes ts serve a release v1.0.0 of owner/repo with one
+// asset, name, whose contents are data.
+func serveReleaseAsset(ts *TestServer, name string, data []byte) {
+	ts.SetHandler("/repos/owner/repo/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name":"v1.0.0","assets":[{"name":%q,"size":%d,"url":%q}]}`,
+			name, len(data), ts.URL()+"/assets/1")
+	})
+	ts.SetHandler("/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/octet-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Write(data)
+	})
+}
+
+func TestAddReleaseAsset(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset string
+		data  func(t *testing.T) []byte
+	}{
+		{
+			name:  "zip",
+			asset: "my-skill.zip",
+			data: func(t *testing.T) []byte {
+				return buildZip(t, map[string]string{"SKILL.md": "# Skill", "scripts/run.sh": "echo hi"})
+			},
+		},
+		{
+			name:  "tar.gz with a top-level folder",
+			asset: "my-skill.tar.gz",
+			data: func(t *testing.T) []byte {
+				return buildTarGz(t, map[string]string{"my-skill-1.0.0/SKILL.md": "# Skill", "my-skill-1.0.0/scripts/run.sh": "echo hi"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+			serveReleaseAsset(ts, tt.asset, tt.data(t))
+
+			dataDir := t.TempDir()
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+			client.SetDataDir(dataDir)
+
+			stats, err := client.AddReleaseAsset(context.Background(), "https://github.com/owner/repo/releases/download/v1.0.0/"+tt.asset)
+			if err != nil {
+				t.Fatalf("AddReleaseAsset() error = %v", err)
+			}
+			if stats.FilesDownloaded != 2 {
+				t.Errorf("FilesDownloaded = %d, want 2", stats.FilesDownloaded)
+			}
+
+			storePath := filepath.Join(paths.SkillsDir(dataDir), "my-skill")
+			for _, name := range []string{"SKILL.md", "scripts/run.sh"} {
+				if _, err := os.Stat(filepath.Join(storePath, name)); err != nil {
+					t.Errorf("%s not extracted: %v", name, err)
+				}
+			}
+
+			skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "my-skill")
+			if err != nil {
+				t.Fatalf("skill not registered: %v", err)
+			}
+			if skill.Version != "v1.0.0" || skill.RefType != RefTypeTag {
+				t.Errorf("Version = %q, RefType = %q, want v1.0.0 pinned as a tag", skill.Version, skill.RefType)
+			}
+		})
+	}
+}
+
+func TestAddReleaseAsset_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantType ErrorType
+		wantErr  error
+	}{
+		{
+			name:     "zip-slip",
+			files:    map[string]string{"SKILL.md": "# Skill", "../../evil.sh": "rm -rf ~"},
+			wantType: ErrorTypeValidation,
+			wantErr:  ErrUnsafeArchivePath,
+		},
+		{
+			name:     "missing SKILL.md",
+			files:    map[string]string{"README.md": "# Not a skill"},
+			wantType: ErrorTypeValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestServer()
+			defer ts.Close()
+			serveReleaseAsset(ts, "my-skill.zip", buildZip(t, tt.files))
+
+			dataDir := t.TempDir()
+			client := NewClient("")
+			client.SetBaseURL(ts.URL())
+			client.SetDataDir(dataDir)
+
+			_, err := client.AddReleaseAsset(context.Background(), "https://github.com/owner/repo/releases/download/v1.0.0/my-skill.zip")
+			var downloadErr *DownloadError
+			if !errors.As(err, &downloadErr) || downloadErr.Type != tt.wantType {
+				t.Fatalf("AddReleaseAsset() error = %v, want type %v", err, tt.wantType)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error should wrap %v: %v", tt.wantErr, err)
+			}
+
+			entries, _ := os.ReadDir(paths.SkillsDir(dataDir))
+			if len(entries) != 0 {
+				t.Errorf("rejected archive left %d entries in the store", len(entries))
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dataDir), "evil.sh")); err == nil {
+				t.Error("zip-slip entry was written outside the store")
+			}
+		})
+	}
+}
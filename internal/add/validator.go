@@ -2,25 +2,122 @@ package add
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"path"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/types"
 )
 
-func (c *Client) checkSKILLExists(ctx context.Context, repoInfo *GitHubRepoInfo) (bool, error) {
-	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", c.baseURL, repoInfo.Owner, repoInfo.Repo, path.Join(repoInfo.Path, "SKILL.md"), repoInfo.Branch)
+// skillManifestNames are the files that mark a skill package, in order of
+// preference when a directory has more than one. SKILL.md is the canonical
+// manifest; SKILL.yaml and SKILL.json are structured alternatives used by
+// some skill ecosystems.
+var skillManifestNames = []string{"SKILL.md", "SKILL.yaml", "SKILL.json"}
 
-	resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
-	if err != nil {
-		return false, fmt.Errorf("failed to check SKILL.md: %w", err)
+// SkillManifestNames returns the file names accepted as a skill manifest, in
+// order of preference.
+func SkillManifestNames() []string {
+	return append([]string(nil), skillManifestNames...)
+}
+
+// IsSkillManifest reports whether a file name is accepted as the skill
+// manifest. The match is case-insensitive, so skill.md and Skill.yaml are
+// accepted as well; other files such as README.md are not.
+func IsSkillManifest(name string) bool {
+	return manifestRank(name) >= 0
+}
+
+// manifestRank returns the index of name in skillManifestNames, ignoring
+// case, or -1 when name is not a skill manifest.
+func manifestRank(name string) int {
+	for i, manifest := range skillManifestNames {
+		if strings.EqualFold(name, manifest) {
+			return i
+		}
 	}
+	return -1
+}
+
+// hasSkillManifest reports whether a directory listing contains a skill manifest file.
+func hasSkillManifest(contents []types.GitHubContent) bool {
+	for _, item := range contents {
+		if item.Type == "file" && IsSkillManifest(item.Name) {
+			return true
+		}
+	}
+	return false
+}
 
-	if resp.StatusCode() == 404 {
-		return false, nil
+// IsSkillDirectory reports whether the local directory dir contains a skill
+// manifest file.
+func IsSkillDirectory(dir string) (bool, error) {
+	manifest, err := FindSkillManifest(dir)
+	return manifest != "", err
+}
+
+// FindSkillManifest returns the path of the skill manifest in the local
+// directory dir, matched like IsSkillManifest, or "" if there is none. When
+// there are several, SKILL.md is preferred over SKILL.yaml over SKILL.json.
+func FindSkillManifest(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
 	}
+	found, best := "", len(skillManifestNames)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if rank := manifestRank(entry.Name()); rank >= 0 && rank < best {
+			found, best = filepath.Join(dir, entry.Name()), rank
+		}
+	}
+	return found, nil
+}
+
+// checkSKILLExists reports whether the target directory contains a skill
+// manifest: SKILL.md, SKILL.yaml or SKILL.json. The directory is listed and
+// matched with IsSkillManifest, so the manifest name is not case-sensitive.
+// A missing directory reports false.
+// Rate-limited responses are retried with the same backoff as the downloads.
+// An authenticated 404 is diagnosed with diagnoseNotFound.
+func (c *Client) checkSKILLExists(ctx context.Context, repoInfo *GitHubRepoInfo) (bool, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", c.baseURL, repoInfo.Owner, repoInfo.Repo, repoInfo.Path, repoInfo.Branch)
+
+	for attempt := range c.retryAttempts {
+		resp, err := c.restyClient.R().SetContext(ctx).Get(apiURL)
+		if err != nil {
+			return false, fmt.Errorf("failed to check SKILL.md: %w", err)
+		}
+
+		if resp.StatusCode() == 404 {
+			if err := c.diagnoseNotFound(ctx, repoInfo); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+
+		if resp.StatusCode() != 200 {
+			if isRateLimitResponse(resp.StatusCode()) && attempt < c.retryAttempts-1 {
+				if err := c.waitForRetry(ctx, attempt); err != nil {
+					return false, err
+				}
+				continue
+			}
+			return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode())
+		}
+
+		var contents []types.GitHubContent
+		if err := json.Unmarshal(resp.Body(), &contents); err != nil {
+			// The path points at a file rather than a directory.
+			return false, nil
+		}
 
-	if resp.StatusCode() != 200 {
-		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode())
+		return hasSkillManifest(contents), nil
 	}
 
-	return true, nil
+	return false, fmt.Errorf("failed to check SKILL.md: retries exhausted")
 }
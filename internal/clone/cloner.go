@@ -0,0 +1,84 @@
+// Package clone provides functionality to duplicate an installed skill under
+// a new name so it can be modified without affecting the original.
+package clone
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/fsutil"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// SourcePrefix marks the SourceURL of a cloned skill. The remainder of the
+// source is the ID of the skill it was cloned from.
+const SourcePrefix = "local-clone-of:"
+
+// validateSkillName checks that name can be used as a store directory name.
+func validateSkillName(name string) error {
+	if name == "" {
+		return fmt.Errorf("new skill name cannot be empty")
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid skill name '%s'", name)
+	}
+	return nil
+}
+
+// CloneSkill copies the store directory of the skill named srcName to a new
+// store directory named newName and registers it as an independent skill.
+// The original skill and its links are left untouched; the clone starts with
+// no linked projects.
+// Returns the metadata of the new skill.
+func CloneSkill(srcName, newName string) (*types.SkillMetadata, error) {
+	if err := validateSkillName(newName); err != nil {
+		return nil, err
+	}
+
+	src, err := registry.FindSkillByName(srcName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := registry.FindSkillByName(newName); err == nil {
+		return nil, fmt.Errorf("skill '%s' already exists in registry", newName)
+	}
+
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	storePath := filepath.Join(paths.SkillsDir(dataDir), newName)
+	if _, err := os.Lstat(storePath); err == nil {
+		return nil, fmt.Errorf("store directory '%s' already exists", storePath)
+	}
+
+	if err := fsutil.CopyDir(src.StorePath, storePath); err != nil {
+		os.RemoveAll(storePath)
+		return nil, fmt.Errorf("failed to copy skill directory: %w", err)
+	}
+
+	cloned := &types.SkillMetadata{
+		ID:          fmt.Sprintf("%s@%s", newName, src.Version),
+		Name:        newName,
+		SourceURL:   SourcePrefix + src.ID,
+		StorePath:   storePath,
+		UpdatedAt:   time.Now(),
+		Version:     src.Version,
+		CommitSHA:   src.CommitSHA,
+		Description: src.Description,
+	}
+
+	if err := registry.AddOrUpdateSkill(cloned); err != nil {
+		os.RemoveAll(storePath)
+		return nil, fmt.Errorf("failed to add cloned skill to registry: %w", err)
+	}
+
+	return cloned, nil
+}
@@ -0,0 +1,140 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func setupTestSkill(t *testing.T) string {
+	t.Helper()
+
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+
+	skillDir := filepath.Join(homeDir, ".gskills", "skills", "test-skill")
+	if err := os.MkdirAll(filepath.Join(skillDir, "docs"), 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# Test Skill"), 0644); err != nil {
+		t.Fatalf("failed to create SKILL.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "docs", "guide.md"), []byte("guide"), 0644); err != nil {
+		t.Fatalf("failed to create guide.md: %v", err)
+	}
+
+	skill := &types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/test-skill",
+		StorePath: skillDir,
+		UpdatedAt: time.Now(),
+		LinkedProjects: map[string]types.LinkedProjectInfo{
+			"/tmp/project": {SymlinkPath: "/tmp/project/.opencode/skills/test-skill", LinkedAt: time.Now()},
+		},
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	return homeDir
+}
+
+func TestCloneSkill(t *testing.T) {
+	homeDir := setupTestSkill(t)
+
+	cloned, err := CloneSkill("test-skill", "my-skill")
+	if err != nil {
+		t.Fatalf("CloneSkill() error = %v", err)
+	}
+
+	wantPath := filepath.Join(homeDir, ".gskills", "skills", "my-skill")
+	if cloned.StorePath != wantPath {
+		t.Errorf("StorePath = %s, want %s", cloned.StorePath, wantPath)
+	}
+	if cloned.SourceURL != SourcePrefix+"test-skill@main" {
+		t.Errorf("SourceURL = %s, want %s", cloned.SourceURL, SourcePrefix+"test-skill@main")
+	}
+	if len(cloned.LinkedProjects) != 0 {
+		t.Errorf("cloned skill should have no linked projects, got %d", len(cloned.LinkedProjects))
+	}
+
+	content, err := os.ReadFile(filepath.Join(wantPath, "docs", "guide.md"))
+	if err != nil {
+		t.Fatalf("failed to read cloned file: %v", err)
+	}
+	if string(content) != "guide" {
+		t.Errorf("cloned content = %s, want 'guide'", string(content))
+	}
+
+	// Modifying the clone must not affect the original.
+	if err := os.WriteFile(filepath.Join(wantPath, "SKILL.md"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to modify clone: %v", err)
+	}
+	original, err := os.ReadFile(filepath.Join(homeDir, ".gskills", "skills", "test-skill", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to read original SKILL.md: %v", err)
+	}
+	if string(original) != "# Test Skill" {
+		t.Errorf("original SKILL.md was modified: %s", string(original))
+	}
+
+	skills, err := registry.LoadRegistry()
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if len(skills) != 2 {
+		t.Fatalf("registry has %d skills, want 2", len(skills))
+	}
+
+	src, err := registry.FindSkillByName("test-skill")
+	if err != nil {
+		t.Fatalf("original skill missing from registry: %v", err)
+	}
+	if len(src.LinkedProjects) != 1 {
+		t.Errorf("original skill links changed, got %d", len(src.LinkedProjects))
+	}
+}
+
+func TestCloneSkill_Errors(t *testing.T) {
+	homeDir := setupTestSkill(t)
+
+	if err := os.MkdirAll(filepath.Join(homeDir, ".gskills", "skills", "orphan-dir"), 0755); err != nil {
+		t.Fatalf("failed to create orphan directory: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		srcName     string
+		newName     string
+		errContains string
+	}{
+		{name: "empty new name", srcName: "test-skill", newName: "", errContains: "cannot be empty"},
+		{name: "path separator", srcName: "test-skill", newName: "a/b", errContains: "invalid skill name"},
+		{name: "dot dot", srcName: "test-skill", newName: "..", errContains: "invalid skill name"},
+		{name: "source not found", srcName: "missing", newName: "copy", errContains: "not found"},
+		{name: "name collides with registry", srcName: "test-skill", newName: "test-skill", errContains: "already exists"},
+		{name: "name collides with directory", srcName: "test-skill", newName: "orphan-dir", errContains: "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CloneSkill(tt.srcName, tt.newName)
+			if err == nil {
+				t.Fatalf("CloneSkill() expected error containing %q", tt.errContains)
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("CloneSkill() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
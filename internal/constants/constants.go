@@ -3,4 +3,8 @@ package constants
 const (
 	// OpencodeSkillsDir is the relative path to the skills directory within projects.
 	OpencodeSkillsDir = ".opencode/skills"
+	// ClaudeSkillsDir is the skills directory of projects using Claude.
+	ClaudeSkillsDir = ".claude/skills"
+	// CursorSkillsDir is the skills directory of projects using Cursor.
+	CursorSkillsDir = ".cursor/skills"
 )
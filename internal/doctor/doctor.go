@@ -0,0 +1,360 @@
+// Package doctor diagnoses inconsistencies between the skills registry, the
+// skills store and project links, and repairs the ones that are safe to fix.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// IssueKind identifies the kind of problem found by the doctor.
+type IssueKind string
+
+const (
+	// IssueMissingStore is a registry entry whose store directory does not exist.
+	IssueMissingStore IssueKind = "missing_store"
+	// IssueBrokenLink is a recorded project link whose symlink is missing or
+	// no longer resolves, or whose copy made with link --copy is missing.
+	IssueBrokenLink IssueKind = "broken_link"
+	// IssueUnregistered is a skill directory in the store without a registry entry.
+	IssueUnregistered IssueKind = "unregistered_skill"
+)
+
+// Issue describes a single problem found by the doctor.
+type Issue struct {
+	Kind IssueKind `json:"kind"`
+	// Skill is the name of the affected skill.
+	Skill string `json:"skill"`
+	// Path is the store directory or, for broken links, the symlink path.
+	Path string `json:"path"`
+	// Project is the linked project directory of a broken link.
+	Project string `json:"project,omitempty"`
+	// Fixed reports whether Fix repaired the issue.
+	Fixed bool `json:"fixed"`
+}
+
+// Report lists the issues found by Diagnose or Fix.
+type Report struct {
+	Issues []Issue `json:"issues"`
+	// Fixed is the number of issues repaired.
+	Fixed int `json:"fixed"`
+}
+
+// Doctor checks and repairs the gskills data directory.
+type Doctor struct {
+	logger  Logger
+	dataDir string
+	// confirmReAdd decides whether an unregistered skill is registered again.
+	confirmReAdd func(issue Issue) (bool, error)
+}
+
+// New creates a Doctor for the default data directory with a NoOpLogger.
+func New() *Doctor {
+	return &Doctor{
+		logger: NoOpLogger{},
+	}
+}
+
+// SetLogger sets the logger used to record every fix.
+func (d *Doctor) SetLogger(logger Logger) {
+	d.logger = logger
+}
+
+// SetDataDir sets the gskills data directory to check. An empty dir selects
+// the default one.
+func (d *Doctor) SetDataDir(dir string) {
+	d.dataDir = dir
+}
+
+// SetConfirmReAdd sets the function asked before an unregistered skill is
+// registered again. A nil function registers every unregistered skill.
+func (d *Doctor) SetConfirmReAdd(confirm func(issue Issue) (bool, error)) {
+	d.confirmReAdd = confirm
+}
+
+// Diagnose reports the problems found without changing anything.
+func (d *Doctor) Diagnose() (*Report, error) {
+	dataDir, err := paths.ResolveDataDir(d.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := registry.LoadRegistryWithPath(paths.RegistryPath(dataDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skills registry: %w", err)
+	}
+
+	issues, err := diagnose(dataDir, skills, !paths.RegistryOverridden())
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Issues: issues}, nil
+}
+
+// Fix diagnoses the data directory and repairs what is safe to repair:
+// registry entries of skills whose store directory is gone are removed
+// together with their symlinks, broken links are removed from the registry
+// and from disk, and unregistered store directories are registered again
+// with an unknown source after confirmation.
+//
+// Confirmation is asked before the registry is locked. The repairs are then
+// made in one registry transaction against the registry as it is at that
+// point, so each issue is checked again and one that was resolved meanwhile,
+// e.g. a directory registered by another command, is left alone.
+func (d *Doctor) Fix() (*Report, error) {
+	dataDir, err := paths.ResolveDataDir(d.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	registryPath := paths.RegistryPath(dataDir)
+
+	skills, err := registry.LoadRegistryWithPath(registryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skills registry: %w", err)
+	}
+
+	issues, err := diagnose(dataDir, skills, !paths.RegistryOverridden())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Issues: issues}
+	if len(issues) == 0 {
+		return report, nil
+	}
+
+	reAdd := make(map[int]bool)
+	for i, issue := range report.Issues {
+		if issue.Kind != IssueUnregistered {
+			continue
+		}
+		if d.confirmReAdd != nil {
+			ok, err := d.confirmReAdd(issue)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		reAdd[i] = true
+	}
+
+	err = registry.UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		skills = d.applyFixes(report, skills, reAdd)
+		if report.Fixed == 0 {
+			return nil, errNothingFixed
+		}
+		return skills, nil
+	})
+	if err != nil && !errors.Is(err, errNothingFixed) {
+		return nil, fmt.Errorf("failed to update skills registry: %w", err)
+	}
+
+	return report, nil
+}
+
+// errNothingFixed ends the registry transaction of Fix without saving when
+// no issue was repaired.
+var errNothingFixed = errors.New("nothing fixed")
+
+// applyFixes repairs the issues of report in skills, the current registry,
+// and returns the updated skills. Unregistered directories are registered
+// only if their index is in reAdd. Issues are marked Fixed and counted in
+// report.Fixed as they are repaired.
+func (d *Doctor) applyFixes(report *Report, skills []types.SkillMetadata, reAdd map[int]bool) []types.SkillMetadata {
+	byName := make(map[string]*types.SkillMetadata)
+	for i := range skills {
+		byName[skills[i].Name] = &skills[i]
+	}
+	removed := make(map[string]bool)
+	var added []types.SkillMetadata
+
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		skill := byName[issue.Skill]
+		switch issue.Kind {
+		case IssueMissingStore:
+			if skill == nil || skill.StorePath != issue.Path || storeExists(skill.StorePath) {
+				continue
+			}
+			for _, linkInfo := range skill.LinkedProjects {
+				removeSymlink(linkInfo.SymlinkPath)
+			}
+			removed[skill.ID] = true
+			issue.Fixed = true
+			d.logger.Info("Removed registry entry with missing store", "skill", issue.Skill, "path", issue.Path)
+
+		case IssueBrokenLink:
+			if skill == nil {
+				continue
+			}
+			linkInfo, linked := skill.LinkedProjects[issue.Project]
+			if !linked || linkInfo.SymlinkPath != issue.Path || linkResolves(linkInfo) {
+				continue
+			}
+			if err := removeSymlink(issue.Path); err != nil {
+				d.logger.Error("Failed to remove broken symlink", err, "path", issue.Path)
+				continue
+			}
+			delete(skill.LinkedProjects, issue.Project)
+			if len(skill.LinkedProjects) == 0 {
+				skill.LinkedProjects = nil
+			}
+			issue.Fixed = true
+			d.logger.Info("Removed broken link", "skill", issue.Skill, "project", issue.Project)
+
+		case IssueUnregistered:
+			// A skill of the same name, e.g. one kept outside the store
+			// with --store, would be shadowed by a second entry.
+			if !reAdd[i] || skill != nil {
+				continue
+			}
+			added = append(added, untrackedSkill(issue.Skill, issue.Path))
+			byName[issue.Skill] = &added[len(added)-1]
+			issue.Fixed = true
+			d.logger.Info("Registered untracked skill", "skill", issue.Skill, "path", issue.Path)
+		}
+
+		if issue.Fixed {
+			report.Fixed++
+		}
+	}
+
+	kept := make([]types.SkillMetadata, 0, len(skills)+len(added))
+	for _, skill := range skills {
+		if !removed[skill.ID] {
+			kept = append(kept, skill)
+		}
+	}
+	return append(kept, added...)
+}
+
+// storeExists reports whether the store directory storePath exists.
+func storeExists(storePath string) bool {
+	_, err := os.Stat(storePath)
+	return err == nil
+}
+
+// diagnose compares skills with the store directory of dataDir and the
+// recorded project links. The store is only scanned for unregistered skills
+// when scanStore is set: an alternate registry set with --registry holds
+// just one skill set, so the other store directories are not its own.
+func diagnose(dataDir string, skills []types.SkillMetadata, scanStore bool) ([]Issue, error) {
+	var issues []Issue
+	registered := make(map[string]bool)
+	registeredNames := make(map[string]bool)
+
+	for _, skill := range skills {
+		registered[filepath.Clean(skill.StorePath)] = true
+		registeredNames[skill.Name] = true
+
+		if _, err := os.Stat(skill.StorePath); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to check store directory of '%s': %w", skill.Name, err)
+			}
+			// The entry is removed as a whole, so its links are not
+			// reported separately.
+			issues = append(issues, Issue{Kind: IssueMissingStore, Skill: skill.Name, Path: skill.StorePath})
+			continue
+		}
+
+		projects := make([]string, 0, len(skill.LinkedProjects))
+		for projectPath := range skill.LinkedProjects {
+			projects = append(projects, projectPath)
+		}
+		sort.Strings(projects)
+
+		for _, projectPath := range projects {
+			linkInfo := skill.LinkedProjects[projectPath]
+			if !linkResolves(linkInfo) {
+				issues = append(issues, Issue{Kind: IssueBrokenLink, Skill: skill.Name, Path: linkInfo.SymlinkPath, Project: projectPath})
+			}
+		}
+	}
+
+	if !scanStore {
+		return issues, nil
+	}
+
+	skillsDir := paths.SkillsDir(dataDir)
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read skills store: %w", err)
+	}
+	for _, entry := range entries {
+		// Hidden entries are temporary download directories.
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		storePath := filepath.Join(skillsDir, entry.Name())
+		// A directory named like a registered skill stored elsewhere, e.g.
+		// with --store, cannot be registered without a duplicate name.
+		if registered[storePath] || registeredNames[entry.Name()] {
+			continue
+		}
+		if ok, err := add.IsSkillDirectory(storePath); err != nil || !ok {
+			continue
+		}
+		issues = append(issues, Issue{Kind: IssueUnregistered, Skill: entry.Name(), Path: storePath})
+	}
+
+	return issues, nil
+}
+
+// linkResolves reports whether the recorded link is a symlink whose target
+// exists or, for a link made with link --copy, a directory.
+func linkResolves(linkInfo types.LinkedProjectInfo) bool {
+	info, err := os.Lstat(linkInfo.SymlinkPath)
+	if err != nil {
+		return false
+	}
+	if linkInfo.Copy {
+		return info.IsDir()
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	_, err = os.Stat(linkInfo.SymlinkPath)
+	return err == nil
+}
+
+// removeSymlink removes the symlink at path. Paths that do not exist or that
+// are not symlinks are left alone, so user files are never deleted.
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// untrackedSkill returns the registry entry for a store directory found
+// without one. The source is marked as unknown so update skips it.
+func untrackedSkill(name, storePath string) types.SkillMetadata {
+	return types.SkillMetadata{
+		ID:        fmt.Sprintf("%s@%s", name, add.LocalVersion),
+		Name:      name,
+		SourceURL: add.UntrackedSource,
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+		Version:   add.LocalVersion,
+		CommitSHA: add.LocalVersion,
+	}
+}
@@ -0,0 +1,373 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// fixture is a data directory with a registry and a store.
+type fixture struct {
+	dataDir      string
+	registryPath string
+	skillsDir    string
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	dataDir := t.TempDir()
+	f := &fixture{
+		dataDir:      dataDir,
+		registryPath: paths.RegistryPath(dataDir),
+		skillsDir:    paths.SkillsDir(dataDir),
+	}
+	if err := os.MkdirAll(f.skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create skills dir: %v", err)
+	}
+	return f
+}
+
+// storeSkill creates a store directory holding a SKILL.md and returns its path.
+func (f *fixture) storeSkill(t *testing.T, name string) string {
+	t.Helper()
+	storePath := filepath.Join(f.skillsDir, name)
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# "+name), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	return storePath
+}
+
+// link creates a project symlink to storePath and returns the project and
+// symlink paths.
+func link(t *testing.T, name, storePath string) (string, string) {
+	t.Helper()
+	projectPath := t.TempDir()
+	symlinkPath := filepath.Join(projectPath, ".opencode", "skills", name)
+	if err := os.MkdirAll(filepath.Dir(symlinkPath), 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	if err := os.Symlink(storePath, symlinkPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	return projectPath, symlinkPath
+}
+
+func (f *fixture) save(t *testing.T, skills ...types.SkillMetadata) {
+	t.Helper()
+	if err := registry.SaveRegistryWithPath(f.registryPath, skills); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+}
+
+func (f *fixture) load(t *testing.T) []types.SkillMetadata {
+	t.Helper()
+	skills, err := registry.LoadRegistryWithPath(f.registryPath)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	return skills
+}
+
+func (f *fixture) doctor() *Doctor {
+	d := New()
+	d.SetDataDir(f.dataDir)
+	return d
+}
+
+func skillEntry(name, storePath string, links map[string]types.LinkedProjectInfo) types.SkillMetadata {
+	return types.SkillMetadata{
+		ID:             name + "@main",
+		Name:           name,
+		SourceURL:      "https://github.com/owner/repo/tree/main/" + name,
+		StorePath:      storePath,
+		Version:        "main",
+		CommitSHA:      "abc123",
+		LinkedProjects: links,
+	}
+}
+
+func TestDiagnose_Healthy(t *testing.T) {
+	f := newFixture(t)
+	storePath := f.storeSkill(t, "good")
+	project, symlink := link(t, "good", storePath)
+	f.save(t, skillEntry("good", storePath, map[string]types.LinkedProjectInfo{project: {SymlinkPath: symlink}}))
+
+	report, err := f.doctor().Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Diagnose() issues = %+v, want none", report.Issues)
+	}
+}
+
+func TestDiagnose_DoesNotChangeAnything(t *testing.T) {
+	f := newFixture(t)
+	f.save(t, skillEntry("gone", filepath.Join(f.skillsDir, "gone"), nil))
+	f.storeSkill(t, "orphan")
+
+	report, err := f.doctor().Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if len(report.Issues) != 2 || report.Fixed != 0 {
+		t.Errorf("Diagnose() = %+v, want 2 unfixed issues", report)
+	}
+	if skills := f.load(t); len(skills) != 1 {
+		t.Errorf("registry has %d skills after Diagnose(), want 1", len(skills))
+	}
+}
+
+func TestFix_MissingStore(t *testing.T) {
+	f := newFixture(t)
+	goodStore := f.storeSkill(t, "good")
+	goneStore := filepath.Join(f.skillsDir, "gone")
+	project, symlink := link(t, "gone", goneStore)
+	f.save(t,
+		skillEntry("good", goodStore, nil),
+		skillEntry("gone", goneStore, map[string]types.LinkedProjectInfo{project: {SymlinkPath: symlink}}),
+	)
+
+	report, err := f.doctor().Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != IssueMissingStore || report.Fixed != 1 {
+		t.Fatalf("Fix() = %+v, want one fixed missing store", report)
+	}
+
+	skills := f.load(t)
+	if len(skills) != 1 || skills[0].Name != "good" {
+		t.Errorf("registry = %+v, want only 'good'", skills)
+	}
+	if _, err := os.Lstat(symlink); !os.IsNotExist(err) {
+		t.Error("dangling symlink of the removed skill should be deleted")
+	}
+
+	assertHealthy(t, f)
+}
+
+func TestFix_BrokenLinks(t *testing.T) {
+	f := newFixture(t)
+	storePath := f.storeSkill(t, "skill")
+	goodProject, goodSymlink := link(t, "skill", storePath)
+	missingProject := t.TempDir()
+	missingSymlink := filepath.Join(missingProject, ".opencode", "skills", "skill")
+	danglingProject, danglingSymlink := link(t, "skill", filepath.Join(f.skillsDir, "old-name"))
+	clobberedProject := t.TempDir()
+	clobberedPath := filepath.Join(clobberedProject, ".opencode", "skills", "skill")
+	if err := os.MkdirAll(filepath.Dir(clobberedPath), 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	if err := os.WriteFile(clobberedPath, []byte("user file"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	// Links made with link --copy are directories, not symlinks.
+	copyProject := t.TempDir()
+	copyPath := filepath.Join(copyProject, ".opencode", "skills", "skill")
+	if err := os.MkdirAll(copyPath, 0755); err != nil {
+		t.Fatalf("failed to create copy: %v", err)
+	}
+	missingCopyProject := t.TempDir()
+	missingCopyPath := filepath.Join(missingCopyProject, ".opencode", "skills", "skill")
+
+	f.save(t, skillEntry("skill", storePath, map[string]types.LinkedProjectInfo{
+		goodProject:        {SymlinkPath: goodSymlink},
+		missingProject:     {SymlinkPath: missingSymlink},
+		danglingProject:    {SymlinkPath: danglingSymlink},
+		clobberedProject:   {SymlinkPath: clobberedPath},
+		copyProject:        {SymlinkPath: copyPath, Copy: true},
+		missingCopyProject: {SymlinkPath: missingCopyPath, Copy: true},
+	}))
+
+	report, err := f.doctor().Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(report.Issues) != 4 || report.Fixed != 4 {
+		t.Fatalf("Fix() = %+v, want 4 fixed broken links", report)
+	}
+	for _, issue := range report.Issues {
+		if issue.Kind != IssueBrokenLink {
+			t.Errorf("issue kind = %s, want %s", issue.Kind, IssueBrokenLink)
+		}
+	}
+
+	skills := f.load(t)
+	if len(skills) != 1 || len(skills[0].LinkedProjects) != 2 {
+		t.Fatalf("registry = %+v, want one skill with two links", skills)
+	}
+	for _, project := range []string{goodProject, copyProject} {
+		if _, ok := skills[0].LinkedProjects[project]; !ok {
+			t.Errorf("valid link into %s should be kept", project)
+		}
+	}
+	if _, err := os.Lstat(danglingSymlink); !os.IsNotExist(err) {
+		t.Error("dangling symlink should be deleted")
+	}
+	if content, err := os.ReadFile(clobberedPath); err != nil || string(content) != "user file" {
+		t.Error("a regular file at the link path must not be deleted")
+	}
+
+	assertHealthy(t, f)
+}
+
+func TestFix_Unregistered(t *testing.T) {
+	tests := []struct {
+		name      string
+		confirm   func(Issue) (bool, error)
+		wantAdded bool
+	}{
+		{name: "re-added without confirmation hook", confirm: nil, wantAdded: true},
+		{name: "re-added when confirmed", confirm: func(Issue) (bool, error) { return true, nil }, wantAdded: true},
+		{name: "kept unregistered when declined", confirm: func(Issue) (bool, error) { return false, nil }, wantAdded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			storePath := f.storeSkill(t, "orphan")
+			// Neither temporary download directories nor directories
+			// without SKILL.md are skills.
+			if err := os.MkdirAll(filepath.Join(f.skillsDir, ".tmp.orphan.1"), 0755); err != nil {
+				t.Fatalf("failed to create tmp dir: %v", err)
+			}
+			if err := os.MkdirAll(filepath.Join(f.skillsDir, "not-a-skill"), 0755); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+			f.save(t)
+
+			d := f.doctor()
+			d.SetConfirmReAdd(tt.confirm)
+			report, err := d.Fix()
+			if err != nil {
+				t.Fatalf("Fix() error = %v", err)
+			}
+			if len(report.Issues) != 1 || report.Issues[0].Kind != IssueUnregistered {
+				t.Fatalf("Fix() issues = %+v, want one unregistered skill", report.Issues)
+			}
+
+			skills := f.load(t)
+			if !tt.wantAdded {
+				if len(skills) != 0 || report.Fixed != 0 {
+					t.Errorf("declined re-add changed the registry: %+v", skills)
+				}
+				return
+			}
+
+			if len(skills) != 1 || report.Fixed != 1 {
+				t.Fatalf("registry = %+v, want the re-added skill", skills)
+			}
+			s := skills[0]
+			if s.Name != "orphan" || s.StorePath != storePath || s.SourceURL != add.UntrackedSource {
+				t.Errorf("re-added skill = %+v, want orphan at %s with source %q", s, storePath, add.UntrackedSource)
+			}
+
+			assertHealthy(t, f)
+		})
+	}
+}
+
+func TestFix_KeepsConcurrentRegistryWrites(t *testing.T) {
+	f := newFixture(t)
+	f.storeSkill(t, "orphan")
+	f.storeSkill(t, "raced")
+	other := skillEntry("other", f.storeSkill(t, "other"), nil)
+	f.save(t, other)
+
+	d := f.doctor()
+	// Another command adds "raced" while the user is being asked.
+	d.SetConfirmReAdd(func(issue Issue) (bool, error) {
+		if issue.Skill == "orphan" {
+			raced := skillEntry("raced", filepath.Join(f.skillsDir, "raced"), nil)
+			if err := registry.AddOrUpdateSkillWithPath(f.registryPath, &raced); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	report, err := d.Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if report.Fixed != 1 {
+		t.Errorf("Fix() fixed %d issues, want only orphan: %+v", report.Fixed, report.Issues)
+	}
+
+	names := make(map[string]int)
+	for _, skill := range f.load(t) {
+		names[skill.Name]++
+	}
+	for _, name := range []string{"orphan", "raced", "other"} {
+		if names[name] != 1 {
+			t.Errorf("registry has %d entries named %s, want 1: %v", names[name], name, names)
+		}
+	}
+}
+
+func TestDiagnose_SkipsDirectoryOfRegisteredName(t *testing.T) {
+	f := newFixture(t)
+	f.storeSkill(t, "shadowed")
+	// The registered skill of that name lives outside the store.
+	elsewhere := filepath.Join(t.TempDir(), "shadowed")
+	if err := os.MkdirAll(elsewhere, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+	f.save(t, skillEntry("shadowed", elsewhere, nil))
+
+	report, err := f.doctor().Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Fix() issues = %+v, want none", report.Issues)
+	}
+	if skills := f.load(t); len(skills) != 1 {
+		t.Errorf("registry = %+v, want only the original entry", skills)
+	}
+}
+
+// assertHealthy checks that a second diagnosis finds nothing left to fix.
+func assertHealthy(t *testing.T, f *fixture) {
+	t.Helper()
+	report, err := f.doctor().Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("issues after Fix() = %+v, want none", report.Issues)
+	}
+}
+
+func TestDiagnose_AlternateRegistrySkipsStoreScan(t *testing.T) {
+	f := newFixture(t)
+	f.storeSkill(t, "other-set")
+	alternate := filepath.Join(t.TempDir(), "work.json")
+	if err := paths.SetRegistryPath(alternate); err != nil {
+		t.Fatalf("SetRegistryPath() error = %v", err)
+	}
+	t.Cleanup(func() { paths.SetRegistryPath("") })
+
+	report, err := f.doctor().Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Fix() issues = %+v, want the store of another registry left alone", report.Issues)
+	}
+	skills, err := registry.LoadRegistryWithPath(alternate)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	if len(skills) != 0 {
+		t.Errorf("alternate registry = %+v, want it empty", skills)
+	}
+}
@@ -0,0 +1,15 @@
+package doctor
+
+type Logger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, err error, fields ...interface{})
+}
+
+type NoOpLogger struct{}
+
+func (l NoOpLogger) Debug(msg string, fields ...interface{})            {}
+func (l NoOpLogger) Info(msg string, fields ...interface{})             {}
+func (l NoOpLogger) Warn(msg string, fields ...interface{})             {}
+func (l NoOpLogger) Error(msg string, err error, fields ...interface{}) {}
@@ -0,0 +1,150 @@
+// Package history keeps an append-only log of the operations gskills performs
+// on skills. Each line of ~/.gskills/history.log is one JSON-encoded Entry.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/smy-101/gskills/internal/paths"
+)
+
+// Operation names the kind of change recorded in an Entry.
+type Operation string
+
+const (
+	OpAdd    Operation = "add"
+	OpUpdate Operation = "update"
+	OpRemove Operation = "remove"
+	OpLink   Operation = "link"
+)
+
+// Operations lists every recorded operation in display order.
+var Operations = []Operation{OpAdd, OpUpdate, OpRemove, OpLink}
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry is a single line of the history log.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Operation Operation `json:"operation"`
+	Skill     string    `json:"skill"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// mu serializes appends from concurrent operations such as 'update --all'.
+var mu sync.Mutex
+
+// Append writes entry to the history log of dataDir, creating the log if needed.
+func Append(dataDir string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	logPath := paths.HistoryPath(dataDir)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history log: %w", err)
+	}
+	return f.Close()
+}
+
+// Record appends an entry for op on skill with the current time. A nil opErr
+// is recorded as a success, anything else as a failure with its message.
+func Record(dataDir string, op Operation, skill string, opErr error) error {
+	entry := Entry{
+		Time:      time.Now(),
+		Operation: op,
+		Skill:     skill,
+		Result:    ResultSuccess,
+	}
+	if opErr != nil {
+		entry.Result = ResultFailure
+		entry.Error = opErr.Error()
+	}
+	return Append(dataDir, entry)
+}
+
+// Read returns all entries of the history log of dataDir, oldest first.
+// A missing log yields no entries; lines that cannot be decoded are skipped.
+func Read(dataDir string) ([]Entry, error) {
+	f, err := os.Open(paths.HistoryPath(dataDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("failed to open history log: %w", err)
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history log: %w", err)
+	}
+	return entries, nil
+}
+
+// Filter selects history entries. Zero fields match everything.
+type Filter struct {
+	Skill     string
+	Operation Operation
+	// Limit keeps only the most recent matching entries when positive.
+	Limit int
+}
+
+// Apply returns the entries matching f, oldest first.
+func (f Filter) Apply(entries []Entry) []Entry {
+	matched := []Entry{}
+	for _, entry := range entries {
+		if f.Skill != "" && entry.Skill != f.Skill {
+			continue
+		}
+		if f.Operation != "" && entry.Operation != f.Operation {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched
+}
+
+// ParseOperation validates an operation name given on the command line.
+func ParseOperation(name string) (Operation, error) {
+	for _, op := range Operations {
+		if string(op) == name {
+			return op, nil
+		}
+	}
+	return "", fmt.Errorf("unknown operation '%s' (expected add, update, remove or link)", name)
+}
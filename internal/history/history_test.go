@@ -0,0 +1,155 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	dataDir := t.TempDir()
+
+	if err := Record(dataDir, OpAdd, "alpha", nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := Record(dataDir, OpUpdate, "alpha", errors.New("network down")); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries, err := Read(dataDir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Read() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Operation != OpAdd || entries[0].Result != ResultSuccess || entries[0].Error != "" {
+		t.Errorf("entries[0] = %+v, want a successful add", entries[0])
+	}
+	if entries[1].Operation != OpUpdate || entries[1].Result != ResultFailure || entries[1].Error != "network down" {
+		t.Errorf("entries[1] = %+v, want a failed update", entries[1])
+	}
+	if entries[0].Time.IsZero() {
+		t.Error("entry time not recorded")
+	}
+}
+
+func TestRead_MissingLog(t *testing.T) {
+	entries, err := Read(t.TempDir())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Read() = %v, want no entries", entries)
+	}
+}
+
+func TestRead_SkipsMalformedLines(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := Record(dataDir, OpLink, "alpha", nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	f, err := os.OpenFile(paths.HistoryPath(dataDir), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	if err := Record(dataDir, OpRemove, "alpha", nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries, err := Read(dataDir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Read() returned %d entries, want 2", len(entries))
+	}
+}
+
+func TestRecord_Concurrent(t *testing.T) {
+	dataDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Record(dataDir, OpUpdate, "alpha", nil); err != nil {
+				t.Errorf("Record() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := Read(dataDir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 20 {
+		t.Errorf("Read() returned %d entries, want 20", len(entries))
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	entries := []Entry{
+		{Operation: OpAdd, Skill: "alpha"},
+		{Operation: OpAdd, Skill: "beta"},
+		{Operation: OpUpdate, Skill: "alpha"},
+		{Operation: OpLink, Skill: "alpha"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []Entry
+	}{
+		{
+			name:   "no filter",
+			filter: Filter{},
+			want:   entries,
+		},
+		{
+			name:   "by skill",
+			filter: Filter{Skill: "beta"},
+			want:   []Entry{entries[1]},
+		},
+		{
+			name:   "by operation",
+			filter: Filter{Operation: OpAdd},
+			want:   []Entry{entries[0], entries[1]},
+		},
+		{
+			name:   "limit keeps the most recent",
+			filter: Filter{Skill: "alpha", Limit: 2},
+			want:   []Entry{entries[2], entries[3]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Apply(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOperation(t *testing.T) {
+	for _, op := range Operations {
+		got, err := ParseOperation(string(op))
+		if err != nil || got != op {
+			t.Errorf("ParseOperation(%q) = %q, %v", op, got, err)
+		}
+	}
+	if _, err := ParseOperation("install"); err == nil {
+		t.Error("ParseOperation(\"install\") should fail")
+	}
+}
@@ -10,6 +10,7 @@ const (
 	ErrTypeConfigWrite
 	ErrTypeShellDetection
 	ErrTypePathResolution
+	ErrTypeChecksum
 )
 
 type InitError struct {
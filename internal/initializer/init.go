@@ -4,6 +4,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/smy-101/gskills/internal/paths"
 )
 
 type Initializer struct {
@@ -12,12 +14,10 @@ type Initializer struct {
 }
 
 func New() *Initializer {
-	home, err := os.UserHomeDir()
+	configDir, err := paths.DataDir()
 	if err != nil {
-		home = os.Getenv("HOME")
+		configDir = filepath.Join(os.Getenv("HOME"), ".gskills")
 	}
-
-	configDir := filepath.Join(home, ".gskills")
 	binDir := filepath.Join(configDir, "bin")
 
 	return &Initializer{
@@ -30,6 +30,9 @@ func (i *Initializer) GetBinDir() string {
 	return i.binDir
 }
 
+// InstallBinary copies the binary at sourcePath to <binDir>/gskills.
+// The copy is written to a temporary file in binDir and then renamed over
+// the destination, so a running gskills binary is never truncated in place.
 func (i *Initializer) InstallBinary(sourcePath string) error {
 	if err := os.MkdirAll(i.binDir, 0755); err != nil {
 		return &InitError{
@@ -51,50 +54,55 @@ func (i *Initializer) InstallBinary(sourcePath string) error {
 	}
 	defer sourceFile.Close()
 
-	sourceInfo, err := sourceFile.Stat()
+	tmpFile, err := os.CreateTemp(i.binDir, ".gskills.tmp.*")
 	if err != nil {
 		return &InitError{
 			Type:    ErrTypeBinaryCopy,
-			Message: "无法获取源文件信息",
+			Message: "无法创建临时二进制文件",
 			Err:     err,
 		}
 	}
+	tmpPath := tmpFile.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+		}
+	}()
 
-	destFile, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
-	if err != nil {
+	if _, err := io.Copy(tmpFile, sourceFile); err != nil {
 		return &InitError{
 			Type:    ErrTypeBinaryCopy,
-			Message: "无法创建目标二进制文件",
+			Message: "无法复制二进制文件",
 			Err:     err,
 		}
 	}
-	defer destFile.Close()
 
-	if _, err := io.Copy(destFile, sourceFile); err != nil {
+	if err := tmpFile.Close(); err != nil {
 		return &InitError{
 			Type:    ErrTypeBinaryCopy,
-			Message: "无法复制二进制文件",
+			Message: "无法关闭目标文件",
 			Err:     err,
 		}
 	}
 
-	if err := destFile.Close(); err != nil {
+	if err := os.Chmod(tmpPath, 0755); err != nil {
 		return &InitError{
 			Type:    ErrTypeBinaryCopy,
-			Message: "无法关闭目标文件",
+			Message: "无法设置二进制文件权限",
 			Err:     err,
 		}
 	}
 
-	if err := os.Chmod(destPath, 0755); err != nil {
+	if err := os.Rename(tmpPath, destPath); err != nil {
 		return &InitError{
 			Type:    ErrTypeBinaryCopy,
-			Message: "无法设置二进制文件权限",
+			Message: "无法替换目标二进制文件",
 			Err:     err,
 		}
 	}
-
-	_ = sourceInfo
+	committed = true
 
 	return nil
 }
@@ -3,6 +3,7 @@ package initializer
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -245,12 +246,39 @@ func TestHasExportInConfig(t *testing.T) {
 			want:          true,
 		},
 		{
-			name: "export exists with gskills/bin",
-			configContent: `# gskills
-export PATH="$HOME/.gskills/bin:$PATH"`,
+			name: "gskills block with a different home",
+			configContent: `# gskills PATH export
+export PATH="/Users/other/.gskills/bin:$PATH"`,
 			binPath: "/home/user/.gskills/bin",
 			want:    true,
 		},
+		{
+			name: "fish block",
+			configContent: `# gskills PATH export
+fish_add_path /home/user/.gskills/bin`,
+			binPath: "/home/user/.gskills/bin",
+			want:    true,
+		},
+		{
+			name: "path mentioned in a comment",
+			configContent: `# TODO: add /home/user/.gskills/bin to PATH
+export PATH="/usr/bin:$PATH"`,
+			binPath: "/home/user/.gskills/bin",
+			want:    false,
+		},
+		{
+			name:          "commented-out export",
+			configContent: `# export PATH="/home/user/.gskills/bin:$PATH"`,
+			binPath:       "/home/user/.gskills/bin",
+			want:          false,
+		},
+		{
+			name: "marker without export",
+			configContent: `# gskills PATH export
+alias ll="ls -l"`,
+			binPath: "/home/user/.gskills/bin",
+			want:    false,
+		},
 		{
 			name:          "export does not exist",
 			configContent: `export PATH="/usr/bin:$PATH"`,
@@ -290,6 +318,28 @@ export PATH="$HOME/.gskills/bin:$PATH"`,
 	}
 }
 
+func TestUpdatePATH_Idempotent(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".zshrc")
+	if err := os.WriteFile(configPath, []byte("# see ~/.gskills/bin for tools\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	init := New()
+	for i := 0; i < 2; i++ {
+		if err := init.UpdatePATH("/home/user/.gskills/bin", configPath, ShellZsh); err != nil {
+			t.Fatalf("UpdatePATH() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if got := strings.Count(string(content), ExportMarker); got != 1 {
+		t.Errorf("config contains %d export blocks, want 1:\n%s", got, content)
+	}
+}
+
 func TestNew(t *testing.T) {
 	home := os.Getenv("HOME")
 	if home == "" {
@@ -0,0 +1,84 @@
+package initializer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ChecksumsAssetName is the release asset listing the SHA-256 checksum of
+// every binary in the release, one "<sha256>  <asset name>" pair per line.
+const ChecksumsAssetName = "checksums.txt"
+
+// ReleaseAssetName returns the name of the release binary built for the
+// given operating system and architecture, e.g. "gskills_linux_amd64".
+func ReleaseAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("gskills_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// LookupChecksum returns the checksum recorded for assetName in the content
+// of a checksums file.
+func LookupChecksum(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", &InitError{
+			Type:    ErrTypeChecksum,
+			Message: "无法读取校验和文件",
+			Err:     err,
+		}
+	}
+	return "", &InitError{
+		Type:    ErrTypeChecksum,
+		Message: fmt.Sprintf("校验和文件中没有 %s 的记录", assetName),
+	}
+}
+
+// VerifyChecksum checks that the SHA-256 checksum of the file at path
+// matches the hex-encoded want.
+func VerifyChecksum(path, want string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return &InitError{
+			Type:    ErrTypeChecksum,
+			Message: "无法打开待校验的文件",
+			Err:     err,
+		}
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return &InitError{
+			Type:    ErrTypeChecksum,
+			Message: "无法计算文件校验和",
+			Err:     err,
+		}
+	}
+
+	got := hex.EncodeToString(h.Sum(nil))
+	if got != strings.ToLower(want) {
+		return &InitError{
+			Type:    ErrTypeChecksum,
+			Message: fmt.Sprintf("校验和不匹配: 期望 %s，实际 %s", want, got),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package initializer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallBinary_ReplacesExisting(t *testing.T) {
+	binDir := filepath.Join(t.TempDir(), "bin")
+	init := &Initializer{binDir: binDir}
+
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("failed to create bin dir: %v", err)
+	}
+	destPath := filepath.Join(binDir, "gskills")
+	if err := os.WriteFile(destPath, []byte("old"), 0755); err != nil {
+		t.Fatalf("failed to write old binary: %v", err)
+	}
+
+	srcPath := filepath.Join(t.TempDir(), "gskills-new")
+	if err := os.WriteFile(srcPath, []byte("new"), 0644); err != nil {
+		t.Fatalf("failed to write new binary: %v", err)
+	}
+
+	if err := init.InstallBinary(srcPath); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	got, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("failed to read installed binary: %v", err)
+	}
+	if string(got) != "new" {
+		t.Errorf("installed binary = %q, want %q", string(got), "new")
+	}
+
+	entries, _ := os.ReadDir(binDir)
+	if len(entries) != 1 {
+		t.Errorf("bin dir has %d entries, temporary file was not cleaned up", len(entries))
+	}
+}
+
+func TestLookupChecksum(t *testing.T) {
+	checksums := []byte("abc123  gskills_linux_amd64\nDEF456 *gskills_darwin_arm64\n\nmalformed line here\n")
+
+	tests := []struct {
+		name    string
+		asset   string
+		want    string
+		wantErr bool
+	}{
+		{name: "text mode entry", asset: "gskills_linux_amd64", want: "abc123"},
+		{name: "binary mode entry", asset: "gskills_darwin_arm64", want: "def456"},
+		{name: "missing entry", asset: "gskills_windows_amd64.exe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupChecksum(checksums, tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LookupChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// sha256("hello")
+	const sum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	if err := VerifyChecksum(path, sum); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+	if err := VerifyChecksum(path, "deadbeef"); err == nil {
+		t.Error("VerifyChecksum() should fail on a mismatch")
+	}
+}
@@ -10,6 +10,10 @@ import (
 
 type Shell string
 
+// ExportMarker is the comment line that starts the PATH export block written
+// by init. It identifies the block as managed by gskills.
+const ExportMarker = "# gskills PATH export"
+
 const (
 	ShellZsh     Shell = "zsh"
 	ShellBash    Shell = "bash"
@@ -66,9 +70,9 @@ func getConfigPath(shell Shell) string {
 func GeneratePATHExport(binPath string, shell Shell) string {
 	switch shell {
 	case ShellZsh, ShellBash:
-		return fmt.Sprintf("\n# gskills PATH export\nexport PATH=\"%s:$PATH\"\n", binPath)
+		return fmt.Sprintf("\n%s\nexport PATH=\"%s:$PATH\"\n", ExportMarker, binPath)
 	case ShellFish:
-		return fmt.Sprintf("\n# gskills PATH export\nfish_add_path %s\n", binPath)
+		return fmt.Sprintf("\n%s\nfish_add_path %s\n", ExportMarker, binPath)
 	default:
 		return ""
 	}
@@ -102,9 +106,37 @@ func HasExportInConfig(configPath, binPath string) (bool, error) {
 		}
 	}
 
-	configContent := string(content)
-	return strings.Contains(configContent, binPath) ||
-		strings.Contains(configContent, ".gskills/bin"), nil
+	lines := strings.Split(string(content), "\n")
+	if FindExportBlock(lines) >= 0 {
+		return true, nil
+	}
+	// An export the user wrote by hand for the same directory also counts.
+	for _, line := range lines {
+		if isPATHExport(line) && strings.Contains(line, binPath) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindExportBlock returns the index of the ExportMarker line of the export
+// block written by init, or -1 if lines contain no such block. The marker
+// must be followed by a PATH export, so a stray copy of the comment does not
+// count.
+func FindExportBlock(lines []string) int {
+	for i := 0; i+1 < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == ExportMarker && isPATHExport(lines[i+1]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isPATHExport reports whether line adds a directory to PATH in bash, zsh or
+// fish syntax. Commented-out lines don't count.
+func isPATHExport(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "export PATH=") || strings.HasPrefix(line, "fish_add_path ")
 }
 
 func AppendToConfig(configPath, exportLine string) error {
@@ -0,0 +1,108 @@
+package link
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/smy-101/gskills/internal/fsutil"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// RemoveLink removes the project entry described by info: the symlink, or
+// the copied directory for a link created in copy mode.
+func RemoveLink(info types.LinkedProjectInfo) error {
+	if info.Copy {
+		return os.RemoveAll(info.SymlinkPath)
+	}
+	return os.Remove(info.SymlinkPath)
+}
+
+// RefreshCopies re-copies the store directory of skill into each project it
+// was linked to in copy mode, so that copies follow updates like symlinks
+// do. Each copy is written next to the old one and swapped in, so a failure
+// leaves the old copy in place. Projects whose copy was removed are skipped.
+// Errors of all projects are joined.
+func RefreshCopies(skill *types.SkillMetadata) error {
+	projects := make([]string, 0, len(skill.LinkedProjects))
+	for projectPath, linkInfo := range skill.LinkedProjects {
+		if linkInfo.Copy {
+			projects = append(projects, projectPath)
+		}
+	}
+	sort.Strings(projects)
+
+	var errs []error
+	for _, projectPath := range projects {
+		copyPath := skill.LinkedProjects[projectPath].SymlinkPath
+		if _, err := os.Lstat(copyPath); os.IsNotExist(err) {
+			continue
+		}
+		if err := replaceCopy(skill.StorePath, copyPath); err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh copy in project '%s': %w", projectPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Repoint makes the project entry described by info refer to storePath
+// instead: a symlink is replaced by one pointing at storePath and a copy by
+// a fresh copy of it. The path of the entry does not change. The new symlink
+// is renamed over the old one, so the path never dangles.
+func Repoint(info types.LinkedProjectInfo, storePath string) error {
+	if info.Copy {
+		return replaceCopy(storePath, info.SymlinkPath)
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(info.SymlinkPath), fmt.Sprintf(".tmp.%s.%d", filepath.Base(info.SymlinkPath), time.Now().UnixNano()))
+	if err := os.Symlink(storePath, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, info.SymlinkPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// copyDir copies a directory tree to a destination that does not exist yet.
+// It is a variable so tests can make a copy fail part way.
+var copyDir = fsutil.CopyDir
+
+// copyTree copies the store directory src to dst, which must not exist yet.
+// Files are streamed rather than read into memory and keep their permission
+// bits. When the copy fails part way, for instance because the project is on
+// another filesystem that runs out of space, the partial dst is removed so
+// that no half-copied skill is left in the project.
+func copyTree(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("destination '%s' already exists", dst)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check destination: %w", err)
+	}
+
+	if err := copyDir(src, dst); err != nil {
+		if removeErr := os.RemoveAll(dst); removeErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to remove partial copy '%s': %w", dst, removeErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// replaceCopy replaces the directory at copyPath with a fresh copy of
+// storePath.
+func replaceCopy(storePath, copyPath string) error {
+	tmpPath := filepath.Join(filepath.Dir(copyPath), fmt.Sprintf(".tmp.%s.%d", filepath.Base(copyPath), time.Now().UnixNano()))
+	if err := copyTree(storePath, tmpPath); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(copyPath); err != nil {
+		os.RemoveAll(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, copyPath)
+}
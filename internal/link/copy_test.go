@@ -0,0 +1,105 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("# Skill"), 0644)
+	os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo hi"), 0755)
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "scripts", "run.sh"))
+	if err != nil {
+		t.Fatalf("script not copied: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("script mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	os.WriteFile(filepath.Join(dst, "local.txt"), []byte("keep"), 0644)
+	if err := copyTree(src, dst); err == nil {
+		t.Error("copyTree() onto an existing destination should fail")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "local.txt")); err != nil {
+		t.Errorf("an existing destination must not be removed: %v", err)
+	}
+}
+
+// failCopyAfterFirstFile makes copies write the first file of the tree and
+// then fail as if the target filesystem were full.
+func failCopyAfterFirstFile(t *testing.T) {
+	t.Helper()
+	oldCopyDir := copyDir
+	copyDir = func(src, dst string) error {
+		if err := os.MkdirAll(filepath.Join(dst, "scripts"), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dst, "SKILL.md"), []byte("# partial"), 0644); err != nil {
+			return err
+		}
+		return &os.PathError{Op: "write", Path: filepath.Join(dst, "scripts", "run.sh"), Err: syscall.ENOSPC}
+	}
+	t.Cleanup(func() { copyDir = oldCopyDir })
+}
+
+func TestLinker_LinkSkillCopy_RemovesPartialCopy(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "big-skill")
+	if err := os.MkdirAll(filepath.Join(storePath, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# Skill"), 0644)
+	os.WriteFile(filepath.Join(storePath, "scripts", "run.sh"), []byte("echo hi"), 0755)
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "big-skill@main",
+		Name:      "big-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	failCopyAfterFirstFile(t)
+
+	projectDir := t.TempDir()
+	linker := NewLinker()
+	linker.SetCopy(true)
+	err := linker.LinkSkill(context.Background(), "big-skill", projectDir)
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("LinkSkill() error = %v, want the write error", err)
+	}
+
+	copyPath := filepath.Join(projectDir, ".opencode", "skills", "big-skill")
+	if _, err := os.Lstat(copyPath); !os.IsNotExist(err) {
+		t.Errorf("partial copy left at %s: %v", copyPath, err)
+	}
+	skill, err := registry.FindSkillByName("big-skill")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	if _, ok := skill.LinkedProjects[projectDir]; ok {
+		t.Error("failed copy recorded as a link")
+	}
+}
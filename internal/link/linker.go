@@ -1,15 +1,18 @@
 // Package link provides functionality to create symlinks from gskills-managed
-// skill directories to project .opencode/skills/ directories.
+// skill directories to the skills directory of a project, such as
+// .opencode/skills/ or .claude/skills/.
 package link
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/smy-101/gskills/internal/constants"
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
 )
@@ -17,16 +20,67 @@ import (
 // Linker handles creating and managing symlinks between gskills-managed
 // skill directories and project directories.
 type Linker struct {
-	logger Logger
+	logger    Logger
+	dataDir   string
+	targetDir string
+	copyMode  bool
+	pruneDirs bool
 }
 
 // NewLinker creates a new Linker instance with a NoOpLogger.
 func NewLinker() *Linker {
 	return &Linker{
-		logger: NoOpLogger{},
+		logger:    NoOpLogger{},
+		pruneDirs: true,
 	}
 }
 
+// SetLogger sets the logger used by the linker.
+func (l *Linker) SetLogger(logger Logger) {
+	l.logger = logger
+}
+
+// SetDataDir sets the gskills data directory holding the skills store and
+// registry. An empty dir selects the default ~/.gskills.
+func (l *Linker) SetDataDir(dir string) {
+	l.dataDir = dir
+}
+
+// SetTargetDir sets the skills directory, relative to the project, in which
+// symlinks are created. An empty dir detects it per project with
+// DetectTargetDir.
+func (l *Linker) SetTargetDir(dir string) {
+	l.targetDir = dir
+}
+
+// SetCopy makes LinkSkill copy the skill into the project instead of
+// creating a symlink, for tools that don't follow symlinks or projects
+// synced to another machine. Copies are refreshed when the skill is updated.
+func (l *Linker) SetCopy(copyMode bool) {
+	l.copyMode = copyMode
+}
+
+// SetPruneEmptyDirs sets whether UnlinkSkill removes the directories that
+// held the link, such as .opencode/skills and .opencode, once they are left
+// empty. It is on by default.
+func (l *Linker) SetPruneEmptyDirs(prune bool) {
+	l.pruneDirs = prune
+}
+
+// resolveDataDir returns the configured data directory or the default one,
+// as a LinkError if the default cannot be determined.
+func (l *Linker) resolveDataDir() (string, error) {
+	dataDir, err := paths.ResolveDataDir(l.dataDir)
+	if err != nil {
+		return "", &LinkError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to get home directory",
+			Err:     err,
+		}
+	}
+	return dataDir, nil
+}
+
 // checkContextCanceled checks if the context has been canceled and returns an appropriate error.
 func (l *Linker) checkContextCanceled(ctx context.Context) error {
 	select {
@@ -41,57 +95,94 @@ func (l *Linker) checkContextCanceled(ctx context.Context) error {
 	}
 }
 
-// LinkSkill creates a symlink from the gskills-managed skill directory to
-// the target project's .opencode/skills/<skill_name> directory.
-// It updates the skills registry with linked skill metadata.
-// Returns an error if the skill doesn't exist, the project path is invalid,
-// or a symlink already exists at the target location.
-func (l *Linker) LinkSkill(ctx context.Context, skillName, projectPath string) error {
+// LinkPlan describes the symlink LinkSkill would create.
+type LinkPlan struct {
+	// SkillPath is the absolute store directory the symlink points to.
+	SkillPath string
+	// ProjectPath is the absolute project directory.
+	ProjectPath string
+	// TargetPath is where the symlink is created.
+	TargetPath string
+	// Alias is the symlink name if it differs from the skill name.
+	Alias string
+
+	registryPath string
+}
+
+// PlanLink resolves the skill and project paths of a link without creating
+// anything or changing the registry. The symlink is named alias, or the
+// skill name if alias is empty. It fails for the same reasons as LinkSkillAs
+// would before creating the symlink: the skill doesn't exist, the project
+// path or alias is invalid, or a symlink already exists at the target
+// location.
+func (l *Linker) PlanLink(skillName, projectPath, alias string) (*LinkPlan, error) {
 	if skillName == "" {
-		return &LinkError{
+		return nil, &LinkError{
 			Type:    ErrorTypeInvalidPath,
 			Message: "skill name cannot be empty",
 		}
 	}
+	if alias == "." || alias == ".." || strings.ContainsAny(alias, `/\`) {
+		return nil, &LinkError{
+			Type:    ErrorTypeInvalidPath,
+			Message: fmt.Sprintf("invalid link name '%s'", alias),
+		}
+	}
+	linkName := skillName
+	if alias != "" {
+		linkName = alias
+	}
 	if projectPath == "" {
-		return &LinkError{
+		return nil, &LinkError{
 			Type:    ErrorTypeInvalidPath,
 			Message: "project path cannot be empty",
 		}
 	}
 
-	if err := l.checkContextCanceled(ctx); err != nil {
-		return err
-	}
-
-	skillPath, err := l.getSkillPath(skillName)
+	dataDir, err := l.resolveDataDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	registryPath := paths.RegistryPath(dataDir)
 
-	if err := l.checkContextCanceled(ctx); err != nil {
-		return err
+	skill, skillPath, err := l.getSkillPath(registryPath, skillName)
+	if err != nil {
+		return nil, err
 	}
 
 	absProjectPath, err := filepath.Abs(projectPath)
 	if err != nil {
-		return &LinkError{
+		return nil, &LinkError{
 			Type:    ErrorTypeInvalidPath,
 			Message: "failed to get absolute project path",
 			Err:     err,
 		}
 	}
+	// A mistyped project path must fail here rather than have the target
+	// directory created under a new directory tree.
+	if err := l.validateProjectPath(absProjectPath); err != nil {
+		return nil, err
+	}
 
-	if err := l.checkContextCanceled(ctx); err != nil {
-		return err
+	if err := l.checkNotLinked(skill, absProjectPath); err != nil {
+		return nil, err
 	}
 
-	targetDir := filepath.Join(absProjectPath, constants.OpencodeSkillsDir)
-	targetPath := filepath.Join(targetDir, skillName)
+	targetDir := l.targetDir
+	if targetDir == "" {
+		targetDir = DetectTargetDir(absProjectPath)
+	} else if err := validateTargetDir(targetDir); err != nil {
+		return nil, &LinkError{
+			Type:    ErrorTypeInvalidPath,
+			Message: "invalid target directory",
+			Err:     err,
+		}
+	}
+	targetPath := filepath.Join(absProjectPath, targetDir, linkName)
 
 	exists, err := l.checkPathExists(targetPath)
 	if err != nil {
-		return &LinkError{
+		return nil, &LinkError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to check target path existence",
 			Err:     err,
@@ -99,87 +190,203 @@ func (l *Linker) LinkSkill(ctx context.Context, skillName, projectPath string) e
 	}
 
 	if exists {
-		return &LinkError{
+		message := fmt.Sprintf("skill '%s' is already linked in project '%s'", skillName, absProjectPath)
+		if linkName != skillName {
+			message = fmt.Sprintf("link name '%s' is already in use in project '%s'", linkName, absProjectPath)
+		}
+		return nil, &LinkError{
 			Type:    ErrorTypeSymlinkExists,
-			Message: fmt.Sprintf("skill '%s' is already linked in project '%s'", skillName, absProjectPath),
+			Message: message,
 		}
 	}
 
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return &LinkError{
-			Type:    ErrorTypeFilesystem,
-			Message: "failed to create target directory",
-			Err:     err,
-		}
+	plan := &LinkPlan{
+		SkillPath:    skillPath,
+		ProjectPath:  absProjectPath,
+		TargetPath:   targetPath,
+		registryPath: registryPath,
+	}
+	if linkName != skillName {
+		plan.Alias = linkName
 	}
+	return plan, nil
+}
 
+// checkNotLinked returns an error if skill already has a link into
+// projectPath. The registry records one link of a skill per project, so a
+// second one, e.g. under another --as name, would lose track of the first. A
+// recorded link whose symlink is gone is stale and may be replaced.
+func (l *Linker) checkNotLinked(skill *types.SkillMetadata, projectPath string) error {
+	existing, linked := skill.LinkedProjects[projectPath]
+	if !linked {
+		return nil
+	}
+	if present, _ := l.checkPathExists(existing.SymlinkPath); !present {
+		return nil
+	}
+	return &LinkError{
+		Type:    ErrorTypeSymlinkExists,
+		Message: fmt.Sprintf("skill '%s' is already linked in project '%s' as '%s'; unlink it first", skill.Name, projectPath, filepath.Base(existing.SymlinkPath)),
+	}
+}
+
+// LinkSkill creates a symlink from the gskills-managed skill directory to
+// <skill_name> in the skills directory of the target project: the one set
+// with SetTargetDir, or else the one DetectTargetDir finds.
+// It updates the skills registry with linked skill metadata.
+// Returns an error if the skill doesn't exist, the project path is invalid,
+// or a symlink already exists at the target location.
+func (l *Linker) LinkSkill(ctx context.Context, skillName, projectPath string) error {
+	return l.LinkSkillAs(ctx, skillName, projectPath, "")
+}
+
+// LinkSkillAs is like LinkSkill but names the symlink alias instead of the
+// skill name, e.g. to link a long skill name under a short one. The alias is
+// recorded with the link so that unlink and tidy find the symlink. An empty
+// alias behaves like LinkSkill.
+//
+// The symlink is created and recorded in one registry transaction, in which
+// the skill is looked up again, so a link made or a skill removed by another
+// command after PlanLink is not overwritten or linked to a deleted store.
+// The symlink is removed again if the registry cannot be saved.
+func (l *Linker) LinkSkillAs(ctx context.Context, skillName, projectPath, alias string) error {
 	if err := l.checkContextCanceled(ctx); err != nil {
 		return err
 	}
 
-	if err := os.Symlink(skillPath, targetPath); err != nil {
-		return &LinkError{
-			Type:    ErrorTypeFilesystem,
-			Message: "failed to create symlink",
-			Err:     err,
-		}
+	plan, err := l.PlanLink(skillName, projectPath, alias)
+	if err != nil {
+		return err
+	}
+	skillPath, absProjectPath, targetPath := plan.SkillPath, plan.ProjectPath, plan.TargetPath
+
+	linkInfo := types.LinkedProjectInfo{
+		SymlinkPath: targetPath,
+		LinkedAt:    time.Now(),
+		Alias:       plan.Alias,
+		Copy:        l.copyMode,
 	}
 
-	existingSkill, err := registry.FindSkillByName(skillName)
+	created := false
+	err = registry.UpdateWithPath(plan.registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		skill := findSkill(skills, skillName)
+		if skill == nil || !sameStorePath(skill.StorePath, skillPath) {
+			return nil, &LinkError{
+				Type:    ErrorTypeSkillNotFound,
+				Message: fmt.Sprintf("skill '%s' was removed or replaced while linking", skillName),
+			}
+		}
+		if err := l.checkNotLinked(skill, absProjectPath); err != nil {
+			return nil, err
+		}
+
+		if err := l.createLink(ctx, skillPath, targetPath); err != nil {
+			return nil, err
+		}
+		created = true
+		if err := l.checkContextCanceled(ctx); err != nil {
+			return nil, err
+		}
+
+		linkInfo.Version = skill.Version
+		linkInfo.CommitSHA = skill.CommitSHA
+		if skill.LinkedProjects == nil {
+			skill.LinkedProjects = make(map[string]types.LinkedProjectInfo)
+		}
+		skill.LinkedProjects[absProjectPath] = linkInfo
+		skill.UpdatedAt = linkInfo.LinkedAt
+		return skills, nil
+	})
 	if err != nil {
-		l.logger.Error("Failed to find skill in registry", err, "skill", skillName)
-		if removeErr := os.Remove(targetPath); removeErr != nil {
-			l.logger.Error("Failed to clean up symlink after error", removeErr, "path", targetPath)
+		if created {
+			if removeErr := RemoveLink(linkInfo); removeErr != nil {
+				l.logger.Error("Failed to clean up symlink after error", removeErr, "path", targetPath)
+			}
+		}
+		var linkErr *LinkError
+		if errors.As(err, &linkErr) {
+			return err
 		}
-		return fmt.Errorf("failed to find skill '%s' in registry: %w", skillName, err)
+		l.logger.Error("Failed to update skills registry", err, "skill", skillName)
+		return fmt.Errorf("failed to update skills registry: %w", err)
 	}
 
+	l.logger.Info("Successfully linked skill", "skill", skillName, "path", targetPath)
+	return nil
+}
+
+// createLink creates the target directory and then the symlink to skillPath
+// at targetPath, or with SetCopy a copy of skillPath.
+func (l *Linker) createLink(ctx context.Context, skillPath, targetPath string) error {
 	if err := l.checkContextCanceled(ctx); err != nil {
-		if removeErr := os.Remove(targetPath); removeErr != nil {
-			l.logger.Error("Failed to clean up symlink after cancellation", removeErr, "path", targetPath)
-		}
 		return err
 	}
 
-	if existingSkill.LinkedProjects == nil {
-		existingSkill.LinkedProjects = make(map[string]types.LinkedProjectInfo)
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return &LinkError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to create target directory",
+			Err:     err,
+		}
 	}
 
-	existingSkill.LinkedProjects[absProjectPath] = types.LinkedProjectInfo{
-		SymlinkPath: targetPath,
-		LinkedAt:    time.Now(),
+	if err := l.checkContextCanceled(ctx); err != nil {
+		return err
 	}
 
-	existingSkill.UpdatedAt = time.Now()
-
-	if err := registry.UpdateSkill(existingSkill); err != nil {
-		l.logger.Error("Failed to update skills registry", err, "skill", skillName)
-		if removeErr := os.Remove(targetPath); removeErr != nil {
-			l.logger.Error("Failed to clean up symlink after error", removeErr, "path", targetPath)
+	if l.copyMode {
+		if err := copyTree(skillPath, targetPath); err != nil {
+			return &LinkError{
+				Type:    ErrorTypeFilesystem,
+				Message: "failed to copy skill",
+				Err:     err,
+			}
+		}
+	} else if err := os.Symlink(skillPath, targetPath); err != nil {
+		return &LinkError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to create symlink",
+			Err:     err,
 		}
-		return fmt.Errorf("failed to update skills registry: %w", err)
 	}
+	return nil
+}
 
-	l.logger.Info("Successfully linked skill", "skill", skillName, "path", targetPath)
+// sameStorePath reports whether storePath, as recorded in the registry, is
+// the absolute store directory skillPath.
+func sameStorePath(storePath, skillPath string) bool {
+	absPath, err := filepath.Abs(storePath)
+	return err == nil && absPath == skillPath
+}
+
+// findSkill returns the entry of the named skill in skills, or nil.
+func findSkill(skills []types.SkillMetadata, name string) *types.SkillMetadata {
+	for i := range skills {
+		if skills[i].Name == name {
+			return &skills[i]
+		}
+	}
 	return nil
 }
 
-// getSkillPath retrieves the absolute path to a gskills-managed skill directory.
-// Returns an error if the skill doesn't exist in ~/.gskills/skills/.
-func (l *Linker) getSkillPath(skillName string) (string, error) {
-	homeDir, err := os.UserHomeDir()
+// getSkillPath returns the registry entry of skillName in the registry at
+// registryPath and its absolute store directory, so skills stored outside the
+// default store (e.g. with add --store) can be linked. Returns an error if the
+// skill is not registered or its store directory does not exist.
+func (l *Linker) getSkillPath(registryPath, skillName string) (*types.SkillMetadata, string, error) {
+	skill, err := registry.FindSkillByNameWithPath(registryPath, skillName)
 	if err != nil {
-		return "", &LinkError{
-			Type:    ErrorTypeFilesystem,
-			Message: "failed to get home directory",
+		return nil, "", &LinkError{
+			Type:    ErrorTypeSkillNotFound,
+			Message: fmt.Sprintf("skill '%s' not found in registry", skillName),
 			Err:     err,
 		}
 	}
 
-	skillsDir := filepath.Join(homeDir, ".gskills", "skills", skillName)
+	skillsDir := skill.StorePath
 	exists, err := l.checkPathExists(skillsDir)
 	if err != nil {
-		return "", &LinkError{
+		return nil, "", &LinkError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to check skill directory",
 			Err:     err,
@@ -187,22 +394,22 @@ func (l *Linker) getSkillPath(skillName string) (string, error) {
 	}
 
 	if !exists {
-		return "", &LinkError{
+		return nil, "", &LinkError{
 			Type:    ErrorTypeSkillNotFound,
-			Message: fmt.Sprintf("skill '%s' not found in ~/.gskills/skills/", skillName),
+			Message: fmt.Sprintf("store directory of skill '%s' not found: %s", skillName, skillsDir),
 		}
 	}
 
 	absPath, err := filepath.Abs(skillsDir)
 	if err != nil {
-		return "", &LinkError{
+		return nil, "", &LinkError{
 			Type:    ErrorTypeFilesystem,
 			Message: "failed to get absolute skill path",
 			Err:     err,
 		}
 	}
 
-	return absPath, nil
+	return skill, absPath, nil
 }
 
 // validateProjectPath validates that the project path exists and is a directory.
@@ -247,7 +454,8 @@ func (l *Linker) checkPathExists(path string) (bool, error) {
 	return true, nil
 }
 
-// UnlinkSkill removes a symlink from a project and updates the registry.
+// UnlinkSkill removes a symlink, or a copy made with SetCopy, from a project
+// and updates the registry.
 // Returns an error if the skill is not found, not linked to the project,
 // or if the symlink removal fails.
 func (l *Linker) UnlinkSkill(skillName, projectPath string) error {
@@ -264,14 +472,11 @@ func (l *Linker) UnlinkSkill(skillName, projectPath string) error {
 		}
 	}
 
-	skill, err := registry.FindSkillByName(skillName)
+	dataDir, err := l.resolveDataDir()
 	if err != nil {
-		return &LinkError{
-			Type:    ErrorTypeSkillNotFound,
-			Message: fmt.Sprintf("skill '%s' not found in registry", skillName),
-			Err:     err,
-		}
+		return err
 	}
+	registryPath := paths.RegistryPath(dataDir)
 
 	absProjectPath, err := filepath.Abs(projectPath)
 	if err != nil {
@@ -282,40 +487,86 @@ func (l *Linker) UnlinkSkill(skillName, projectPath string) error {
 		}
 	}
 
-	if skill.LinkedProjects == nil {
-		return &LinkError{
-			Type:    ErrorTypeInvalidPath,
-			Message: fmt.Sprintf("skill '%s' is not linked to any projects", skillName),
+	// The link is looked up, removed and dropped from the registry in one
+	// transaction, so it cannot change between the lookup and the write.
+	var linkInfo types.LinkedProjectInfo
+	err = registry.UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		skill := findSkill(skills, skillName)
+		if skill == nil {
+			return nil, &LinkError{
+				Type:    ErrorTypeSkillNotFound,
+				Message: fmt.Sprintf("skill '%s' not found in registry", skillName),
+				Err:     registry.ErrSkillNotFound,
+			}
 		}
-	}
 
-	linkInfo, linked := skill.LinkedProjects[absProjectPath]
-	if !linked {
-		return &LinkError{
-			Type:    ErrorTypeInvalidPath,
-			Message: fmt.Sprintf("skill '%s' is not linked to project '%s'", skillName, absProjectPath),
+		if skill.LinkedProjects == nil {
+			return nil, &LinkError{
+				Type:    ErrorTypeInvalidPath,
+				Message: fmt.Sprintf("skill '%s' is not linked to any projects", skillName),
+			}
 		}
-	}
 
-	if err := os.Remove(linkInfo.SymlinkPath); err != nil {
-		return &LinkError{
-			Type:    ErrorTypeFilesystem,
-			Message: "failed to remove symlink",
-			Err:     err,
+		var linked bool
+		linkInfo, linked = skill.LinkedProjects[absProjectPath]
+		if !linked {
+			return nil, &LinkError{
+				Type:    ErrorTypeInvalidPath,
+				Message: fmt.Sprintf("skill '%s' is not linked to project '%s'", skillName, absProjectPath),
+			}
 		}
-	}
 
-	delete(skill.LinkedProjects, absProjectPath)
+		if err := RemoveLink(linkInfo); err != nil {
+			return nil, &LinkError{
+				Type:    ErrorTypeFilesystem,
+				Message: "failed to remove symlink",
+				Err:     err,
+			}
+		}
 
-	if len(skill.LinkedProjects) == 0 {
-		skill.LinkedProjects = nil
+		delete(skill.LinkedProjects, absProjectPath)
+		if len(skill.LinkedProjects) == 0 {
+			skill.LinkedProjects = nil
+		}
+		skill.UpdatedAt = time.Now()
+		return skills, nil
+	})
+	if err != nil {
+		var linkErr *LinkError
+		if errors.As(err, &linkErr) {
+			return err
+		}
+		return fmt.Errorf("failed to update skills registry: %w", err)
 	}
 
-	skill.UpdatedAt = time.Now()
-
-	if err := registry.UpdateSkill(skill); err != nil {
-		return fmt.Errorf("failed to update skills registry: %w", err)
+	if l.pruneDirs {
+		l.pruneEmptyDirs(filepath.Dir(linkInfo.SymlinkPath), absProjectPath)
 	}
 
 	return nil
 }
+
+// pruneEmptyDirs removes dir and then each of its parents while they are
+// empty, stopping before projectPath. Pruning is best effort: the first
+// directory that is not empty or cannot be removed ends it. A symlinked
+// directory, e.g. a .opencode shared between projects, is never removed.
+func (l *Linker) pruneEmptyDirs(dir, projectPath string) {
+	for {
+		rel, err := filepath.Rel(projectPath, dir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		if info, err := os.Lstat(dir); err == nil && !info.IsDir() {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			if !os.IsNotExist(err) {
+				l.logger.Debug("Keeping skills directory", "path", dir, "reason", err.Error())
+				return
+			}
+		} else {
+			l.logger.Debug("Removed empty skills directory", "path", dir)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -2,9 +2,12 @@ package link
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -82,6 +85,15 @@ func TestLinker_getSkillPath(t *testing.T) {
 		t.Fatalf("failed to create test skill directory: %v", err)
 	}
 
+	registryPath := filepath.Join(homeDir, ".gskills", "skills.json")
+	skills := []types.SkillMetadata{
+		{ID: "test-skill@main", Name: "test-skill", Version: "main", StorePath: testSkillDir},
+		{ID: "gone-skill@main", Name: "gone-skill", Version: "main", StorePath: filepath.Join(skillsDir, "gone-skill")},
+	}
+	if err := registry.SaveRegistryWithPath(registryPath, skills); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+
 	tests := []struct {
 		name          string
 		skillName     string
@@ -102,6 +114,13 @@ func TestLinker_getSkillPath(t *testing.T) {
 			wantErr:       true,
 			errorContains: "not found",
 		},
+		{
+			name:          "registered skill without store directory",
+			skillName:     "gone-skill",
+			setupFunc:     func() func() { return func() {} },
+			wantErr:       true,
+			errorContains: "not found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,7 +129,7 @@ func TestLinker_getSkillPath(t *testing.T) {
 			defer teardown()
 
 			linker := NewLinker()
-			path, err := linker.getSkillPath(tt.skillName)
+			_, path, err := linker.getSkillPath(registryPath, tt.skillName)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getSkillPath() error = %v, wantErr %v", err, tt.wantErr)
@@ -306,6 +325,450 @@ func TestLinker_LinkSkill_Integration(t *testing.T) {
 	os.Remove(targetPath)
 }
 
+func TestLinker_LinkSkill_CustomStorePath(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// The skill lives outside ~/.gskills/skills, e.g. added with --store.
+	storePath := filepath.Join(t.TempDir(), "elsewhere", "custom-skill")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("custom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "custom-skill@main",
+		Name:      "custom-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/custom",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := NewLinker().LinkSkill(context.Background(), "custom-skill", projectDir); err != nil {
+		t.Fatalf("LinkSkill() error = %v", err)
+	}
+
+	target, err := os.Readlink(filepath.Join(projectDir, ".opencode/skills", "custom-skill"))
+	if err != nil {
+		t.Fatalf("symlink not created: %v", err)
+	}
+	if target != storePath {
+		t.Errorf("symlink points to %s, want the recorded StorePath %s", target, storePath)
+	}
+}
+
+func TestLinker_LinkSkill_MissingProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	storePath := filepath.Join(t.TempDir(), "test-skill")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test-skill",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	parent := t.TempDir()
+	filePath := filepath.Join(parent, "not-a-dir")
+	if err := os.WriteFile(filePath, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		projectPath string
+	}{
+		{name: "nonexistent project", projectPath: filepath.Join(parent, "typo", "project")},
+		{name: "project is a file", projectPath: filePath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewLinker().LinkSkill(context.Background(), "test-skill", tt.projectPath)
+			var linkErr *LinkError
+			if !errors.As(err, &linkErr) || linkErr.Type != ErrorTypeInvalidPath {
+				t.Fatalf("LinkSkill() error = %v, want an invalid path error", err)
+			}
+
+			entries, err := os.ReadDir(parent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("LinkSkill() created directories under %s: %v", parent, entries)
+			}
+			skill, _ := registry.FindSkillByName("test-skill")
+			if len(skill.LinkedProjects) != 0 {
+				t.Errorf("LinkedProjects = %v, want no link recorded", skill.LinkedProjects)
+			}
+		})
+	}
+}
+
+func TestLinker_LinkSkillAs(t *testing.T) {
+	homeDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills", "my-prompt-engineer")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "my-prompt-engineer@main",
+		Name:      "my-prompt-engineer",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: skillsDir,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	linker := NewLinker()
+
+	for _, alias := range []string{"..", "a/b"} {
+		if err := linker.LinkSkillAs(context.Background(), "my-prompt-engineer", projectDir, alias); err == nil {
+			t.Errorf("LinkSkillAs() with alias %q should fail", alias)
+		}
+	}
+
+	if err := linker.LinkSkillAs(context.Background(), "my-prompt-engineer", projectDir, "pe"); err != nil {
+		t.Fatalf("LinkSkillAs() failed: %v", err)
+	}
+
+	aliasPath := filepath.Join(projectDir, ".opencode", "skills", "pe")
+	if target, err := os.Readlink(aliasPath); err != nil || target != skillsDir {
+		t.Errorf("symlink %s -> %s (%v), want -> %s", aliasPath, target, err, skillsDir)
+	}
+	if _, err := os.Lstat(filepath.Join(projectDir, ".opencode", "skills", "my-prompt-engineer")); !os.IsNotExist(err) {
+		t.Errorf("symlink should only be created under the alias: %v", err)
+	}
+
+	skill, err := registry.FindSkillByName("my-prompt-engineer")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	linkInfo := skill.LinkedProjects[projectDir]
+	if linkInfo.Alias != "pe" || linkInfo.SymlinkPath != aliasPath {
+		t.Errorf("recorded link = %+v, want alias pe at %s", linkInfo, aliasPath)
+	}
+
+	for _, alias := range []string{"", "prompts"} {
+		err := linker.LinkSkillAs(context.Background(), "my-prompt-engineer", projectDir, alias)
+		if !errors.Is(err, &LinkError{Type: ErrorTypeSymlinkExists}) {
+			t.Errorf("second LinkSkillAs() with alias %q error = %v, want ErrorTypeSymlinkExists", alias, err)
+		}
+	}
+	if skill, err := registry.FindSkillByName("my-prompt-engineer"); err != nil || skill.LinkedProjects[projectDir].SymlinkPath != aliasPath {
+		t.Errorf("recorded link after a second link = %+v (%v), want the first kept", skill, err)
+	}
+
+	if err := linker.UnlinkSkill("my-prompt-engineer", projectDir); err != nil {
+		t.Fatalf("UnlinkSkill() failed: %v", err)
+	}
+	if _, err := os.Lstat(aliasPath); !os.IsNotExist(err) {
+		t.Errorf("aliased symlink still exists after unlink: %v", err)
+	}
+	skill, err = registry.FindSkillByName("my-prompt-engineer")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	if _, linked := skill.LinkedProjects[projectDir]; linked {
+		t.Error("project still recorded as linked after unlink")
+	}
+}
+
+func TestLinker_UnlinkSkill_PrunesEmptyDirs(t *testing.T) {
+	homeDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	for _, name := range []string{"skill-a", "skill-b"} {
+		storePath := filepath.Join(homeDir, ".gskills", "skills", name)
+		if err := os.MkdirAll(storePath, 0755); err != nil {
+			t.Fatalf("failed to create test skill directory: %v", err)
+		}
+		if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			CommitSHA: "abc123",
+			SourceURL: "https://example.com/test",
+			StorePath: storePath,
+			UpdatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to add test skill to registry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		prune        bool
+		keepFile     bool
+		wantSkills   bool
+		wantOpencode bool
+	}{
+		{name: "removes empty dirs", prune: true},
+		{name: "keeps non-empty parent", prune: true, keepFile: true, wantOpencode: true},
+		{name: "no prune", prune: false, wantSkills: true, wantOpencode: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			opencodeDir := filepath.Join(projectDir, ".opencode")
+			skillsDir := filepath.Join(opencodeDir, "skills")
+			if tt.keepFile {
+				if err := os.MkdirAll(opencodeDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(opencodeDir, "config.json"), []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			linker := NewLinker()
+			linker.SetPruneEmptyDirs(tt.prune)
+			for _, name := range []string{"skill-a", "skill-b"} {
+				if err := linker.LinkSkill(context.Background(), name, projectDir); err != nil {
+					t.Fatalf("LinkSkill(%s) failed: %v", name, err)
+				}
+			}
+
+			if err := linker.UnlinkSkill("skill-a", projectDir); err != nil {
+				t.Fatalf("UnlinkSkill(skill-a) failed: %v", err)
+			}
+			if _, err := os.Stat(skillsDir); err != nil {
+				t.Fatalf("skills directory still holding skill-b was removed: %v", err)
+			}
+
+			if err := linker.UnlinkSkill("skill-b", projectDir); err != nil {
+				t.Fatalf("UnlinkSkill(skill-b) failed: %v", err)
+			}
+			if _, err := os.Stat(skillsDir); (err == nil) != tt.wantSkills {
+				t.Errorf("%s exists = %v, want %v", skillsDir, err == nil, tt.wantSkills)
+			}
+			if _, err := os.Stat(opencodeDir); (err == nil) != tt.wantOpencode {
+				t.Errorf("%s exists = %v, want %v", opencodeDir, err == nil, tt.wantOpencode)
+			}
+			if _, err := os.Stat(projectDir); err != nil {
+				t.Errorf("project directory must never be removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestLinker_LinkSkillCopy(t *testing.T) {
+	homeDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "copied-skill")
+	if err := os.MkdirAll(filepath.Join(storePath, "scripts"), 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# v1"), 0644)
+	os.WriteFile(filepath.Join(storePath, "scripts", "run.sh"), []byte("echo hi"), 0644)
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "copied-skill@main",
+		Name:      "copied-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	linker := NewLinker()
+	linker.SetCopy(true)
+	if err := linker.LinkSkill(context.Background(), "copied-skill", projectDir); err != nil {
+		t.Fatalf("LinkSkill() failed: %v", err)
+	}
+
+	copyPath := filepath.Join(projectDir, ".opencode", "skills", "copied-skill")
+	info, err := os.Lstat(copyPath)
+	if err != nil || !info.IsDir() {
+		t.Fatalf("%s should be a real directory, got %v (%v)", copyPath, info, err)
+	}
+	for _, name := range []string{"SKILL.md", filepath.Join("scripts", "run.sh")} {
+		if info, err := os.Lstat(filepath.Join(copyPath, name)); err != nil || !info.Mode().IsRegular() {
+			t.Errorf("%s should be a copied regular file: %v", name, err)
+		}
+	}
+
+	skill, err := registry.FindSkillByName("copied-skill")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	if !skill.LinkedProjects[projectDir].Copy {
+		t.Errorf("recorded link = %+v, want copy mode", skill.LinkedProjects[projectDir])
+	}
+
+	os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# v2"), 0644)
+	if err := RefreshCopies(skill); err != nil {
+		t.Fatalf("RefreshCopies() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(copyPath, "SKILL.md")); string(data) != "# v2" {
+		t.Errorf("copy not refreshed, SKILL.md = %q", data)
+	}
+
+	if err := linker.UnlinkSkill("copied-skill", projectDir); err != nil {
+		t.Fatalf("UnlinkSkill() failed: %v", err)
+	}
+	if _, err := os.Lstat(copyPath); !os.IsNotExist(err) {
+		t.Errorf("copy still exists after unlink: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storePath, "SKILL.md")); err != nil {
+		t.Errorf("unlinking a copy must not touch the store: %v", err)
+	}
+}
+
+func TestLinker_LinkSkill_Concurrent(t *testing.T) {
+	homeDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills", "test-skill")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+
+	testSkill := &types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: skillsDir,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(testSkill); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	const numProjects = 10
+	projects := make([]string, numProjects)
+	for i := range projects {
+		projects[i] = t.TempDir()
+	}
+
+	var wg sync.WaitGroup
+	for _, projectDir := range projects {
+		wg.Add(1)
+		go func(projectDir string) {
+			defer wg.Done()
+			if err := NewLinker().LinkSkill(context.Background(), "test-skill", projectDir); err != nil {
+				t.Errorf("LinkSkill(%s) failed: %v", projectDir, err)
+			}
+		}(projectDir)
+	}
+	wg.Wait()
+
+	skill, err := registry.FindSkillByName("test-skill")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	if len(skill.LinkedProjects) != numProjects {
+		t.Errorf("registry records %d linked projects, want %d", len(skill.LinkedProjects), numProjects)
+	}
+	for _, projectDir := range projects {
+		info, ok := skill.LinkedProjects[projectDir]
+		if !ok {
+			t.Errorf("project %s missing from LinkedProjects", projectDir)
+			continue
+		}
+		if info.CommitSHA != "abc123" || info.Version != "main" {
+			t.Errorf("project %s linked at %s@%s, want main@abc123", projectDir, info.Version, info.CommitSHA)
+		}
+	}
+}
+
+func TestLinker_LinkSkillAs_ConcurrentIntoSameProject(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills", "test-skill")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: skillsDir,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	aliases := []string{"a", "b", "c", "d", "e"}
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	for _, alias := range aliases {
+		wg.Add(1)
+		go func(alias string) {
+			defer wg.Done()
+			err := NewLinker().LinkSkillAs(context.Background(), "test-skill", projectDir, alias)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, &LinkError{Type: ErrorTypeSymlinkExists}):
+				t.Errorf("LinkSkillAs(%s) error = %v, want ErrorTypeSymlinkExists", alias, err)
+			}
+		}(alias)
+	}
+	wg.Wait()
+
+	if n := succeeded.Load(); n != 1 {
+		t.Fatalf("%d links succeeded, want exactly 1", n)
+	}
+	skill, err := registry.FindSkillByName("test-skill")
+	if err != nil {
+		t.Fatalf("failed to find skill: %v", err)
+	}
+	recorded := skill.LinkedProjects[projectDir].SymlinkPath
+	for _, alias := range aliases {
+		linkPath := filepath.Join(projectDir, ".opencode", "skills", alias)
+		_, statErr := os.Lstat(linkPath)
+		if exists := statErr == nil; exists != (linkPath == recorded) {
+			t.Errorf("symlink %s exists = %v, recorded link = %s", alias, exists, recorded)
+		}
+	}
+}
+
 func TestLinkError(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -386,7 +849,7 @@ func BenchmarkLinker_getSkillPath(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = linker.getSkillPath("test-skill")
+		_, _, _ = linker.getSkillPath(filepath.Join(homeDir, ".gskills"), "test-skill")
 	}
 }
 
@@ -0,0 +1,187 @@
+package link
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/constants"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// legacyLinkIDPrefix marks registry entries written by the old linker.
+// It recorded every link as a separate registry entry carrying the linked
+// skill's Name and the symlink location as StorePath, instead of adding the
+// project to the skill's LinkedProjects.
+const legacyLinkIDPrefix = "linked-"
+
+// MigrationReport summarizes a legacy link migration.
+type MigrationReport struct {
+	// Migrated is the number of legacy entries converted into LinkedProjects.
+	Migrated int
+	// Unresolved lists the IDs of legacy entries that were left in place
+	// because the linked skill is not installed or the symlink path is not
+	// inside a project's skills directory.
+	Unresolved []string
+}
+
+// IsLegacyLink reports whether skill is a link entry written by the old linker.
+func IsLegacyLink(skill types.SkillMetadata) bool {
+	return strings.HasPrefix(skill.ID, legacyLinkIDPrefix)
+}
+
+// CheckLegacyLinks returns the legacy link entries in the registry.
+func (l *Linker) CheckLegacyLinks() ([]types.SkillMetadata, error) {
+	skills, err := l.loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	var legacy []types.SkillMetadata
+	for _, skill := range skills {
+		if IsLegacyLink(skill) {
+			legacy = append(legacy, skill)
+		}
+	}
+	return legacy, nil
+}
+
+// updateRegistry applies the migration to the registry. It is a variable so
+// tests can inject a write failure.
+var updateRegistry = registry.UpdateWithPath
+
+// MigrateLegacyLinks converts legacy link entries into LinkedProjects of the
+// skills they link and removes them from the registry. Entries that cannot be
+// matched to an installed skill are reported as unresolved and kept.
+//
+// The new registry is computed in memory and written once in a single
+// registry transaction, so the migration is all-or-nothing: if the write
+// fails the registry is left as it was.
+func (l *Linker) MigrateLegacyLinks() (*MigrationReport, error) {
+	dataDir, err := l.resolveDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var report *MigrationReport
+	err = updateRegistry(paths.RegistryPath(dataDir), func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		var migrated []types.SkillMetadata
+		report, migrated = l.migrateLegacyLinks(skills)
+		if report.Migrated == 0 {
+			return nil, errNothingMigrated
+		}
+		return migrated, nil
+	})
+	if errors.Is(err, errNothingMigrated) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, &LinkError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to save migrated registry",
+			Err:     err,
+		}
+	}
+
+	l.logger.Info("Migrated legacy links", "count", report.Migrated)
+	return report, nil
+}
+
+// errNothingMigrated aborts the registry transaction of MigrateLegacyLinks
+// when there is nothing to write.
+var errNothingMigrated = errors.New("no legacy links to migrate")
+
+// migrateLegacyLinks folds the legacy link entries of skills into the
+// LinkedProjects of the skills they link and returns the report and the
+// skills without the migrated entries. skills is modified.
+func (l *Linker) migrateLegacyLinks(skills []types.SkillMetadata) (*MigrationReport, []types.SkillMetadata) {
+	installed := make(map[string]int)
+	for i := range skills {
+		if !IsLegacyLink(skills[i]) {
+			installed[skills[i].Name] = i
+		}
+	}
+
+	report := &MigrationReport{}
+	migrated := make(map[string]bool)
+	for _, entry := range skills {
+		if !IsLegacyLink(entry) {
+			continue
+		}
+
+		projectPath, ok := legacyProjectPath(entry.StorePath)
+		if !ok {
+			l.logger.Warn("Legacy link is not inside a project skills directory", "id", entry.ID, "path", entry.StorePath)
+			report.Unresolved = append(report.Unresolved, entry.ID)
+			continue
+		}
+
+		idx, ok := installed[entry.Name]
+		if !ok {
+			l.logger.Warn("Legacy link refers to a skill that is not installed", "id", entry.ID, "skill", entry.Name)
+			report.Unresolved = append(report.Unresolved, entry.ID)
+			continue
+		}
+
+		skill := &skills[idx]
+		if skill.LinkedProjects == nil {
+			skill.LinkedProjects = make(map[string]types.LinkedProjectInfo)
+		}
+		linkedAt := entry.UpdatedAt
+		if linkedAt.IsZero() {
+			linkedAt = time.Now()
+		}
+		skill.LinkedProjects[projectPath] = types.LinkedProjectInfo{
+			SymlinkPath: entry.StorePath,
+			LinkedAt:    linkedAt,
+		}
+
+		migrated[entry.ID] = true
+		l.logger.Debug("Migrating legacy link", "skill", entry.Name, "project", projectPath)
+	}
+
+	newSkills := make([]types.SkillMetadata, 0, len(skills)-len(migrated))
+	for _, skill := range skills {
+		if !migrated[skill.ID] {
+			newSkills = append(newSkills, skill)
+		}
+	}
+	report.Migrated = len(migrated)
+	return report, newSkills
+}
+
+// loadRegistry loads the registry of the configured data directory.
+func (l *Linker) loadRegistry() ([]types.SkillMetadata, error) {
+	dataDir, err := l.resolveDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := registry.LoadRegistryWithPath(paths.RegistryPath(dataDir))
+	if err != nil {
+		return nil, &LinkError{
+			Type:    ErrorTypeFilesystem,
+			Message: "failed to load skills registry",
+			Err:     err,
+		}
+	}
+	return skills, nil
+}
+
+// legacyProjectPath returns the project directory of a symlink located at
+// <project>/.opencode/skills/<name>.
+func legacyProjectPath(symlinkPath string) (string, bool) {
+	if symlinkPath == "" || !filepath.IsAbs(symlinkPath) {
+		return "", false
+	}
+
+	skillsDir := filepath.Dir(filepath.Clean(symlinkPath))
+	suffix := string(filepath.Separator) + filepath.FromSlash(constants.OpencodeSkillsDir)
+	if !strings.HasSuffix(skillsDir, suffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(skillsDir, suffix), true
+}
@@ -0,0 +1,207 @@
+package link
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// seedLegacyRegistry writes a registry holding the installed skill "golang"
+// plus legacy link entries and returns the data dir and the project paths.
+func seedLegacyRegistry(t *testing.T) (dataDir, project1, project2 string) {
+	t.Helper()
+	dataDir = t.TempDir()
+	projectsDir := t.TempDir()
+	project1 = filepath.Join(projectsDir, "p1")
+	project2 = filepath.Join(projectsDir, "p2")
+	linkedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "linked-golang-p1",
+			Name:      "golang",
+			StorePath: filepath.Join(project1, ".opencode", "skills", "golang"),
+			UpdatedAt: linkedAt,
+		},
+		{
+			ID:        "owner/repo/golang",
+			Name:      "golang",
+			StorePath: filepath.Join(paths.SkillsDir(dataDir), "golang"),
+			Version:   "main",
+			CommitSHA: "abc123",
+		},
+		{
+			ID:        "linked-golang-p2",
+			Name:      "golang",
+			StorePath: filepath.Join(project2, ".opencode", "skills", "golang"),
+			UpdatedAt: linkedAt,
+		},
+		{
+			ID:        "linked-missing-p1",
+			Name:      "missing",
+			StorePath: filepath.Join(project1, ".opencode", "skills", "missing"),
+		},
+		{
+			ID:        "linked-golang-elsewhere",
+			Name:      "golang",
+			StorePath: filepath.Join(projectsDir, "golang"),
+		},
+	}
+	if err := registry.SaveRegistryWithPath(paths.RegistryPath(dataDir), skills); err != nil {
+		t.Fatalf("failed to seed registry: %v", err)
+	}
+	return dataDir, project1, project2
+}
+
+func TestLinker_CheckLegacyLinks(t *testing.T) {
+	dataDir, _, _ := seedLegacyRegistry(t)
+
+	linker := NewLinker()
+	linker.SetDataDir(dataDir)
+
+	legacy, err := linker.CheckLegacyLinks()
+	if err != nil {
+		t.Fatalf("CheckLegacyLinks() error = %v", err)
+	}
+	if len(legacy) != 4 {
+		t.Errorf("CheckLegacyLinks() returned %d entries, want 4", len(legacy))
+	}
+}
+
+func TestLinker_MigrateLegacyLinks(t *testing.T) {
+	dataDir, project1, project2 := seedLegacyRegistry(t)
+	registryPath := paths.RegistryPath(dataDir)
+
+	linker := NewLinker()
+	linker.SetDataDir(dataDir)
+
+	report, err := linker.MigrateLegacyLinks()
+	if err != nil {
+		t.Fatalf("MigrateLegacyLinks() error = %v", err)
+	}
+
+	if report.Migrated != 2 {
+		t.Errorf("Migrated = %d, want 2", report.Migrated)
+	}
+	wantUnresolved := []string{"linked-missing-p1", "linked-golang-elsewhere"}
+	if !reflect.DeepEqual(report.Unresolved, wantUnresolved) {
+		t.Errorf("Unresolved = %v, want %v", report.Unresolved, wantUnresolved)
+	}
+
+	skills, err := registry.LoadRegistryWithPath(registryPath)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	var ids []string
+	for _, s := range skills {
+		ids = append(ids, s.ID)
+	}
+	wantIDs := []string{"owner/repo/golang", "linked-missing-p1", "linked-golang-elsewhere"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("registry IDs = %v, want %v", ids, wantIDs)
+	}
+
+	skill, err := registry.FindSkillByNameWithPath(registryPath, "golang")
+	if err != nil {
+		t.Fatalf("FindSkillByNameWithPath() error = %v", err)
+	}
+	for _, project := range []string{project1, project2} {
+		info, ok := skill.LinkedProjects[project]
+		if !ok {
+			t.Errorf("project %s not in LinkedProjects: %v", project, skill.LinkedProjects)
+			continue
+		}
+		if want := filepath.Join(project, ".opencode", "skills", "golang"); info.SymlinkPath != want {
+			t.Errorf("SymlinkPath = %s, want %s", info.SymlinkPath, want)
+		}
+		if info.LinkedAt.IsZero() {
+			t.Error("LinkedAt should carry over the legacy entry's time")
+		}
+	}
+
+	// A second run has nothing left to convert.
+	report, err = linker.MigrateLegacyLinks()
+	if err != nil {
+		t.Fatalf("second MigrateLegacyLinks() error = %v", err)
+	}
+	if report.Migrated != 0 {
+		t.Errorf("second run Migrated = %d, want 0", report.Migrated)
+	}
+}
+
+func TestLegacyProjectPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		symlinkPath string
+		want        string
+		wantOK      bool
+	}{
+		{name: "project skills dir", symlinkPath: "/home/u/proj/.opencode/skills/golang", want: "/home/u/proj", wantOK: true},
+		{name: "outside skills dir", symlinkPath: "/home/u/proj/golang", wantOK: false},
+		{name: "relative", symlinkPath: "proj/.opencode/skills/golang", wantOK: false},
+		{name: "empty", symlinkPath: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := legacyProjectPath(tt.symlinkPath)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("legacyProjectPath(%q) = %q, %v; want %q, %v", tt.symlinkPath, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLinker_MigrateLegacyLinks_FailureLeavesRegistryUnchanged(t *testing.T) {
+	dataDir, _, _ := seedLegacyRegistry(t)
+	registryPath := paths.RegistryPath(dataDir)
+
+	before, err := os.ReadFile(registryPath)
+	if err != nil {
+		t.Fatalf("failed to read registry: %v", err)
+	}
+
+	// The first legacy entry has already been folded in memory when the
+	// write fails.
+	oldUpdate := updateRegistry
+	updateRegistry = func(registryPath string, fn func([]types.SkillMetadata) ([]types.SkillMetadata, error)) error {
+		return oldUpdate(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+			if _, err := fn(skills); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("disk full")
+		})
+	}
+	defer func() { updateRegistry = oldUpdate }()
+
+	linker := NewLinker()
+	linker.SetDataDir(dataDir)
+
+	if _, err := linker.MigrateLegacyLinks(); err == nil {
+		t.Fatal("MigrateLegacyLinks() should fail when the registry cannot be written")
+	}
+
+	after, err := os.ReadFile(registryPath)
+	if err != nil {
+		t.Fatalf("failed to read registry: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("registry changed after a failed migration:\nbefore: %s\nafter: %s", before, after)
+	}
+
+	legacy, err := linker.CheckLegacyLinks()
+	if err != nil {
+		t.Fatalf("CheckLegacyLinks() error = %v", err)
+	}
+	if len(legacy) != 4 {
+		t.Errorf("CheckLegacyLinks() = %d entries after a failed migration, want 4", len(legacy))
+	}
+}
@@ -0,0 +1,56 @@
+package link
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/constants"
+)
+
+// frameworkTargets maps the marker directory of each supported agent
+// framework to its skills directory, in detection order.
+var frameworkTargets = []struct {
+	marker    string
+	skillsDir string
+}{
+	{marker: ".opencode", skillsDir: constants.OpencodeSkillsDir},
+	{marker: ".claude", skillsDir: constants.ClaudeSkillsDir},
+	{marker: ".cursor", skillsDir: constants.CursorSkillsDir},
+}
+
+// DetectTargetDir returns the skills directory, relative to projectPath, of
+// the agent framework the project uses. Frameworks are recognized by their
+// marker directory (.opencode, .claude or .cursor) and probed in that order;
+// a project without any marker gets .opencode/skills.
+func DetectTargetDir(projectPath string) string {
+	for _, target := range frameworkTargets {
+		info, err := os.Stat(filepath.Join(projectPath, target.marker))
+		if err == nil && info.IsDir() {
+			return target.skillsDir
+		}
+	}
+	return constants.OpencodeSkillsDir
+}
+
+// SkillsDirs returns the skills directories, relative to a project, of every
+// supported agent framework, in detection order.
+func SkillsDirs() []string {
+	dirs := make([]string, 0, len(frameworkTargets))
+	for _, target := range frameworkTargets {
+		dirs = append(dirs, target.skillsDir)
+	}
+	return dirs
+}
+
+// validateTargetDir checks that dir is a relative path that stays inside
+// the project.
+func validateTargetDir(dir string) error {
+	clean := filepath.Clean(dir)
+	if filepath.IsAbs(dir) || clean == "." || clean == ".." ||
+		strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("target directory '%s' must be a relative path inside the project", dir)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package link
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestDetectTargetDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers []string
+		want    string
+	}{
+		{name: "no marker", want: ".opencode/skills"},
+		{name: "opencode", markers: []string{".opencode"}, want: ".opencode/skills"},
+		{name: "claude", markers: []string{".claude"}, want: ".claude/skills"},
+		{name: "cursor", markers: []string{".cursor"}, want: ".cursor/skills"},
+		{name: "opencode wins over claude", markers: []string{".claude", ".opencode"}, want: ".opencode/skills"},
+		{name: "claude wins over cursor", markers: []string{".cursor", ".claude"}, want: ".claude/skills"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for _, marker := range tt.markers {
+				if err := os.Mkdir(filepath.Join(projectDir, marker), 0755); err != nil {
+					t.Fatalf("failed to create marker: %v", err)
+				}
+			}
+
+			if got := DetectTargetDir(projectDir); got != tt.want {
+				t.Errorf("DetectTargetDir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinker_LinkSkill_TargetDir(t *testing.T) {
+	homeDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills", "test-skill")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/test",
+		StorePath: skillsDir,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		targetDir string
+		want      string
+		wantErr   bool
+	}{
+		{name: "detected from .claude", want: ".claude/skills"},
+		{name: "explicit target", targetDir: "agents/skills", want: "agents/skills"},
+		{name: "absolute target", targetDir: "/tmp/skills", wantErr: true},
+		{name: "target outside project", targetDir: "../skills", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(projectDir, ".claude"), 0755); err != nil {
+				t.Fatalf("failed to create marker: %v", err)
+			}
+
+			linker := NewLinker()
+			linker.SetTargetDir(tt.targetDir)
+			err := linker.LinkSkill(context.Background(), "test-skill", projectDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LinkSkill() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			targetPath := filepath.Join(projectDir, tt.want, "test-skill")
+			if target, err := os.Readlink(targetPath); err != nil || target != skillsDir {
+				t.Errorf("symlink %s -> %s (%v), want -> %s", targetPath, target, err, skillsDir)
+			}
+
+			skill, err := registry.FindSkillByName("test-skill")
+			if err != nil {
+				t.Fatalf("failed to find skill: %v", err)
+			}
+			if got := skill.LinkedProjects[projectDir].SymlinkPath; got != targetPath {
+				t.Errorf("recorded SymlinkPath = %s, want %s", got, targetPath)
+			}
+		})
+	}
+}
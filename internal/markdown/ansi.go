@@ -0,0 +1,81 @@
+// Package markdown renders the Markdown of skill manifests for a terminal.
+// It understands the subset skills commonly use: YAML front matter,
+// headings, lists, block quotes, fenced code and inline code, bold text and
+// links. Everything else is passed through unchanged.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ANSI escape sequences used by RenderANSI.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiUnderline = "\x1b[4m"
+	ansiCyan      = "\x1b[36m"
+)
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletPattern     = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+	boldPattern       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// RenderANSI renders src with ANSI styles for display in a terminal.
+func RenderANSI(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	inFrontMatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inFrontMatter:
+			out = append(out, ansiDim+line+ansiReset)
+			if i > 0 && trimmed == "---" {
+				inFrontMatter = false
+			}
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+			out = append(out, ansiDim+line+ansiReset)
+		case inCode:
+			out = append(out, ansiCyan+"    "+line+ansiReset)
+		default:
+			out = append(out, renderLine(line))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderLine styles a single line outside code blocks and front matter.
+func renderLine(line string) string {
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		style := ansiBold
+		if len(m[1]) == 1 {
+			style += ansiUnderline
+		}
+		return style + m[2] + ansiReset
+	}
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		return m[1] + "  • " + renderInline(m[2])
+	}
+	if rest, ok := strings.CutPrefix(strings.TrimLeft(line, " "), ">"); ok {
+		return ansiDim + "│ " + ansiReset + renderInline(strings.TrimPrefix(rest, " "))
+	}
+	return renderInline(line)
+}
+
+// renderInline styles inline code, bold text and links.
+func renderInline(s string) string {
+	s = inlineCodePattern.ReplaceAllString(s, ansiCyan+"$1"+ansiReset)
+	s = boldPattern.ReplaceAllString(s, ansiBold+"$1$2"+ansiReset)
+	s = linkPattern.ReplaceAllString(s, ansiUnderline+"$1"+ansiReset+" "+ansiDim+"($2)"+ansiReset)
+	return s
+}
@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "top-level heading is bold and underlined",
+			src:  "# Prompt Engineer",
+			want: ansiBold + ansiUnderline + "Prompt Engineer" + ansiReset,
+		},
+		{
+			name: "sub-heading is bold",
+			src:  "## Usage ##",
+			want: ansiBold + "Usage" + ansiReset,
+		},
+		{
+			name: "bullets",
+			src:  "- first\n  * nested",
+			want: "  • first\n    • nested",
+		},
+		{
+			name: "inline code and bold",
+			src:  "Run `gskills add` **first**",
+			want: "Run " + ansiCyan + "gskills add" + ansiReset + " " + ansiBold + "first" + ansiReset,
+		},
+		{
+			name: "links show their target",
+			src:  "See [docs](https://example.com)",
+			want: "See " + ansiUnderline + "docs" + ansiReset + " " + ansiDim + "(https://example.com)" + ansiReset,
+		},
+		{
+			name: "block quote",
+			src:  "> note",
+			want: ansiDim + "│ " + ansiReset + "note",
+		},
+		{
+			name: "code blocks are not styled inline",
+			src:  "```\n# not a heading\n```",
+			want: ansiDim + "```" + ansiReset + "\n" + ansiCyan + "    # not a heading" + ansiReset + "\n" + ansiDim + "```" + ansiReset,
+		},
+		{
+			name: "plain text is unchanged",
+			src:  "just text",
+			want: "just text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderANSI(tt.src); got != tt.want {
+				t.Errorf("RenderANSI(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderANSI_FrontMatter(t *testing.T) {
+	src := "---\nname: test\n---\n# Title"
+	got := strings.Split(RenderANSI(src), "\n")
+	if len(got) != 4 {
+		t.Fatalf("RenderANSI() returned %d lines, want 4", len(got))
+	}
+	for i := 0; i < 3; i++ {
+		if !strings.HasPrefix(got[i], ansiDim) {
+			t.Errorf("front matter line %d = %q, want dimmed", i, got[i])
+		}
+	}
+	if got[3] != ansiBold+ansiUnderline+"Title"+ansiReset {
+		t.Errorf("line after front matter = %q, want a heading", got[3])
+	}
+}
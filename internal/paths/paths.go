@@ -0,0 +1,193 @@
+// Package paths resolves the on-disk locations used by gskills: the data
+// directory, the config directory, the skills registry file, the config
+// file, the skills store, the operation history log and the state of an
+// interrupted batch add.
+//
+// Everything lives in ~/.gskills by default. When the XDG base directory
+// variables are set and ~/.gskills does not exist, data goes to
+// $XDG_DATA_HOME/gskills and the config file to $XDG_CONFIG_HOME/gskills.
+// An existing ~/.gskills keeps being used until it is migrated; for the
+// config file, only an existing ~/.gskills/config.json counts.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// HomeEnv names the environment variable that overrides the data directory.
+	HomeEnv = "GSKILLS_HOME"
+	// XDGDataHomeEnv names the XDG base directory variable for user data.
+	XDGDataHomeEnv = "XDG_DATA_HOME"
+	// XDGConfigHomeEnv names the XDG base directory variable for user config.
+	XDGConfigHomeEnv = "XDG_CONFIG_HOME"
+	// dataDirName is the name of the data directory inside the user's home.
+	dataDirName = ".gskills"
+	// xdgDirName is the name of the gskills directory inside the XDG base directories.
+	xdgDirName = "gskills"
+	// registryFileName is the name of the skills registry inside the data directory.
+	registryFileName = "skills.json"
+	// configFileName is the name of the config file inside the config directory.
+	configFileName = "config.json"
+	// skillsDirName is the name of the skills store inside the data directory.
+	skillsDirName = "skills"
+	// historyFileName is the name of the operation history inside the data directory.
+	historyFileName = "history.log"
+	// batchFileName is the name of the interrupted batch add state inside the data directory.
+	batchFileName = "add-batch.json"
+)
+
+// DataDir returns the gskills data directory: $GSKILLS_HOME when set,
+// otherwise ~/.gskills if it exists, otherwise $XDG_DATA_HOME/gskills if
+// XDG_DATA_HOME is set, otherwise ~/.gskills.
+func DataDir() (string, error) {
+	return resolveDir("", XDGDataDir)
+}
+
+// ResolveDataDir returns dataDir, or DataDir when dataDir is empty. It is used
+// by the components that can be pointed at a data directory other than the
+// default one.
+func ResolveDataDir(dataDir string) (string, error) {
+	if dataDir != "" {
+		return dataDir, nil
+	}
+	return DataDir()
+}
+
+// ConfigDir returns the directory holding the config file. It is resolved
+// like DataDir, with $XDG_CONFIG_HOME/gskills in place of the XDG data
+// directory, and is the data directory when XDG_CONFIG_HOME is not set.
+// ~/.gskills is only kept when it already holds a config file: it may exist
+// just for data, and switching to it would lose the config written to the
+// XDG directory.
+func ConfigDir() (string, error) {
+	return resolveDir(configFileName, func() (string, bool, error) {
+		if dir, ok, err := XDGConfigDir(); ok || err != nil {
+			return dir, ok, err
+		}
+		return XDGDataDir()
+	})
+}
+
+// LegacyDataDir returns ~/.gskills, the data directory used before XDG
+// support and whenever no XDG variable is set.
+func LegacyDataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, dataDirName), nil
+}
+
+// XDGDataDir returns $XDG_DATA_HOME/gskills and whether XDG_DATA_HOME is set.
+func XDGDataDir() (string, bool, error) {
+	return xdgDir(XDGDataHomeEnv)
+}
+
+// XDGConfigDir returns $XDG_CONFIG_HOME/gskills and whether XDG_CONFIG_HOME
+// is set.
+func XDGConfigDir() (string, bool, error) {
+	return xdgDir(XDGConfigHomeEnv)
+}
+
+// xdgDir returns the gskills directory inside the base directory named by
+// env, and whether env is set.
+func xdgDir(env string) (string, bool, error) {
+	base := os.Getenv(env)
+	if base == "" {
+		return "", false, nil
+	}
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve %s: %w", env, err)
+	}
+	return filepath.Join(absBase, xdgDirName), true, nil
+}
+
+// resolveDir applies the GSKILLS_HOME, ~/.gskills and XDG precedence, with
+// xdg returning the XDG directory to use if any. ~/.gskills takes precedence
+// over XDG when legacyEntry exists inside it, or when it exists at all if
+// legacyEntry is empty.
+func resolveDir(legacyEntry string, xdg func() (string, bool, error)) (string, error) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", HomeEnv, err)
+		}
+		return absDir, nil
+	}
+
+	legacyDir, err := LegacyDataDir()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, legacyEntry)); err == nil {
+		return legacyDir, nil
+	}
+
+	dir, ok, err := xdg()
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return dir, nil
+	}
+	return legacyDir, nil
+}
+
+// registryOverride replaces the default registry location when set by
+// SetRegistryPath.
+var registryOverride string
+
+// SetRegistryPath makes RegistryPath return path instead of the registry
+// inside the data directory, so that separate skill sets can be kept in
+// separate registry files. An empty path restores the default.
+func SetRegistryPath(path string) error {
+	if path == "" {
+		registryOverride = ""
+		return nil
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve registry path: %w", err)
+	}
+	registryOverride = absPath
+	return nil
+}
+
+// RegistryOverridden reports whether SetRegistryPath replaced the default
+// registry location.
+func RegistryOverridden() bool {
+	return registryOverride != ""
+}
+
+// RegistryPath returns the path of the skills registry inside dataDir, or
+// the path set by SetRegistryPath.
+func RegistryPath(dataDir string) string {
+	if registryOverride != "" {
+		return registryOverride
+	}
+	return filepath.Join(dataDir, registryFileName)
+}
+
+// ConfigPath returns the path of the config file inside configDir.
+func ConfigPath(configDir string) string {
+	return filepath.Join(configDir, configFileName)
+}
+
+// SkillsDir returns the path of the skills store inside dataDir.
+func SkillsDir(dataDir string) string {
+	return filepath.Join(dataDir, skillsDirName)
+}
+
+// HistoryPath returns the path of the operation history log inside dataDir.
+func HistoryPath(dataDir string) string {
+	return filepath.Join(dataDir, historyFileName)
+}
+
+// BatchPath returns the path of the interrupted batch add state inside dataDir.
+func BatchPath(dataDir string) string {
+	return filepath.Join(dataDir, batchFileName)
+}
@@ -0,0 +1,88 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataDirAndConfigDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		gskillsHome bool
+		legacy      bool
+		// legacyConfig also creates ~/.gskills/config.json.
+		legacyConfig bool
+		xdgData      bool
+		xdgConfig    bool
+		wantData     string
+		wantConfig   string
+	}{
+		{name: "default", wantData: "home/.gskills", wantConfig: "home/.gskills"},
+		{name: "XDG data and config", xdgData: true, xdgConfig: true, wantData: "data/gskills", wantConfig: "config/gskills"},
+		{name: "XDG data only", xdgData: true, wantData: "data/gskills", wantConfig: "data/gskills"},
+		{name: "XDG config only", xdgConfig: true, wantData: "home/.gskills", wantConfig: "config/gskills"},
+		{name: "existing ~/.gskills wins over XDG", legacy: true, legacyConfig: true, xdgData: true, xdgConfig: true, wantData: "home/.gskills", wantConfig: "home/.gskills"},
+		{name: "~/.gskills without config keeps XDG config", legacy: true, xdgConfig: true, wantData: "home/.gskills", wantConfig: "config/gskills"},
+		{name: "~/.gskills without config keeps XDG data config", legacy: true, xdgData: true, wantData: "home/.gskills", wantConfig: "data/gskills"},
+		{name: "GSKILLS_HOME wins over everything", gskillsHome: true, legacy: true, legacyConfig: true, xdgData: true, xdgConfig: true, wantData: "custom", wantConfig: "custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("HOME", filepath.Join(root, "home"))
+			t.Setenv(HomeEnv, "")
+			t.Setenv(XDGDataHomeEnv, "")
+			t.Setenv(XDGConfigHomeEnv, "")
+
+			if tt.gskillsHome {
+				t.Setenv(HomeEnv, filepath.Join(root, "custom"))
+			}
+			if tt.legacy {
+				if err := os.MkdirAll(filepath.Join(root, "home", ".gskills"), 0755); err != nil {
+					t.Fatalf("failed to create legacy directory: %v", err)
+				}
+			}
+			if tt.legacyConfig {
+				if err := os.WriteFile(filepath.Join(root, "home", ".gskills", "config.json"), []byte("{}"), 0600); err != nil {
+					t.Fatalf("failed to create legacy config: %v", err)
+				}
+			}
+			if tt.xdgData {
+				t.Setenv(XDGDataHomeEnv, filepath.Join(root, "data"))
+			}
+			if tt.xdgConfig {
+				t.Setenv(XDGConfigHomeEnv, filepath.Join(root, "config"))
+			}
+
+			dataDir, err := DataDir()
+			if err != nil {
+				t.Fatalf("DataDir() error = %v", err)
+			}
+			if want := filepath.Join(root, tt.wantData); dataDir != want {
+				t.Errorf("DataDir() = %s, want %s", dataDir, want)
+			}
+
+			configDir, err := ConfigDir()
+			if err != nil {
+				t.Fatalf("ConfigDir() error = %v", err)
+			}
+			if want := filepath.Join(root, tt.wantConfig); configDir != want {
+				t.Errorf("ConfigDir() = %s, want %s", configDir, want)
+			}
+		})
+	}
+}
+
+func TestResolveDataDir(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(HomeEnv, filepath.Join(root, "default"))
+
+	if got, err := ResolveDataDir(filepath.Join(root, "custom")); err != nil || got != filepath.Join(root, "custom") {
+		t.Errorf("ResolveDataDir(custom) = %s, %v, want the given directory", got, err)
+	}
+	if got, err := ResolveDataDir(""); err != nil || got != filepath.Join(root, "default") {
+		t.Errorf("ResolveDataDir(\"\") = %s, %v, want the default data directory", got, err)
+	}
+}
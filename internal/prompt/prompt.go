@@ -0,0 +1,45 @@
+// Package prompt asks the user yes/no questions on the terminal without
+// blocking when gskills runs in a pipeline or CI job.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// IsInteractive reports whether stdin is a terminal. It is a variable so
+// tests can simulate a terminal while feeding answers through a pipe.
+var IsInteractive = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Confirm prints question followed by " [y/N]: " and reports whether the user
+// answered y or yes. When stdin is not a terminal nobody can answer, so it
+// returns the safe default false without reading stdin.
+func Confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N]: ", question)
+
+	if !IsInteractive() {
+		fmt.Println("N (stdin is not a terminal)")
+		return false, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read user input: %w", err)
+	}
+	return IsYes(line), nil
+}
+
+// IsYes reports whether answer is y or yes, ignoring case and surrounding
+// whitespace.
+func IsYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
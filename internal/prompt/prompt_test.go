@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// withStdin replaces stdin with a pipe holding input. The write end is left
+// open when keepOpen is set, so a read blocks until the test ends.
+func withStdin(t *testing.T, input string, keepOpen bool) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	oldStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = oldStdin
+		r.Close()
+		w.Close()
+	})
+
+	w.WriteString(input)
+	if !keepOpen {
+		w.Close()
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "y", input: "y\n", want: true},
+		{name: "YES with spaces", input: "  YES \n", want: true},
+		{name: "no", input: "n\n", want: false},
+		{name: "empty", input: "\n", want: false},
+		{name: "several words", input: "yes please\n", want: false},
+		{name: "EOF without newline", input: "y", want: true},
+		{name: "closed stdin", input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input, false)
+			oldIsInteractive := IsInteractive
+			IsInteractive = func() bool { return true }
+			defer func() { IsInteractive = oldIsInteractive }()
+
+			got, err := Confirm("Proceed?")
+			if err != nil {
+				t.Fatalf("Confirm() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirm_NonInteractiveDoesNotBlock(t *testing.T) {
+	// A pipe is not a terminal. Its write end stays open, so reading from
+	// it would block forever.
+	withStdin(t, "", true)
+	if IsInteractive() {
+		t.Fatal("IsInteractive() = true for a pipe")
+	}
+
+	done := make(chan bool, 1)
+	go func() {
+		got, _ := Confirm("Proceed?")
+		done <- got
+	}()
+
+	select {
+	case got := <-done:
+		if got {
+			t.Error("Confirm() = true without a terminal, want the default false")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Confirm() blocked on a non-interactive stdin")
+	}
+}
@@ -2,27 +2,46 @@ package registry
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/sourceurl"
 	"github.com/smy-101/gskills/internal/types"
 )
 
-const skillsRegistryFile = "skills.json"
-
 var (
 	registryMutexes sync.Map
 )
 
+// ErrSkillNotFound is matched by errors.Is for lookups of a skill name that
+// is not in the registry.
+var ErrSkillNotFound = errors.New("skill not found in registry")
+
+// notFoundError reports a missing skill by name and matches ErrSkillNotFound.
+type notFoundError struct {
+	name string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("skill '%s' not found in registry", e.name)
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrSkillNotFound
+}
+
 func getRegistryPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	dataDir, err := paths.DataDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	return filepath.Join(homeDir, ".gskills", skillsRegistryFile), nil
+	return paths.RegistryPath(dataDir), nil
 }
 
 func LoadRegistry() ([]types.SkillMetadata, error) {
@@ -31,10 +50,12 @@ func LoadRegistry() ([]types.SkillMetadata, error) {
 		return nil, err
 	}
 
-	return loadRegistryWithPath(registryPath)
+	return LoadRegistryWithPath(registryPath)
 }
 
-func loadRegistryWithPath(registryPath string) ([]types.SkillMetadata, error) {
+// LoadRegistryWithPath loads the skills registry stored at registryPath.
+// A missing registry file yields an empty slice.
+func LoadRegistryWithPath(registryPath string) ([]types.SkillMetadata, error) {
 	data, err := os.ReadFile(registryPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -84,6 +105,44 @@ func SaveRegistryWithPath(registryPath string, skills []types.SkillMetadata) err
 	return nil
 }
 
+// Update applies fn to the default registry as a single transaction. See
+// UpdateWithPath.
+func Update(fn func(skills []types.SkillMetadata) ([]types.SkillMetadata, error)) error {
+	registryPath, err := getRegistryPath()
+	if err != nil {
+		return err
+	}
+
+	return UpdateWithPath(registryPath, fn)
+}
+
+// UpdateWithPath loads the registry at registryPath, passes its skills to fn
+// and saves the skills fn returns, all under the registry lock, so a change
+// made of several steps is not interleaved with other registry writes of
+// this process. fn may modify and return the slice it is given. When fn
+// returns an error nothing is saved and that error is returned.
+func UpdateWithPath(registryPath string, fn func(skills []types.SkillMetadata) ([]types.SkillMetadata, error)) error {
+	muIface, _ := registryMutexes.LoadOrStore(registryPath, &sync.Mutex{})
+	mu, ok := muIface.(*sync.Mutex)
+	if !ok {
+		return fmt.Errorf("failed to get mutex for registry path")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	skills, err := LoadRegistryWithPath(registryPath)
+	if err != nil {
+		return err
+	}
+
+	skills, err = fn(skills)
+	if err != nil {
+		return err
+	}
+
+	return SaveRegistryWithPath(registryPath, skills)
+}
+
 func validateSkillMetadata(skill *types.SkillMetadata) error {
 	if skill == nil {
 		return fmt.Errorf("skill metadata cannot be nil")
@@ -119,39 +178,49 @@ func AddOrUpdateSkill(skill *types.SkillMetadata) error {
 		return err
 	}
 
-	return addOrUpdateSkillWithPath(registryPath, skill)
+	return AddOrUpdateSkillWithPath(registryPath, skill)
 }
 
-func addOrUpdateSkillWithPath(registryPath string, skill *types.SkillMetadata) error {
+// AddOrUpdateSkillWithPath adds skill to the registry at registryPath, or
+// replaces the entry with the same ID. An entry with the same name whose
+// source URL has the same canonical form is the same skill and is replaced
+// too, so equivalent URL spellings never leave duplicates. The source URL is
+// stored in canonical form.
+func AddOrUpdateSkillWithPath(registryPath string, skill *types.SkillMetadata) error {
 	if err := validateSkillMetadata(skill); err != nil {
 		return err
 	}
+	canonical := *skill
+	canonical.SourceURL = sourceurl.Canonicalize(skill.SourceURL)
+	skill = &canonical
 
-	muIface, _ := registryMutexes.LoadOrStore(registryPath, &sync.Mutex{})
-	mu, ok := muIface.(*sync.Mutex)
-	if !ok {
-		return fmt.Errorf("failed to get mutex for registry path")
-	}
-	mu.Lock()
-	defer mu.Unlock()
-
-	skills, err := loadRegistryWithPath(registryPath)
-	if err != nil {
-		return err
-	}
+	return UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		return replaceSkill(skills, skill), nil
+	})
+}
 
-	indexMap := make(map[string]int, len(skills))
-	for i, s := range skills {
-		indexMap[s.ID] = i
+// replaceSkill returns skills with skill added, or replacing the entries of
+// the same skill as described for AddOrUpdateSkillWithPath.
+func replaceSkill(skills []types.SkillMetadata, skill *types.SkillMetadata) []types.SkillMetadata {
+	kept := skills[:0]
+	replaced := false
+	for _, s := range skills {
+		sameSkill := s.ID == skill.ID ||
+			(s.Name == skill.Name && sourceurl.Canonicalize(s.SourceURL) == skill.SourceURL)
+		switch {
+		case sameSkill && !replaced:
+			kept = append(kept, *skill)
+			replaced = true
+		case sameSkill:
+			// A second entry of the same skill is a duplicate.
+		default:
+			kept = append(kept, s)
+		}
 	}
-
-	if idx, exists := indexMap[skill.ID]; exists {
-		skills[idx] = *skill
-	} else {
-		skills = append(skills, *skill)
+	if !replaced {
+		kept = append(kept, *skill)
 	}
-
-	return SaveRegistryWithPath(registryPath, skills)
+	return kept
 }
 
 func RemoveSkill(skillID string) error {
@@ -164,43 +233,46 @@ func RemoveSkill(skillID string) error {
 		return err
 	}
 
-	return removeSkillWithPath(registryPath, skillID)
+	return RemoveSkillWithPath(registryPath, skillID)
 }
 
-func removeSkillWithPath(registryPath string, skillID string) error {
+// RemoveSkillWithPath removes the skill with skillID from the registry at registryPath.
+func RemoveSkillWithPath(registryPath string, skillID string) error {
 	if skillID == "" {
 		return fmt.Errorf("skill ID cannot be empty")
 	}
 
-	muIface, _ := registryMutexes.LoadOrStore(registryPath, &sync.Mutex{})
-	mu, ok := muIface.(*sync.Mutex)
-	if !ok {
-		return fmt.Errorf("failed to get mutex for registry path")
-	}
-	mu.Lock()
-	defer mu.Unlock()
+	return UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		newSkills := make([]types.SkillMetadata, 0, len(skills))
+		for _, s := range skills {
+			if s.ID != skillID {
+				newSkills = append(newSkills, s)
+			}
+		}
+		return newSkills, nil
+	})
+}
 
-	skills, err := loadRegistryWithPath(registryPath)
-	if err != nil {
-		return err
+func FindSkillByName(name string) (*types.SkillMetadata, error) {
+	if name == "" {
+		return nil, fmt.Errorf("skill name cannot be empty")
 	}
 
-	newSkills := make([]types.SkillMetadata, 0, len(skills))
-	for _, s := range skills {
-		if s.ID != skillID {
-			newSkills = append(newSkills, s)
-		}
+	registryPath, err := getRegistryPath()
+	if err != nil {
+		return nil, err
 	}
 
-	return SaveRegistryWithPath(registryPath, newSkills)
+	return FindSkillByNameWithPath(registryPath, name)
 }
 
-func FindSkillByName(name string) (*types.SkillMetadata, error) {
+// FindSkillByNameWithPath looks up a skill by name in the registry at registryPath.
+func FindSkillByNameWithPath(registryPath string, name string) (*types.SkillMetadata, error) {
 	if name == "" {
 		return nil, fmt.Errorf("skill name cannot be empty")
 	}
 
-	skills, err := LoadRegistry()
+	skills, err := LoadRegistryWithPath(registryPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
@@ -211,7 +283,7 @@ func FindSkillByName(name string) (*types.SkillMetadata, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("skill '%s' not found in registry", name)
+	return nil, &notFoundError{name: name}
 }
 
 func UpdateSkill(skill *types.SkillMetadata) error {
@@ -227,31 +299,97 @@ func UpdateSkill(skill *types.SkillMetadata) error {
 		return err
 	}
 
-	muIface, _ := registryMutexes.LoadOrStore(registryPath, &sync.Mutex{})
-	mu, ok := muIface.(*sync.Mutex)
-	if !ok {
-		return fmt.Errorf("failed to get mutex for registry path")
+	return UpdateSkillWithPath(registryPath, skill)
+}
+
+// UpdateSkillWithPath replaces an existing skill entry in the registry at
+// registryPath. Returns an error if no skill with the same ID exists.
+func UpdateSkillWithPath(registryPath string, skill *types.SkillMetadata) error {
+	if skill == nil {
+		return fmt.Errorf("skill cannot be nil")
 	}
-	mu.Lock()
-	defer mu.Unlock()
+	if skill.ID == "" {
+		return fmt.Errorf("skill ID cannot be empty")
+	}
+
+	return UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		for i := range skills {
+			if skills[i].ID == skill.ID {
+				skills[i] = *skill
+				return skills, nil
+			}
+		}
+		return nil, fmt.Errorf("skill with ID '%s' not found", skill.ID)
+	})
+}
 
-	skills, err := loadRegistryWithPath(registryPath)
+// AddLinkedProject records that skillName is linked into projectPath in the
+// default registry. See AddLinkedProjectWithPath.
+func AddLinkedProject(skillName, projectPath string, info types.LinkedProjectInfo) error {
+	registryPath, err := getRegistryPath()
 	if err != nil {
 		return err
 	}
 
-	found := false
-	for i := range skills {
-		if skills[i].ID == skill.ID {
-			skills[i] = *skill
-			found = true
-			break
-		}
+	return AddLinkedProjectWithPath(registryPath, skillName, projectPath, info)
+}
+
+// AddLinkedProjectWithPath records info as the link of skillName into
+// projectPath in the registry at registryPath. The entry is read, changed and
+// saved under the registry lock, so concurrent links of the same skill do not
+// overwrite each other. An empty Version or CommitSHA in info is filled from
+// the skill's current entry. The skill's UpdatedAt is set to info.LinkedAt.
+func AddLinkedProjectWithPath(registryPath, skillName, projectPath string, info types.LinkedProjectInfo) error {
+	if skillName == "" {
+		return fmt.Errorf("skill name cannot be empty")
 	}
+	if projectPath == "" {
+		return fmt.Errorf("project path cannot be empty")
+	}
+
+	return UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		for i := range skills {
+			if skills[i].Name != skillName {
+				continue
+			}
+			if info.Version == "" {
+				info.Version = skills[i].Version
+			}
+			if info.CommitSHA == "" {
+				info.CommitSHA = skills[i].CommitSHA
+			}
+			if skills[i].LinkedProjects == nil {
+				skills[i].LinkedProjects = make(map[string]types.LinkedProjectInfo)
+			}
+			skills[i].LinkedProjects[projectPath] = info
+			skills[i].UpdatedAt = info.LinkedAt
+			return skills, nil
+		}
+		return nil, &notFoundError{name: skillName}
+	})
+}
 
-	if !found {
-		return fmt.Errorf("skill with ID '%s' not found", skill.ID)
+// RemoveLinkedProjectWithPath drops the link of skillName into projectPath
+// from the registry at registryPath under the registry lock, like
+// AddLinkedProjectWithPath. Removing a link that is not recorded is not an
+// error.
+func RemoveLinkedProjectWithPath(registryPath, skillName, projectPath string) error {
+	if skillName == "" {
+		return fmt.Errorf("skill name cannot be empty")
 	}
 
-	return SaveRegistryWithPath(registryPath, skills)
+	return UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		for i := range skills {
+			if skills[i].Name != skillName {
+				continue
+			}
+			delete(skills[i].LinkedProjects, projectPath)
+			if len(skills[i].LinkedProjects) == 0 {
+				skills[i].LinkedProjects = nil
+			}
+			skills[i].UpdatedAt = time.Now()
+			return skills, nil
+		}
+		return nil, &notFoundError{name: skillName}
+	})
 }
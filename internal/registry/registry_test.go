@@ -1,8 +1,10 @@
 package registry
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -447,3 +449,172 @@ func TestUpdateSkill(t *testing.T) {
 		})
 	}
 }
+
+func TestAddLinkedProjectWithPath_Concurrent(t *testing.T) {
+	registryPath := filepath.Join(t.TempDir(), "skills.json")
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/test",
+		StorePath: "/store/test",
+		UpdatedAt: time.Now(),
+	}
+	if err := AddOrUpdateSkillWithPath(registryPath, skill); err != nil {
+		t.Fatalf("AddOrUpdateSkillWithPath() error = %v", err)
+	}
+
+	const numProjects = 20
+	var wg sync.WaitGroup
+	for i := 0; i < numProjects; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			project := fmt.Sprintf("/projects/p%d", i)
+			info := types.LinkedProjectInfo{SymlinkPath: project + "/.opencode/skills/test", LinkedAt: time.Now()}
+			if err := AddLinkedProjectWithPath(registryPath, "test", project, info); err != nil {
+				t.Errorf("AddLinkedProjectWithPath(%s) error = %v", project, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, err := FindSkillByNameWithPath(registryPath, "test")
+	if err != nil {
+		t.Fatalf("FindSkillByNameWithPath() error = %v", err)
+	}
+	if len(got.LinkedProjects) != numProjects {
+		t.Fatalf("LinkedProjects has %d entries, want %d", len(got.LinkedProjects), numProjects)
+	}
+	if info := got.LinkedProjects["/projects/p0"]; info.CommitSHA != "abc123" || info.Version != "main" {
+		t.Errorf("link recorded at %s@%s, want main@abc123", info.Version, info.CommitSHA)
+	}
+
+	if err := RemoveLinkedProjectWithPath(registryPath, "test", "/projects/p0"); err != nil {
+		t.Fatalf("RemoveLinkedProjectWithPath() error = %v", err)
+	}
+	got, _ = FindSkillByNameWithPath(registryPath, "test")
+	if _, ok := got.LinkedProjects["/projects/p0"]; ok || len(got.LinkedProjects) != numProjects-1 {
+		t.Errorf("after removal LinkedProjects = %v", got.LinkedProjects)
+	}
+
+	if err := AddLinkedProjectWithPath(registryPath, "missing", "/projects/x", types.LinkedProjectInfo{}); err == nil {
+		t.Error("AddLinkedProjectWithPath() for an unknown skill should fail")
+	}
+}
+
+func TestUpdateWithPath_Concurrent(t *testing.T) {
+	registryPath := filepath.Join(t.TempDir(), "skills.json")
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/test",
+		StorePath: "/store/test",
+	}
+	if err := AddOrUpdateSkillWithPath(registryPath, skill); err != nil {
+		t.Fatalf("AddOrUpdateSkillWithPath() error = %v", err)
+	}
+
+	// Each transaction reads the tags and writes them back with one more, and
+	// adds a skill of its own; an interleaved read-modify-write would lose
+	// some of them.
+	const numTransactions = 30
+	var wg sync.WaitGroup
+	for i := 0; i < numTransactions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+				for j := range skills {
+					if skills[j].Name == "test" {
+						tags := append([]string(nil), skills[j].Tags...)
+						skills[j].Tags = append(tags, fmt.Sprintf("t%d", i))
+					}
+				}
+				extra := *skill
+				extra.ID = fmt.Sprintf("extra-%d@main", i)
+				extra.Name = fmt.Sprintf("extra-%d", i)
+				return append(skills, extra), nil
+			})
+			if err != nil {
+				t.Errorf("UpdateWithPath() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	skills, err := LoadRegistryWithPath(registryPath)
+	if err != nil {
+		t.Fatalf("LoadRegistryWithPath() error = %v", err)
+	}
+	if len(skills) != numTransactions+1 {
+		t.Errorf("registry has %d skills, want %d", len(skills), numTransactions+1)
+	}
+	got, err := FindSkillByNameWithPath(registryPath, "test")
+	if err != nil {
+		t.Fatalf("FindSkillByNameWithPath() error = %v", err)
+	}
+	if len(got.Tags) != numTransactions {
+		t.Errorf("skill has %d tags, want %d: updates were lost", len(got.Tags), numTransactions)
+	}
+}
+
+func TestUpdateWithPath_ErrorSavesNothing(t *testing.T) {
+	registryPath := filepath.Join(t.TempDir(), "skills.json")
+	if err := SaveRegistryWithPath(registryPath, []types.SkillMetadata{{ID: "keep@main", Name: "keep"}}); err != nil {
+		t.Fatalf("SaveRegistryWithPath() error = %v", err)
+	}
+
+	wantErr := fmt.Errorf("abort")
+	err := UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		return nil, wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("UpdateWithPath() error = %v, want %v", err, wantErr)
+	}
+
+	skills, err := LoadRegistryWithPath(registryPath)
+	if err != nil || len(skills) != 1 || skills[0].Name != "keep" {
+		t.Errorf("registry after a failed transaction = %v, %v, want it unchanged", skills, err)
+	}
+}
+
+func TestAddOrUpdateSkill_DeduplicatesEquivalentSources(t *testing.T) {
+	registryPath := filepath.Join(t.TempDir(), "skills.json")
+
+	legacy := &types.SkillMetadata{
+		ID:        "my-skill@legacy",
+		Name:      "my-skill",
+		Version:   "main",
+		CommitSHA: "sha1",
+		SourceURL: "http://www.github.com/owner/repo/tree/main/skills/my-skill/",
+		StorePath: "/store/my-skill",
+		UpdatedAt: time.Now(),
+	}
+	// Written directly, as by a version that stored URLs verbatim.
+	if err := SaveRegistryWithPath(registryPath, []types.SkillMetadata{*legacy}); err != nil {
+		t.Fatal(err)
+	}
+
+	readded := *legacy
+	readded.ID = "my-skill@main"
+	readded.CommitSHA = "sha2"
+	readded.SourceURL = "https://github.com/owner/repo/tree/main/skills/my-skill"
+	if err := AddOrUpdateSkillWithPath(registryPath, &readded); err != nil {
+		t.Fatalf("AddOrUpdateSkillWithPath() error = %v", err)
+	}
+
+	skills, err := LoadRegistryWithPath(registryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skills) != 1 {
+		t.Fatalf("registry has %d entries, want the equivalent sources merged into 1: %+v", len(skills), skills)
+	}
+	if skills[0].SourceURL != readded.SourceURL || skills[0].CommitSHA != "sha2" {
+		t.Errorf("entry = %+v, want the re-added skill with the canonical URL", skills[0])
+	}
+}
@@ -0,0 +1,212 @@
+// Package relocate moves an existing ~/.gskills layout to the XDG base
+// directories: the config file to $XDG_CONFIG_HOME/gskills and everything
+// else to $XDG_DATA_HOME/gskills. Registry store paths under the old
+// directory are rewritten and project symlinks pointing into the old store
+// are re-created to point into the new one.
+package relocate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/fsutil"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// ErrNoXDG is returned by Migrate when XDG_DATA_HOME is not set, so there is
+// no directory to move the data to.
+var ErrNoXDG = errors.New(paths.XDGDataHomeEnv + " is not set")
+
+// Report summarizes a migration.
+type Report struct {
+	// From is the directory that was moved.
+	From string
+	// DataDir and ConfigDir are the new data and config directories.
+	DataDir   string
+	ConfigDir string
+	// Skills is the number of registry entries whose store path was rewritten.
+	Skills int
+	// Links is the number of project symlinks re-pointed to the new store.
+	Links int
+}
+
+// Migrate moves the legacy data directory from to the XDG directories
+// resolved from the environment. It returns a nil report if from does not
+// exist. See MigrateTo.
+func Migrate(from string) (*Report, error) {
+	dataDir, ok, err := paths.XDGDataDir()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoXDG
+	}
+	configDir, ok, err := paths.XDGConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		configDir = dataDir
+	}
+	return MigrateTo(from, dataDir, configDir)
+}
+
+// MigrateTo moves the data directory from to dataDir and its config file to
+// configDir, then updates the registry and project symlinks. It returns a
+// nil report if from does not exist, and fails without changing anything if
+// dataDir is not empty or both from and configDir hold a config file.
+func MigrateTo(from, dataDir, configDir string) (*Report, error) {
+	if _, err := os.Stat(from); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check %s: %w", from, err)
+	}
+
+	if entries, err := os.ReadDir(dataDir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("data directory %s already exists and is not empty", dataDir)
+	}
+	configPath := paths.ConfigPath(configDir)
+	// Without a config file in from, the one in configDir is already in use
+	// and is kept.
+	if _, err := os.Lstat(paths.ConfigPath(from)); err == nil && configDir != dataDir {
+		if _, err := os.Lstat(configPath); err == nil {
+			return nil, fmt.Errorf("config file %s already exists", configPath)
+		}
+	}
+
+	if err := moveDir(from, dataDir); err != nil {
+		return nil, err
+	}
+
+	report := &Report{From: from, DataDir: dataDir, ConfigDir: configDir}
+
+	if configDir != dataDir {
+		if err := moveConfig(paths.ConfigPath(dataDir), configPath); err != nil {
+			return report, err
+		}
+	}
+
+	skills, links, err := rewriteStorePaths(paths.RegistryPath(dataDir), from, dataDir)
+	report.Skills, report.Links = skills, links
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// moveDir renames from to to, copying and removing the tree when they are on
+// different filesystems.
+func moveDir(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	// An empty target left by an earlier attempt would make the rename fail.
+	os.Remove(to)
+
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	if err := fsutil.CopyDir(from, to); err != nil {
+		os.RemoveAll(to)
+		return fmt.Errorf("failed to copy %s to %s: %w", from, to, err)
+	}
+	if err := os.RemoveAll(from); err != nil {
+		return fmt.Errorf("copied to %s but failed to remove %s: %w", to, from, err)
+	}
+	return nil
+}
+
+// moveConfig moves the config file from to to. A missing file is not an error.
+func moveConfig(from, to string) error {
+	if _, err := os.Stat(from); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := os.WriteFile(to, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return os.Remove(from)
+}
+
+// rewriteStorePaths replaces the from prefix of the store paths in the
+// registry at registryPath with to, and re-points project symlinks that
+// pointed to the old store paths, in a single registry transaction. It
+// returns the number of rewritten skills and re-pointed symlinks. The store
+// has already moved, so a symlink that cannot be re-pointed does not stop the
+// store paths from being saved; the failures are reported afterwards.
+func rewriteStorePaths(registryPath, from, to string) (int, int, error) {
+	var rewritten, relinked int
+	var linkErrs []error
+	err := registry.UpdateWithPath(registryPath, func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		for i := range skills {
+			oldStore := skills[i].StorePath
+			newStore, ok := movedPath(oldStore, from, to)
+			if !ok {
+				continue
+			}
+			skills[i].StorePath = newStore
+			rewritten++
+
+			for _, linkInfo := range skills[i].LinkedProjects {
+				target, err := os.Readlink(linkInfo.SymlinkPath)
+				if err != nil || filepath.Clean(target) != filepath.Clean(oldStore) {
+					continue
+				}
+				if err := os.Remove(linkInfo.SymlinkPath); err != nil {
+					linkErrs = append(linkErrs, fmt.Errorf("failed to remove symlink %s: %w", linkInfo.SymlinkPath, err))
+					continue
+				}
+				if err := symlink(newStore, linkInfo.SymlinkPath); err != nil {
+					linkErrs = append(linkErrs, fmt.Errorf("failed to re-create symlink %s: %w", linkInfo.SymlinkPath, err))
+					continue
+				}
+				relinked++
+			}
+		}
+		if rewritten == 0 {
+			return nil, errNothingToRewrite
+		}
+		return skills, nil
+	})
+	switch {
+	case errors.Is(err, errNothingToRewrite):
+		return 0, 0, nil
+	case err != nil:
+		return rewritten, relinked, errors.Join(append([]error{fmt.Errorf("failed to update registry: %w", err)}, linkErrs...)...)
+	}
+	return rewritten, relinked, errors.Join(linkErrs...)
+}
+
+// symlink creates the re-pointed project symlinks; tests replace it to make
+// re-creating a symlink fail.
+var symlink = os.Symlink
+
+// errNothingToRewrite aborts the registry transaction of rewriteStorePaths
+// when no store path is inside the moved directory.
+var errNothingToRewrite = errors.New("no store paths to rewrite")
+
+// movedPath returns p with its from prefix replaced by to, and whether p was
+// inside from.
+func movedPath(p, from, to string) (string, bool) {
+	rel, err := filepath.Rel(from, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(to, rel), true
+}
@@ -0,0 +1,199 @@
+package relocate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// setupLegacyDir creates a ~/.gskills layout under root with one skill
+// linked into a project, and returns the legacy directory and the symlink.
+func setupLegacyDir(t *testing.T, root string) (string, string) {
+	t.Helper()
+
+	legacyDir := filepath.Join(root, "home", ".gskills")
+	storePath := filepath.Join(legacyDir, "skills", "my-skill")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# My skill"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(legacyDir, "config.json"), []byte(`{"proxy": "http://proxy"}`), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	projectDir := filepath.Join(root, "project")
+	symlinkPath := filepath.Join(projectDir, ".opencode", "skills", "my-skill")
+	if err := os.MkdirAll(filepath.Dir(symlinkPath), 0755); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if err := os.Symlink(storePath, symlinkPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "my-skill@main",
+			Name:      "my-skill",
+			Version:   "main",
+			CommitSHA: "abc123",
+			SourceURL: "https://github.com/owner/repo/tree/main/my-skill",
+			StorePath: storePath,
+			UpdatedAt: time.Now(),
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectDir: {SymlinkPath: symlinkPath, LinkedAt: time.Now()},
+			},
+		},
+		{
+			ID:        "outside@local",
+			Name:      "outside",
+			Version:   "local",
+			CommitSHA: "local",
+			SourceURL: "file:///src/outside",
+			StorePath: filepath.Join(root, "elsewhere", "outside"),
+			UpdatedAt: time.Now(),
+		},
+	}
+	if err := registry.SaveRegistryWithPath(filepath.Join(legacyDir, "skills.json"), skills); err != nil {
+		t.Fatalf("failed to write registry: %v", err)
+	}
+	return legacyDir, symlinkPath
+}
+
+func TestMigrate(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv(paths.HomeEnv, "")
+	t.Setenv(paths.XDGDataHomeEnv, filepath.Join(root, "data"))
+	t.Setenv(paths.XDGConfigHomeEnv, filepath.Join(root, "config"))
+
+	legacyDir, symlinkPath := setupLegacyDir(t, root)
+
+	report, err := Migrate(legacyDir)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	dataDir := filepath.Join(root, "data", "gskills")
+	configDir := filepath.Join(root, "config", "gskills")
+	if report.DataDir != dataDir || report.ConfigDir != configDir {
+		t.Errorf("report dirs = %s, %s, want %s, %s", report.DataDir, report.ConfigDir, dataDir, configDir)
+	}
+	if report.Skills != 1 || report.Links != 1 {
+		t.Errorf("report = %+v, want 1 skill and 1 link", report)
+	}
+
+	if _, err := os.Stat(legacyDir); !os.IsNotExist(err) {
+		t.Errorf("legacy directory still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "config.json")); err != nil {
+		t.Errorf("config file not moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "config.json")); !os.IsNotExist(err) {
+		t.Error("config file should not stay in the data directory")
+	}
+
+	// The resolved directories now follow XDG.
+	if got, _ := paths.DataDir(); got != dataDir {
+		t.Errorf("DataDir() after migration = %s, want %s", got, dataDir)
+	}
+	if got, _ := paths.ConfigDir(); got != configDir {
+		t.Errorf("ConfigDir() after migration = %s, want %s", got, configDir)
+	}
+
+	newStore := filepath.Join(dataDir, "skills", "my-skill")
+	skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "my-skill")
+	if err != nil {
+		t.Fatalf("skill missing from migrated registry: %v", err)
+	}
+	if skill.StorePath != newStore {
+		t.Errorf("StorePath = %s, want %s", skill.StorePath, newStore)
+	}
+	outside, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "outside")
+	if err != nil {
+		t.Fatalf("skill missing from migrated registry: %v", err)
+	}
+	if want := filepath.Join(root, "elsewhere", "outside"); outside.StorePath != want {
+		t.Errorf("StorePath outside the data directory = %s, want it unchanged", outside.StorePath)
+	}
+
+	if target, err := os.Readlink(symlinkPath); err != nil || target != newStore {
+		t.Errorf("symlink -> %s (%v), want -> %s", target, err, newStore)
+	}
+	if _, err := os.Stat(filepath.Join(symlinkPath, "SKILL.md")); err != nil {
+		t.Errorf("symlink does not resolve after migration: %v", err)
+	}
+}
+
+func TestMigrate_SymlinkFailureKeepsStorePaths(t *testing.T) {
+	root := t.TempDir()
+	legacyDir, symlinkPath := setupLegacyDir(t, root)
+	dataDir := filepath.Join(root, "data")
+
+	oldSymlink := symlink
+	symlink = func(oldname, newname string) error { return errors.New("symlink failed") }
+	defer func() { symlink = oldSymlink }()
+
+	report, err := MigrateTo(legacyDir, dataDir, dataDir)
+	if err == nil || !strings.Contains(err.Error(), symlinkPath) {
+		t.Fatalf("MigrateTo() error = %v, want it to name %s", err, symlinkPath)
+	}
+	if report == nil || report.Skills != 1 || report.Links != 0 {
+		t.Errorf("report = %+v, want 1 skill and no links", report)
+	}
+
+	skill, err := registry.FindSkillByNameWithPath(paths.RegistryPath(dataDir), "my-skill")
+	if err != nil {
+		t.Fatalf("skill missing from migrated registry: %v", err)
+	}
+	if want := filepath.Join(dataDir, "skills", "my-skill"); skill.StorePath != want {
+		t.Errorf("StorePath = %s, want %s saved despite the symlink failure", skill.StorePath, want)
+	}
+}
+
+func TestMigrate_Refusals(t *testing.T) {
+	t.Run("XDG_DATA_HOME not set", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(paths.XDGDataHomeEnv, "")
+		legacyDir, _ := setupLegacyDir(t, root)
+
+		if _, err := Migrate(legacyDir); !errors.Is(err, ErrNoXDG) {
+			t.Errorf("Migrate() error = %v, want ErrNoXDG", err)
+		}
+	})
+
+	t.Run("nothing to migrate", func(t *testing.T) {
+		root := t.TempDir()
+		report, err := MigrateTo(filepath.Join(root, "missing"), filepath.Join(root, "data"), filepath.Join(root, "config"))
+		if report != nil || err != nil {
+			t.Errorf("MigrateTo() = %+v, %v, want nil, nil", report, err)
+		}
+	})
+
+	t.Run("data directory not empty", func(t *testing.T) {
+		root := t.TempDir()
+		legacyDir, _ := setupLegacyDir(t, root)
+		dataDir := filepath.Join(root, "data")
+		if err := os.MkdirAll(dataDir, 0755); err != nil {
+			t.Fatalf("failed to create data dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dataDir, "skills.json"), []byte("[]"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if _, err := MigrateTo(legacyDir, dataDir, dataDir); err == nil {
+			t.Fatal("MigrateTo() into a non-empty directory should fail")
+		}
+		if _, err := os.Stat(filepath.Join(legacyDir, "skills.json")); err != nil {
+			t.Errorf("legacy directory changed after a refused migration: %v", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package remove
+
+import (
+	"sort"
+	"time"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// PruneFilter selects the skills 'gskills prune' removes. A skill must match
+// every filter that is set.
+type PruneFilter struct {
+	// Unlinked selects skills that are not linked into any project.
+	Unlinked bool
+	// OlderThan, when positive, selects skills last updated more than
+	// OlderThan ago.
+	OlderThan time.Duration
+}
+
+// IsSet reports whether at least one filter is set.
+func (f PruneFilter) IsSet() bool {
+	return f.Unlinked || f.OlderThan > 0
+}
+
+// Matches reports whether skill is selected by f at time now.
+func (f PruneFilter) Matches(skill *types.SkillMetadata, now time.Time) bool {
+	if f.Unlinked && len(skill.LinkedProjects) > 0 {
+		return false
+	}
+	if f.OlderThan > 0 && !skill.UpdatedAt.Before(now.Add(-f.OlderThan)) {
+		return false
+	}
+	return true
+}
+
+// PruneCandidates returns the skills selected by filter at time now, sorted
+// by name. No skill is selected when no filter is set.
+func PruneCandidates(skills []types.SkillMetadata, filter PruneFilter, now time.Time) []types.SkillMetadata {
+	candidates := []types.SkillMetadata{}
+	if !filter.IsSet() {
+		return candidates
+	}
+	for i := range skills {
+		if filter.Matches(&skills[i], now) {
+			candidates = append(candidates, skills[i])
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates
+}
@@ -0,0 +1,59 @@
+package remove
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	linked := map[string]types.LinkedProjectInfo{"/proj": {SymlinkPath: "/proj/.opencode/skills/x"}}
+	skills := []types.SkillMetadata{
+		{Name: "old-unlinked", UpdatedAt: now.AddDate(0, 0, -90)},
+		{Name: "new-unlinked", UpdatedAt: now.AddDate(0, 0, -1)},
+		{Name: "old-linked", UpdatedAt: now.AddDate(0, 0, -90), LinkedProjects: linked},
+		{Name: "new-linked", UpdatedAt: now.AddDate(0, 0, -1), LinkedProjects: linked},
+	}
+
+	tests := []struct {
+		name   string
+		filter PruneFilter
+		want   []string
+	}{
+		{
+			name:   "no filter selects nothing",
+			filter: PruneFilter{},
+			want:   []string{},
+		},
+		{
+			name:   "unlinked",
+			filter: PruneFilter{Unlinked: true},
+			want:   []string{"new-unlinked", "old-unlinked"},
+		},
+		{
+			name:   "older than",
+			filter: PruneFilter{OlderThan: 30 * 24 * time.Hour},
+			want:   []string{"old-linked", "old-unlinked"},
+		},
+		{
+			name:   "unlinked and older than",
+			filter: PruneFilter{Unlinked: true, OlderThan: 30 * 24 * time.Hour},
+			want:   []string{"old-unlinked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, s := range PruneCandidates(skills, tt.filter, now) {
+				got = append(got, s.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,50 +2,37 @@ package remove
 
 import (
 	"fmt"
-	"io"
-	"os"
-	"strings"
 
-	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/prompt"
 )
 
+// promptForConfirmation asks the user to confirm before removing a skill.
+// Without a terminal the answer is no.
 func promptForConfirmation(name string) (bool, error) {
-	fmt.Printf("Are you sure you want to remove skill '%s'? [y/N]: ", name)
-
-	var response string
-	_, err := fmt.Scanln(&response)
-	if err != nil {
-		if err == io.EOF {
-			return false, nil
-		}
-		if err.Error() == "unexpected newline" {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to read user input: %w", err)
-	}
-
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes", nil
-}
-
-// removeSkillDirectory deletes the skill directory at the given path.
-func removeSkillDirectory(storePath string) error {
-	if err := os.RemoveAll(storePath); err != nil {
-		return fmt.Errorf("failed to remove skill directory '%s': %w", storePath, err)
-	}
-	return nil
+	return prompt.Confirm(fmt.Sprintf("Are you sure you want to remove skill '%s'?", name))
 }
 
 // RemoveSkillByName removes a skill by its name from the registry and deletes its directory.
-// It prompts the user for confirmation before performing the removal.
+// It prompts the user for confirmation before performing the removal unless
+// assumeYes is set; without a terminal the prompt answers no.
 // If the skill is linked to any projects, it will also remove all symlinks.
-func RemoveSkillByName(name string) error {
-	skill, err := registry.FindSkillByName(name)
+//
+// Every removal the user does not cancel is recorded in the history log,
+// whether or not it succeeds.
+func RemoveSkillByName(name string, assumeYes bool) error {
+	return RemoveSkill(add.NewManager("", "", nil), name, assumeYes)
+}
+
+// RemoveSkill is RemoveSkillByName for the skills of manager. The removal
+// itself is done by manager.Remove, so the CLI and library callers share it.
+func RemoveSkill(manager *add.Manager, name string, assumeYes bool) error {
+	skill, err := manager.Find(name)
 	if err != nil {
 		return err
 	}
 
-	var confirmed bool
+	confirmed := assumeYes
 
 	if len(skill.LinkedProjects) > 0 {
 		fmt.Printf("Warning: Skill '%s' is linked to %d project(s):\n", name, len(skill.LinkedProjects))
@@ -53,19 +40,13 @@ func RemoveSkillByName(name string) error {
 			fmt.Printf("  • %s (linked at %s)\n", projectPath, linkInfo.LinkedAt.Format("2006-01-02 15:04"))
 		}
 
-		confirmed, err = promptForConfirmationWithLinks(name, len(skill.LinkedProjects))
-		if err != nil {
-			return err
-		}
-
-		if confirmed {
-			for _, linkInfo := range skill.LinkedProjects {
-				if err := os.Remove(linkInfo.SymlinkPath); err != nil {
-					fmt.Printf("Warning: Failed to remove symlink %s: %v\n", linkInfo.SymlinkPath, err)
-				}
+		if !confirmed {
+			confirmed, err = promptForConfirmationWithLinks(name, len(skill.LinkedProjects))
+			if err != nil {
+				return err
 			}
 		}
-	} else {
+	} else if !confirmed {
 		confirmed, err = promptForConfirmation(name)
 		if err != nil {
 			return err
@@ -76,34 +57,12 @@ func RemoveSkillByName(name string) error {
 		return fmt.Errorf("operation cancelled")
 	}
 
-	if err := removeSkillDirectory(skill.StorePath); err != nil {
-		return err
-	}
-
-	if err := registry.RemoveSkill(skill.ID); err != nil {
-		return fmt.Errorf("failed to remove skill from registry: %w", err)
-	}
-
-	return nil
+	return manager.Remove(name)
 }
 
 // promptForConfirmationWithLinks asks the user to confirm before removing a skill with links.
-// Returns true if the user confirms (y/yes), false otherwise.
+// Returns true if the user confirms (y/yes), false otherwise. Without a
+// terminal the answer is no.
 func promptForConfirmationWithLinks(name string, linkCount int) (bool, error) {
-	fmt.Printf("Remove skill '%s' and all %d symlink(s)? [y/N]: ", name, linkCount)
-
-	var response string
-	_, err := fmt.Scanln(&response)
-	if err != nil {
-		if err == io.EOF {
-			return false, nil
-		}
-		if err.Error() == "unexpected newline" {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to read user input: %w", err)
-	}
-
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes", nil
+	return prompt.Confirm(fmt.Sprintf("Remove skill '%s' and all %d symlink(s)?", name, linkCount))
 }
@@ -9,10 +9,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/smy-101/gskills/internal/prompt"
 	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
 )
 
+// simulateTerminal makes prompts read answers from the piped stdin the tests
+// set up, as they would from a terminal.
+func simulateTerminal(t *testing.T) {
+	t.Helper()
+	oldIsInteractive := prompt.IsInteractive
+	prompt.IsInteractive = func() bool { return true }
+	t.Cleanup(func() { prompt.IsInteractive = oldIsInteractive })
+}
+
 func TestPromptForConfirmation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -79,6 +89,7 @@ func TestPromptForConfirmation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Mock stdin
+			simulateTerminal(t)
 			oldStdin := os.Stdin
 			r, w, _ := os.Pipe()
 			os.Stdin = r
@@ -106,59 +117,6 @@ func TestPromptForConfirmation(t *testing.T) {
 	}
 }
 
-func TestRemoveSkillDirectory(t *testing.T) {
-	tests := []struct {
-		name        string
-		setupFunc   func() string
-		wantErr     bool
-		errContains string
-	}{
-		{
-			name: "remove existing directory",
-			setupFunc: func() string {
-				tmpDir := t.TempDir()
-				skillDir := filepath.Join(tmpDir, "skill")
-				os.MkdirAll(filepath.Join(skillDir, "subdir"), 0755)
-				os.WriteFile(filepath.Join(skillDir, "file.txt"), []byte("test"), 0644)
-				return skillDir
-			},
-			wantErr: false,
-		},
-		{
-			name: "remove non-existent directory",
-			setupFunc: func() string {
-				tmpDir := t.TempDir()
-				return filepath.Join(tmpDir, "nonexistent")
-			},
-			wantErr: false, // RemoveAll doesn't error on non-existent path
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			storePath := tt.setupFunc()
-
-			err := removeSkillDirectory(storePath)
-
-			if (err != nil) != tt.wantErr {
-				t.Errorf("removeSkillDirectory() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if tt.wantErr && tt.errContains != "" {
-				if !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("error should contain %q, got %q", tt.errContains, err.Error())
-				}
-			}
-
-			// Verify directory is removed
-			if _, err := os.Stat(storePath); !os.IsNotExist(err) {
-				t.Errorf("directory still exists after removal: %s", storePath)
-			}
-		})
-	}
-}
-
 func TestRemoveSkillByName(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -306,6 +264,7 @@ func TestRemoveSkillByName(t *testing.T) {
 			}
 
 			// Mock stdin
+			simulateTerminal(t)
 			oldStdin := os.Stdin
 			r, w, _ := os.Pipe()
 			os.Stdin = r
@@ -328,7 +287,7 @@ func TestRemoveSkillByName(t *testing.T) {
 			// Run test in goroutine to close pipes
 			done := make(chan error, 1)
 			go func() {
-				done <- RemoveSkillByName(tt.skillName)
+				done <- RemoveSkillByName(tt.skillName, false)
 			}()
 
 			// Copy output
@@ -375,3 +334,57 @@ func TestRemoveSkillByName(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveSkillByName_NonInteractive(t *testing.T) {
+	tests := []struct {
+		name        string
+		assumeYes   bool
+		wantRemoved bool
+	}{
+		{name: "prompt answers no without a terminal", assumeYes: false, wantRemoved: false},
+		{name: "assumeYes removes without asking", assumeYes: true, wantRemoved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			originalHome := os.Getenv("HOME")
+			os.Setenv("HOME", homeDir)
+			defer os.Setenv("HOME", originalHome)
+
+			oldIsInteractive := prompt.IsInteractive
+			prompt.IsInteractive = func() bool { return false }
+			defer func() { prompt.IsInteractive = oldIsInteractive }()
+
+			skillDir := filepath.Join(homeDir, ".gskills", "skills", "ci-skill")
+			if err := os.MkdirAll(skillDir, 0755); err != nil {
+				t.Fatalf("failed to create skill dir: %v", err)
+			}
+			skill := &types.SkillMetadata{
+				ID:        "ci-skill@main",
+				Name:      "ci-skill",
+				SourceURL: "https://github.com/test/repo/tree/main/ci-skill",
+				StorePath: skillDir,
+				Version:   "main",
+				CommitSHA: "abc123",
+				UpdatedAt: time.Now(),
+			}
+			if err := registry.AddOrUpdateSkill(skill); err != nil {
+				t.Fatalf("failed to add skill: %v", err)
+			}
+
+			err := RemoveSkillByName("ci-skill", tt.assumeYes)
+			if tt.wantRemoved && err != nil {
+				t.Fatalf("RemoveSkillByName() error = %v", err)
+			}
+			if !tt.wantRemoved && (err == nil || err.Error() != "operation cancelled") {
+				t.Fatalf("RemoveSkillByName() error = %v, want operation cancelled", err)
+			}
+
+			_, statErr := os.Stat(skillDir)
+			if removed := os.IsNotExist(statErr); removed != tt.wantRemoved {
+				t.Errorf("skill directory removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package sourceurl normalizes the source URLs skills are recorded with.
+package sourceurl
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Canonicalize returns the canonical form of a skill URL, so that URLs
+// naming the same skill compare equal: the scheme and host are lower-cased,
+// a leading "www." of the host is dropped, github.com is always
+// reached over https, and trailing slashes and any fragment are removed.
+// The path keeps its case because branches and paths are case-sensitive.
+// Sources that are not http(s) URLs, such as local paths, are returned
+// unchanged.
+func Canonicalize(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return rawURL
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Host = strings.TrimPrefix(parsed.Host, "www.")
+	// An enterprise server may only serve http, so only github.com itself
+	// is upgraded.
+	if parsed.Host == "github.com" {
+		parsed.Scheme = "https"
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	parsed.RawPath = strings.TrimRight(parsed.RawPath, "/")
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	return parsed.String()
+}
@@ -0,0 +1,36 @@
+package sourceurl
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	const canonical = "https://github.com/owner/repo/tree/main/skills/my-skill"
+
+	equivalent := []string{
+		canonical,
+		canonical + "/",
+		canonical + "//",
+		"http://github.com/owner/repo/tree/main/skills/my-skill",
+		"https://www.github.com/owner/repo/tree/main/skills/my-skill",
+		"HTTPS://GitHub.com/owner/repo/tree/main/skills/my-skill/",
+		"http://WWW.GITHUB.COM/owner/repo/tree/main/skills/my-skill#readme",
+		"  " + canonical + "  ",
+	}
+	for _, rawURL := range equivalent {
+		if got := Canonicalize(rawURL); got != canonical {
+			t.Errorf("Canonicalize(%q) = %q, want %q", rawURL, got, canonical)
+		}
+	}
+
+	unchanged := []string{
+		"./path/to/skill",
+		"/home/user/skills/my-skill",
+		"file:///home/user/skills/my-skill",
+		// Paths are case-sensitive.
+		"https://github.com/owner/repo/tree/main/skills/My-Skill",
+	}
+	for _, source := range unchanged {
+		if got := Canonicalize(source); got != source {
+			t.Errorf("Canonicalize(%q) = %q, want it unchanged", source, got)
+		}
+	}
+}
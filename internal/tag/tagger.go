@@ -0,0 +1,130 @@
+// Package tag manages user-defined tags on installed skills. Tags are
+// local metadata stored in the skills registry; they are not part of the
+// skill source and are kept when a skill is updated or re-added.
+package tag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// Normalize trims and lowercases tag and validates it. Tags may not be
+// empty or contain whitespace or commas.
+func Normalize(tag string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tag))
+	if t == "" {
+		return "", fmt.Errorf("tag cannot be empty")
+	}
+	if strings.ContainsAny(t, " \t\n,") {
+		return "", fmt.Errorf("invalid tag '%s': tags cannot contain whitespace or commas", tag)
+	}
+	return t, nil
+}
+
+// normalizeAll normalizes every tag in tags.
+func normalizeAll(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		n, err := Normalize(t)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// AddTags adds tags to the named skill and returns the updated metadata.
+// Tags the skill already has are ignored.
+func AddTags(name string, tags []string) (*types.SkillMetadata, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	skill, err := registry.FindSkillByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(skill.Tags)+len(normalized))
+	for _, t := range skill.Tags {
+		set[t] = true
+	}
+	for _, t := range normalized {
+		set[t] = true
+	}
+	skill.Tags = sortedKeys(set)
+
+	if err := registry.UpdateSkill(skill); err != nil {
+		return nil, fmt.Errorf("failed to update skills registry: %w", err)
+	}
+	return skill, nil
+}
+
+// RemoveTags removes tags from the named skill and returns the updated
+// metadata. Tags the skill does not have are ignored.
+func RemoveTags(name string, tags []string) (*types.SkillMetadata, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	skill, err := registry.FindSkillByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(skill.Tags))
+	for _, t := range skill.Tags {
+		set[t] = true
+	}
+	for _, t := range normalized {
+		delete(set, t)
+	}
+	skill.Tags = sortedKeys(set)
+
+	if err := registry.UpdateSkill(skill); err != nil {
+		return nil, fmt.Errorf("failed to update skills registry: %w", err)
+	}
+	return skill, nil
+}
+
+// HasAll reports whether skill carries every tag in tags. Tags are compared
+// after normalization; an empty tags list matches every skill.
+func HasAll(skill *types.SkillMetadata, tags []string) bool {
+	for _, want := range tags {
+		n, err := Normalize(want)
+		if err != nil {
+			return false
+		}
+		found := false
+		for _, t := range skill.Tags {
+			if t == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of set in sorted order, or nil if set is empty.
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
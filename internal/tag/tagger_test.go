@@ -0,0 +1,125 @@
+package tag
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func setupTestSkill(t *testing.T, tags []string) {
+	t.Helper()
+
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+
+	skill := &types.SkillMetadata{
+		ID:        "test-skill@main",
+		Name:      "test-skill",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/test-skill",
+		CommitSHA: "abc123",
+		StorePath: "/tmp/test-skill",
+		UpdatedAt: time.Now(),
+		Tags:      tags,
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+}
+
+func TestAddTags(t *testing.T) {
+	setupTestSkill(t, []string{"writing"})
+
+	skill, err := AddTags("test-skill", []string{"Frontend", "writing", " mobile "})
+	if err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+	want := []string{"frontend", "mobile", "writing"}
+	if !reflect.DeepEqual(skill.Tags, want) {
+		t.Errorf("Tags = %v, want %v", skill.Tags, want)
+	}
+
+	stored, err := registry.FindSkillByName("test-skill")
+	if err != nil {
+		t.Fatalf("FindSkillByName() error = %v", err)
+	}
+	if !reflect.DeepEqual(stored.Tags, want) {
+		t.Errorf("stored Tags = %v, want %v", stored.Tags, want)
+	}
+}
+
+func TestRemoveTags(t *testing.T) {
+	setupTestSkill(t, []string{"frontend", "writing"})
+
+	skill, err := RemoveTags("test-skill", []string{"FRONTEND", "unknown"})
+	if err != nil {
+		t.Fatalf("RemoveTags() error = %v", err)
+	}
+	if !reflect.DeepEqual(skill.Tags, []string{"writing"}) {
+		t.Errorf("Tags = %v, want [writing]", skill.Tags)
+	}
+
+	skill, err = RemoveTags("test-skill", []string{"writing"})
+	if err != nil {
+		t.Fatalf("RemoveTags() error = %v", err)
+	}
+	if skill.Tags != nil {
+		t.Errorf("Tags = %v, want nil", skill.Tags)
+	}
+}
+
+func TestTagErrors(t *testing.T) {
+	setupTestSkill(t, nil)
+
+	tests := []struct {
+		name        string
+		skill       string
+		tags        []string
+		errContains string
+	}{
+		{name: "empty tag", skill: "test-skill", tags: []string{" "}, errContains: "cannot be empty"},
+		{name: "tag with space", skill: "test-skill", tags: []string{"front end"}, errContains: "invalid tag"},
+		{name: "tag with comma", skill: "test-skill", tags: []string{"a,b"}, errContains: "invalid tag"},
+		{name: "unknown skill", skill: "missing", tags: []string{"x"}, errContains: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AddTags(tt.skill, tt.tags)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("AddTags() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestHasAll(t *testing.T) {
+	skill := &types.SkillMetadata{Tags: []string{"frontend", "writing"}}
+
+	tests := []struct {
+		name string
+		tags []string
+		want bool
+	}{
+		{name: "no filter", tags: nil, want: true},
+		{name: "single match", tags: []string{"frontend"}, want: true},
+		{name: "case insensitive", tags: []string{"Writing"}, want: true},
+		{name: "all match", tags: []string{"frontend", "writing"}, want: true},
+		{name: "one missing", tags: []string{"frontend", "mobile"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasAll(skill, tt.tags); got != tt.want {
+				t.Errorf("HasAll(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// Package testutil provides helpers shared by the gskills tests: an
+// in-memory fake of the GitHub API and an isolated gskills home directory.
+package testutil
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// FakeGitHub serves the GitHub API endpoints used by gskills from an
+// in-memory fixture tree:
+//
+//	GET /repos/{owner}/{repo}
+//	GET /repos/{owner}/{repo}/commits/{ref}
+//	GET /repos/{owner}/{repo}/git/ref/{heads|tags}/{ref}
+//	GET /repos/{owner}/{repo}/tags
+//	GET /repos/{owner}/{repo}/contents/{path}?ref={ref}
+//	GET /raw/{owner}/{repo}/{path}
+//
+// Every ref of a repository sees the same files; change the files and move
+// the branch to a new SHA to simulate an upstream commit. Releases are not
+// served, so /releases/latest reports 404 like a repository without releases.
+type FakeGitHub struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	repos    map[string]*fakeRepo
+	requests map[string]int
+	failures map[string]int
+}
+
+type fakeRepo struct {
+	defaultBranch string
+	branches      map[string]string
+	tags          map[string]string
+	files         map[string]string
+}
+
+// NewFakeGitHub starts a fake GitHub server that is closed when the test ends.
+func NewFakeGitHub(t testing.TB) *FakeGitHub {
+	t.Helper()
+
+	f := &FakeGitHub{
+		repos:    make(map[string]*fakeRepo),
+		requests: make(map[string]int),
+		failures: make(map[string]int),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL returns the base URL to pass to SetBaseURL.
+func (f *FakeGitHub) URL() string {
+	return f.server.URL
+}
+
+// SetBranch points branch of owner/repo at sha. The first branch set on a
+// repository becomes its default branch.
+func (f *FakeGitHub) SetBranch(owner, repo, branch, sha string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.repo(owner, repo)
+	if r.defaultBranch == "" {
+		r.defaultBranch = branch
+	}
+	r.branches[branch] = sha
+}
+
+// SetTag points tag of owner/repo at sha.
+func (f *FakeGitHub) SetTag(owner, repo, tag, sha string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.repo(owner, repo).tags[tag] = sha
+}
+
+// SetFile creates or replaces the file at filePath in owner/repo.
+func (f *FakeGitHub) SetFile(owner, repo, filePath, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.repo(owner, repo).files[strings.Trim(filePath, "/")] = content
+}
+
+// RemoveFile deletes the file at filePath from owner/repo.
+func (f *FakeGitHub) RemoveFile(owner, repo, filePath string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.repo(owner, repo).files, strings.Trim(filePath, "/"))
+}
+
+// SetFailure makes every request for urlPath fail with status, e.g. to
+// simulate a rate limit or a server error. A status of 0 clears the failure.
+func (f *FakeGitHub) SetFailure(urlPath string, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if status == 0 {
+		delete(f.failures, urlPath)
+		return
+	}
+	f.failures[urlPath] = status
+}
+
+// Requests returns how many times urlPath was requested.
+func (f *FakeGitHub) Requests(urlPath string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[urlPath]
+}
+
+// repo returns the fixture of owner/repo, creating it if needed. f.mu must be held.
+func (f *FakeGitHub) repo(owner, repo string) *fakeRepo {
+	key := owner + "/" + repo
+	r, ok := f.repos[key]
+	if !ok {
+		r = &fakeRepo{
+			branches: make(map[string]string),
+			tags:     make(map[string]string),
+			files:    make(map[string]string),
+		}
+		f.repos[key] = r
+	}
+	return r
+}
+
+func (f *FakeGitHub) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[req.URL.Path]++
+	if status, ok := f.failures[req.URL.Path]; ok {
+		w.WriteHeader(status)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) >= 4 && parts[0] == "raw":
+		f.serveRaw(w, parts[1], parts[2], strings.Join(parts[3:], "/"))
+	case len(parts) >= 3 && parts[0] == "repos":
+		r, ok := f.repos[parts[1]+"/"+parts[2]]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		f.serveRepo(w, req, parts[1], parts[2], r, parts[3:])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (f *FakeGitHub) serveRepo(w http.ResponseWriter, req *http.Request, owner, repo string, r *fakeRepo, rest []string) {
+	switch {
+	case len(rest) == 0:
+		writeJSON(w, map[string]string{
+			"full_name":      owner + "/" + repo,
+			"default_branch": r.defaultBranch,
+		})
+	case len(rest) >= 2 && rest[0] == "commits":
+		ref := strings.Join(rest[1:], "/")
+		sha, ok := r.resolve(ref)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		writeJSON(w, map[string]string{"sha": sha})
+	case len(rest) >= 4 && rest[0] == "git" && rest[1] == "ref":
+		refs := r.branches
+		if rest[2] == "tags" {
+			refs = r.tags
+		}
+		ref := strings.Join(rest[3:], "/")
+		sha, ok := refs[ref]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		writeJSON(w, map[string]any{
+			"ref":    "refs/" + rest[2] + "/" + ref,
+			"object": map[string]string{"sha": sha},
+		})
+	case len(rest) == 1 && rest[0] == "tags":
+		names := make([]string, 0, len(r.tags))
+		for name := range r.tags {
+			names = append(names, name)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(names)))
+		tags := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, map[string]any{
+				"name":   name,
+				"commit": map[string]string{"sha": r.tags[name]},
+			})
+		}
+		writeJSON(w, tags)
+	case len(rest) >= 1 && rest[0] == "contents":
+		ref := req.URL.Query().Get("ref")
+		if ref == "" {
+			ref = r.defaultBranch
+		}
+		if _, ok := r.resolve(ref); !ok {
+			http.NotFound(w, req)
+			return
+		}
+		f.serveContents(w, req, owner, repo, r, strings.Join(rest[1:], "/"))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// serveContents answers the contents API: a file yields a single object, a
+// directory the list of its immediate children.
+func (f *FakeGitHub) serveContents(w http.ResponseWriter, req *http.Request, owner, repo string, r *fakeRepo, dir string) {
+	dir = strings.Trim(dir, "/")
+	if content, ok := r.files[dir]; ok && dir != "" {
+		writeJSON(w, f.fileContent(owner, repo, dir, content))
+		return
+	}
+
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	seenDirs := make(map[string]bool)
+	var entries []types.GitHubContent
+	for filePath, content := range r.files {
+		if !strings.HasPrefix(filePath, prefix) {
+			continue
+		}
+		name, _, nested := strings.Cut(strings.TrimPrefix(filePath, prefix), "/")
+		if !nested {
+			entries = append(entries, f.fileContent(owner, repo, filePath, content))
+			continue
+		}
+		if !seenDirs[name] {
+			seenDirs[name] = true
+			entries = append(entries, types.GitHubContent{
+				Type: "dir",
+				Name: name,
+				Path: prefix + name,
+			})
+		}
+	}
+	if len(entries) == 0 {
+		http.NotFound(w, req)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	writeJSON(w, entries)
+}
+
+func (f *FakeGitHub) fileContent(owner, repo, filePath, content string) types.GitHubContent {
+	sum := sha1.Sum([]byte(content))
+	return types.GitHubContent{
+		Type:        "file",
+		Name:        path.Base(filePath),
+		Path:        filePath,
+		SHA:         hex.EncodeToString(sum[:]),
+		Size:        len(content),
+		DownloadURL: f.server.URL + "/raw/" + owner + "/" + repo + "/" + filePath,
+	}
+}
+
+func (f *FakeGitHub) serveRaw(w http.ResponseWriter, owner, repo, filePath string) {
+	r, ok := f.repos[owner+"/"+repo]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	content, ok := r.files[filePath]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write([]byte(content))
+}
+
+// resolve returns the commit SHA of a branch or tag.
+func (r *fakeRepo) resolve(ref string) (string, bool) {
+	if sha, ok := r.branches[ref]; ok {
+		return sha, true
+	}
+	sha, ok := r.tags[ref]
+	return sha, ok
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// TempHome points HOME and GSKILLS_HOME at fresh temporary directories for the
+// duration of the test and returns the gskills data directory.
+func TempHome(t testing.TB) string {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	dataDir := t.TempDir()
+	t.Setenv(paths.HomeEnv, dataDir)
+	return dataDir
+}
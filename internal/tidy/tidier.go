@@ -2,12 +2,15 @@ package tidy
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/smy-101/gskills/internal/constants"
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
 )
@@ -15,6 +18,9 @@ import (
 const (
 	// maxWorkers limits the number of concurrent goroutines during cleanup operations.
 	maxWorkers = 10
+	// maxSymlinkHops is how many symlinks isSymlinkLoop follows before
+	// giving up, the same limit Linux applies when resolving a path.
+	maxSymlinkHops = 40
 )
 
 // CleanupReport summarizes the results of a tidy operation.
@@ -22,13 +28,25 @@ const (
 // stale registry entries removed and orphaned symlinks deleted.
 type CleanupReport struct {
 	// StaleRegistryEntries is the count of invalid project links removed from the registry.
-	StaleRegistryEntries int
+	StaleRegistryEntries int `json:"stale_registry_entries"`
 	// OrphanedSymlinks is the count of symlinks removed from project directories.
-	OrphanedSymlinks int
+	OrphanedSymlinks int `json:"orphaned_symlinks"`
 	// SkillsChecked is the total number of skills processed.
-	SkillsChecked int
+	SkillsChecked int `json:"skills_checked"`
 	// ProjectsScanned is the number of unique project directories examined.
-	ProjectsScanned int
+	ProjectsScanned int `json:"projects_scanned"`
+	// RemovedEntries lists the project links removed from the registry,
+	// sorted by skill and project.
+	RemovedEntries []RemovedEntry `json:"removed_entries"`
+	// RemovedSymlinks lists the orphaned symlinks deleted, sorted by path.
+	RemovedSymlinks []string `json:"removed_symlinks"`
+}
+
+// RemovedEntry is a stale project link removed from the registry.
+type RemovedEntry struct {
+	Skill       string `json:"skill"`
+	Project     string `json:"project"`
+	SymlinkPath string `json:"symlink_path"`
 }
 
 // Field represents a key-value pair for structured logging.
@@ -64,10 +82,12 @@ func (NoOpLogger) Error(msg string, err error, fields ...Field) {}
 
 // Tidier handles cleanup of stale registry entries and orphaned symlinks.
 // It performs two main operations:
-// 1. Removes registry entries for symlinks that no longer exist on disk
+// 1. Removes registry entries whose symlink is missing or no longer points at the skill
 // 2. Deletes orphaned symlinks that point to non-existent skills
 type Tidier struct {
 	logger Logger
+	// project, when set, restricts cleanup to this absolute project directory.
+	project string
 }
 
 // NewTidier creates a new Tidier instance with a no-op logger.
@@ -84,7 +104,39 @@ func NewTidierWithLogger(logger Logger) *Tidier {
 	}
 }
 
+// SetProject restricts cleanup to a single project directory: only registry
+// links into projectPath are checked and only its skills directory is
+// scanned for orphaned symlinks. An empty projectPath removes the filter.
+func (t *Tidier) SetProject(projectPath string) error {
+	if projectPath == "" {
+		t.project = ""
+		return nil
+	}
+
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return &TidyError{
+			Type:    ErrorTypeInvalidPath,
+			Message: "failed to resolve project path",
+			Err:     err,
+		}
+	}
+	t.project = absPath
+	return nil
+}
+
+// includesProject reports whether projectPath is covered by the project filter.
+func (t *Tidier) includesProject(projectPath string) bool {
+	return t.project == "" || filepath.Clean(projectPath) == t.project
+}
+
+// betweenPhases runs after stale registry entries are removed and before the
+// orphaned symlink scan. It is a variable so tests can change the registry
+// between the two phases.
+var betweenPhases = func() {}
+
 // Tidy performs cleanup of stale registry entries and orphaned symlinks.
+// The registry is loaded once and the same snapshot drives both phases.
 // It uses a worker pool pattern to limit concurrent goroutines to maxWorkers.
 // The operation can be cancelled via the provided context.
 //
@@ -93,7 +145,6 @@ func NewTidierWithLogger(logger Logger) *Tidier {
 func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 	report := &CleanupReport{}
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
 	skills, err := registry.LoadRegistry()
 	if err != nil {
@@ -109,18 +160,19 @@ func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 	uniqueProjectPaths := make(map[string]struct{})
 	for _, skill := range skills {
 		for projectPath := range skill.LinkedProjects {
-			uniqueProjectPaths[projectPath] = struct{}{}
+			if t.includesProject(projectPath) {
+				uniqueProjectPaths[projectPath] = struct{}{}
+			}
 		}
 	}
+	if t.project != "" {
+		// The project may hold orphaned symlinks even when no skill is
+		// recorded as linked to it anymore.
+		uniqueProjectPaths[t.project] = struct{}{}
+	}
 
 	report.ProjectsScanned = len(uniqueProjectPaths)
 
-	type pendingUpdate struct {
-		skillID       string
-		staleProjects []string
-		skill         types.SkillMetadata
-	}
-
 	updateChan := make(chan pendingUpdate, len(skills))
 	sem := make(chan struct{}, maxWorkers)
 
@@ -147,10 +199,6 @@ func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 			staleEntries := t.findStaleLinks(s)
 
 			if len(staleEntries) > 0 {
-				mu.Lock()
-				report.StaleRegistryEntries += len(staleEntries)
-				mu.Unlock()
-
 				updateChan <- pendingUpdate{
 					skillID:       s.ID,
 					staleProjects: staleEntries,
@@ -170,24 +218,23 @@ func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 		pendingUpdates = append(pendingUpdates, update)
 	}
 
-	for _, update := range pendingUpdates {
-		for _, projectPath := range update.staleProjects {
-			delete(update.skill.LinkedProjects, projectPath)
-		}
-
-		if len(update.skill.LinkedProjects) == 0 {
-			update.skill.LinkedProjects = nil
-		}
-
-		if err := registry.UpdateSkill(&update.skill); err != nil {
-			t.logger.Error("Failed to remove stale links from registry", err,
-				Field{Key: "skill", Value: update.skill.Name})
+	report.RemovedEntries = []RemovedEntry{}
+	if len(pendingUpdates) > 0 {
+		removed, err := t.removeStaleLinks(pendingUpdates)
+		if err != nil {
+			t.logger.Error("Failed to remove stale links from registry", err)
 		} else {
-			t.logger.Info("Removed stale links",
-				Field{Key: "skill", Value: update.skill.Name},
-				Field{Key: "count", Value: len(update.staleProjects)})
+			report.RemovedEntries = removed
 		}
 	}
+	sort.Slice(report.RemovedEntries, func(i, j int) bool {
+		a, b := report.RemovedEntries[i], report.RemovedEntries[j]
+		if a.Skill != b.Skill {
+			return a.Skill < b.Skill
+		}
+		return a.Project < b.Project
+	})
+	report.StaleRegistryEntries = len(report.RemovedEntries)
 
 	select {
 	case <-ctx.Done():
@@ -199,7 +246,11 @@ func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 	default:
 	}
 
-	orphanedSymlinks, err := t.findAndRemoveOrphanedSymlinks(ctx, uniqueProjectPaths)
+	betweenPhases()
+
+	removedSymlinks, err := t.findAndRemoveOrphanedSymlinks(ctx, skills, uniqueProjectPaths)
+	report.RemovedSymlinks = removedSymlinks
+	report.OrphanedSymlinks = len(removedSymlinks)
 	if err != nil {
 		return report, &TidyError{
 			Type:    ErrorTypeFilesystem,
@@ -208,19 +259,29 @@ func (t *Tidier) Tidy(ctx context.Context) (*CleanupReport, error) {
 		}
 	}
 
-	report.OrphanedSymlinks = orphanedSymlinks
-
 	return report, nil
 }
 
-// findStaleLinks identifies project links where the symlink no longer exists.
-// It checks each linked project and returns a list of project paths where
-// the recorded symlink path does not exist on disk.
+// findStaleLinks identifies project links that no longer point at the skill.
+// Projects outside the project filter are ignored.
+// A link is stale when nothing exists at the recorded symlink path, when the
+// path has been replaced by a regular file or directory, or when the symlink
+// points somewhere other than the skill's StorePath.
 func (t *Tidier) findStaleLinks(skill types.SkillMetadata) []string {
 	var staleEntries []string
 
 	for projectPath, linkInfo := range skill.LinkedProjects {
-		exists, err := t.checkSymlinkExists(linkInfo.SymlinkPath)
+		if !t.includesProject(projectPath) {
+			continue
+		}
+
+		var valid bool
+		var err error
+		if linkInfo.Copy {
+			valid, err = checkCopyValid(linkInfo.SymlinkPath)
+		} else {
+			valid, err = t.checkSymlinkValid(linkInfo.SymlinkPath, skill.StorePath)
+		}
 		if err != nil {
 			t.logger.Warn("Failed to check symlink",
 				Field{Key: "path", Value: linkInfo.SymlinkPath},
@@ -228,7 +289,7 @@ func (t *Tidier) findStaleLinks(skill types.SkillMetadata) []string {
 			continue
 		}
 
-		if !exists {
+		if !valid {
 			staleEntries = append(staleEntries, projectPath)
 			t.logger.Debug("Found stale link",
 				Field{Key: "skill", Value: skill.Name},
@@ -239,9 +300,11 @@ func (t *Tidier) findStaleLinks(skill types.SkillMetadata) []string {
 	return staleEntries
 }
 
-// checkSymlinkExists checks if a symlink exists at the given path.
-func (t *Tidier) checkSymlinkExists(symlinkPath string) (bool, error) {
-	_, err := os.Lstat(symlinkPath)
+// checkSymlinkValid reports whether symlinkPath is a symlink whose target is
+// storePath. A missing path, a non-symlink, a circular symlink and a symlink
+// pointing elsewhere all report false without an error.
+func (t *Tidier) checkSymlinkValid(symlinkPath, storePath string) (bool, error) {
+	info, err := os.Lstat(symlinkPath)
 	if os.IsNotExist(err) {
 		return false, nil
 	}
@@ -249,23 +312,158 @@ func (t *Tidier) checkSymlinkExists(symlinkPath string) (bool, error) {
 		return false, err
 	}
 
-	return true, nil
+	if info.Mode()&os.ModeSymlink == 0 || isSymlinkLoop(symlinkPath) {
+		return false, nil
+	}
+
+	target, err := resolveSymlinkTarget(symlinkPath)
+	if err != nil {
+		return false, err
+	}
+
+	return target == filepath.Clean(storePath), nil
 }
 
-// findAndRemoveOrphanedSymlinks scans project directories for symlinks pointing
-// to non-existent skills and removes them.
-func (t *Tidier) findAndRemoveOrphanedSymlinks(ctx context.Context, projectPaths map[string]struct{}) (int, error) {
-	skills, err := registry.LoadRegistry()
+// checkCopyValid reports whether a link made with link --copy still has its
+// directory at copyPath.
+func checkCopyValid(copyPath string) (bool, error) {
+	info, err := os.Lstat(copyPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to load registry: %w", err)
+		return false, err
 	}
+	return info.IsDir(), nil
+}
+
+// resolveSymlinkTarget returns the absolute, cleaned target of the symlink at
+// symlinkPath. Relative targets are resolved against the symlink's directory.
+func resolveSymlinkTarget(symlinkPath string) (string, error) {
+	target, err := os.Readlink(symlinkPath)
+	if err != nil {
+		return "", err
+	}
+
+	target = filepath.Clean(target)
+	if filepath.IsAbs(target) {
+		return target, nil
+	}
+	return filepath.Abs(filepath.Join(filepath.Dir(symlinkPath), target))
+}
+
+// pendingUpdate is a skill whose links into staleProjects were found stale.
+type pendingUpdate struct {
+	skillID       string
+	staleProjects []string
+	skill         types.SkillMetadata
+}
 
+// removeStaleLinks drops the stale links of updates from the registry in a
+// single registry transaction and returns the removed entries. A link that
+// changed since it was found stale, e.g. because the skill was linked again
+// meanwhile, is kept.
+func (t *Tidier) removeStaleLinks(updates []pendingUpdate) ([]RemovedEntry, error) {
+	stale := make(map[string]pendingUpdate, len(updates))
+	for _, update := range updates {
+		stale[update.skillID] = update
+	}
+
+	var removed []RemovedEntry
+	err := registry.Update(func(skills []types.SkillMetadata) ([]types.SkillMetadata, error) {
+		for i := range skills {
+			skill := &skills[i]
+			update, ok := stale[skill.ID]
+			if !ok {
+				continue
+			}
+			count := 0
+			for _, projectPath := range update.staleProjects {
+				link, ok := skill.LinkedProjects[projectPath]
+				found := update.skill.LinkedProjects[projectPath]
+				if !ok || link.SymlinkPath != found.SymlinkPath || !link.LinkedAt.Equal(found.LinkedAt) {
+					continue
+				}
+				removed = append(removed, RemovedEntry{
+					Skill:       skill.Name,
+					Project:     projectPath,
+					SymlinkPath: link.SymlinkPath,
+				})
+				delete(skill.LinkedProjects, projectPath)
+				count++
+			}
+			if len(skill.LinkedProjects) == 0 {
+				skill.LinkedProjects = nil
+			}
+			if count > 0 {
+				t.logger.Info("Removed stale links",
+					Field{Key: "skill", Value: skill.Name},
+					Field{Key: "count", Value: count})
+			}
+		}
+		return skills, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// isSymlinkLoop reports whether following the symlink at symlinkPath comes
+// back to a symlink already followed, itself included, or passes through
+// more than maxSymlinkHops symlinks. Such a symlink never resolves. A chain
+// that ends at a missing path or at anything but a symlink is not a loop.
+func isSymlinkLoop(symlinkPath string) bool {
+	visited := make(map[string]bool)
+	path := filepath.Clean(symlinkPath)
+	for range maxSymlinkHops {
+		if visited[path] {
+			return true
+		}
+		visited[path] = true
+
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return false
+		}
+		next, err := resolveSymlinkTarget(path)
+		if err != nil {
+			return false
+		}
+		path = next
+	}
+	return true
+}
+
+// findAndRemoveOrphanedSymlinks scans the skills directory of every supported
+// agent framework (.opencode/skills, .claude/skills and .cursor/skills) in the
+// project directories for symlinks pointing to skills that are not in skills,
+// removes them and returns their paths, sorted. In the .claude and .cursor
+// skills directories, which other tools use too, only symlinks into a
+// gskills store or recorded in the registry are considered. skills is the
+// registry snapshot taken at the start of Tidy, so registry writes made by
+// other commands while tidy runs cannot make a valid link look orphaned.
+func (t *Tidier) findAndRemoveOrphanedSymlinks(ctx context.Context, skills []types.SkillMetadata, projectPaths map[string]struct{}) ([]string, error) {
 	validSkillStorePaths := make(map[string]string)
+	// Links created under an alias are named differently from their skill,
+	// so their recorded symlink paths are accepted as well.
+	recordedSymlinks := make(map[string]bool)
+	// storeDirs are the directories gskills keeps skills in: the default
+	// store and the parent of every registered store path, which covers
+	// skills added with --store.
+	storeDirs := make(map[string]bool)
+	if dataDir, err := paths.DataDir(); err == nil {
+		storeDirs[paths.SkillsDir(dataDir)] = true
+	}
 	for _, skill := range skills {
 		validSkillStorePaths[skill.StorePath] = skill.Name
+		storeDirs[filepath.Dir(skill.StorePath)] = true
+		for _, linkInfo := range skill.LinkedProjects {
+			recordedSymlinks[linkInfo.SymlinkPath] = true
+		}
 	}
 
-	var orphanedCount int
+	removed := []string{}
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -274,7 +472,9 @@ func (t *Tidier) findAndRemoveOrphanedSymlinks(ctx context.Context, projectPaths
 	for projectPath := range projectPaths {
 		select {
 		case <-ctx.Done():
-			return orphanedCount, ctx.Err()
+			wg.Wait()
+			sort.Strings(removed)
+			return removed, ctx.Err()
 		default:
 		}
 
@@ -283,83 +483,93 @@ func (t *Tidier) findAndRemoveOrphanedSymlinks(ctx context.Context, projectPaths
 		go func(ppath string) {
 			defer func() { <-sem; wg.Done() }()
 
-			skillsDirPath := filepath.Join(ppath, constants.OpencodeSkillsDir)
-			entries, err := os.ReadDir(skillsDirPath)
-			if err != nil {
-				if os.IsNotExist(err) {
-					return
-				}
-				t.logger.Warn("Failed to read project skills directory",
-					Field{Key: "path", Value: skillsDirPath},
-					Field{Key: "error", Value: err})
-				return
-			}
-
-			localOrphaned := 0
+			var localRemoved []string
 
-			for _, entry := range entries {
-				symlinkPath := filepath.Join(skillsDirPath, entry.Name())
-
-				info, err := os.Lstat(symlinkPath)
+			for _, skillsDir := range link.SkillsDirs() {
+				// The .claude and .cursor skills directories are shared
+				// with other tools, so only symlinks gskills could have
+				// created are removed there.
+				shared := skillsDir != constants.OpencodeSkillsDir
+				skillsDirPath := filepath.Join(ppath, skillsDir)
+				entries, err := os.ReadDir(skillsDirPath)
 				if err != nil {
+					if !os.IsNotExist(err) {
+						t.logger.Warn("Failed to read project skills directory",
+							Field{Key: "path", Value: skillsDirPath},
+							Field{Key: "error", Value: err})
+					}
 					continue
 				}
 
-				if info.Mode()&os.ModeSymlink == 0 {
-					continue
-				}
+				for _, entry := range entries {
+					symlinkPath := filepath.Join(skillsDirPath, entry.Name())
 
-				target, err := os.Readlink(symlinkPath)
-				if err != nil {
-					t.logger.Warn("Failed to read symlink",
-						Field{Key: "path", Value: symlinkPath},
-						Field{Key: "error", Value: err})
-					continue
-				}
-
-				target = filepath.Clean(target)
+					info, err := os.Lstat(symlinkPath)
+					if err != nil {
+						continue
+					}
 
-				isValid := false
+					if info.Mode()&os.ModeSymlink == 0 {
+						continue
+					}
 
-				var absTarget string
-				if filepath.IsAbs(target) {
-					absTarget = target
-				} else {
-					absTarget, err = filepath.Abs(filepath.Join(filepath.Dir(symlinkPath), target))
+					absTarget, err := resolveSymlinkTarget(symlinkPath)
 					if err != nil {
-						t.logger.Warn("Failed to resolve absolute path",
+						t.logger.Warn("Failed to resolve symlink target",
 							Field{Key: "path", Value: symlinkPath},
-							Field{Key: "target", Value: target},
 							Field{Key: "error", Value: err})
 						continue
 					}
-				}
-
-				if skillName, ok := validSkillStorePaths[absTarget]; ok {
-					if skillName == entry.Name() {
-						isValid = true
+					if shared && !recordedSymlinks[symlinkPath] && !insideStore(absTarget, storeDirs) {
+						continue
 					}
-				}
 
-				if !isValid {
-					if err := os.Remove(symlinkPath); err != nil {
-						t.logger.Error("Failed to remove orphaned symlink", err,
-							Field{Key: "path", Value: symlinkPath})
-					} else {
-						t.logger.Info("Removed orphaned symlink",
+					isValid := false
+
+					// A symlink that points at itself or loops through other
+					// symlinks can never reach a skill, whatever its target
+					// string looks like, so it is always orphaned.
+					if isSymlinkLoop(symlinkPath) {
+						t.logger.Debug("Found circular symlink",
 							Field{Key: "path", Value: symlinkPath})
-						localOrphaned++
+					} else if skillName, ok := validSkillStorePaths[absTarget]; ok {
+						if skillName == entry.Name() || recordedSymlinks[symlinkPath] {
+							isValid = true
+						}
+					}
+
+					if !isValid {
+						if err := os.Remove(symlinkPath); err != nil {
+							t.logger.Error("Failed to remove orphaned symlink", err,
+								Field{Key: "path", Value: symlinkPath})
+						} else {
+							t.logger.Info("Removed orphaned symlink",
+								Field{Key: "path", Value: symlinkPath})
+							localRemoved = append(localRemoved, symlinkPath)
+						}
 					}
 				}
 			}
 
 			mu.Lock()
-			orphanedCount += localOrphaned
+			removed = append(removed, localRemoved...)
 			mu.Unlock()
 		}(projectPath)
 	}
 
 	wg.Wait()
+	sort.Strings(removed)
+
+	return removed, nil
+}
 
-	return orphanedCount, nil
+// insideStore reports whether path is inside one of storeDirs.
+func insideStore(path string, storeDirs map[string]bool) bool {
+	for dir := range storeDirs {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -12,69 +14,98 @@ import (
 	"github.com/smy-101/gskills/internal/types"
 )
 
-func TestCheckSymlinkExists(t *testing.T) {
+func TestCheckSymlinkValid(t *testing.T) {
 	tidier := NewTidier()
 
 	tests := []struct {
 		name    string
-		setup   func() (string, func())
+		setup   func(t *testing.T, storePath string) string
 		want    bool
 		wantErr bool
 	}{
 		{
-			name: "existing symlink",
-			setup: func() (string, func()) {
-				tmpDir := t.TempDir()
-				targetFile := filepath.Join(tmpDir, "target")
-				if err := os.WriteFile(targetFile, []byte("test"), 0644); err != nil {
-					t.Fatalf("failed to create target file: %v", err)
+			name: "symlink to store path",
+			setup: func(t *testing.T, storePath string) string {
+				symlinkPath := filepath.Join(t.TempDir(), "symlink")
+				if err := os.Symlink(storePath, symlinkPath); err != nil {
+					t.Fatalf("failed to create symlink: %v", err)
 				}
-				symlinkPath := filepath.Join(tmpDir, "symlink")
-				if err := os.Symlink(targetFile, symlinkPath); err != nil {
+				return symlinkPath
+			},
+			want: true,
+		},
+		{
+			name: "relative symlink to store path",
+			setup: func(t *testing.T, storePath string) string {
+				symlinkPath := filepath.Join(filepath.Dir(storePath), "project", "symlink")
+				if err := os.MkdirAll(filepath.Dir(symlinkPath), 0755); err != nil {
+					t.Fatalf("failed to create project dir: %v", err)
+				}
+				if err := os.Symlink(filepath.Join("..", filepath.Base(storePath)), symlinkPath); err != nil {
 					t.Fatalf("failed to create symlink: %v", err)
 				}
-				return symlinkPath, func() {}
+				return symlinkPath
 			},
-			want:    true,
-			wantErr: false,
+			want: true,
 		},
 		{
 			name: "non-existent symlink",
-			setup: func() (string, func()) {
-				tmpDir := t.TempDir()
-				symlinkPath := filepath.Join(tmpDir, "nonexistent")
-				return symlinkPath, func() {}
+			setup: func(t *testing.T, storePath string) string {
+				return filepath.Join(t.TempDir(), "nonexistent")
 			},
-			want:    false,
-			wantErr: false,
+			want: false,
 		},
 		{
-			name: "regular file (not symlink)",
-			setup: func() (string, func()) {
-				tmpDir := t.TempDir()
-				filePath := filepath.Join(tmpDir, "regular")
+			name: "clobbered by regular file",
+			setup: func(t *testing.T, storePath string) string {
+				filePath := filepath.Join(t.TempDir(), "regular")
 				if err := os.WriteFile(filePath, []byte("test"), 0644); err != nil {
 					t.Fatalf("failed to create file: %v", err)
 				}
-				return filePath, func() {}
+				return filePath
 			},
-			want:    true,
-			wantErr: false,
+			want: false,
+		},
+		{
+			name: "clobbered by directory",
+			setup: func(t *testing.T, storePath string) string {
+				dirPath := filepath.Join(t.TempDir(), "dir")
+				if err := os.Mkdir(dirPath, 0755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				return dirPath
+			},
+			want: false,
+		},
+		{
+			name: "symlink to wrong target",
+			setup: func(t *testing.T, storePath string) string {
+				otherTarget := t.TempDir()
+				symlinkPath := filepath.Join(t.TempDir(), "symlink")
+				if err := os.Symlink(otherTarget, symlinkPath); err != nil {
+					t.Fatalf("failed to create symlink: %v", err)
+				}
+				return symlinkPath
+			},
+			want: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, cleanup := tt.setup()
-			defer cleanup()
+			storePath := filepath.Join(t.TempDir(), "skill")
+			if err := os.MkdirAll(storePath, 0755); err != nil {
+				t.Fatalf("failed to create store dir: %v", err)
+			}
+			path := tt.setup(t, storePath)
 
-			got, err := tidier.checkSymlinkExists(path)
+			got, err := tidier.checkSymlinkValid(path, storePath)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("checkSymlinkExists() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("checkSymlinkValid() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("checkSymlinkExists() = %v, want %v", got, tt.want)
+				t.Errorf("checkSymlinkValid() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -84,40 +115,56 @@ func TestFindStaleLinks(t *testing.T) {
 	tidier := NewTidier()
 
 	tmpDir := t.TempDir()
-	projectPath := filepath.Join(tmpDir, "project")
-	if err := os.MkdirAll(projectPath, 0755); err != nil {
-		t.Fatalf("failed to create project dir: %v", err)
+	storePath := filepath.Join(tmpDir, "store", "test-skill")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
 	}
 
-	existingSymlink := filepath.Join(projectPath, "existing_link")
-	if err := os.Symlink("/some/target", existingSymlink); err != nil {
+	newProject := func(name string) string {
+		projectPath := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(projectPath, 0755); err != nil {
+			t.Fatalf("failed to create project dir: %v", err)
+		}
+		return projectPath
+	}
+
+	validProject := newProject("valid")
+	validSymlink := filepath.Join(validProject, "test-skill")
+	if err := os.Symlink(storePath, validSymlink); err != nil {
 		t.Fatalf("failed to create symlink: %v", err)
 	}
 
-	missingSymlink := filepath.Join(projectPath, "missing_link")
+	clobberedProject := newProject("clobbered")
+	clobberedPath := filepath.Join(clobberedProject, "test-skill")
+	if err := os.WriteFile(clobberedPath, []byte("not a link"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	wrongTargetProject := newProject("wrong-target")
+	wrongTargetSymlink := filepath.Join(wrongTargetProject, "test-skill")
+	if err := os.Symlink("/some/target", wrongTargetSymlink); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
 
 	skill := types.SkillMetadata{
 		ID:        "test-skill-1",
 		Name:      "test-skill",
-		StorePath: "/store/path",
+		StorePath: storePath,
 		LinkedProjects: map[string]types.LinkedProjectInfo{
-			projectPath: {
-				SymlinkPath: existingSymlink,
-			},
-			"/another/project": {
-				SymlinkPath: missingSymlink,
-			},
+			validProject:       {SymlinkPath: validSymlink},
+			clobberedProject:   {SymlinkPath: clobberedPath},
+			wrongTargetProject: {SymlinkPath: wrongTargetSymlink},
+			"/another/project": {SymlinkPath: filepath.Join(tmpDir, "missing_link")},
 		},
 	}
 
 	staleLinks := tidier.findStaleLinks(skill)
+	sort.Strings(staleLinks)
 
-	if len(staleLinks) != 1 {
-		t.Errorf("findStaleLinks() returned %d stale links, want 1", len(staleLinks))
-	}
-
-	if staleLinks[0] != "/another/project" {
-		t.Errorf("findStaleLinks() returned %s, want /another/project", staleLinks[0])
+	want := []string{"/another/project", clobberedProject, wrongTargetProject}
+	sort.Strings(want)
+	if !reflect.DeepEqual(staleLinks, want) {
+		t.Errorf("findStaleLinks() = %v, want %v", staleLinks, want)
 	}
 }
 
@@ -304,6 +351,370 @@ func TestTidy(t *testing.T) {
 	}
 }
 
+func TestTidy_UsesRegistrySnapshotForBothPhases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "skill1")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+
+	projectPath := filepath.Join(tmpDir, "project")
+	skillsDir := filepath.Join(projectPath, ".opencode", "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	symlinkPath := filepath.Join(skillsDir, "skill1")
+	if err := os.Symlink(storePath, symlinkPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "skill-1",
+			Name:      "skill1",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectPath: {SymlinkPath: symlinkPath},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	// Another command rewrites the registry while tidy runs, e.g. a remove
+	// followed by a re-add that has not been saved yet.
+	oldBetweenPhases := betweenPhases
+	betweenPhases = func() {
+		if err := registry.SaveRegistry([]types.SkillMetadata{}); err != nil {
+			t.Errorf("failed to rewrite registry: %v", err)
+		}
+	}
+	defer func() { betweenPhases = oldBetweenPhases }()
+
+	report, err := NewTidier().Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.OrphanedSymlinks != 0 {
+		t.Errorf("Tidy() OrphanedSymlinks = %d, want 0", report.OrphanedSymlinks)
+	}
+	if _, err := os.Lstat(symlinkPath); err != nil {
+		t.Errorf("valid symlink was removed: %v", err)
+	}
+}
+
+func TestTidy_KeepsAliasedLinks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "my-prompt-engineer")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+
+	projectPath := filepath.Join(tmpDir, "project")
+	skillsDir := filepath.Join(projectPath, ".opencode", "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	aliasPath := filepath.Join(skillsDir, "pe")
+	if err := os.Symlink(storePath, aliasPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	// An unrecorded symlink to the same store under another name is orphaned.
+	strayPath := filepath.Join(skillsDir, "stray")
+	if err := os.Symlink(storePath, strayPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "my-prompt-engineer@main",
+			Name:      "my-prompt-engineer",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectPath: {SymlinkPath: aliasPath, Alias: "pe"},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	report, err := NewTidier().Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.OrphanedSymlinks != 1 || report.StaleRegistryEntries != 0 {
+		t.Errorf("Tidy() report = %+v, want 1 orphan and no stale entries", report)
+	}
+	if _, err := os.Lstat(aliasPath); err != nil {
+		t.Errorf("aliased symlink was removed: %v", err)
+	}
+	if _, err := os.Lstat(strayPath); !os.IsNotExist(err) {
+		t.Error("unrecorded symlink should be removed")
+	}
+}
+
+func TestTidy_RemovesCircularSymlinks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "skill1")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+
+	projectPath := filepath.Join(tmpDir, "project")
+	skillsDir := filepath.Join(projectPath, ".opencode", "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	validPath := filepath.Join(skillsDir, "skill1")
+	if err := os.Symlink(storePath, validPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	// A self-referencing symlink, and two symlinks pointing at each other.
+	selfPath := filepath.Join(skillsDir, "self")
+	loopA := filepath.Join(skillsDir, "loop-a")
+	loopB := filepath.Join(skillsDir, "loop-b")
+	for _, link := range [][2]string{{"self", selfPath}, {loopB, loopA}, {loopA, loopB}} {
+		if err := os.Symlink(link[0], link[1]); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "skill1@main",
+			Name:      "skill1",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectPath: {SymlinkPath: validPath},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	report, err := NewTidier().Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.OrphanedSymlinks != 3 || report.StaleRegistryEntries != 0 {
+		t.Errorf("Tidy() report = %+v, want 3 orphans and no stale entries", report)
+	}
+	if _, err := os.Lstat(validPath); err != nil {
+		t.Errorf("valid symlink was removed: %v", err)
+	}
+	for _, path := range []string{selfPath, loopA, loopB} {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("circular symlink %s should be removed", filepath.Base(path))
+		}
+	}
+}
+
+func TestTidy_ScansAllFrameworkSkillsDirs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "skill1")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+
+	projectPath := filepath.Join(tmpDir, "project")
+	validPath := filepath.Join(projectPath, ".claude", "skills", "skill1")
+	var orphans []string
+	for _, dir := range []string{".opencode", ".claude", ".cursor"} {
+		skillsDir := filepath.Join(projectPath, dir, "skills")
+		if err := os.MkdirAll(skillsDir, 0755); err != nil {
+			t.Fatalf("failed to create project skills dir: %v", err)
+		}
+		orphan := filepath.Join(skillsDir, "deleted-skill")
+		if err := os.Symlink(filepath.Join(tmpDir, "skills", "deleted-skill"), orphan); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+		orphans = append(orphans, orphan)
+	}
+	if err := os.Symlink(storePath, validPath); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "skill1@main",
+			Name:      "skill1",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectPath: {SymlinkPath: validPath},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	report, err := NewTidier().Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.OrphanedSymlinks != len(orphans) || report.StaleRegistryEntries != 0 {
+		t.Errorf("Tidy() report = %+v, want %d orphans and no stale entries", report, len(orphans))
+	}
+	if _, err := os.Lstat(validPath); err != nil {
+		t.Errorf("valid symlink was removed: %v", err)
+	}
+	for _, path := range orphans {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("orphaned symlink %s should be removed", path)
+		}
+	}
+}
+
+func TestTidy_KeepsForeignSymlinksInSharedDirs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "skill1")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+	foreignTarget := filepath.Join(tmpDir, "other-tool", "their-skill")
+	if err := os.MkdirAll(foreignTarget, 0755); err != nil {
+		t.Fatalf("failed to create foreign target: %v", err)
+	}
+
+	projectPath := filepath.Join(tmpDir, "project")
+	validPath := filepath.Join(projectPath, ".claude", "skills", "skill1")
+	foreignPath := filepath.Join(projectPath, ".claude", "skills", "their-skill")
+	orphanPath := filepath.Join(projectPath, ".claude", "skills", "deleted-skill")
+	if err := os.MkdirAll(filepath.Dir(validPath), 0755); err != nil {
+		t.Fatalf("failed to create project skills dir: %v", err)
+	}
+	for path, target := range map[string]string{
+		validPath:   storePath,
+		foreignPath: foreignTarget,
+		orphanPath:  filepath.Join(tmpDir, "skills", "deleted-skill"),
+	} {
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+	}
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "skill1@main",
+			Name:      "skill1",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				projectPath: {SymlinkPath: validPath},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	report, err := NewTidier().Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.OrphanedSymlinks != 1 {
+		t.Errorf("Tidy() report = %+v, want only the symlink into the store removed", report)
+	}
+	if _, err := os.Lstat(foreignPath); err != nil {
+		t.Errorf("symlink of another tool was removed: %v", err)
+	}
+	if _, err := os.Lstat(orphanPath); !os.IsNotExist(err) {
+		t.Errorf("orphaned symlink into the store should be removed")
+	}
+}
+
+func TestTidy_ProjectFilter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	storePath := filepath.Join(tmpDir, "skills", "skill1")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+
+	// Each project has a stale registry link (its symlink is missing) and an
+	// orphaned symlink to a deleted skill.
+	newProject := func(name string) (projectPath, orphanPath string) {
+		projectPath = filepath.Join(tmpDir, name)
+		skillsDir := filepath.Join(projectPath, ".opencode", "skills")
+		if err := os.MkdirAll(skillsDir, 0755); err != nil {
+			t.Fatalf("failed to create project skills dir: %v", err)
+		}
+		orphanPath = filepath.Join(skillsDir, "deleted-skill")
+		if err := os.Symlink(filepath.Join(tmpDir, "skills", "deleted-skill"), orphanPath); err != nil {
+			t.Fatalf("failed to create orphaned symlink: %v", err)
+		}
+		return projectPath, orphanPath
+	}
+	targetProject, targetOrphan := newProject("target")
+	otherProject, otherOrphan := newProject("other")
+
+	skills := []types.SkillMetadata{
+		{
+			ID:        "skill-1",
+			Name:      "skill1",
+			StorePath: storePath,
+			LinkedProjects: map[string]types.LinkedProjectInfo{
+				targetProject: {SymlinkPath: filepath.Join(targetProject, ".opencode", "skills", "skill1")},
+				otherProject:  {SymlinkPath: filepath.Join(otherProject, ".opencode", "skills", "skill1")},
+			},
+		},
+	}
+	if err := registry.SaveRegistry(skills); err != nil {
+		t.Fatalf("failed to setup registry: %v", err)
+	}
+
+	tidier := NewTidier()
+	if err := tidier.SetProject(targetProject); err != nil {
+		t.Fatalf("SetProject() error = %v", err)
+	}
+
+	report, err := tidier.Tidy(context.Background())
+	if err != nil {
+		t.Fatalf("Tidy() error = %v", err)
+	}
+
+	if report.StaleRegistryEntries != 1 || report.OrphanedSymlinks != 1 || report.ProjectsScanned != 1 {
+		t.Errorf("Tidy() report = %+v, want 1 stale entry, 1 orphan, 1 project", report)
+	}
+
+	if _, err := os.Lstat(targetOrphan); !os.IsNotExist(err) {
+		t.Error("orphaned symlink in the target project should be removed")
+	}
+	if _, err := os.Lstat(otherOrphan); err != nil {
+		t.Errorf("orphaned symlink in the other project should be kept: %v", err)
+	}
+
+	skill, err := registry.FindSkillByName("skill1")
+	if err != nil {
+		t.Fatalf("FindSkillByName() error = %v", err)
+	}
+	if _, ok := skill.LinkedProjects[targetProject]; ok {
+		t.Error("stale link to the target project should be removed from the registry")
+	}
+	if _, ok := skill.LinkedProjects[otherProject]; !ok {
+		t.Error("link to the other project should be kept in the registry")
+	}
+}
+
 func TestTidyError(t *testing.T) {
 	tests := []struct {
 		name   string
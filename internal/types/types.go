@@ -4,14 +4,36 @@ import "time"
 
 // SkillMetadata 技能元数据
 type SkillMetadata struct {
-	ID             string                       `json:"id"`
-	Name           string                       `json:"name"`
-	SourceURL      string                       `json:"source_url"`
-	StorePath      string                       `json:"store_path"`
-	UpdatedAt      time.Time                    `json:"updated_at"`
-	Version        string                       `json:"version,omitempty"`
-	CommitSHA      string                       `json:"commit_sha"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	SourceURL string    `json:"source_url"`
+	StorePath string    `json:"store_path"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   string    `json:"version,omitempty"`
+	CommitSHA string    `json:"commit_sha"`
+	// RefType records whether Version is a branch ("branch") or a tag
+	// ("tag"). Empty for local skills and entries added before it existed,
+	// which are treated as branches.
+	RefType string `json:"ref_type,omitempty"`
+	// TagPattern, for skills added from a tag, is a glob such as "v*". When
+	// set, update moves the skill to the newest release tag matching it.
+	TagPattern string `json:"tag_pattern,omitempty"`
+	// Unverified marks a skill added without checking for SKILL.md.
+	Unverified bool `json:"unverified,omitempty"`
+	// Checksum is the aggregate checksum ("sha256:<hex>") the skill was
+	// verified against when added with --checksum.
+	Checksum string `json:"checksum,omitempty"`
+	// IgnoreApplied records that the skill's .gskillsignore was applied, so
+	// some upstream files were deliberately not downloaded.
+	IgnoreApplied bool `json:"ignore_applied,omitempty"`
+	// IncludeHidden records that the skill was added with --include-hidden,
+	// so update keeps its top-level dotfiles too.
+	IncludeHidden bool `json:"include_hidden,omitempty"`
+	// UpdatePolicy decides how update treats the skill: "auto", "manual",
+	// "pinned" or "latest-tag". Empty behaves like "auto".
+	UpdatePolicy   string                       `json:"update_policy,omitempty"`
 	Description    string                       `json:"description,omitempty"`
+	Tags           []string                     `json:"tags,omitempty"`
 	LinkedProjects map[string]LinkedProjectInfo `json:"linked_projects,omitempty"`
 }
 
@@ -19,6 +41,15 @@ type SkillMetadata struct {
 type LinkedProjectInfo struct {
 	SymlinkPath string    `json:"symlink_path"`
 	LinkedAt    time.Time `json:"linked_at"`
+	// Version and CommitSHA record the skill version at link time.
+	Version   string `json:"version,omitempty"`
+	CommitSHA string `json:"commit_sha,omitempty"`
+	// Alias is the name the skill was linked under when it differs from the
+	// skill name; SymlinkPath then ends in the alias.
+	Alias string `json:"alias,omitempty"`
+	// Copy is set when the skill was copied into the project instead of
+	// symlinked; SymlinkPath is then a directory holding the copy.
+	Copy bool `json:"copy,omitempty"`
 }
 
 // GitHubContent GitHub API返回的内容项
@@ -32,3 +63,19 @@ type GitHubContent struct {
 	HTMLURL     string `json:"html_url"`
 	DownloadURL string `json:"download_url"`
 }
+
+// GitHubRelease GitHub API返回的 release 信息
+type GitHubRelease struct {
+	TagName string               `json:"tag_name"`
+	Name    string               `json:"name"`
+	HTMLURL string               `json:"html_url"`
+	Assets  []GitHubReleaseAsset `json:"assets"`
+}
+
+// GitHubReleaseAsset release 中的可下载文件
+type GitHubReleaseAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	URL                string `json:"url"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
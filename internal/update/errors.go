@@ -9,6 +9,16 @@ const (
 	UpdateErrorTypeDownload
 	UpdateErrorTypeRegistry
 	UpdateErrorTypeNotFound
+	// UpdateErrorTypeInvalid is an update whose new content is not a valid
+	// skill, e.g. because SKILL.md was renamed or removed upstream. The
+	// installed version is kept.
+	UpdateErrorTypeInvalid
+	// UpdateErrorTypeSource is a skill whose recorded source cannot be parsed
+	// or has nothing to update from, such as a cloned or untracked skill.
+	UpdateErrorTypeSource
+	// UpdateErrorTypeFilesystem is a failure to read or write the local store
+	// while installing an update.
+	UpdateErrorTypeFilesystem
 )
 
 type UpdateError struct {
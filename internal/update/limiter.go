@@ -0,0 +1,86 @@
+package update
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+)
+
+// lowRateLimitRemaining is the remaining GitHub quota below which update
+// checks stop running concurrently and are spread over the reset window.
+const lowRateLimitRemaining = 50
+
+// paceWait waits d before the next paced check. It is a variable so tests
+// can observe the pacing without sleeping.
+var paceWait = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// checkLimiter bounds the concurrent update checks of CheckAllUpdates. While
+// the GitHub quota is healthy up to maxConcurrentChecks run at once; once the
+// remaining quota drops below lowRateLimitRemaining, checks run one at a
+// time, each waiting its share of the time left until the quota resets, so
+// that a burst does not end in 403s.
+type checkLimiter struct {
+	sem       chan struct{}
+	serial    sync.Mutex
+	rateLimit func() *add.RateLimit
+}
+
+func newCheckLimiter(rateLimit func() *add.RateLimit) *checkLimiter {
+	return &checkLimiter{sem: make(chan struct{}, maxConcurrentChecks), rateLimit: rateLimit}
+}
+
+// acquire waits for a check slot and returns the function releasing it.
+func (l *checkLimiter) acquire(ctx context.Context) (func(), error) {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if !isLowRateLimit(l.rateLimit()) {
+		return func() { <-l.sem }, nil
+	}
+
+	l.serial.Lock()
+	// The quota may have changed while waiting for the previous check.
+	if limit := l.rateLimit(); isLowRateLimit(limit) {
+		if err := paceWait(ctx, paceDelay(limit, time.Now())); err != nil {
+			l.serial.Unlock()
+			<-l.sem
+			return nil, err
+		}
+	}
+	return func() {
+		l.serial.Unlock()
+		<-l.sem
+	}, nil
+}
+
+// isLowRateLimit reports whether limit is close enough to exhaustion that
+// checks must be paced. Servers without rate limiting report no limit.
+func isLowRateLimit(limit *add.RateLimit) bool {
+	return limit != nil && limit.Limit > 0 && limit.Remaining < lowRateLimitRemaining
+}
+
+// paceDelay spreads the remaining quota evenly over the time left until it
+// resets. With nothing left it waits for the reset itself.
+func paceDelay(limit *add.RateLimit, now time.Time) time.Duration {
+	window := limit.Reset.Sub(now)
+	if window <= 0 {
+		return 0
+	}
+	if limit.Remaining <= 0 {
+		return window
+	}
+	return window / time.Duration(limit.Remaining)
+}
@@ -0,0 +1,64 @@
+package update
+
+import (
+	"fmt"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// Policy returns the update policy of skill, add.UpdatePolicyAuto when none
+// is recorded.
+func Policy(skill *types.SkillMetadata) string {
+	if skill.UpdatePolicy == "" {
+		return add.UpdatePolicyAuto
+	}
+	return skill.UpdatePolicy
+}
+
+// IsPolicyPinned reports whether skill's update policy forbids any update.
+func IsPolicyPinned(skill *types.SkillMetadata) bool {
+	return skill.UpdatePolicy == add.UpdatePolicyPinned
+}
+
+// SkipsBulkUpdate reports whether skill is left out when all skills are
+// checked or updated: pinned skills never update, and manual ones only when
+// named explicitly.
+func SkipsBulkUpdate(skill *types.SkillMetadata) bool {
+	return skill.UpdatePolicy == add.UpdatePolicyManual || skill.UpdatePolicy == add.UpdatePolicyPinned
+}
+
+// followedTagPattern returns the glob of the release tags a skill added from
+// a tag moves to, or "" when its tag is frozen. The latest-tag policy
+// follows every tag unless the skill has its own pattern.
+func followedTagPattern(skill *types.SkillMetadata) string {
+	if skill.TagPattern == "" && skill.UpdatePolicy == add.UpdatePolicyLatestTag {
+		return "*"
+	}
+	return skill.TagPattern
+}
+
+// SetPolicy records policy, which must be one of add.UpdatePolicies, as the
+// update policy of the named skill and returns the updated metadata. The
+// latest-tag policy requires a skill added from a tag.
+func SetPolicy(name, policy string) (*types.SkillMetadata, error) {
+	policy, err := add.ParseUpdatePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	skill, err := registry.FindSkillByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if policy == add.UpdatePolicyLatestTag && !IsTagPinned(skill) {
+		return nil, fmt.Errorf("the %s policy requires a skill added from a tag, but '%s' tracks %s", policy, name, skill.Version)
+	}
+
+	skill.UpdatePolicy = policy
+	if err := registry.UpdateSkill(skill); err != nil {
+		return nil, fmt.Errorf("failed to update skills registry: %w", err)
+	}
+	return skill, nil
+}
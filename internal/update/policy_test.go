@@ -0,0 +1,203 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// policyServer serves owner/repo with the tags v1.0.0 and v1.2.0 and every
+// ref at commit "newsha". It counts the requests it receives.
+func policyServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rate_limit":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.URL.Path == "/repos/owner/repo/tags":
+			json.NewEncoder(w).Encode([]map[string]string{{"name": "v1.2.0"}, {"name": "v1.0.0"}})
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		}
+		requests.Add(1)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests
+}
+
+// policySkill returns a skill of owner/repo at oldsha with policy. A
+// non-empty tag makes it a skill added from that tag; otherwise it tracks
+// main.
+func policySkill(dataDir, policy, tag string) *types.SkillMetadata {
+	skill := &types.SkillMetadata{
+		ID:           "test@main",
+		Name:         "test",
+		Version:      "main",
+		SourceURL:    "https://github.com/owner/repo/tree/main/skills/test",
+		CommitSHA:    "oldsha",
+		RefType:      add.RefTypeBranch,
+		UpdatePolicy: policy,
+		StorePath:    filepath.Join(dataDir, "skills", "test"),
+		UpdatedAt:    time.Now(),
+	}
+	if tag != "" {
+		skill.ID = "test@" + tag
+		skill.Version = tag
+		skill.SourceURL = "https://github.com/owner/repo/tree/" + tag + "/skills/test"
+		skill.RefType = add.RefTypeTag
+	}
+	return skill
+}
+
+func TestCheckAllUpdates_HonorsPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		tag         string
+		wantStatus  UpdateStatus
+		wantVersion string
+	}{
+		{name: "no policy tracks the branch", wantStatus: UpdateStatusAvailable, wantVersion: "main"},
+		{name: "auto tracks the branch", policy: add.UpdatePolicyAuto, wantStatus: UpdateStatusAvailable, wantVersion: "main"},
+		{name: "manual is skipped", policy: add.UpdatePolicyManual, wantStatus: UpdateStatusSkipped},
+		{name: "pinned is skipped", policy: add.UpdatePolicyPinned, wantStatus: UpdateStatusSkipped},
+		{name: "tag without policy is frozen", tag: "v1.0.0", wantStatus: UpdateStatusUpToDate},
+		{name: "latest-tag follows the newest tag", policy: add.UpdatePolicyLatestTag, tag: "v1.0.0", wantStatus: UpdateStatusAvailable, wantVersion: "v1.2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := testutil.TempHome(t)
+			ts, _ := policyServer(t)
+			if err := registry.AddOrUpdateSkill(policySkill(dataDir, tt.policy, tt.tag)); err != nil {
+				t.Fatalf("failed to add skill to registry: %v", err)
+			}
+
+			updater := NewUpdater("")
+			updater.SetBaseURL(ts.URL)
+
+			infos, err := updater.CheckAllUpdates(context.Background())
+			if err != nil {
+				t.Fatalf("CheckAllUpdates() error = %v", err)
+			}
+			if len(infos) != 1 {
+				t.Fatalf("CheckAllUpdates() returned %d results, want 1", len(infos))
+			}
+			if infos[0].Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v (error: %v)", infos[0].Status, tt.wantStatus, infos[0].Error)
+			}
+			if infos[0].NewVersion != tt.wantVersion {
+				t.Errorf("NewVersion = %q, want %q", infos[0].NewVersion, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCheckUpdate_Policy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		wantUpdate bool
+	}{
+		{name: "manual updates when named", policy: add.UpdatePolicyManual, wantUpdate: true},
+		{name: "pinned never updates", policy: add.UpdatePolicyPinned, wantUpdate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, requests := policyServer(t)
+
+			updater := NewUpdater("")
+			updater.SetBaseURL(ts.URL)
+
+			hasUpdate, _, err := updater.CheckUpdate(context.Background(), policySkill(t.TempDir(), tt.policy, ""))
+			if err != nil {
+				t.Fatalf("CheckUpdate() error = %v", err)
+			}
+			if hasUpdate != tt.wantUpdate {
+				t.Errorf("CheckUpdate() hasUpdate = %v, want %v", hasUpdate, tt.wantUpdate)
+			}
+			if !tt.wantUpdate && requests.Load() != 0 {
+				t.Errorf("CheckUpdate() made %d requests for a pinned skill, want 0", requests.Load())
+			}
+		})
+	}
+}
+
+func TestUpdateAll_SkipsManualAndPinned(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	ts, requests := policyServer(t)
+
+	manual := policySkill(dataDir, add.UpdatePolicyManual, "")
+	pinned := policySkill(dataDir, add.UpdatePolicyPinned, "")
+	pinned.Name = "pinned"
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	stats, results, err := updater.UpdateAll(context.Background(), []*types.SkillMetadata{manual, pinned})
+	if err != nil {
+		t.Fatalf("UpdateAll() error = %v", err)
+	}
+	if stats.Skipped != 2 || stats.Updated != 0 || stats.Failed != 0 {
+		t.Errorf("stats = %+v, want 2 skipped", stats)
+	}
+	for _, r := range results {
+		if r.Status != UpdateStatusSkipped {
+			t.Errorf("%s status = %v, want UpdateStatusSkipped", r.Skill.Name, r.Status)
+		}
+	}
+	if requests.Load() != 0 {
+		t.Errorf("UpdateAll() made %d requests, want 0", requests.Load())
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		policy  string
+		want    string
+		wantErr bool
+	}{
+		{name: "manual", policy: "manual", want: add.UpdatePolicyManual},
+		{name: "case and spaces ignored", policy: " Pinned ", want: add.UpdatePolicyPinned},
+		{name: "latest-tag on a tag", tag: "v1.0.0", policy: "latest-tag", want: add.UpdatePolicyLatestTag},
+		{name: "latest-tag on a branch", policy: "latest-tag", wantErr: true},
+		{name: "unknown policy", policy: "nightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := testutil.TempHome(t)
+			if err := registry.AddOrUpdateSkill(policySkill(dataDir, "", tt.tag)); err != nil {
+				t.Fatalf("failed to add skill to registry: %v", err)
+			}
+
+			_, err := SetPolicy("test", tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			skill, err := registry.FindSkillByName("test")
+			if err != nil {
+				t.Fatalf("FindSkillByName() error = %v", err)
+			}
+			if skill.UpdatePolicy != tt.want {
+				t.Errorf("UpdatePolicy = %q, want %q", skill.UpdatePolicy, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package update
+
+import (
+	"path"
+
+	"github.com/smy-101/gskills/internal/version"
+)
+
+// NewestTag returns the highest semantic version among tags that matches the
+// glob pattern and is newer than current, or "" if there is none. Tags that
+// are not semantic versions are ignored, and so are pre-releases unless
+// includePrerelease is set.
+func NewestTag(tags []string, pattern, current string, includePrerelease bool) string {
+	newest := ""
+	for _, tag := range tags {
+		if ok, err := path.Match(pattern, tag); err != nil || !ok {
+			continue
+		}
+		if version.IsPrerelease(tag) && !includePrerelease {
+			continue
+		}
+		if cmp, err := version.Compare(tag, current); err != nil || cmp <= 0 {
+			continue
+		}
+		if newest != "" {
+			if cmp, _ := version.Compare(tag, newest); cmp <= 0 {
+				continue
+			}
+		}
+		newest = tag
+	}
+	return newest
+}
@@ -0,0 +1,31 @@
+package update
+
+import "testing"
+
+func TestNewestTag(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.10.0", "v1.9.3", "v2.0.0-rc.1", "latest", "release-3", "v1.2"}
+
+	tests := []struct {
+		name              string
+		pattern           string
+		current           string
+		includePrerelease bool
+		want              string
+	}{
+		{name: "highest release wins over string order", pattern: "v*", current: "v1.0.0", want: "v1.10.0"},
+		{name: "pre-releases excluded by default", pattern: "v*", current: "v1.10.0", want: ""},
+		{name: "pre-releases included on request", pattern: "v*", current: "v1.10.0", includePrerelease: true, want: "v2.0.0-rc.1"},
+		{name: "pattern restricts the tags", pattern: "v1.9.*", current: "v1.0.0", want: "v1.9.3"},
+		{name: "nothing newer than current", pattern: "v*", current: "v3.0.0", want: ""},
+		{name: "non-semver current never updates", pattern: "*", current: "latest", want: ""},
+		{name: "invalid pattern matches nothing", pattern: "[", current: "v1.0.0", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewestTag(tags, tt.pattern, tt.current, tt.includePrerelease); got != tt.want {
+				t.Errorf("NewestTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,20 +18,27 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/clone"
+	"github.com/smy-101/gskills/internal/history"
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
 )
 
+// DefaultCheckTimeout bounds an update check until SetCheckTimeout changes it.
+const DefaultCheckTimeout = 30 * time.Second
+
 const (
-	checkTimeout           = 30 * time.Second
 	updateTimeout          = 5 * time.Minute
-	maxRetryAttempt        = 5
 	maxConcurrentChecks    = 5 // Limit concurrent API calls to avoid rate limits
 	maxConcurrentUpdates   = 3 // Limit concurrent downloads to avoid resource exhaustion
 	maxConcurrentDownloads = 3 // Limit concurrent file downloads per skill
@@ -43,18 +50,33 @@ const (
 	UpdateStatusUpToDate UpdateStatus = iota
 	UpdateStatusAvailable
 	UpdateStatusFailed
+	UpdateStatusSkipped
+	UpdateStatusUpdated
 )
 
 type SkillUpdateInfo struct {
 	Skill        *types.SkillMetadata
 	Status       UpdateStatus
 	NewCommitSHA string
-	Error        error
+	// NewVersion is the version the update would install. Skills tracking a
+	// branch keep their version, so it equals Skill.Version for them.
+	NewVersion string
+	Error      error
 }
 
 type Updater struct {
-	client *add.Client
-	logger add.Logger
+	client        *add.Client
+	logger        add.Logger
+	checkTimeout  time.Duration
+	updateTimeout time.Duration
+	// includePrerelease lets tag-following skills move to pre-release tags.
+	includePrerelease bool
+	// downloadSem bounds the file and directory requests in flight across
+	// all skills updated concurrently, so UpdateAll never exceeds one limit.
+	downloadSem chan struct{}
+	// onFailure, when set, is asked after each failed update of UpdateAll
+	// whether to go on with the remaining skills.
+	onFailure func(skill *types.SkillMetadata, err error) bool
 }
 
 // UpdateStats contains statistics about bulk update operations.
@@ -64,6 +86,57 @@ type UpdateStats struct {
 	Skipped  int
 	Failed   int
 	Duration time.Duration
+	// BytesDownloaded is the total size of the files fetched for the updated skills.
+	BytesDownloaded int64
+}
+
+// BytesPerSecond returns the average download speed over the whole operation.
+func (s *UpdateStats) BytesPerSecond() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.BytesDownloaded) / s.Duration.Seconds()
+}
+
+// SkillUpdateResult is the outcome of updating one skill in UpdateAll. Status
+// is UpdateStatusUpdated on success and UpdateStatusFailed with Error set
+// otherwise, or UpdateStatusSkipped when the skill's update policy is manual
+// or pinned or the failure handler stopped the batch before the skill was
+// updated.
+type SkillUpdateResult struct {
+	Skill  *types.SkillMetadata
+	Status UpdateStatus
+	Error  error
+}
+
+// MergeRetryResults returns results with the entries of the skills retried in
+// retryResults replaced by their retry outcome, keeping the order of results.
+func MergeRetryResults(results, retryResults []SkillUpdateResult) []SkillUpdateResult {
+	retried := make(map[*types.SkillMetadata]SkillUpdateResult, len(retryResults))
+	for _, r := range retryResults {
+		retried[r.Skill] = r
+	}
+
+	merged := make([]SkillUpdateResult, len(results))
+	for i, r := range results {
+		if retry, ok := retried[r.Skill]; ok {
+			r = retry
+		}
+		merged[i] = r
+	}
+	return merged
+}
+
+// FailedSkills returns the skills whose update failed, in result order, e.g.
+// to pass them to UpdateAll again.
+func FailedSkills(results []SkillUpdateResult) []*types.SkillMetadata {
+	var failed []*types.SkillMetadata
+	for _, r := range results {
+		if r.Status == UpdateStatusFailed {
+			failed = append(failed, r.Skill)
+		}
+	}
+	return failed
 }
 
 // NewUpdater creates a new Updater instance with the given GitHub token.
@@ -71,9 +144,74 @@ type UpdateStats struct {
 // with a 30-second timeout for update checks and 5-minute timeout for downloads.
 func NewUpdater(token string) *Updater {
 	return &Updater{
-		client: add.NewClient(token),
-		logger: add.NoOpLogger{},
+		client:        add.NewClient(token),
+		logger:        add.NoOpLogger{},
+		checkTimeout:  DefaultCheckTimeout,
+		updateTimeout: updateTimeout,
+		downloadSem:   make(chan struct{}, maxConcurrentDownloads),
+	}
+}
+
+// SetConcurrency sets how many download requests may be in flight at once,
+// shared by all skills of an UpdateAll. Non-positive values keep the default
+// and values above add.MaxConcurrency are capped. It must not be called
+// while an update is running.
+func (u *Updater) SetConcurrency(n int) {
+	switch {
+	case n <= 0:
+		return
+	case n > add.MaxConcurrency:
+		n = add.MaxConcurrency
+	}
+	u.downloadSem = make(chan struct{}, n)
+}
+
+// Concurrency returns how many download requests may be in flight at once.
+func (u *Updater) Concurrency() int {
+	return cap(u.downloadSem)
+}
+
+// SetCheckTimeout overrides the time allowed for checking one skill for an
+// update (default 30s). Non-positive values are ignored.
+func (u *Updater) SetCheckTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	u.checkTimeout = d
+}
+
+// SetDownloadTimeout overrides the time allowed for downloading one skill,
+// both when updating it and when adding it through the updater's client
+// (default 5m). Non-positive values are ignored.
+func (u *Updater) SetDownloadTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	u.updateTimeout = d
+	u.client.SetDownloadTimeout(d)
+}
+
+// SetTimeout overrides the check, update and per-request HTTP timeouts
+// with d. Non-positive values are ignored.
+func (u *Updater) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		return
 	}
+	u.checkTimeout = d
+	u.updateTimeout = d
+	u.client.SetTimeout(d)
+}
+
+// SetDownloadLimits bounds the number of files and total bytes downloaded
+// for a single skill. Non-positive values keep the defaults.
+func (u *Updater) SetDownloadLimits(maxFiles int, maxTotalBytes int64) {
+	u.client.SetDownloadLimits(maxFiles, maxTotalBytes)
+}
+
+// SetRetries sets how often failed requests are retried and the wait before
+// the first retry; see add.Client.SetRetries.
+func (u *Updater) SetRetries(maxRetries int, retryWait time.Duration) {
+	u.client.SetRetries(maxRetries, retryWait)
 }
 
 // SetLogger sets the logger for the updater. If no logger is set,
@@ -82,43 +220,98 @@ func (u *Updater) SetLogger(logger add.Logger) {
 	u.logger = logger
 }
 
+// SetIncludePrerelease lets skills that follow a tag pattern move to
+// pre-release tags such as v2.0.0-rc.1. They are skipped by default.
+func (u *Updater) SetIncludePrerelease(include bool) {
+	u.includePrerelease = include
+}
+
+// SetOnFailure makes UpdateAll call fn after each skill that fails to
+// update. When fn returns false the remaining skills are not updated and are
+// reported as UpdateStatusSkipped. With a handler set, UpdateAll updates one
+// skill at a time, in order, so the answer applies to every skill not yet
+// started. A nil fn restores concurrent updates that never stop early.
+func (u *Updater) SetOnFailure(fn func(skill *types.SkillMetadata, err error) bool) {
+	u.onFailure = fn
+}
+
 // SetBaseURL sets the base URL for GitHub API requests.
 // This method is intended for testing purposes only.
 func (u *Updater) SetBaseURL(url string) {
 	u.client.SetBaseURL(url)
 }
 
+// SetServer makes the updater check and download skills on server, e.g. a
+// GitHub Enterprise server; see add.Client.SetServer.
+func (u *Updater) SetServer(server add.Server) {
+	u.client.SetServer(server)
+}
+
 // CheckUpdate checks if a skill has an available update by comparing
-// the current commit SHA with the latest commit SHA from GitHub.
+// the current commit SHA with the latest commit SHA from GitHub. Local
+// skills and skills frozen at a tag are reported as up to date without
+// querying GitHub. Skills following a tag pattern have an update when a newer
+// matching release tag exists; newSHA is then the commit of that tag.
 //
 // Returns:
 //   - hasUpdate: true if the skill has an update available
 //   - newSHA: the latest commit SHA from GitHub
 //   - err: any error that occurred during the check
-func (u *Updater) CheckUpdate(skill *types.SkillMetadata) (hasUpdate bool, newSHA string, err error) {
+func (u *Updater) CheckUpdate(ctx context.Context, skill *types.SkillMetadata) (hasUpdate bool, newSHA string, err error) {
+	hasUpdate, newSHA, _, err = u.checkUpdate(ctx, skill)
+	return hasUpdate, newSHA, err
+}
+
+// checkUpdate is CheckUpdate that also returns the version the update would
+// install: the newest matching tag for skills following a tag pattern or
+// the latest-tag policy, and the unchanged Version otherwise. Skills with
+// the pinned policy never have an update.
+func (u *Updater) checkUpdate(ctx context.Context, skill *types.SkillMetadata) (hasUpdate bool, newSHA, newVersion string, err error) {
 	if skill == nil {
-		return false, "", fmt.Errorf("skill metadata cannot be nil")
+		return false, "", "", fmt.Errorf("skill metadata cannot be nil")
 	}
 	if skill.SourceURL == "" {
-		return false, "", fmt.Errorf("skill source URL cannot be empty")
+		return false, "", "", fmt.Errorf("skill source URL cannot be empty")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	if IsLocalSkill(skill) || IsPolicyPinned(skill) || (IsTagPinned(skill) && followedTagPattern(skill) == "") {
+		return false, skill.CommitSHA, skill.Version, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, u.checkTimeout)
 	defer cancel()
 
-	repoInfo, err := add.ParseGitHubURL(skill.SourceURL)
+	repoInfo, err := u.client.Server().ParseGitHubURL(skill.SourceURL)
 	if err != nil {
-		return false, "", &UpdateError{
-			Type:    UpdateErrorTypeCheck,
+		return false, "", "", &UpdateError{
+			Type:    UpdateErrorTypeSource,
 			Message: "failed to parse source URL",
 			Err:     err,
 			Skill:   skill.Name,
 		}
 	}
 
-	newSHA, err = u.getCommitSHAWithRetry(ctx, repoInfo)
+	newVersion = skill.Version
+	if IsTagPinned(skill) {
+		tags, err := u.client.ListTags(ctx, repoInfo.Owner, repoInfo.Repo)
+		if err != nil {
+			return false, "", "", &UpdateError{
+				Type:    UpdateErrorTypeCheck,
+				Message: "failed to list tags",
+				Err:     err,
+				Skill:   skill.Name,
+			}
+		}
+		newVersion = NewestTag(tags, followedTagPattern(skill), skill.Version, u.includePrerelease)
+		if newVersion == "" {
+			return false, skill.CommitSHA, skill.Version, nil
+		}
+		repoInfo.Branch = newVersion
+	}
+
+	newSHA, err = u.client.GetBranchCommitSHA(ctx, repoInfo)
 	if err != nil {
-		return false, "", &UpdateError{
+		return false, "", "", &UpdateError{
 			Type:    UpdateErrorTypeCheck,
 			Message: "failed to fetch latest commit SHA",
 			Err:     err,
@@ -126,38 +319,11 @@ func (u *Updater) CheckUpdate(skill *types.SkillMetadata) (hasUpdate bool, newSH
 		}
 	}
 
-	if newSHA == skill.CommitSHA {
-		return false, newSHA, nil
-	}
-
-	return true, newSHA, nil
-}
-
-// getCommitSHAWithRetry fetches the latest commit SHA from GitHub with retry logic
-// for handling rate limits. Uses exponential backoff with a maximum of 16 seconds.
-func (u *Updater) getCommitSHAWithRetry(ctx context.Context, repoInfo *add.GitHubRepoInfo) (string, error) {
-	var lastErr error
-	for attempt := range maxRetryAttempt {
-		sha, err := u.client.GetBranchCommitSHA(ctx, repoInfo)
-		if err == nil {
-			return sha, nil
-		}
-
-		lastErr = err
-		if isRateLimitError(err) && attempt < maxRetryAttempt-1 {
-			backoff := min(time.Duration(1<<uint(attempt))*time.Second, 16*time.Second)
-			u.logger.Warn("Rate limit hit, backing off", "attempt", attempt+1, "backoff", backoff)
-
-			select {
-			case <-time.After(backoff):
-				continue
-			case <-ctx.Done():
-				return "", ctx.Err()
-			}
-		}
+	if newSHA == skill.CommitSHA && newVersion == skill.Version {
+		return false, newSHA, newVersion, nil
 	}
 
-	return "", lastErr
+	return true, newSHA, newVersion, nil
 }
 
 // UpdateSkill checks for updates to a single skill and downloads the latest version
@@ -168,59 +334,205 @@ func (u *Updater) getCommitSHAWithRetry(ctx context.Context, repoInfo *add.GitHu
 //  4. Updates the registry with new metadata
 //
 // Returns nil if the skill is up to date or if the update succeeds.
-func (u *Updater) UpdateSkill(skill *types.SkillMetadata) error {
+func (u *Updater) UpdateSkill(ctx context.Context, skill *types.SkillMetadata) error {
+	_, err := u.updateSkill(ctx, skill)
+	return err
+}
+
+// updateSkill is UpdateSkill that also returns the number of bytes written
+// to the store. Updates that change the skill or fail are recorded in the
+// history log; a skill that is already up to date is not.
+func (u *Updater) updateSkill(ctx context.Context, skill *types.SkillMetadata) (int64, error) {
 	if skill == nil {
-		return fmt.Errorf("skill metadata cannot be nil")
+		return 0, fmt.Errorf("skill metadata cannot be nil")
+	}
+
+	var bytes int64
+	var err error
+	if IsLocalSkill(skill) {
+		bytes, err = u.refreshLocalSkill(skill)
+	} else {
+		var hasUpdate bool
+		var newSHA, newVersion string
+		hasUpdate, newSHA, newVersion, err = u.checkUpdate(ctx, skill)
+		if err == nil && !hasUpdate {
+			return 0, nil
+		}
+		if err == nil {
+			bytes, err = u.downloadAndUpdate(ctx, skill, newSHA, newVersion)
+		}
+	}
+	if err == nil {
+		u.refreshCopies(skill)
+	}
+
+	u.recordHistory(skill.Name, err)
+	return bytes, err
+}
+
+// refreshCopies updates the projects skill was copied into with link --copy
+// after its store changed. Failing to refresh a copy does not fail the
+// update.
+func (u *Updater) refreshCopies(skill *types.SkillMetadata) {
+	if err := link.RefreshCopies(skill); err != nil {
+		u.logger.Warn("Failed to refresh linked copies", "skill", skill.Name, "error", err)
 	}
+}
 
-	hasUpdate, newSHA, err := u.CheckUpdate(skill)
+// recordHistory appends an update of skill to the history log. Failing to
+// write the log does not fail the update.
+func (u *Updater) recordHistory(skill string, updateErr error) {
+	dataDir, err := paths.DataDir()
+	if err == nil {
+		err = history.Record(dataDir, history.OpUpdate, skill, updateErr)
+	}
 	if err != nil {
-		return err
+		u.logger.Warn("Failed to record history", "skill", skill, "error", err)
 	}
+}
 
-	if !hasUpdate {
-		return nil
+// IsLocalSkill reports whether a skill was added from a local directory,
+// cloned from another skill or re-registered without a known source. Such
+// skills have no GitHub source to check.
+func IsLocalSkill(skill *types.SkillMetadata) bool {
+	return skill.SourceURL == add.UntrackedSource || add.IsLocalSource(skill.SourceURL) ||
+		strings.HasPrefix(skill.SourceURL, clone.SourcePrefix)
+}
+
+// IsTagPinned reports whether a skill was added from a tag. Tags are not
+// expected to move, so such skills never have updates.
+func IsTagPinned(skill *types.SkillMetadata) bool {
+	return skill.RefType == add.RefTypeTag
+}
+
+// LinksBehind returns the sorted project paths of skill that were linked at a
+// commit other than the skill's current CommitSHA. The symlinks already
+// resolve to the updated store, so this only tells which projects last saw
+// an older version. Links recorded without a commit are ignored.
+func LinksBehind(skill *types.SkillMetadata) []string {
+	var projects []string
+	for projectPath, linkInfo := range skill.LinkedProjects {
+		if linkInfo.CommitSHA != "" && linkInfo.CommitSHA != skill.CommitSHA {
+			projects = append(projects, projectPath)
+		}
 	}
+	sort.Strings(projects)
+	return projects
+}
 
-	return u.downloadAndUpdate(skill, newSHA)
+// RefreshLocalSkill re-copies a skill added from a local directory from its
+// source path into its store directory. Cloned and untracked skills have no
+// source path and cannot be refreshed. The refresh is recorded in the history
+// log.
+func (u *Updater) RefreshLocalSkill(skill *types.SkillMetadata) error {
+	_, err := u.refreshLocalSkill(skill)
+	if err == nil {
+		u.refreshCopies(skill)
+	}
+	u.recordHistory(skill.Name, err)
+	return err
 }
 
-// downloadAndUpdate performs the actual download and update of a skill.
+// refreshLocalSkill is RefreshLocalSkill that also returns the number of
+// bytes copied.
+func (u *Updater) refreshLocalSkill(skill *types.SkillMetadata) (int64, error) {
+	if strings.HasPrefix(skill.SourceURL, clone.SourcePrefix) {
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeSource,
+			Message: "cloned skills have no upstream source to update from",
+			Skill:   skill.Name,
+		}
+	}
+	if skill.SourceURL == add.UntrackedSource {
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeSource,
+			Message: "skill was registered without a known source and cannot be updated",
+			Skill:   skill.Name,
+		}
+	}
+
+	srcPath, err := add.LocalSourcePath(skill.SourceURL)
+	if err != nil {
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeSource,
+			Message: "failed to parse local source",
+			Err:     err,
+			Skill:   skill.Name,
+		}
+	}
+
+	u.logger.Info("Refreshing local skill", "skill", skill.Name, "source", srcPath)
+
+	stats, err := add.InstallLocal(srcPath, skill.StorePath)
+	if err != nil {
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeFilesystem,
+			Message: "failed to copy local skill",
+			Err:     err,
+			Skill:   skill.Name,
+		}
+	}
+
+	updatedSkill := *skill
+	updatedSkill.UpdatedAt = time.Now()
+	// The content changed, so it no longer matches the pinned checksum.
+	updatedSkill.Checksum = ""
+
+	if err := registry.UpdateSkill(&updatedSkill); err != nil {
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeRegistry,
+			Message: "failed to update registry",
+			Err:     err,
+			Skill:   skill.Name,
+		}
+	}
+
+	return stats.BytesDownloaded, nil
+}
+
+// downloadAndUpdate performs the actual download and update of a skill and
+// returns the number of bytes downloaded.
 // Downloads files to a temporary directory, then atomically moves them
-// to the final location.
-func (u *Updater) downloadAndUpdate(skill *types.SkillMetadata, newSHA string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+// to the final location. If ctx is cancelled the temporary directory is
+// removed and the existing skill directory is left untouched. A newVersion
+// other than the skill's Version is a newer tag: it is downloaded instead and
+// recorded in Version and SourceURL. The registry ID is kept.
+func (u *Updater) downloadAndUpdate(ctx context.Context, skill *types.SkillMetadata, newSHA, newVersion string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.updateTimeout)
 	defer cancel()
 
-	repoInfo, err := add.ParseGitHubURL(skill.SourceURL)
+	repoInfo, err := u.client.Server().ParseGitHubURL(skill.SourceURL)
 	if err != nil {
-		return &UpdateError{
-			Type:    UpdateErrorTypeDownload,
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeSource,
 			Message: "failed to parse source URL",
 			Err:     err,
 			Skill:   skill.Name,
 		}
 	}
+	if newVersion != skill.Version {
+		repoInfo.Branch = newVersion
+	}
 
 	localPath := skill.StorePath
 	if localPath == "" {
-		homeDir, err := os.UserHomeDir()
+		dataDir, err := paths.DataDir()
 		if err != nil {
-			return &UpdateError{
-				Type:    UpdateErrorTypeDownload,
-				Message: "failed to get home directory",
+			return 0, &UpdateError{
+				Type:    UpdateErrorTypeFilesystem,
+				Message: "failed to get data directory",
 				Err:     err,
 				Skill:   skill.Name,
 			}
 		}
 		skillName := filepath.Base(repoInfo.Path)
-		localPath = filepath.Join(homeDir, ".gskills", "skills", skillName)
+		localPath = filepath.Join(paths.SkillsDir(dataDir), skillName)
 	}
 
 	tmpDir := filepath.Join(filepath.Dir(localPath), ".tmp."+filepath.Base(localPath)+fmt.Sprintf(".%d", time.Now().UnixNano()))
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
-		return &UpdateError{
-			Type:    UpdateErrorTypeDownload,
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeFilesystem,
 			Message: "failed to create temporary directory",
 			Err:     err,
 			Skill:   skill.Name,
@@ -229,18 +541,29 @@ func (u *Updater) downloadAndUpdate(skill *types.SkillMetadata, newSHA string) e
 
 	u.logger.Debug("Created temporary directory", "path", tmpDir)
 
+	committed := false
 	defer func() {
-		if err != nil {
-			u.logger.Error("Cleaning up temporary directory", err, "path", tmpDir)
+		if !committed {
+			u.logger.Warn("Cleaning up temporary directory", "path", tmpDir)
 			os.RemoveAll(tmpDir)
 		}
 	}()
 
 	u.logger.Info("Starting update", "skill", skill.Name, "target", tmpDir)
 
-	stats, err := u.downloadRecursive(ctx, repoInfo, tmpDir, repoInfo.Path)
+	ignore, err := u.client.FetchIgnoreRules(ctx, repoInfo)
 	if err != nil {
-		return &UpdateError{
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeDownload,
+			Message: "failed to read " + add.IgnoreFileName,
+			Err:     err,
+			Skill:   skill.Name,
+		}
+	}
+
+	stats, err := u.downloadRecursive(ctx, repoInfo, tmpDir, repoInfo.Path, ignore, skill.IncludeHidden)
+	if err != nil {
+		return 0, &UpdateError{
 			Type:    UpdateErrorTypeDownload,
 			Message: "failed to download files",
 			Err:     err,
@@ -248,9 +571,13 @@ func (u *Updater) downloadAndUpdate(skill *types.SkillMetadata, newSHA string) e
 		}
 	}
 
+	if err := validateUpdatedSkill(skill, tmpDir); err != nil {
+		return 0, err
+	}
+
 	if err := os.RemoveAll(localPath); err != nil {
-		return &UpdateError{
-			Type:    UpdateErrorTypeDownload,
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeFilesystem,
 			Message: "failed to remove existing directory",
 			Err:     err,
 			Skill:   skill.Name,
@@ -258,22 +585,30 @@ func (u *Updater) downloadAndUpdate(skill *types.SkillMetadata, newSHA string) e
 	}
 
 	if err := os.Rename(tmpDir, localPath); err != nil {
-		return &UpdateError{
-			Type:    UpdateErrorTypeDownload,
+		return 0, &UpdateError{
+			Type:    UpdateErrorTypeFilesystem,
 			Message: "failed to move files to final location",
 			Err:     err,
 			Skill:   skill.Name,
 		}
 	}
+	committed = true
 
 	u.logger.Info("Update complete", "skill", skill.Name, "files", stats.FilesDownloaded)
 
 	updatedSkill := *skill
 	updatedSkill.CommitSHA = newSHA
+	// The content changed, so it no longer matches the pinned checksum.
+	updatedSkill.Checksum = ""
+	updatedSkill.IgnoreApplied = ignore != nil
+	if newVersion != skill.Version {
+		updatedSkill.Version = newVersion
+		updatedSkill.SourceURL = repoInfo.SkillURL(repoInfo.Path)
+	}
 	updatedSkill.UpdatedAt = time.Now()
 
 	if err := registry.UpdateSkill(&updatedSkill); err != nil {
-		return &UpdateError{
+		return 0, &UpdateError{
 			Type:    UpdateErrorTypeRegistry,
 			Message: "failed to update registry",
 			Err:     err,
@@ -281,15 +616,62 @@ func (u *Updater) downloadAndUpdate(skill *types.SkillMetadata, newSHA string) e
 		}
 	}
 
+	return stats.BytesDownloaded, nil
+}
+
+// validateUpdatedSkill checks that dir, the downloaded new content of skill,
+// still has a skill manifest. Without this an upstream that renamed or
+// removed SKILL.md would silently replace the skill with a directory no tool
+// loads. Skills added with --skip-skill-check are not checked.
+func validateUpdatedSkill(skill *types.SkillMetadata, dir string) error {
+	if skill.Unverified {
+		return nil
+	}
+	isSkill, err := add.IsSkillDirectory(dir)
+	if err != nil {
+		return &UpdateError{
+			Type:    UpdateErrorTypeFilesystem,
+			Message: "failed to read downloaded files",
+			Err:     err,
+			Skill:   skill.Name,
+		}
+	}
+	if !isSkill {
+		return &UpdateError{
+			Type:    UpdateErrorTypeInvalid,
+			Message: "no " + manifestList() + " in the new version of",
+			Err:     add.ErrSkillManifestMissing,
+			Skill:   skill.Name,
+		}
+	}
 	return nil
 }
 
+// manifestList returns the accepted skill manifest names for messages, e.g.
+// "SKILL.md, SKILL.yaml or SKILL.json".
+func manifestList() string {
+	names := add.SkillManifestNames()
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
 // CheckAllUpdates checks all installed skills for available updates concurrently.
 // Returns a slice of SkillUpdateInfo with the status of each skill.
 //
+// Local skills and skills whose update policy is manual or pinned are not
+// checked and reported as UpdateStatusSkipped.
+//
 // The function uses concurrency to check multiple skills simultaneously,
-// with a limit of maxConcurrentChecks (5) concurrent operations.
-func (u *Updater) CheckAllUpdates() ([]SkillUpdateInfo, error) {
+// with a limit of maxConcurrentChecks (5) concurrent operations. When the
+// remaining GitHub quota runs low the checks run one at a time, paced over
+// the quota's reset window (see checkLimiter).
+//
+// Before any skill is checked GitHub is pinged once; when it is unreachable,
+// rejects the token or has no rate limit left, the error is returned instead
+// of one failure per skill.
+func (u *Updater) CheckAllUpdates(ctx context.Context) ([]SkillUpdateInfo, error) {
 	skills, err := registry.LoadRegistry()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load registry: %w", err)
@@ -299,21 +681,48 @@ func (u *Updater) CheckAllUpdates() ([]SkillUpdateInfo, error) {
 		return []SkillUpdateInfo{}, nil
 	}
 
+	for i := range skills {
+		if !IsLocalSkill(&skills[i]) {
+			if _, err := u.client.Ping(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
 	results := make([]SkillUpdateInfo, len(skills))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	sem := make(chan struct{}, maxConcurrentChecks)
+	limiter := newCheckLimiter(u.client.RateLimit)
 
-	for i, skill := range skills {
+	for i := range skills {
 		wg.Add(1)
+		// Each goroutine gets a pointer into skills, never to a loop
+		// variable, so results[idx].Skill is always the skill at idx.
 		go func(idx int, s *types.SkillMetadata) {
 			defer wg.Done()
 
-			sem <- struct{}{}
-			defer func() { <-sem }()
+			if IsLocalSkill(s) || SkipsBulkUpdate(s) {
+				mu.Lock()
+				results[idx] = SkillUpdateInfo{
+					Skill:  s,
+					Status: UpdateStatusSkipped,
+				}
+				mu.Unlock()
+				return
+			}
 
-			hasUpdate, newSHA, err := u.CheckUpdate(s)
+			release, err := limiter.acquire(ctx)
+			if err != nil {
+				mu.Lock()
+				results[idx] = SkillUpdateInfo{Skill: s, Status: UpdateStatusFailed, Error: err}
+				mu.Unlock()
+				return
+			}
+			defer release()
+
+			hasUpdate, newSHA, newVersion, err := u.checkUpdate(ctx, s)
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -331,6 +740,7 @@ func (u *Updater) CheckAllUpdates() ([]SkillUpdateInfo, error) {
 					Skill:        s,
 					Status:       UpdateStatusAvailable,
 					NewCommitSHA: newSHA,
+					NewVersion:   newVersion,
 				}
 			} else {
 				results[idx] = SkillUpdateInfo{
@@ -338,7 +748,7 @@ func (u *Updater) CheckAllUpdates() ([]SkillUpdateInfo, error) {
 					Status: UpdateStatusUpToDate,
 				}
 			}
-		}(i, &skill)
+		}(i, &skills[i])
 	}
 
 	wg.Wait()
@@ -346,70 +756,141 @@ func (u *Updater) CheckAllUpdates() ([]SkillUpdateInfo, error) {
 }
 
 // UpdateAll updates multiple skills concurrently and returns statistics
-// about the operation. Skills are updated with a limit of maxConcurrentUpdates (3)
-// concurrent operations to avoid resource exhaustion.
+// about the operation together with the result of each skill, in the order
+// of skillsToUpdate. Skills are updated with a limit of maxConcurrentUpdates (3)
+// concurrent operations to avoid resource exhaustion. Nothing is logged while
+// the updates run, so concurrent updates never interleave their output.
 //
 // Parameters:
+//   - ctx: context bounding the whole operation; cancelling it aborts in-flight downloads
 //   - skillsToUpdate: slice of skill metadata to update
 //
 // Returns:
 //   - UpdateStats: statistics about the update operation
+//   - []SkillUpdateResult: the outcome of each skill; see FailedSkills to retry failures
 //   - error: any error that occurred during the update process
-func (u *Updater) UpdateAll(skillsToUpdate []*types.SkillMetadata) (*UpdateStats, error) {
+//
+// Skills whose update policy is manual or pinned are skipped; update a
+// manual skill with UpdateSkill. See SetOnFailure for stopping after a
+// failure.
+func (u *Updater) UpdateAll(ctx context.Context, skillsToUpdate []*types.SkillMetadata) (*UpdateStats, []SkillUpdateResult, error) {
 	if skillsToUpdate == nil {
-		return &UpdateStats{}, nil
+		return &UpdateStats{}, []SkillUpdateResult{}, nil
+	}
+	if u.onFailure != nil {
+		stats, results := u.updateInOrder(ctx, skillsToUpdate)
+		return stats, results, nil
 	}
 	startTime := time.Now()
 	stats := &UpdateStats{
 		Total: len(skillsToUpdate),
 	}
+	results := make([]SkillUpdateResult, len(skillsToUpdate))
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
 	sem := make(chan struct{}, maxConcurrentUpdates)
 
-	for _, skill := range skillsToUpdate {
+	for i, skill := range skillsToUpdate {
 		wg.Add(1)
-		go func(s *types.SkillMetadata) {
+		go func(idx int, s *types.SkillMetadata) {
 			defer wg.Done()
 
+			if SkipsBulkUpdate(s) {
+				mu.Lock()
+				results[idx] = SkillUpdateResult{Skill: s, Status: UpdateStatusSkipped}
+				stats.Skipped++
+				mu.Unlock()
+				return
+			}
+
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			err := u.UpdateSkill(s)
+			bytes, err := u.updateSkill(ctx, s)
 
 			mu.Lock()
 			defer mu.Unlock()
 
+			results[idx] = SkillUpdateResult{Skill: s, Status: UpdateStatusUpdated}
 			if err != nil {
 				stats.Failed++
-				u.logger.Error("Failed to update skill", err, "skill", s.Name)
+				results[idx].Status = UpdateStatusFailed
+				results[idx].Error = err
 			} else {
 				stats.Updated++
+				stats.BytesDownloaded += bytes
 			}
-		}(skill)
+		}(i, skill)
 	}
 
 	wg.Wait()
 	stats.Duration = time.Since(startTime)
 
-	return stats, nil
+	// Failures are logged once the batch is done so that the log follows the
+	// order of skillsToUpdate rather than the order the updates finished in.
+	for _, r := range results {
+		if r.Status == UpdateStatusFailed {
+			u.logger.Error("Failed to update skill", r.Error, "skill", r.Skill.Name)
+		}
+	}
+
+	return stats, results, nil
+}
+
+// updateInOrder is UpdateAll with a failure handler: skills are updated one
+// at a time and the handler decides after each failure whether the rest are
+// updated or skipped.
+func (u *Updater) updateInOrder(ctx context.Context, skillsToUpdate []*types.SkillMetadata) (*UpdateStats, []SkillUpdateResult) {
+	startTime := time.Now()
+	stats := &UpdateStats{Total: len(skillsToUpdate)}
+	results := make([]SkillUpdateResult, len(skillsToUpdate))
+
+	stopped := false
+	for i, s := range skillsToUpdate {
+		if stopped || SkipsBulkUpdate(s) {
+			results[i] = SkillUpdateResult{Skill: s, Status: UpdateStatusSkipped}
+			stats.Skipped++
+			continue
+		}
+
+		bytes, err := u.updateSkill(ctx, s)
+		if err != nil {
+			results[i] = SkillUpdateResult{Skill: s, Status: UpdateStatusFailed, Error: err}
+			stats.Failed++
+			u.logger.Error("Failed to update skill", err, "skill", s.Name)
+			stopped = !u.onFailure(s, err)
+			continue
+		}
+		results[i] = SkillUpdateResult{Skill: s, Status: UpdateStatusUpdated}
+		stats.Updated++
+		stats.BytesDownloaded += bytes
+	}
+
+	stats.Duration = time.Since(startTime)
+	return stats, results
 }
 
 // downloadRecursive recursively downloads files and directories from GitHub.
-// Uses a worker pool pattern with maxConcurrentDownloads (3) concurrent downloads.
-func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRepoInfo, localPath string, downloadPath string) (*add.DownloadStats, error) {
+// Requests take a slot of the updater's shared download semaphore, so the
+// limit set with SetConcurrency (default 3) holds across concurrent skills.
+// Entries matched by ignore, and hidden entries unless includeHidden, are
+// skipped. The walk is aborted once it exceeds the client's download limits.
+func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRepoInfo, localPath string, downloadPath string, ignore *add.IgnoreRules, includeHidden bool) (*add.DownloadStats, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	limits := u.client.DownloadLimits()
+
 	stats := &add.DownloadStats{
 		FilesDownloaded: 0,
 		DirsCreated:     0,
 		BytesDownloaded: 0,
 	}
 
-	sem := make(chan struct{}, maxConcurrentDownloads)
+	// The semaphore is shared with the other skills of an UpdateAll.
+	sem := u.downloadSem
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var downloadErr error
@@ -437,6 +918,20 @@ func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRep
 		for _, item := range contents {
 			itemLocalPath := filepath.Join(localTarget, item.Name)
 
+			relPath := strings.TrimPrefix(strings.TrimPrefix(path.Join(remotePath, item.Name), downloadPath), "/")
+			if !includeHidden && add.IsHidden(relPath) {
+				mu.Lock()
+				stats.Hidden++
+				mu.Unlock()
+				continue
+			}
+			if ignore.Match(relPath, item.Type == "dir") {
+				mu.Lock()
+				stats.Ignored++
+				mu.Unlock()
+				continue
+			}
+
 			if item.Type == "dir" {
 				if err := os.MkdirAll(itemLocalPath, 0755); err != nil {
 					mu.Lock()
@@ -453,6 +948,17 @@ func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRep
 				wg.Add(1)
 				go downloadTaskFunc(item.Path, itemLocalPath)
 			} else if item.Type == "file" {
+				mu.Lock()
+				err := limits.Check(stats.FilesDownloaded+1, stats.BytesDownloaded+int64(item.Size))
+				if err != nil {
+					downloadErr = err
+				}
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+
 				data, err := u.client.DownloadFile(ctx, item.DownloadURL)
 				if err != nil {
 					mu.Lock()
@@ -462,6 +968,17 @@ func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRep
 					return
 				}
 
+				mu.Lock()
+				err = limits.Check(stats.FilesDownloaded+1, stats.BytesDownloaded+int64(len(data)))
+				if err != nil {
+					downloadErr = err
+				}
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+
 				if err := os.WriteFile(itemLocalPath, data, 0644); err != nil {
 					mu.Lock()
 					downloadErr = fmt.Errorf("failed to write file %s: %w", itemLocalPath, err)
@@ -486,6 +1003,12 @@ func (u *Updater) downloadRecursive(ctx context.Context, repoInfo *add.GitHubRep
 		return nil, downloadErr
 	}
 
+	// Workers waiting on the semaphore return silently when ctx is cancelled,
+	// so a cancelled walk must be reported here.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	return stats, nil
 }
 
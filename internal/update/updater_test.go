@@ -3,14 +3,22 @@ package update
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
 	"github.com/smy-101/gskills/internal/types"
 )
 
@@ -100,7 +108,7 @@ func TestCheckUpdate(t *testing.T) {
 				updater.SetBaseURL(ts.URL)
 			}
 
-			hasUpdate, newSHA, err := updater.CheckUpdate(tt.skill)
+			hasUpdate, newSHA, err := updater.CheckUpdate(context.Background(), tt.skill)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckUpdate() error = %v, wantErr %v", err, tt.wantErr)
@@ -121,7 +129,7 @@ func TestCheckUpdate(t *testing.T) {
 func TestUpdateSkill(t *testing.T) {
 	t.Run("nil skill", func(t *testing.T) {
 		updater := NewUpdater("")
-		err := updater.UpdateSkill(nil)
+		err := updater.UpdateSkill(context.Background(), nil)
 		if err == nil {
 			t.Error("UpdateSkill() should error with nil skill")
 		}
@@ -143,7 +151,7 @@ func TestUpdateSkill(t *testing.T) {
 		updater := NewUpdater("")
 		updater.SetBaseURL(ts.URL)
 
-		err := updater.UpdateSkill(skill)
+		err := updater.UpdateSkill(context.Background(), skill)
 		if err != nil {
 			t.Errorf("UpdateSkill() with no update should not error, got: %v", err)
 		}
@@ -173,6 +181,13 @@ func TestUpdateAll(t *testing.T) {
 			} else if r.URL.Path == "/repos/owner/repo/contents/skills/skill1" || r.URL.Path == "/repos/owner/repo/contents/skills/skill2" {
 				w.WriteHeader(200)
 				json.NewEncoder(w).Encode([]types.GitHubContent{
+					{
+						Type:        "file",
+						Name:        "SKILL.md",
+						Path:        "skills/skill1/SKILL.md",
+						SHA:         "skillsha",
+						DownloadURL: ts.URL + "/download/test.txt",
+					},
 					{
 						Type:        "file",
 						Name:        "test.txt",
@@ -210,7 +225,7 @@ func TestUpdateAll(t *testing.T) {
 		updater := NewUpdater("")
 		updater.SetBaseURL(ts.URL)
 
-		stats, err := updater.UpdateAll(skills)
+		stats, _, err := updater.UpdateAll(context.Background(), skills)
 		if err != nil {
 			t.Logf("UpdateAll() error = %v", err)
 		}
@@ -317,7 +332,7 @@ func TestDownloadRecursive(t *testing.T) {
 		}
 
 		ctx := context.Background()
-		stats, err := updater.downloadRecursive(ctx, repoInfo, targetDir, "skills/test")
+		stats, err := updater.downloadRecursive(ctx, repoInfo, targetDir, "skills/test", nil, false)
 		if err != nil {
 			t.Fatalf("downloadRecursive() error = %v", err)
 		}
@@ -368,7 +383,7 @@ func TestDownloadRecursive(t *testing.T) {
 
 		cancel()
 
-		_, err := updater.downloadRecursive(ctx, repoInfo, tmpDir, "skills/test")
+		_, err := updater.downloadRecursive(ctx, repoInfo, tmpDir, "skills/test", nil, false)
 
 		select {
 		case <-serverCalled:
@@ -382,3 +397,975 @@ func TestDownloadRecursive(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckAllUpdates_LocalSkill(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "local-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("v1"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "local-skill")
+	if _, err := add.InstallLocal(srcDir, storePath); err != nil {
+		t.Fatalf("InstallLocal() error = %v", err)
+	}
+
+	skill := &types.SkillMetadata{
+		ID:        "local-skill@local",
+		Name:      "local-skill",
+		Version:   add.LocalVersion,
+		CommitSHA: add.LocalVersion,
+		SourceURL: "file://" + srcDir,
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL("http://127.0.0.1:0")
+
+	results, err := updater.CheckAllUpdates(context.Background())
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("CheckAllUpdates() returned %d results, want 1", len(results))
+	}
+	if results[0].Status != UpdateStatusSkipped {
+		t.Errorf("local skill status = %v, want UpdateStatusSkipped", results[0].Status)
+	}
+
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("v2"), 0644); err != nil {
+		t.Fatalf("failed to modify SKILL.md: %v", err)
+	}
+	if err := updater.UpdateSkill(context.Background(), skill); err != nil {
+		t.Fatalf("UpdateSkill() on local skill error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(storePath, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("failed to read refreshed SKILL.md: %v", err)
+	}
+	if string(content) != "v2" {
+		t.Errorf("refreshed SKILL.md = %s, want v2", string(content))
+	}
+}
+
+func TestCheckAllUpdates_ResultsMatchSkills(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Each skill lives in its own repository whose head is "<repo>-sha".
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) < 5 || parts[4] != "commits" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"sha": parts[3] + "-sha"})
+	}))
+	defer ts.Close()
+
+	names := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta"}
+	for _, name := range names {
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/" + name + "/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: filepath.Join(homeDir, ".gskills", "skills", name),
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	results, err := updater.CheckAllUpdates(context.Background())
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() error = %v", err)
+	}
+	if len(results) != len(names) {
+		t.Fatalf("CheckAllUpdates() returned %d results, want %d", len(results), len(names))
+	}
+	for i, name := range names {
+		if results[i].Skill == nil || results[i].Skill.Name != name {
+			t.Errorf("results[%d].Skill = %+v, want %s", i, results[i].Skill, name)
+			continue
+		}
+		if results[i].NewCommitSHA != name+"-sha" {
+			t.Errorf("results[%d].NewCommitSHA = %s, want %s-sha", i, results[i].NewCommitSHA, name)
+		}
+	}
+}
+
+func TestCheckAllUpdates_AbortsWhenPingFails(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var commitRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rate_limit" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		commitRequests.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+	}))
+	defer ts.Close()
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/" + name + "/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: filepath.Join(homeDir, ".gskills", "skills", name),
+			UpdatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+	}
+
+	updater := NewUpdater("bad-token")
+	updater.SetBaseURL(ts.URL)
+
+	results, err := updater.CheckAllUpdates(context.Background())
+	if !errors.Is(err, add.ErrGitHubUnavailable) {
+		t.Fatalf("CheckAllUpdates() error = %v, want ErrGitHubUnavailable", err)
+	}
+	if !strings.Contains(err.Error(), "github_token") {
+		t.Errorf("error should point at the token: %v", err)
+	}
+	if results != nil {
+		t.Errorf("CheckAllUpdates() results = %+v, want none", results)
+	}
+	if n := commitRequests.Load(); n != 0 {
+		t.Errorf("%d skills were checked after the failed ping, want 0", n)
+	}
+}
+
+func TestCheckAllUpdates_PacesWhenRateLimitLow(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+		wantPaced bool
+	}{
+		{name: "low quota serializes", remaining: 5, wantPaced: true},
+		{name: "healthy quota runs concurrently", remaining: 4000, wantPaced: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			originalHome := os.Getenv("HOME")
+			os.Setenv("HOME", homeDir)
+			defer os.Setenv("HOME", originalHome)
+
+			reset := time.Now().Add(time.Minute).Unix()
+			var inFlight, maxInFlight atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/rate_limit" {
+					fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":%d,"reset":%d}}}`, tt.remaining, reset)
+					return
+				}
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					peak := maxInFlight.Load()
+					if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tt.remaining))
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+				json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+			}))
+			defer ts.Close()
+
+			var waits atomic.Int32
+			originalWait := paceWait
+			paceWait = func(ctx context.Context, d time.Duration) error {
+				if d <= 0 || d > time.Minute {
+					t.Errorf("pace delay = %v, want a share of the reset window", d)
+				}
+				waits.Add(1)
+				return nil
+			}
+			defer func() { paceWait = originalWait }()
+
+			names := []string{"alpha", "beta", "gamma", "delta"}
+			for _, name := range names {
+				if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+					ID:        name + "@main",
+					Name:      name,
+					Version:   "main",
+					SourceURL: "https://github.com/owner/" + name + "/tree/main/skills/" + name,
+					CommitSHA: "oldsha",
+					StorePath: filepath.Join(homeDir, ".gskills", "skills", name),
+					UpdatedAt: time.Now(),
+				}); err != nil {
+					t.Fatalf("failed to add skill to registry: %v", err)
+				}
+			}
+
+			updater := NewUpdater("")
+			updater.SetBaseURL(ts.URL)
+
+			results, err := updater.CheckAllUpdates(context.Background())
+			if err != nil {
+				t.Fatalf("CheckAllUpdates() error = %v", err)
+			}
+			for _, result := range results {
+				if result.Status == UpdateStatusFailed {
+					t.Errorf("%s failed: %v", result.Skill.Name, result.Error)
+				}
+			}
+
+			if tt.wantPaced {
+				if n := maxInFlight.Load(); n != 1 {
+					t.Errorf("max concurrent requests = %d, want 1", n)
+				}
+				if n := waits.Load(); n != int32(len(names)) {
+					t.Errorf("paced %d checks, want %d", n, len(names))
+				}
+			} else if n := waits.Load(); n != 0 {
+				t.Errorf("paced %d checks with a healthy quota, want 0", n)
+			}
+		})
+	}
+}
+
+func TestUpdateSkill_CancelCleansUpTempDir(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills")
+	storePath := filepath.Join(skillsDir, "test")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	var once sync.Once
+
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case "/repos/owner/repo/contents/skills/test":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/test/SKILL.md", DownloadURL: serverURL + "/slow"},
+			})
+		case "/slow":
+			once.Do(func() { close(started) })
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/test",
+		CommitSHA: "oldsha",
+		StorePath: storePath,
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	if err := updater.UpdateSkill(ctx, skill); err == nil {
+		t.Fatal("UpdateSkill() expected error after cancellation, got nil")
+	}
+
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		t.Fatalf("failed to read skills directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp.") {
+			t.Errorf("temporary directory %s was not cleaned up", entry.Name())
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(storePath, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("existing skill was removed: %v", err)
+	}
+	if string(content) != "old" {
+		t.Errorf("existing SKILL.md = %s, want 'old'", string(content))
+	}
+}
+
+func TestUpdateSkill_PreservesTags(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "test")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case "/repos/owner/repo/contents/skills/test":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/test/SKILL.md", DownloadURL: serverURL + "/skillmd"},
+			})
+		case "/skillmd":
+			w.Write([]byte("new"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/test",
+		CommitSHA: "oldsha",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+		Tags:      []string{"frontend", "writing"},
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	if err := updater.UpdateSkill(context.Background(), skill); err != nil {
+		t.Fatalf("UpdateSkill() error = %v", err)
+	}
+
+	updated, err := registry.FindSkillByName("test")
+	if err != nil {
+		t.Fatalf("skill missing from registry: %v", err)
+	}
+	if updated.CommitSHA != "newsha" {
+		t.Errorf("CommitSHA = %s, want newsha", updated.CommitSHA)
+	}
+	if strings.Join(updated.Tags, ",") != "frontend,writing" {
+		t.Errorf("Tags = %v, want [frontend writing]", updated.Tags)
+	}
+}
+
+func TestUpdateSkill_RejectsMissingManifest(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "test")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	// Upstream renamed SKILL.md to README.md.
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case "/repos/owner/repo/contents/skills/test":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "README.md", Path: "skills/test/README.md", DownloadURL: serverURL + "/readme"},
+			})
+		case "/readme":
+			w.Write([]byte("new"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/test",
+		CommitSHA: "oldsha",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	err := updater.UpdateSkill(context.Background(), skill)
+	if !errors.Is(err, &UpdateError{Type: UpdateErrorTypeInvalid}) {
+		t.Fatalf("UpdateSkill() error = %v, want UpdateErrorTypeInvalid", err)
+	}
+	if !errors.Is(err, add.ErrSkillManifestMissing) {
+		t.Errorf("UpdateSkill() error = %v, want it to wrap ErrSkillManifestMissing", err)
+	}
+	if !strings.Contains(err.Error(), "SKILL.md, SKILL.yaml or SKILL.json") {
+		t.Errorf("UpdateSkill() error = %v, want it to list the accepted manifests", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(storePath, "SKILL.md")); err != nil || string(data) != "old" {
+		t.Errorf("installed SKILL.md = %q (%v), want the old version kept", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(storePath, "README.md")); !os.IsNotExist(err) {
+		t.Errorf("rejected update must not touch the store: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(storePath))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp.") {
+			t.Errorf("temporary directory %s left behind", entry.Name())
+		}
+	}
+
+	updated, err := registry.FindSkillByName("test")
+	if err != nil {
+		t.Fatalf("skill missing from registry: %v", err)
+	}
+	if updated.CommitSHA != "oldsha" {
+		t.Errorf("CommitSHA = %s, want oldsha", updated.CommitSHA)
+	}
+
+	// A skill added with --skip-skill-check never had a manifest to lose.
+	skill.Unverified = true
+	if err := registry.UpdateSkill(skill); err != nil {
+		t.Fatalf("failed to update registry: %v", err)
+	}
+	if err := updater.UpdateSkill(context.Background(), skill); err != nil {
+		t.Fatalf("UpdateSkill() of an unverified skill error = %v", err)
+	}
+}
+
+func TestLinksBehind(t *testing.T) {
+	skill := &types.SkillMetadata{
+		Name:      "skill",
+		CommitSHA: "new",
+		LinkedProjects: map[string]types.LinkedProjectInfo{
+			"/projects/b":       {CommitSHA: "old"},
+			"/projects/a":       {CommitSHA: "older"},
+			"/projects/current": {CommitSHA: "new"},
+			"/projects/legacy":  {},
+		},
+	}
+
+	got := LinksBehind(skill)
+	want := []string{"/projects/a", "/projects/b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LinksBehind() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckUpdate_RefType(t *testing.T) {
+	tests := []struct {
+		name         string
+		refType      string
+		wantUpdate   bool
+		wantRequests int
+	}{
+		{name: "branch tracks the head", refType: add.RefTypeBranch, wantUpdate: true, wantRequests: 1},
+		{name: "unknown ref type tracks like a branch", refType: "", wantUpdate: true, wantRequests: 1},
+		{name: "tag is frozen", refType: add.RefTypeTag, wantUpdate: false, wantRequests: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+			}))
+			defer ts.Close()
+
+			skill := &types.SkillMetadata{
+				Name:      "test-skill",
+				SourceURL: "https://github.com/owner/repo/tree/v1.0.0/skills/test",
+				Version:   "v1.0.0",
+				CommitSHA: "oldsha",
+				RefType:   tt.refType,
+			}
+
+			updater := NewUpdater("")
+			updater.SetBaseURL(ts.URL)
+
+			hasUpdate, _, err := updater.CheckUpdate(context.Background(), skill)
+			if err != nil {
+				t.Fatalf("CheckUpdate() error = %v", err)
+			}
+			if hasUpdate != tt.wantUpdate {
+				t.Errorf("CheckUpdate() hasUpdate = %v, want %v", hasUpdate, tt.wantUpdate)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("CheckUpdate() made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestUpdateSkill_FollowsNewestTag(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "test")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+
+	var serverURL, downloadedRef string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/tags":
+			json.NewEncoder(w).Encode([]map[string]string{
+				{"name": "v2.0.0-beta.1"}, {"name": "v1.2.0"}, {"name": "v1.1.0"}, {"name": "v1.0.0"},
+			})
+		case "/repos/owner/repo/commits/v1.2.0":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "sha120"})
+		case "/repos/owner/repo/contents/skills/test":
+			downloadedRef = r.URL.Query().Get("ref")
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/test/SKILL.md", DownloadURL: serverURL + "/skillmd"},
+			})
+		case "/skillmd":
+			w.Write([]byte("v1.2.0"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	skill := &types.SkillMetadata{
+		ID:         "test@v1.0.0",
+		Name:       "test",
+		Version:    "v1.0.0",
+		SourceURL:  "https://github.com/owner/repo/tree/v1.0.0/skills/test",
+		CommitSHA:  "sha100",
+		RefType:    add.RefTypeTag,
+		TagPattern: "v*",
+		StorePath:  storePath,
+		UpdatedAt:  time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	infos, err := updater.CheckAllUpdates(context.Background())
+	if err != nil {
+		t.Fatalf("CheckAllUpdates() error = %v", err)
+	}
+	if len(infos) != 1 || infos[0].Status != UpdateStatusAvailable || infos[0].NewVersion != "v1.2.0" {
+		t.Fatalf("CheckAllUpdates() = %+v, want an update to v1.2.0", infos)
+	}
+
+	if err := updater.UpdateSkill(context.Background(), skill); err != nil {
+		t.Fatalf("UpdateSkill() error = %v", err)
+	}
+	if downloadedRef != "v1.2.0" {
+		t.Errorf("downloaded ref = %q, want v1.2.0", downloadedRef)
+	}
+
+	updated, err := registry.FindSkillByName("test")
+	if err != nil {
+		t.Fatalf("skill missing from registry: %v", err)
+	}
+	if updated.Version != "v1.2.0" || updated.CommitSHA != "sha120" ||
+		updated.SourceURL != "https://github.com/owner/repo/tree/v1.2.0/skills/test" {
+		t.Errorf("updated skill = %+v, want v1.2.0 at sha120", updated)
+	}
+	if updated.ID != skill.ID || updated.TagPattern != "v*" {
+		t.Errorf("ID and TagPattern should be kept, got %s and %q", updated.ID, updated.TagPattern)
+	}
+}
+
+func TestUpdateAll_AggregatesBytesDownloaded(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	files := map[string]string{
+		"alpha": "alpha skill content",
+		"beta":  strings.Repeat("b", 4096),
+	}
+
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/commits/"):
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/contents/skills/"):
+			name := filepath.Base(r.URL.Path)
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/" + name + "/SKILL.md", DownloadURL: serverURL + "/raw/" + name},
+			})
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			w.Write([]byte(files[filepath.Base(r.URL.Path)]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	var skills []*types.SkillMetadata
+	var wantBytes int64
+	for name, content := range files {
+		storePath := filepath.Join(homeDir, ".gskills", "skills", name)
+		if err := os.MkdirAll(storePath, 0755); err != nil {
+			t.Fatalf("failed to create store directory: %v", err)
+		}
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/repo/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: storePath,
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+		skills = append(skills, skill)
+		wantBytes += int64(len(content))
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	stats, _, err := updater.UpdateAll(context.Background(), skills)
+	if err != nil {
+		t.Fatalf("UpdateAll() error = %v", err)
+	}
+	if stats.Updated != len(files) {
+		t.Fatalf("stats.Updated = %d, want %d", stats.Updated, len(files))
+	}
+	if stats.BytesDownloaded != wantBytes {
+		t.Errorf("stats.BytesDownloaded = %d, want %d", stats.BytesDownloaded, wantBytes)
+	}
+	if stats.BytesPerSecond() <= 0 {
+		t.Errorf("stats.BytesPerSecond() = %v, want > 0", stats.BytesPerSecond())
+	}
+}
+
+func TestUpdateAll_ReportsFailedSkillsForRetry(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var mu sync.Mutex
+	brokenDownloads := true
+
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/commits/"):
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/contents/skills/"):
+			name := filepath.Base(r.URL.Path)
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/" + name + "/SKILL.md", DownloadURL: serverURL + "/raw/" + name},
+			})
+		case r.URL.Path == "/raw/broken":
+			mu.Lock()
+			broken := brokenDownloads
+			mu.Unlock()
+			if broken {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte("fixed"))
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	var skills []*types.SkillMetadata
+	for _, name := range []string{"healthy", "broken"} {
+		storePath := filepath.Join(homeDir, ".gskills", "skills", name)
+		if err := os.MkdirAll(storePath, 0755); err != nil {
+			t.Fatalf("failed to create store directory: %v", err)
+		}
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/repo/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: storePath,
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+		skills = append(skills, skill)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	stats, results, err := updater.UpdateAll(context.Background(), skills)
+	if err != nil {
+		t.Fatalf("UpdateAll() error = %v", err)
+	}
+	if stats.Updated != 1 || stats.Failed != 1 {
+		t.Fatalf("stats = %+v, want 1 updated and 1 failed", stats)
+	}
+	if results[0].Skill.Name != "healthy" || results[0].Status != UpdateStatusUpdated {
+		t.Errorf("results[0] = %+v, want healthy updated", results[0])
+	}
+	if results[1].Skill.Name != "broken" || results[1].Status != UpdateStatusFailed || results[1].Error == nil {
+		t.Errorf("results[1] = %+v, want broken failed with an error", results[1])
+	}
+
+	failed := FailedSkills(results)
+	if len(failed) != 1 || failed[0].Name != "broken" {
+		t.Fatalf("FailedSkills() = %v, want [broken]", failed)
+	}
+
+	mu.Lock()
+	brokenDownloads = false
+	mu.Unlock()
+
+	retryStats, retryResults, err := updater.UpdateAll(context.Background(), failed)
+	if err != nil {
+		t.Fatalf("retry UpdateAll() error = %v", err)
+	}
+	if retryStats.Total != 1 || retryStats.Updated != 1 || retryStats.Failed != 0 {
+		t.Errorf("retry stats = %+v, want only the failed skill updated", retryStats)
+	}
+	if len(FailedSkills(retryResults)) != 0 {
+		t.Errorf("retry still reports failures: %+v", retryResults)
+	}
+
+	updated, err := registry.FindSkillByName("broken")
+	if err != nil {
+		t.Fatalf("skill missing from registry: %v", err)
+	}
+	if updated.CommitSHA != "newsha" {
+		t.Errorf("CommitSHA after retry = %s, want newsha", updated.CommitSHA)
+	}
+}
+
+func TestUpdateAll_OnFailureStops(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/commits/"):
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/contents/skills/"):
+			name := filepath.Base(r.URL.Path)
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skills/" + name + "/SKILL.md", DownloadURL: serverURL + "/raw/" + name},
+			})
+		case r.URL.Path == "/raw/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	var skills []*types.SkillMetadata
+	for _, name := range []string{"first", "broken", "third", "fourth"} {
+		storePath := filepath.Join(homeDir, ".gskills", "skills", name)
+		if err := os.MkdirAll(storePath, 0755); err != nil {
+			t.Fatalf("failed to create store directory: %v", err)
+		}
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/repo/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: storePath,
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+		skills = append(skills, skill)
+	}
+
+	var asked []string
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+	updater.SetOnFailure(func(skill *types.SkillMetadata, err error) bool {
+		asked = append(asked, skill.Name)
+		if err == nil {
+			t.Error("failure handler called without an error")
+		}
+		return false
+	})
+
+	stats, results, err := updater.UpdateAll(context.Background(), skills)
+	if err != nil {
+		t.Fatalf("UpdateAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(asked, []string{"broken"}) {
+		t.Errorf("failure handler called for %v, want [broken]", asked)
+	}
+	if stats.Updated != 1 || stats.Failed != 1 || stats.Skipped != 2 {
+		t.Errorf("stats = %+v, want 1 updated, 1 failed, 2 skipped", stats)
+	}
+	wantStatus := []UpdateStatus{UpdateStatusUpdated, UpdateStatusFailed, UpdateStatusSkipped, UpdateStatusSkipped}
+	for i, want := range wantStatus {
+		if results[i].Status != want {
+			t.Errorf("results[%d] (%s) status = %v, want %v", i, results[i].Skill.Name, results[i].Status, want)
+		}
+	}
+
+	for _, name := range []string{"third", "fourth"} {
+		skill, err := registry.FindSkillByName(name)
+		if err != nil {
+			t.Fatalf("skill missing from registry: %v", err)
+		}
+		if skill.CommitSHA != "oldsha" {
+			t.Errorf("%s was updated after the handler said stop", name)
+		}
+	}
+}
+
+func TestUpdateAll_SharesDownloadLimitAcrossSkills(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var inFlight, maxInFlight atomic.Int32
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/commits/"):
+			json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/contents/skills/"):
+			dir := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/contents/")
+			entries := []types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: dir + "/SKILL.md", DownloadURL: serverURL + "/raw/" + dir + "/SKILL.md"},
+			}
+			if strings.Count(dir, "/") == 1 {
+				for _, sub := range []string{"a", "b", "c"} {
+					entries = append(entries, types.GitHubContent{Type: "dir", Name: sub, Path: dir + "/" + sub})
+				}
+			}
+			json.NewEncoder(w).Encode(entries)
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			w.Write([]byte("content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	var skills []*types.SkillMetadata
+	for _, name := range []string{"one", "two", "three", "four"} {
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/repo/tree/main/skills/" + name,
+			CommitSHA: "oldsha",
+			StorePath: filepath.Join(homeDir, ".gskills", "skills", name),
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+		skills = append(skills, skill)
+	}
+
+	const limit = 2
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+	updater.SetConcurrency(limit)
+
+	stats, _, err := updater.UpdateAll(context.Background(), skills)
+	if err != nil {
+		t.Fatalf("UpdateAll() error = %v", err)
+	}
+	if stats.Updated != len(skills) {
+		t.Fatalf("stats.Updated = %d, want %d", stats.Updated, len(skills))
+	}
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max in-flight downloads across skills = %d, want at most %d", got, limit)
+	}
+}
@@ -0,0 +1,65 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxWatchBackoff caps how many intervals Watch waits after cycles that hit
+// the GitHub rate limit.
+const maxWatchBackoff = 8
+
+// Watch checks all skills for updates immediately and then every interval
+// until ctx is cancelled, passing the results of each cycle to report. It
+// only checks; nothing is downloaded.
+//
+// When a cycle hits the GitHub rate limit the wait before the next check is
+// doubled, up to maxWatchBackoff intervals, and reset after a clean cycle.
+// A cycle that fails as a whole, e.g. because the registry cannot be read,
+// is logged and retried at the next interval.
+//
+// Watch returns nil once ctx is cancelled.
+func (u *Updater) Watch(ctx context.Context, interval time.Duration, report func([]SkillUpdateInfo)) error {
+	if interval <= 0 {
+		return errors.New("watch interval must be positive")
+	}
+
+	backoff := 1
+	for {
+		infos, err := u.CheckAllUpdates(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			u.logger.Error("Failed to check for updates", err)
+		} else {
+			report(infos)
+		}
+
+		if rateLimited(infos) {
+			backoff = min(backoff*2, maxWatchBackoff)
+			u.logger.Warn("Rate limit hit, slowing down checks", "next_check", interval*time.Duration(backoff))
+		} else {
+			backoff = 1
+		}
+
+		timer := time.NewTimer(interval * time.Duration(backoff))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		}
+	}
+}
+
+// rateLimited reports whether any check in infos failed on the rate limit.
+func rateLimited(infos []SkillUpdateInfo) bool {
+	for _, info := range infos {
+		if info.Status == UpdateStatusFailed && isRateLimitError(info.Error) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestWatch_ChecksEachIntervalUntilCancelled(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{"sha": "newsha"})
+	}))
+	defer ts.Close()
+
+	skill := &types.SkillMetadata{
+		ID:        "test@main",
+		Name:      "test",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/test",
+		CommitSHA: "oldsha",
+		StorePath: t.TempDir(),
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cycles int
+	done := make(chan error, 1)
+	go func() {
+		done <- updater.Watch(ctx, 10*time.Millisecond, func(infos []SkillUpdateInfo) {
+			cycles++
+			if len(infos) != 1 || infos[0].Status != UpdateStatusAvailable {
+				t.Errorf("cycle %d: infos = %+v, want one available update", cycles, infos)
+			}
+			if cycles == 2 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not stop after the context was cancelled")
+	}
+
+	if cycles < 2 {
+		t.Errorf("Watch() ran %d check cycles, want at least 2", cycles)
+	}
+	if got := requests.Load(); got < 2 {
+		t.Errorf("server saw %d commit requests, want at least 2", got)
+	}
+}
+
+func TestWatch_RejectsNonPositiveInterval(t *testing.T) {
+	updater := NewUpdater("")
+	if err := updater.Watch(context.Background(), 0, func([]SkillUpdateInfo) {}); err == nil {
+		t.Error("Watch() with a zero interval should fail")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	infos := []SkillUpdateInfo{
+		{Status: UpdateStatusUpToDate},
+		{Status: UpdateStatusFailed, Error: &UpdateError{Type: UpdateErrorTypeCheck, Message: "API returned 403: rate limit exceeded"}},
+	}
+	if !rateLimited(infos) {
+		t.Error("rateLimited() = false for a rate-limited check")
+	}
+	if rateLimited(infos[:1]) {
+		t.Error("rateLimited() = true without failures")
+	}
+}
@@ -0,0 +1,184 @@
+// Package version reports the build version of gskills.
+//
+// Release builds inject the version and commit with -ldflags:
+//
+//	go build -ldflags "-X github.com/smy-101/gskills/internal/version.Version=v1.2.0 \
+//	  -X github.com/smy-101/gskills/internal/version.Commit=abc1234" ./cmd/gskills
+//
+// Without them the values are read from the module build info, which is
+// populated by `go install module@version` and by builds from a VCS checkout.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+const (
+	// devVersion is reported when no version is known.
+	devVersion = "dev"
+	// userAgentPrefix is prepended to the version in the User-Agent header.
+	userAgentPrefix = "gskills-cli/"
+)
+
+// Version and Commit are set via -ldflags at build time.
+var (
+	Version = ""
+	Commit  = ""
+)
+
+// readBuildInfo is a variable so tests can stub the module build info.
+var readBuildInfo = debug.ReadBuildInfo
+
+// Info describes the running gskills build.
+type Info struct {
+	Version   string
+	Commit    string
+	GoVersion string
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := readBuildInfo(); ok {
+		if info.Version == "" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		if info.Commit == "" {
+			for _, setting := range buildInfo.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+					break
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// UserAgent returns the User-Agent sent with GitHub API requests.
+func UserAgent() string {
+	return userAgentPrefix + Get().Version
+}
+
+// Repository that publishes gskills releases.
+const (
+	RepoOwner = "smy-101"
+	RepoName  = "gskills"
+)
+
+// Compare compares two semantic versions such as "v1.2.3" or "1.2.3-rc.1".
+// It returns -1, 0 or +1 when a is lower than, equal to or higher than b.
+// A pre-release sorts before the corresponding release; pre-release
+// identifiers are otherwise compared as plain strings.
+func Compare(a, b string) (int, error) {
+	va, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.core {
+		if va.core[i] != vb.core[i] {
+			if va.core[i] < vb.core[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	switch {
+	case va.pre == vb.pre:
+		return 0, nil
+	case va.pre == "":
+		return 1, nil
+	case vb.pre == "":
+		return -1, nil
+	case va.pre < vb.pre:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+type semver struct {
+	core [3]int
+	pre  string
+}
+
+// parseSemver parses MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD] with an
+// optional leading "v".
+func parseSemver(s string) (semver, error) {
+	var v semver
+
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.pre = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if rest == "" || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.core[i] = n
+	}
+	return v, nil
+}
+
+// IsPrerelease reports whether s is a semantic version with a pre-release
+// suffix, such as "v1.2.0-rc.1". It returns false for invalid versions.
+func IsPrerelease(s string) bool {
+	v, err := parseSemver(s)
+	return err == nil && v.pre != ""
+}
+
+// Delta names the most significant component that differs between two
+// semantic versions: "major", "minor", "patch" or "prerelease". It returns
+// an empty string if the versions are equal or either is not semantic.
+func Delta(from, to string) string {
+	a, err := parseSemver(from)
+	if err != nil {
+		return ""
+	}
+	b, err := parseSemver(to)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case a.core[0] != b.core[0]:
+		return "major"
+	case a.core[1] != b.core[1]:
+		return "minor"
+	case a.core[2] != b.core[2]:
+		return "patch"
+	case a.pre != b.pre:
+		return "prerelease"
+	default:
+		return ""
+	}
+}
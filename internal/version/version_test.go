@@ -0,0 +1,125 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		commit      string
+		buildInfo   *debug.BuildInfo
+		wantVersion string
+		wantCommit  string
+	}{
+		{
+			name:        "ldflags take precedence",
+			version:     "v1.2.0",
+			commit:      "abc1234",
+			buildInfo:   &debug.BuildInfo{Main: debug.Module{Version: "v0.9.0"}},
+			wantVersion: "v1.2.0",
+			wantCommit:  "abc1234",
+		},
+		{
+			name:    "module build info",
+			version: "",
+			buildInfo: &debug.BuildInfo{
+				Main:     debug.Module{Version: "v1.1.0"},
+				Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "def5678"}},
+			},
+			wantVersion: "v1.1.0",
+			wantCommit:  "def5678",
+		},
+		{
+			name:        "devel build",
+			buildInfo:   &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			wantVersion: "dev",
+		},
+		{
+			name:        "no build info",
+			wantVersion: "dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldVersion, oldCommit, oldRead := Version, Commit, readBuildInfo
+			defer func() { Version, Commit, readBuildInfo = oldVersion, oldCommit, oldRead }()
+
+			Version, Commit = tt.version, tt.commit
+			readBuildInfo = func() (*debug.BuildInfo, bool) {
+				return tt.buildInfo, tt.buildInfo != nil
+			}
+
+			info := Get()
+			if info.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", info.Version, tt.wantVersion)
+			}
+			if info.Commit != tt.wantCommit {
+				t.Errorf("Commit = %q, want %q", info.Commit, tt.wantCommit)
+			}
+			if info.GoVersion == "" {
+				t.Error("GoVersion should not be empty")
+			}
+			if got, want := UserAgent(), "gskills-cli/"+tt.wantVersion; got != want {
+				t.Errorf("UserAgent() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "v1.2.3", b: "v1.2.3", want: 0},
+		{a: "1.2.3", b: "v1.2.3", want: 0},
+		{a: "v1.2.3", b: "v1.10.0", want: -1},
+		{a: "v2.0.0", b: "v1.9.9", want: 1},
+		{a: "v1.2", b: "v1.2.1", want: -1},
+		{a: "v1.3.0-rc.1", b: "v1.3.0", want: -1},
+		{a: "v1.3.0", b: "v1.3.0-rc.1", want: 1},
+		{a: "v1.3.0+build.5", b: "v1.3.0", want: 0},
+		{a: "dev", b: "v1.0.0", wantErr: true},
+		{a: "v1.0.0", b: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			got, err := Compare(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{from: "v1.2.3", to: "v2.0.0", want: "major"},
+		{from: "v1.2.3", to: "v1.3.0", want: "minor"},
+		{from: "v1.2.3", to: "v1.2.4", want: "patch"},
+		{from: "v1.3.0-rc.1", to: "v1.3.0", want: "prerelease"},
+		{from: "v1.2.3", to: "v1.2.3", want: ""},
+		{from: "main", to: "main", want: ""},
+		{from: "v1.2.3", to: "main", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			if got := Delta(tt.from, tt.to); got != tt.want {
+				t.Errorf("Delta(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/prompt"
+)
+
+// promptInput and promptOutput are where interactive prompts read answers
+// and print questions. They are variables so tests can drive prompts with
+// canned input.
+var (
+	promptInput  io.Reader = os.Stdin
+	promptOutput io.Writer = os.Stdout
+)
+
+// promptReader buffers promptInput so that consecutive prompts do not lose
+// input read ahead by an earlier one.
+var (
+	promptReader       *bufio.Reader
+	promptReaderSource io.Reader
+)
+
+// readPromptLine reads one answer from promptInput without the trailing
+// newline. A final line without a newline is returned as is.
+func readPromptLine() (string, error) {
+	if promptReader == nil || promptReaderSource != promptInput {
+		promptReader = bufio.NewReader(promptInput)
+		promptReaderSource = promptInput
+	}
+
+	line, err := promptReader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// resolveAddSources expands rawURL into the sources to add, printing progress
+// to w. A GitHub path without a branch is resolved against the repository's
+// default branch. Anything other
+// than a GitHub repository root is returned unchanged. For a repository root
+// the skill directories of the repository are discovered: a single skill is
+// added directly, several are all added with all, picked interactively on a
+// terminal, or rejected with the list of skills otherwise.
+func resolveAddSources(ctx context.Context, w io.Writer, rawURL string, all bool) ([]string, error) {
+	server := configuredServer()
+	if info, ok := server.ParseBranchlessURL(rawURL); ok {
+		skillURL, err := resolveDefaultBranch(ctx, w, info)
+		if err != nil {
+			return nil, err
+		}
+		return []string{skillURL}, nil
+	}
+
+	repoInfo, ok := server.ParseRepoRootURL(rawURL)
+	if !ok {
+		return []string{rawURL}, nil
+	}
+
+	manager := newManager(configString("github_token"))
+	manager.Client().SetTimeout(rootTimeout)
+
+	fmt.Fprintf(w, "Looking for skills in %s...\n", rawURL)
+	skillDirs, err := manager.Client().FindSkillDirs(ctx, repoInfo)
+	if err != nil || len(skillDirs) == 0 {
+		return nil, formatRepoRootError(rawURL, repoInfo, skillDirs, err)
+	}
+
+	if len(skillDirs) > 1 && !all {
+		if !prompt.IsInteractive() {
+			return nil, fmt.Errorf("%w\nUse --all to install every skill", formatRepoRootError(rawURL, repoInfo, skillDirs, nil))
+		}
+		skillDirs, err = pickSkills(skillDirs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sources := make([]string, len(skillDirs))
+	for i, dir := range skillDirs {
+		sources[i] = repoInfo.SkillURL(dir)
+	}
+	return sources, nil
+}
+
+// resolveDefaultBranch returns the skill URL of info, a GitHub path given
+// without a branch, on the repository's default branch, and notes the branch
+// used on w.
+func resolveDefaultBranch(ctx context.Context, w io.Writer, info *add.GitHubRepoInfo) (string, error) {
+	manager := newManager(configString("github_token"))
+	manager.Client().SetTimeout(rootTimeout)
+
+	branch, err := manager.Client().GetDefaultBranch(ctx, info.Owner, info.Repo)
+	if err != nil {
+		return "", fmt.Errorf("no branch in URL and the default branch of %s/%s could not be determined: %w", info.Owner, info.Repo, err)
+	}
+	info.Branch = branch
+
+	fmt.Fprintf(w, "No branch in URL, using default branch '%s' of %s/%s\n", branch, info.Owner, info.Repo)
+	return info.SkillURL(info.Path), nil
+}
+
+// resolveNestedSkills looks for skills in the immediate subdirectories of
+// rawURL, a GitHub directory without SKILL.md, printing progress to w. It
+// returns nil when there are none or they cannot be listed, so that the
+// caller reports the original error. Several skills are selected as for a
+// repository root: all with all, picked interactively on a terminal, or
+// rejected with the list of skills otherwise.
+func resolveNestedSkills(ctx context.Context, w io.Writer, rawURL string, all bool) ([]string, error) {
+	repoInfo, err := configuredServer().ParseGitHubURL(rawURL)
+	if err != nil {
+		return nil, nil
+	}
+
+	manager := newManager(configString("github_token"))
+	manager.Client().SetTimeout(rootTimeout)
+
+	fmt.Fprintf(w, "No SKILL.md in %s, looking for skills in its subdirectories...\n", rawURL)
+	skillDirs, err := manager.Client().FindNestedSkillDirs(ctx, repoInfo)
+	if err != nil || len(skillDirs) == 0 {
+		return nil, nil
+	}
+
+	if len(skillDirs) > 1 && !all {
+		if !prompt.IsInteractive() {
+			var b strings.Builder
+			fmt.Fprintf(&b, "%s is not a skill directory but contains these skills; add one with:", rawURL)
+			for _, dir := range skillDirs {
+				fmt.Fprintf(&b, "\n  gskills add %s", repoInfo.SkillURL(dir))
+			}
+			b.WriteString("\nUse --all to install every skill")
+			return nil, errors.New(b.String())
+		}
+		skillDirs, err = pickSkills(skillDirs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sources := make([]string, len(skillDirs))
+	for i, dir := range skillDirs {
+		sources[i] = repoInfo.SkillURL(dir)
+	}
+	return sources, nil
+}
+
+// pickSkills prints the candidates as a numbered list and reads the user's
+// choice from promptInput. The answer is a list of numbers separated by
+// spaces or commas, or "all". An empty answer selects nothing and is
+// reported as an error.
+func pickSkills(candidates []string) ([]string, error) {
+	fmt.Fprintln(promptOutput, "Found multiple skills:")
+	for i, candidate := range candidates {
+		fmt.Fprintf(promptOutput, "  %d) %s\n", i+1, candidate)
+	}
+	fmt.Fprint(promptOutput, "Select skills to install (e.g. 1,3 or all): ")
+
+	line, err := readPromptLine()
+	if err != nil {
+		return nil, err
+	}
+
+	answer := strings.ToLower(line)
+	if answer == "" {
+		return nil, errors.New("no skills selected")
+	}
+	if answer == "all" || answer == "a" {
+		return candidates, nil
+	}
+
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	var picked []string
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(candidates) {
+			return nil, fmt.Errorf("invalid selection %q: enter numbers between 1 and %d", field, len(candidates))
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		picked = append(picked, candidates[n-1])
+	}
+	return picked, nil
+}
+
+// repoRootError explains that rawURL points at a repository root rather than
+// at a skill, and lists the skill directories found in the repository so the
+// user can pick one. Discovery failures only drop the list, not the hint.
+func repoRootError(ctx context.Context, client *add.Client, rawURL string, repoInfo *add.GitHubRepoInfo) error {
+	skillDirs, err := client.FindSkillDirs(ctx, repoInfo)
+	return formatRepoRootError(rawURL, repoInfo, skillDirs, err)
+}
+
+// formatRepoRootError builds the error returned for a repository root URL
+// from the result of the skill discovery.
+func formatRepoRootError(rawURL string, repoInfo *add.GitHubRepoInfo, skillDirs []string, discoverErr error) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is a repository root, not a skill directory", rawURL)
+
+	switch {
+	case discoverErr != nil:
+		fmt.Fprintf(&b, " (could not list its skills: %v)", discoverErr)
+	case len(skillDirs) > 0:
+		b.WriteString("\nThe repository contains these skills; add one with:")
+		for _, dir := range skillDirs {
+			fmt.Fprintf(&b, "\n  gskills add %s", repoInfo.SkillURL(dir))
+		}
+		return errors.New(b.String())
+	default:
+		b.WriteString(" and no skills were found in it")
+	}
+
+	branch := repoInfo.Branch
+	if branch == "" {
+		branch = "<branch>"
+	}
+	fmt.Fprintf(&b, "\nPoint at the skill directory instead, e.g. %s/%s/%s/tree/%s/skills/<skill-name>",
+		repoInfo.WebURL(), repoInfo.Owner, repoInfo.Repo, branch)
+	return errors.New(b.String())
+}
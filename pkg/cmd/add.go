@@ -1,43 +1,706 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/prompt"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/sourceurl"
+	"github.com/smy-101/gskills/internal/types"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+)
+
+var (
+	addLinkProject string
+	addAll         bool
+	addOverwrite   bool
+	addNoOverwrite bool
+	addStore       string
+	addTagPattern  string
+	addPolicy      string
+	addMaxRetries  int
+	addRetryWait   time.Duration
+	addSkipCheck   bool
+	addQuiet       bool
+	addForce       bool
+	addChecksum    string
+	addConcurrency int
+	addResume      bool
+	addHidden      bool
+	addReplace     string
+	addPostInstall string
+	addJSON        bool
+	addAllowEmpty  bool
 )
 
 func init() {
 	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().StringVar(&addLinkProject, "link", "", "添加成功后将技能链接到指定项目 (不带值时为当前目录，指定路径请使用 --link=<path>)")
+	addCmd.Flags().Lookup("link").NoOptDefVal = "."
+	addCmd.Flags().BoolVar(&addAll, "all", false, "仓库根地址包含多个技能时全部安装，不再逐个选择")
+	addCmd.Flags().BoolVar(&addResume, "resume", false, "继续同一仓库地址上次中断的批量安装，只安装尚未完成的技能")
+	addCmd.Flags().StringVar(&addReplace, "replace", "", "添加成功后用新技能替换指定的旧技能：项目链接转移到新技能，旧技能被删除")
+	addCmd.Flags().StringVar(&addPostInstall, "post-install", "", "添加成功后在技能的存储目录中运行的命令 (如 'npm install')，失败时保留已添加的技能")
+	addCmd.Flags().BoolVar(&addOverwrite, "overwrite", false, "技能已存在时直接覆盖，不再询问")
+	addCmd.Flags().BoolVar(&addNoOverwrite, "no-overwrite", false, "技能已存在时立即失败，不再询问")
+	addCmd.MarkFlagsMutuallyExclusive("overwrite", "no-overwrite")
+	addCmd.Flags().StringVar(&addStore, "store", "", "将技能存放到指定目录，而不是 ~/.gskills/skills")
+	addCmd.Flags().BoolVar(&addSkipCheck, "skip-skill-check", false, "GitHub 目录中没有 SKILL.md 时仍然下载，并标记为未验证")
+	addCmd.Flags().BoolVar(&addAllowEmpty, "allow-empty", false, "技能目录中没有任何文件时仍然添加 (默认中止)")
+	addCmd.Flags().BoolVar(&addHidden, "include-hidden", false, "同时下载技能根目录下的隐藏文件和目录 (如 .github、.gitignore)，默认跳过")
+	addCmd.Flags().BoolVar(&addForce, "force", false, "即使已安装同一来源的相同提交，也重新下载")
+	addCmd.Flags().StringVar(&addChecksum, "checksum", "", "校验技能内容的聚合 SHA-256 (sha256:<hex>)，不匹配时放弃安装")
+	addCmd.Flags().IntVar(&addConcurrency, "concurrency", 0, fmt.Sprintf("本次下载的并行请求数 (1-%d，超过上限时按上限处理)，默认 3", add.MaxConcurrency))
+	addCmd.Flags().IntVar(&addMaxRetries, "max-retries", 0, fmt.Sprintf("请求失败后的最大重试次数 (0-%d)，默认使用配置项 max_retries 或内置值", add.MaxRetries))
+	addCmd.Flags().DurationVar(&addRetryWait, "retry-wait", 0, fmt.Sprintf("首次重试前的等待时间，之后逐次加倍 (%v-%v)，默认使用配置项 retry_wait 或 1s", add.MinRetryWait, add.MaxRetryWait))
+	addCmd.Flags().BoolVarP(&addQuiet, "quiet", "q", false, "不输出进度和结果，只在出错时向 stderr 输出")
+	addCmd.Flags().BoolVar(&addJSON, "json", false, "以 JSON 格式输出结果：每个添加的技能一行，失败时向 stderr 输出 JSON 错误")
+	addCmd.Flags().StringVar(&addTagPattern, "tag-pattern", "", "从标签添加时，update 跟随匹配该模式的最新发布标签 (如 'v*')")
+	addCmd.Flags().StringVar(&addPolicy, "policy", "", policyFlagUsage)
 }
 
 var addCmd = &cobra.Command{
-	Use:   "add <url>",
-	Short: "从 GitHub 的 skills 仓库下载并添加 skills",
+	Use:   "add <url|path>",
+	Short: "从 GitHub 的 skills 仓库或本地目录添加 skills",
+	Long: `从 GitHub 的 skills 仓库下载并添加 skills，或从本地目录复制 skills。
+
+示例:
+  gskills add https://github.com/owner/repo/tree/main/skills/prompt-engineer
+  gskills add ./path/to/skill
+  gskills add file:///home/user/skills/my-skill
+  gskills add https://github.com/owner/repo/releases/download/v1.0.0/my-skill.zip
+  gskills add https://github.com/owner/repo
+  gskills add https://github.com/owner/repo --all
+  gskills add https://github.com/owner/repo --resume
+
+  gskills add ./path/to/skill --link
+  gskills add ./path/to/skill --link=/home/user/myproject
+  gskills add ./path/to/skill --overwrite
+  gskills add ./path/to/skill --post-install 'npm install --prefix helper'
+  gskills add https://github.com/owner/repo/tree/main/skills/prompt-engineer --force
+  gskills add ./path/to/skill --store ~/work/skills
+  gskills add https://github.com/new-owner/repo/tree/main/skills/prompt-engineer-v2 --replace prompt-engineer
+  gskills add https://github.com/owner/repo/tree/main/skills/big-skill --concurrency 8
+  gskills add https://github.com/owner/repo/tree/v1.0.0/skills/prompt-engineer --tag-pattern 'v*'
+  gskills add https://github.com/owner/repo/tree/v1.0.0/skills/prompt-engineer --checksum sha256:<hex>
+
+本地目录中必须包含 SKILL.md (也可以是 SKILL.yaml 或 SKILL.json)。
+也可以传入 release 附件 (.zip、.tar.gz、.tgz) 地址：压缩包解压后根目录或唯一的顶层目录中必须包含 SKILL.md，
+技能名为去掉扩展名的文件名，版本记录为 release 标签，update 不会更新它。
+使用 --link 可在添加完成后立即将技能链接到项目。
+使用 --post-install <命令> 可在添加完成后于技能的存储目录中运行命令 (如 npm install)；未指定时，
+SKILL.md 头部的 postInstall 字段声明的命令会在终端中确认后运行。命令失败时已添加的技能会保留。
+传入仓库根地址时会列出仓库中的技能供选择，使用 --all 可全部安装。
+GitHub 目录中没有 SKILL.md 时，会查找其直接子目录中的技能，按同样方式选择安装。
+批量安装中途失败时，已安装的技能会保留；使用相同地址加 --resume 只安装剩余的技能。
+技能已存在时，终端中会询问是否覆盖；--overwrite 直接覆盖，--no-overwrite 直接失败。
+非交互环境（如管道、CI）下未指定两者时按 --no-overwrite 处理。
+已安装同一 GitHub 地址的相同提交时不会重新下载，使用 --force 强制重新下载。
+使用 --replace <旧技能> 可在更换来源时替换旧技能：新技能添加成功后，旧技能的项目链接保留原路径并指向新技能，旧技能随后被删除。
+使用 --store 可将技能存放到其他目录，技能仍登记在注册表中，link、update、remove 照常可用；
+未指定时使用配置项 skills_dir (如已设置)。
+GitHub 技能根目录下的隐藏文件和目录 (如 .github、.gitignore) 默认不下载，使用 --include-hidden 保留。
+使用 --checksum 校验下载内容的聚合校验和，不匹配时删除下载内容并失败；校验通过的校验和记录在注册表中。`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			return errors.New("用法:gskills add <github_url>")
+			return errors.New("用法:gskills add <github_url|local_path>")
 		}
 		return nil
 	},
-	RunE: func(cmd *cobra.Command, args []string) error {
-		url := args[0]
-		if err := executeAdd(url); err != nil {
+	RunE: withJSONErrors(&addJSON, func(cmd *cobra.Command, args []string) error {
+		opts := addOptions{
+			confirmOverwrite: overwriteConfirmation(addOverwrite, addNoOverwrite),
+			tagPattern:       addTagPattern,
+			skipSkillCheck:   addSkipCheck,
+			allowEmpty:       addAllowEmpty,
+			quiet:            addQuiet,
+			json:             addJSON,
+			force:            addForce,
+			includeHidden:    addHidden,
+			postInstall:      addPostInstall,
+		}
+		if cmd.Flags().Changed("concurrency") {
+			if addConcurrency < 1 {
+				return &usageError{err: fmt.Errorf("--concurrency 必须至少为 1: %d", addConcurrency)}
+			}
+			opts.concurrency = addConcurrency
+		}
+		opts.maxRetries, opts.retryWait = configuredRetries()
+		opts.retriesSet = opts.maxRetries >= 0
+		if cmd.Flags().Changed("max-retries") {
+			if addMaxRetries < 0 || checkRetries(addMaxRetries, 0) != nil {
+				return &usageError{err: fmt.Errorf("--max-retries 必须在 0 到 %d 之间: %d", add.MaxRetries, addMaxRetries)}
+			}
+			opts.maxRetries, opts.retriesSet = addMaxRetries, true
+		}
+		if cmd.Flags().Changed("retry-wait") {
+			if err := checkRetries(-1, addRetryWait); err != nil || addRetryWait <= 0 {
+				return &usageError{err: fmt.Errorf("--retry-wait 必须在 %v 到 %v 之间: %v", add.MinRetryWait, add.MaxRetryWait, addRetryWait)}
+			}
+			opts.retryWait = addRetryWait
+		}
+		if addPolicy != "" {
+			policy, err := add.ParseUpdatePolicy(addPolicy)
+			if err != nil {
+				return &usageError{err: err}
+			}
+			opts.updatePolicy = policy
+		}
+		if addChecksum != "" {
+			checksum, err := add.NormalizeChecksum(addChecksum)
+			if err != nil {
+				return &usageError{err: err}
+			}
+			opts.checksum = checksum
+		}
+		if addStore != "" {
+			storeDir, err := filepath.Abs(addStore)
+			if err != nil {
+				return fmt.Errorf("invalid --store directory: %w", err)
+			}
+			opts.storeDir = storeDir
+		} else if skillsDir := configuredSkillsDir(); skillsDir != "" {
+			opts.storeDir = skillsDir
+		}
+
+		batchPath, err := batchStatePath()
+		if err != nil {
+			return err
+		}
+
+		var batch *add.Batch
+		var sources []string
+		if addResume {
+			batch, err = add.LoadBatch(batchPath, sourceurl.Canonicalize(args[0]))
+			if err != nil {
+				return err
+			}
+			if batch == nil {
+				return &usageError{err: fmt.Errorf("没有可继续的批量安装: %s", args[0])}
+			}
+			sources = batch.Remaining()
+			fmt.Fprintf(opts.stdout(), "Resuming batch: %d of %d skills left\n", len(sources), len(batch.Skills))
+		} else {
+			sources, err = resolveAddSources(cmd.Context(), opts.stdout(), args[0], addAll)
+			if err != nil {
+				return fmt.Errorf("failed to add skill: %w", err)
+			}
+			if len(sources) > 1 {
+				batch = add.NewBatch(sourceurl.Canonicalize(args[0]), sources)
+			}
+		}
+		if err := checkSingleSourceFlags(opts, sources); err != nil {
+			return err
+		}
+		if addReplace != "" {
+			if _, err := registry.FindSkillByName(addReplace); err != nil {
+				return &usageError{err: fmt.Errorf("--replace: %w", err)}
+			}
+			// Caught before downloading: the add would overwrite the skill
+			// that was meant to be replaced.
+			if len(sources) == 1 {
+				if newName, err := skillNameFromSource(sources[0]); err == nil && newName == addReplace {
+					return &usageError{err: fmt.Errorf("--replace: cannot replace skill '%s' with itself", addReplace)}
+				}
+			}
+		}
+
+		return addSources(cmd.Context(), args[0], sources, batch, batchPath, opts)
+	}),
+}
+
+// addSources adds sources, the skills resolved from rawArg, in order. A
+// batch records the progress of several sources so that a failed add can
+// be resumed. A single GitHub source without SKILL.md is looked up for
+// skills in its immediate subdirectories, which are then added as a batch.
+func addSources(ctx context.Context, rawArg string, sources []string, batch *add.Batch, batchPath string, opts addOptions) error {
+	if batch != nil {
+		if err := pingGitHub(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, source := range sources {
+		if batch != nil {
+			if err := batch.Save(batchPath); err != nil {
+				return err
+			}
+		}
+		if err := executeAdd(ctx, source, opts); err != nil {
+			if errors.Is(err, errAddCancelled) {
+				if batch != nil {
+					batch.MarkDone(source)
+				}
+				continue
+			}
+			if batch != nil {
+				return fmt.Errorf("failed to add skill: %w\n%d of %d skills left; run 'gskills add %s --resume' to continue",
+					err, len(batch.Remaining()), len(batch.Skills), rawArg)
+			}
+			if len(sources) == 1 && errors.Is(err, add.ErrSkillManifestMissing) {
+				nested, nestedErr := resolveNestedSkills(ctx, opts.stdout(), source, addAll)
+				if nestedErr != nil {
+					return fmt.Errorf("failed to add skill: %w", nestedErr)
+				}
+				if len(nested) > 0 {
+					if err := checkSingleSourceFlags(opts, nested); err != nil {
+						return err
+					}
+					if len(nested) == 1 {
+						return addSources(ctx, rawArg, nested, nil, batchPath, opts)
+					}
+					return addSources(ctx, rawArg, nested, add.NewBatch(sourceurl.Canonicalize(rawArg), nested), batchPath, opts)
+				}
+			}
 			return fmt.Errorf("failed to add skill: %w", err)
 		}
+		if batch != nil {
+			batch.MarkDone(source)
+		}
+		if err := executePostInstall(ctx, source, opts); err != nil {
+			if batch != nil {
+				batch.Save(batchPath)
+			}
+			return err
+		}
+		if addReplace != "" {
+			if err := executeAddReplace(opts.stdout(), source, addReplace); err != nil {
+				return err
+			}
+		}
+		if addLinkProject != "" {
+			if err := executeAddLink(ctx, opts.stdout(), source, addLinkProject); err != nil {
+				if batch != nil {
+					batch.Save(batchPath)
+				}
+				return err
+			}
+		}
+	}
+	if batch != nil {
+		return add.ClearBatch(batchPath)
+	}
+	return nil
+}
+
+// checkSingleSourceFlags rejects the flags that only apply to one skill
+// when sources holds several.
+func checkSingleSourceFlags(opts addOptions, sources []string) error {
+	if len(sources) <= 1 {
 		return nil
-	},
+	}
+	if opts.checksum != "" {
+		return &usageError{err: errors.New("--checksum 只能用于添加单个技能")}
+	}
+	if addReplace != "" {
+		return &usageError{err: errors.New("--replace 只能用于添加单个技能")}
+	}
+	return nil
 }
 
-func executeAdd(rawURL string) error {
-	token := viper.GetString("github_token")
-	client := add.NewClient(token)
+// batchStatePath returns where the progress of a batch add is recorded.
+func batchStatePath() (string, error) {
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve data directory: %w", err)
+	}
+	return paths.BatchPath(dataDir), nil
+}
 
-	err := client.Download(rawURL)
+// executeAddLink links a freshly added skill into projectPath. The skill has
+// already been added at this point, so a link failure is reported without
+// rolling the add back.
+func executeAddLink(ctx context.Context, w io.Writer, rawURL, projectPath string) error {
+	skillName, err := skillNameFromSource(rawURL)
 	if err != nil {
-		return err
+		return fmt.Errorf("skill added but failed to determine its name for linking: %w", err)
+	}
+
+	if err := executeLinkTo(ctx, w, skillName, projectPath); err != nil {
+		return fmt.Errorf("skill '%s' added but failed to link: %w", skillName, err)
 	}
 	return nil
 }
+
+// executePostInstall runs the post-install hook of a freshly added skill in
+// its store directory: the --post-install command, or else the postInstall
+// command of its SKILL.md once the user confirms it, since it comes from the
+// skill's author. A failing hook leaves the skill installed so that the
+// store directory it ran in can be inspected and the hook rerun by hand.
+func executePostInstall(ctx context.Context, rawURL string, opts addOptions) error {
+	skillName, err := skillNameFromSource(rawURL)
+	if err != nil {
+		return fmt.Errorf("skill added but failed to determine its name for post-install: %w", err)
+	}
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("skill '%s' added but failed to find it for post-install: %w", skillName, err)
+	}
+
+	command := opts.postInstall
+	if command == "" {
+		command, err = add.ReadPostInstall(skill.StorePath)
+		if err != nil {
+			return fmt.Errorf("skill '%s' added but failed to read its post-install command: %w", skillName, err)
+		}
+		if command == "" {
+			return nil
+		}
+		if !prompt.IsInteractive() {
+			fmt.Fprintf(opts.stderr(), "Skipped the post-install command of '%s' (not a terminal): %s\n", skillName, command)
+			fmt.Fprintln(opts.stderr(), "Pass it with --post-install to run it.")
+			return nil
+		}
+		confirmed, err := prompt.Confirm(fmt.Sprintf("运行技能 '%s' 的 post-install 命令: %s ?", skillName, command))
+		if err != nil {
+			return fmt.Errorf("读取输入失败: %w", err)
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	fmt.Fprintf(opts.stdout(), "Running post-install in %s: %s\n", skill.StorePath, command)
+	if err := add.RunPostInstall(ctx, skill.StorePath, command, opts.stdout(), opts.stderr()); err != nil {
+		return fmt.Errorf("skill '%s' added but %w", skillName, err)
+	}
+	return nil
+}
+
+// pingGitHub checks once that GitHub is usable before a batch add, so that
+// an offline machine or a rejected token fails with one message rather than
+// one per skill. Batches always come from a GitHub repository.
+func pingGitHub(ctx context.Context) error {
+	client := newManager(configString("github_token")).Client()
+	client.SetTimeout(rootTimeout)
+	if _, err := client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to add skills: %w", err)
+	}
+	return nil
+}
+
+// executeAddReplace moves the project links of oldName to the skill just
+// added from rawURL and removes oldName. If the replacement fails, the new
+// skill stays installed next to oldName, which keeps the links that were
+// not moved, so no project is left without the skill.
+func executeAddReplace(w io.Writer, rawURL, oldName string) error {
+	newName, err := skillNameFromSource(rawURL)
+	if err != nil {
+		return fmt.Errorf("skill added but failed to determine its name for --replace: %w", err)
+	}
+
+	moved, err := newManager(configString("github_token")).Replace(oldName, newName)
+	if err != nil {
+		return fmt.Errorf("skill '%s' added but failed to replace '%s': %w", newName, oldName, err)
+	}
+	fmt.Fprintf(w, "Replaced skill '%s' with '%s' (%d link(s) moved)\n", oldName, newName, moved)
+	return nil
+}
+
+// newManager creates the skills manager used by the CLI commands, adding
+// skills from the server set with github_api_url. It is a variable so tests
+// can point the manager at a mock server.
+var newManager = func(token string) *add.Manager {
+	manager := add.NewManager("", token, nil)
+	manager.Client().SetServer(configuredServer())
+	return manager
+}
+
+// githubServer returns the GitHub server set with github_api_url, github.com
+// by default.
+func githubServer() (add.Server, error) {
+	server, err := add.NewServer(configString("github_api_url"))
+	if err != nil {
+		return add.Server{}, fmt.Errorf("配置项 github_api_url 无效: %w", err)
+	}
+	return server, nil
+}
+
+// configuredServer is githubServer for the client factories, which cannot
+// fail: PersistentPreRunE has already rejected an invalid github_api_url for
+// every command that builds a client.
+func configuredServer() add.Server {
+	server, err := githubServer()
+	if err != nil {
+		return add.GitHubDotCom
+	}
+	return server
+}
+
+// skillNameFromSource returns the name under which the skill of source is
+// stored, parsing GitHub URLs against the configured server.
+func skillNameFromSource(source string) (string, error) {
+	return configuredServer().SkillNameFromSource(source)
+}
+
+// overwriteConfirmation returns the confirmation asked before an installed
+// skill is replaced: --overwrite replaces it, --no-overwrite fails, and
+// otherwise the user is asked when stdin is a terminal. Without a terminal
+// nobody can answer, so the add fails instead of waiting for input.
+func overwriteConfirmation(overwrite, noOverwrite bool) func() (bool, error) {
+	switch {
+	case overwrite:
+		return nil
+	case noOverwrite:
+		return add.RefuseOverwrite
+	case prompt.IsInteractive():
+		return add.PromptOverwrite
+	default:
+		return func() (bool, error) {
+			return false, fmt.Errorf("%w; use --overwrite to replace it", add.ErrSkillExists)
+		}
+	}
+}
+
+// addOptions holds the flags of an add that apply to every added source.
+type addOptions struct {
+	// confirmOverwrite is asked before an installed skill is replaced; nil
+	// replaces it without asking.
+	confirmOverwrite func() (bool, error)
+	// storeDir, when set, is the absolute directory the skill is stored in
+	// instead of the default skills store.
+	storeDir string
+	// tagPattern makes a skill added from a tag follow the newest release
+	// tag matching it.
+	tagPattern string
+	// updatePolicy is the update policy recorded for the skill; empty keeps
+	// the policy of a skill already installed under the same name.
+	updatePolicy string
+	// skipSkillCheck downloads GitHub directories without SKILL.md.
+	skipSkillCheck bool
+	// allowEmpty adds a skill directory that has no files.
+	allowEmpty bool
+	// quiet suppresses progress and result output; warnings go to stderr.
+	quiet bool
+	// json replaces progress and result output with one JSON object per
+	// added skill; warnings go to stderr.
+	json bool
+	// force downloads a skill again even when it is already up to date.
+	force bool
+	// includeHidden keeps the top-level dotfiles of GitHub skills.
+	includeHidden bool
+	// checksum is the normalized aggregate checksum the skill must match.
+	checksum string
+	// concurrency overrides the number of parallel requests of the download;
+	// zero keeps the default.
+	concurrency int
+	// maxRetries, when retriesSet, overrides how often failed requests are
+	// retried.
+	maxRetries int
+	retriesSet bool
+	// retryWait, when positive, overrides the wait before the first retry.
+	retryWait time.Duration
+	// postInstall is the command run in the store directory after the add;
+	// empty falls back to the postInstall field of the skill's SKILL.md.
+	postInstall string
+}
+
+// stdout returns where the add prints progress and results.
+func (o addOptions) stdout() io.Writer {
+	if o.quiet || o.json {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// stderr returns where the add prints warnings: stdout unless quiet or json.
+func (o addOptions) stderr() io.Writer {
+	if o.quiet || o.json {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// errAddCancelled is returned by executeAdd when the user declined to
+// overwrite the installed skill. Nothing was added, so the steps that act on
+// the added skill (post-install, --replace and --link) must not run.
+var errAddCancelled = errors.New("add cancelled")
+
+// executeAdd installs rawURL with opts.
+func executeAdd(ctx context.Context, rawURL string, opts addOptions) error {
+	manager := newManager(configString("github_token"))
+	_, downloadTimeout := operationTimeouts()
+	manager.Client().SetDownloadTimeout(downloadTimeout)
+	manager.Client().SetTimeout(rootTimeout)
+	manager.Client().SetDownloadLimits(downloadLimits())
+	manager.Client().SetConfirmOverwrite(opts.confirmOverwrite)
+	manager.Client().SetStoreDir(opts.storeDir)
+	manager.Client().SetTagPattern(opts.tagPattern)
+	manager.Client().SetUpdatePolicy(opts.updatePolicy)
+	manager.Client().SetSkipSkillCheck(opts.skipSkillCheck)
+	manager.Client().SetAllowEmpty(opts.allowEmpty)
+	manager.Client().SetOnProgress(func(event add.ProgressEvent) {
+		switch event.Phase {
+		case add.PhaseListing:
+			fmt.Fprintln(opts.stdout(), "Listing files...")
+		case add.PhaseDownloading:
+			fmt.Fprintf(opts.stdout(), "Downloading %d files (%d bytes)...\n", event.Files, event.Bytes)
+		}
+	})
+	manager.Client().SetForce(opts.force)
+	manager.Client().SetIncludeHidden(opts.includeHidden)
+	manager.Client().SetChecksum(opts.checksum)
+	manager.Client().SetConcurrency(opts.concurrency)
+	maxRetries := -1
+	if opts.retriesSet {
+		maxRetries = opts.maxRetries
+	}
+	manager.Client().SetRetries(maxRetries, opts.retryWait)
+
+	if repoInfo, ok := manager.Client().Server().ParseRepoRootURL(rawURL); ok {
+		return repoRootError(ctx, manager.Client(), rawURL, repoInfo)
+	}
+
+	title := "Download complete!"
+	if add.IsLocalSource(rawURL) {
+		title = "Copy complete!"
+		fmt.Fprintf(opts.stdout(), "Copying skill from %s...\n", rawURL)
+	} else {
+		fmt.Fprintf(opts.stdout(), "Downloading skill from %s...\n", rawURL)
+	}
+
+	stats, err := manager.Add(ctx, rawURL)
+	if errors.Is(err, add.ErrEmptySkill) {
+		return fmt.Errorf("%w (use --allow-empty to add it anyway)", err)
+	}
+	if err != nil {
+		var downloadErr *add.DownloadError
+		if stats == nil || !errors.As(err, &downloadErr) || downloadErr.Type != add.ErrorTypeRegistry {
+			return err
+		}
+		fmt.Fprintf(opts.stderr(), "Warning: %v\n", err)
+		fmt.Fprintln(opts.stderr(), "The skill was added successfully, but may not appear in 'gskills list'.")
+	}
+	if stats != nil && stats.FewFiles && !stats.UpToDate {
+		fmt.Fprintf(opts.stderr(), "Warning: the skill has only %d file(s); check that the URL points at the skill directory.\n", stats.FilesDownloaded)
+	}
+	if opts.json {
+		if err := printAddJSON(os.Stdout, rawURL, stats); err != nil {
+			return err
+		}
+	} else {
+		printAddStats(opts.stdout(), title, stats)
+	}
+	if stats == nil {
+		return errAddCancelled
+	}
+	return nil
+}
+
+// addJSONResult is the --json output of one added skill.
+type addJSONResult struct {
+	Skill *types.SkillMetadata `json:"skill"`
+	Stats *addJSONStats        `json:"stats,omitempty"`
+	// Cancelled is set when the user declined to overwrite the skill.
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+type addJSONStats struct {
+	Files    int    `json:"files"`
+	Dirs     int    `json:"dirs"`
+	Bytes    int64  `json:"bytes"`
+	Location string `json:"location"`
+	UpToDate bool   `json:"up_to_date,omitempty"`
+	Ignored  int    `json:"ignored,omitempty"`
+	Hidden   int    `json:"hidden,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	FewFiles bool   `json:"few_files,omitempty"`
+}
+
+// printAddJSON prints the result of adding rawURL as a single-line JSON
+// object, so that a batch add prints one line per skill. A nil stats means
+// the user declined to overwrite an existing skill.
+func printAddJSON(w io.Writer, rawURL string, stats *add.DownloadStats) error {
+	result := addJSONResult{Cancelled: stats == nil}
+	if stats != nil {
+		result.Stats = &addJSONStats{
+			Files:    stats.FilesDownloaded,
+			Dirs:     stats.DirsCreated,
+			Bytes:    stats.BytesDownloaded,
+			Location: stats.StorePath,
+			UpToDate: stats.UpToDate,
+			Ignored:  stats.Ignored,
+			Hidden:   stats.Hidden,
+			Checksum: stats.Checksum,
+			FewFiles: stats.FewFiles,
+		}
+		if name, err := skillNameFromSource(rawURL); err == nil {
+			if skill, err := registry.FindSkillByName(name); err == nil {
+				result.Skill = skill
+			}
+		}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// withJSONErrors wraps run so that, when *asJSON is set, a failure is
+// reported on stderr as JSON by reportJSONError instead of cobra's plain
+// "Error:" line.
+func withJSONErrors(asJSON *bool, run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceErrors = *asJSON
+		err := run(cmd, args)
+		if err != nil && *asJSON {
+			return reportJSONError(cmd.ErrOrStderr(), err)
+		}
+		return err
+	}
+}
+
+// reportJSONError prints err to w as a JSON object with its message and exit
+// code, and returns it marked as reported so Execute does not print it again.
+func reportJSONError(w io.Writer, err error) error {
+	data, encodeErr := json.Marshal(struct {
+		Error    string `json:"error"`
+		ExitCode int    `json:"exit_code"`
+	}{Error: err.Error(), ExitCode: exitCode(err)})
+	if encodeErr != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(data))
+	return &reportedError{err: err}
+}
+
+// printAddStats prints the result of an add. A nil stats means the user
+// declined to overwrite an existing skill.
+func printAddStats(w io.Writer, title string, stats *add.DownloadStats) {
+	if stats == nil {
+		fmt.Fprintln(w, "Add cancelled.")
+		return
+	}
+	if stats.UpToDate {
+		fmt.Fprintf(w, "\nSkill already up to date, nothing downloaded (use --force to download it again).\n")
+		fmt.Fprintf(w, "  Location: %s\n", stats.StorePath)
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s\n", title)
+	fmt.Fprintf(w, "  Files: %d\n", stats.FilesDownloaded)
+	fmt.Fprintf(w, "  Directories created: %d\n", stats.DirsCreated)
+	fmt.Fprintf(w, "  Total size: %d bytes\n", stats.BytesDownloaded)
+	fmt.Fprintf(w, "  Location: %s\n", stats.StorePath)
+	if stats.Hidden > 0 {
+		fmt.Fprintf(w, "  Hidden: %d skipped (use --include-hidden to keep them)\n", stats.Hidden)
+	}
+	if stats.Ignored > 0 {
+		fmt.Fprintf(w, "  Ignored: %d (%s)\n", stats.Ignored, add.IgnoreFileName)
+	}
+	if stats.Checksum != "" {
+		fmt.Fprintf(w, "  Checksum: %s (verified)\n", stats.Checksum)
+	}
+}
@@ -0,0 +1,997 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/prompt"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/spf13/viper"
+)
+
+func TestAddCmd_Link(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "linked-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Linked"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	projectDir := t.TempDir()
+
+	defer func() { addLinkProject = "" }()
+	rootCmd.SetArgs([]string{"add", srcDir, "--link=" + projectDir})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("add --link error = %v", err)
+	}
+
+	skill, err := registry.FindSkillByName("linked-skill")
+	if err != nil {
+		t.Fatalf("skill not found in registry: %v", err)
+	}
+
+	absProject, _ := filepath.Abs(projectDir)
+	linkInfo, ok := skill.LinkedProjects[absProject]
+	if !ok {
+		t.Fatalf("skill not linked to project %s, links: %v", absProject, skill.LinkedProjects)
+	}
+
+	info, err := os.Lstat(linkInfo.SymlinkPath)
+	if err != nil {
+		t.Fatalf("symlink not created: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("%s is not a symlink", linkInfo.SymlinkPath)
+	}
+}
+
+func TestAddCmd_StoreLinksFromCustomStore(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "stored-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Stored"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	storeDir := filepath.Join(t.TempDir(), "custom-store")
+	projectDir := t.TempDir()
+
+	defer func() { addLinkProject, addStore = "", "" }()
+	rootCmd.SetArgs([]string{"add", srcDir, "--store", storeDir, "--link=" + projectDir})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("add --store --link error = %v", err)
+	}
+
+	wantStorePath := filepath.Join(storeDir, "stored-skill")
+	if _, err := os.Stat(filepath.Join(wantStorePath, "SKILL.md")); err != nil {
+		t.Fatalf("skill not stored in custom store: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(homeDir, ".gskills", "skills", "stored-skill")); !os.IsNotExist(err) {
+		t.Error("skill should not be stored in the default store")
+	}
+
+	skill, err := registry.FindSkillByName("stored-skill")
+	if err != nil {
+		t.Fatalf("skill not found in registry: %v", err)
+	}
+	if skill.StorePath != wantStorePath {
+		t.Errorf("StorePath = %s, want %s", skill.StorePath, wantStorePath)
+	}
+
+	absProject, _ := filepath.Abs(projectDir)
+	linkInfo, ok := skill.LinkedProjects[absProject]
+	if !ok {
+		t.Fatalf("skill not linked to project %s", absProject)
+	}
+	target, err := os.Readlink(linkInfo.SymlinkPath)
+	if err != nil {
+		t.Fatalf("symlink not created: %v", err)
+	}
+	if target != wantStorePath {
+		t.Errorf("symlink target = %s, want %s", target, wantStorePath)
+	}
+}
+
+func TestAddCmd_QuietPrintsNothing(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "quiet-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Quiet"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	projectDir := t.TempDir()
+
+	defer func() { addLinkProject, addQuiet = "", false }()
+	rootCmd.SetArgs([]string{"add", srcDir, "--quiet", "--link=" + projectDir})
+	defer rootCmd.SetArgs(nil)
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := rootCmd.Execute()
+
+	w.Close()
+	os.Stdout = oldStdout
+	var out bytes.Buffer
+	io.Copy(&out, r)
+	r.Close()
+
+	if err != nil {
+		t.Fatalf("add --quiet error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("add --quiet wrote to stdout:\n%s", out.String())
+	}
+	skill, err := registry.FindSkillByName("quiet-skill")
+	if err != nil {
+		t.Fatalf("skill not added: %v", err)
+	}
+	if len(skill.LinkedProjects) != 1 {
+		t.Errorf("skill not linked, links: %v", skill.LinkedProjects)
+	}
+}
+
+func TestAddCmd_LinkFailureKeepsSkill(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "kept-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Kept"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	existing := filepath.Join(projectDir, ".opencode", "skills", "kept-skill")
+	if err := os.MkdirAll(existing, 0755); err != nil {
+		t.Fatalf("failed to create conflicting target: %v", err)
+	}
+
+	if err := executeAdd(context.Background(), srcDir, addOptions{}); err != nil {
+		t.Fatalf("executeAdd() error = %v", err)
+	}
+	if err := executeAddLink(context.Background(), io.Discard, srcDir, projectDir); err == nil {
+		t.Fatal("executeAddLink() should fail when target already exists")
+	}
+
+	if _, err := registry.FindSkillByName("kept-skill"); err != nil {
+		t.Errorf("skill should remain registered after link failure: %v", err)
+	}
+}
+
+func TestExecuteAdd_OverwriteFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		overwrite   bool
+		noOverwrite bool
+		interactive bool
+		wantErr     string
+		wantContent string
+	}{
+		{name: "--overwrite replaces the skill", overwrite: true, wantContent: "# New"},
+		{name: "--no-overwrite fails", noOverwrite: true, interactive: true, wantErr: "already exists", wantContent: "# Old"},
+		{name: "non-interactive default fails", wantErr: "--overwrite", wantContent: "# Old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			originalHome := os.Getenv("HOME")
+			os.Setenv("HOME", homeDir)
+			defer os.Setenv("HOME", originalHome)
+			withPromptInput(t, "", tt.interactive)
+
+			srcDir := filepath.Join(t.TempDir(), "dup-skill")
+			if err := os.MkdirAll(srcDir, 0755); err != nil {
+				t.Fatalf("failed to create source directory: %v", err)
+			}
+			skillMD := filepath.Join(srcDir, "SKILL.md")
+			if err := os.WriteFile(skillMD, []byte("# Old"), 0644); err != nil {
+				t.Fatalf("failed to write SKILL.md: %v", err)
+			}
+			if err := executeAdd(context.Background(), srcDir, addOptions{}); err != nil {
+				t.Fatalf("first executeAdd() error = %v", err)
+			}
+			if err := os.WriteFile(skillMD, []byte("# New"), 0644); err != nil {
+				t.Fatalf("failed to write SKILL.md: %v", err)
+			}
+
+			err := executeAdd(context.Background(), srcDir, addOptions{confirmOverwrite: overwriteConfirmation(tt.overwrite, tt.noOverwrite)})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeAdd() error = %v", err)
+				}
+			} else {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeAdd() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, &add.DownloadError{Type: add.ErrorTypeExists}) {
+					t.Errorf("executeAdd() error type = %v, want ErrorTypeExists", err)
+				}
+			}
+
+			content, err := os.ReadFile(filepath.Join(homeDir, ".gskills", "skills", "dup-skill", "SKILL.md"))
+			if err != nil {
+				t.Fatalf("failed to read installed SKILL.md: %v", err)
+			}
+			if string(content) != tt.wantContent {
+				t.Errorf("installed SKILL.md = %q, want %q", content, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestAddCmd_OverwriteFlagsAreExclusive(t *testing.T) {
+	defer func() {
+		addOverwrite, addNoOverwrite = false, false
+		addCmd.Flags().Lookup("overwrite").Changed = false
+		addCmd.Flags().Lookup("no-overwrite").Changed = false
+	}()
+	rootCmd.SetArgs([]string{"add", t.TempDir(), "--overwrite", "--no-overwrite"})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "none of the others can be") {
+		t.Errorf("add --overwrite --no-overwrite error = %v, want a mutually exclusive flags error", err)
+	}
+}
+
+// useSkillsRepoServer points newManager at a mock GitHub API serving
+// owner/repo, whose default branch is main and whose skills/ directory holds
+// the skills alpha and beta.
+func useSkillsRepoServer(t *testing.T) {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo":
+			json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+		case "/repos/owner/repo/contents/":
+			json.NewEncoder(w).Encode([]types.GitHubContent{{Type: "dir", Name: "skills"}})
+		case "/repos/owner/repo/contents/skills":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "dir", Name: "alpha"},
+				{Type: "dir", Name: "beta"},
+			})
+		case "/repos/owner/repo/contents/skills/alpha", "/repos/owner/repo/contents/skills/beta":
+			json.NewEncoder(w).Encode([]types.GitHubContent{{Type: "file", Name: "SKILL.md"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	t.Cleanup(func() { newManager = oldNewManager })
+}
+
+func TestExecuteAdd_RepoRootURLListsSkills(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	useSkillsRepoServer(t)
+
+	err := executeAdd(context.Background(), "https://github.com/owner/repo", addOptions{})
+	if err == nil {
+		t.Fatal("executeAdd() should fail for a repository root URL")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"is a repository root, not a skill directory",
+		"gskills add https://github.com/owner/repo/tree/main/skills/alpha",
+		"gskills add https://github.com/owner/repo/tree/main/skills/beta",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "path must be specified") {
+		t.Errorf("error %q still reports the raw parser error", msg)
+	}
+}
+
+// withPromptInput feeds input to interactive prompts for the rest of the test.
+func withPromptInput(t *testing.T, input string, interactive bool) {
+	t.Helper()
+	oldInput, oldOutput, oldInteractive := promptInput, promptOutput, prompt.IsInteractive
+	promptInput = strings.NewReader(input)
+	promptOutput = &bytes.Buffer{}
+	prompt.IsInteractive = func() bool { return interactive }
+	t.Cleanup(func() {
+		promptInput, promptOutput, prompt.IsInteractive = oldInput, oldOutput, oldInteractive
+	})
+}
+
+func TestPickSkills(t *testing.T) {
+	candidates := []string{"skills/alpha", "skills/beta", "skills/gamma"}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single", input: "2\n", want: []string{"skills/beta"}},
+		{name: "comma separated", input: "3,1\n", want: []string{"skills/gamma", "skills/alpha"}},
+		{name: "space separated with duplicate", input: "1 1 2\n", want: []string{"skills/alpha", "skills/beta"}},
+		{name: "all", input: "all\n", want: candidates},
+		{name: "no trailing newline", input: "1", want: []string{"skills/alpha"}},
+		{name: "empty", input: "\n", wantErr: true},
+		{name: "out of range", input: "4\n", wantErr: true},
+		{name: "not a number", input: "beta\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPromptInput(t, tt.input, true)
+
+			got, err := pickSkills(candidates)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickSkills() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pickSkills() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAddSources_RepoRoot(t *testing.T) {
+	alphaURL := "https://github.com/owner/repo/tree/main/skills/alpha"
+	betaURL := "https://github.com/owner/repo/tree/main/skills/beta"
+
+	tests := []struct {
+		name        string
+		all         bool
+		interactive bool
+		input       string
+		want        []string
+		wantErr     string
+	}{
+		{name: "all flag", all: true, want: []string{alphaURL, betaURL}},
+		{name: "interactive pick", interactive: true, input: "2\n", want: []string{betaURL}},
+		{name: "non-interactive without --all", wantErr: "Use --all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSkillsRepoServer(t)
+			withPromptInput(t, tt.input, tt.interactive)
+
+			got, err := resolveAddSources(context.Background(), io.Discard, "https://github.com/owner/repo", tt.all)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveAddSources() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveAddSources() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveAddSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNestedSkills(t *testing.T) {
+	parentURL := "https://github.com/owner/repo/tree/main/skills"
+	alphaURL := "https://github.com/owner/repo/tree/main/skills/alpha"
+	betaURL := "https://github.com/owner/repo/tree/main/skills/beta"
+
+	tests := []struct {
+		name        string
+		url         string
+		all         bool
+		interactive bool
+		input       string
+		want        []string
+		wantErr     string
+	}{
+		{name: "all flag", url: parentURL, all: true, want: []string{alphaURL, betaURL}},
+		{name: "interactive pick", url: parentURL, interactive: true, input: "1\n", want: []string{alphaURL}},
+		{name: "non-interactive without --all", url: parentURL, wantErr: "Use --all"},
+		{name: "no nested skills", url: "https://github.com/owner/repo/tree/main/missing", all: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSkillsRepoServer(t)
+			withPromptInput(t, tt.input, tt.interactive)
+
+			got, err := resolveNestedSkills(context.Background(), io.Discard, tt.url, tt.all)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveNestedSkills() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveNestedSkills() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveNestedSkills() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAddSources_PassesThroughSkillURL(t *testing.T) {
+	source := "https://github.com/owner/repo/tree/main/skills/alpha"
+	got, err := resolveAddSources(context.Background(), io.Discard, source, false)
+	if err != nil {
+		t.Fatalf("resolveAddSources() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{source}) {
+		t.Errorf("resolveAddSources() = %v, want [%s]", got, source)
+	}
+}
+
+func TestAddCmd_DefaultBranch(t *testing.T) {
+	testutil.TempHome(t)
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "trunk", "abc123")
+	gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo")
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(gh.URL())
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+
+	output, err := captureStdout(t, func() error {
+		return executeRoot(context.Background(), []string{"add", "https://github.com/owner/repo/skills/demo"})
+	})
+	if err != nil {
+		t.Fatalf("add without a branch error = %v", err)
+	}
+	if !strings.Contains(output, "using default branch 'trunk' of owner/repo") {
+		t.Errorf("output %q does not mention the default branch", output)
+	}
+
+	skill, err := registry.FindSkillByName("demo")
+	if err != nil {
+		t.Fatalf("skill not registered: %v", err)
+	}
+	if want := "https://github.com/owner/repo/tree/trunk/skills/demo"; skill.SourceURL != want {
+		t.Errorf("SourceURL = %q, want %q", skill.SourceURL, want)
+	}
+	if skill.Version != "trunk" {
+		t.Errorf("Version = %q, want trunk", skill.Version)
+	}
+}
+
+func TestAddCmd_RetryFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		config         map[string]string
+		wantCode       int
+		wantMaxRetries int
+		wantRetryWait  time.Duration
+	}{
+		{name: "defaults", wantMaxRetries: 4, wantRetryWait: time.Second},
+		{name: "flags", args: []string{"--max-retries", "8", "--retry-wait", "3s"}, wantMaxRetries: 8, wantRetryWait: 3 * time.Second},
+		{name: "config", config: map[string]string{"max_retries": "6", "retry_wait": "2s"}, wantMaxRetries: 6, wantRetryWait: 2 * time.Second},
+		{name: "flags override config", args: []string{"--max-retries", "0"}, config: map[string]string{"max_retries": "6"}, wantMaxRetries: 0, wantRetryWait: time.Second},
+		{name: "too many retries", args: []string{"--max-retries", "11"}, wantCode: ExitUsage},
+		{name: "negative retries", args: []string{"--max-retries", "-1"}, wantCode: ExitUsage},
+		{name: "wait too short", args: []string{"--retry-wait", "10ms"}, wantCode: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.TempHome(t)
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+			for key, value := range tt.config {
+				if err := executeConfigSet(key, value); err != nil {
+					t.Fatalf("executeConfigSet(%s) error = %v", key, err)
+				}
+			}
+
+			gh := testutil.NewFakeGitHub(t)
+			gh.SetBranch("owner", "repo", "main", "abc123")
+			gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo")
+
+			var manager *add.Manager
+			oldNewManager := newManager
+			newManager = func(token string) *add.Manager {
+				manager = add.NewManager("", token, nil)
+				manager.Client().SetBaseURL(gh.URL())
+				return manager
+			}
+			defer func() { newManager = oldNewManager }()
+			defer func() {
+				addMaxRetries, addRetryWait, addQuiet = 0, 0, false
+				addCmd.Flags().Lookup("max-retries").Changed = false
+				addCmd.Flags().Lookup("retry-wait").Changed = false
+			}()
+
+			args := append([]string{"add", "https://github.com/owner/repo/tree/main/skills/demo", "--quiet"}, tt.args...)
+			err := executeRoot(context.Background(), args)
+			if got := exitCode(err); got != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (error: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			maxRetries, retryWait := manager.Client().Retries()
+			if maxRetries != tt.wantMaxRetries || retryWait != tt.wantRetryWait {
+				t.Errorf("retries = %d, wait %v, want %d, wait %v", maxRetries, retryWait, tt.wantMaxRetries, tt.wantRetryWait)
+			}
+		})
+	}
+}
+
+func TestAddCmd_ConcurrencyFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		want     int
+		wantCode int
+	}{
+		{name: "override", value: "5", want: 5},
+		{name: "capped", value: "100", want: add.MaxConcurrency},
+		{name: "zero rejected", value: "0", wantCode: ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.TempHome(t)
+			gh := testutil.NewFakeGitHub(t)
+			gh.SetBranch("owner", "repo", "main", "abc123")
+			gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo")
+
+			var manager *add.Manager
+			oldNewManager := newManager
+			newManager = func(token string) *add.Manager {
+				manager = add.NewManager("", token, nil)
+				manager.Client().SetBaseURL(gh.URL())
+				return manager
+			}
+			defer func() { newManager = oldNewManager }()
+			defer func() {
+				addConcurrency, addQuiet = 0, false
+				addCmd.Flags().Lookup("concurrency").Changed = false
+			}()
+
+			err := executeRoot(context.Background(), []string{"add", "https://github.com/owner/repo/tree/main/skills/demo", "--quiet", "--concurrency", tt.value})
+			if tt.wantCode != 0 {
+				if got := exitCode(err); got != tt.wantCode {
+					t.Fatalf("exit code = %d, want %d (error: %v)", got, tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("add --concurrency %s error = %v", tt.value, err)
+			}
+			if got := manager.Client().Concurrency(); got != tt.want {
+				t.Errorf("effective concurrency = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddCmd_ResumeFailedBatch(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "main", "abc123")
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		gh.SetFile("owner", "repo", "skills/"+name+"/SKILL.md", "# "+name)
+	}
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(gh.URL())
+		manager.Client().SetConfirmOverwrite(nil)
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+	defer func() { addAll, addResume, addQuiet = false, false, false }()
+
+	installed := func() []string {
+		skills, err := registry.LoadRegistry()
+		if err != nil {
+			t.Fatalf("LoadRegistry() error = %v", err)
+		}
+		var names []string
+		for _, skill := range skills {
+			names = append(names, skill.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// The second skill of the batch fails to download.
+	gh.SetFailure("/raw/owner/repo/skills/beta/SKILL.md", http.StatusInternalServerError)
+	err := executeRoot(context.Background(), []string{"add", "https://github.com/owner/repo", "--all", "--quiet"})
+	if err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("batch add error = %v, want a failure suggesting --resume", err)
+	}
+	if got := installed(); !reflect.DeepEqual(got, []string{"alpha"}) {
+		t.Fatalf("installed after failure = %v, want [alpha]", got)
+	}
+
+	gh.SetFailure("/raw/owner/repo/skills/beta/SKILL.md", 0)
+	alphaRequests := gh.Requests("/raw/owner/repo/skills/alpha/SKILL.md")
+	addAll = false
+	if err := executeRoot(context.Background(), []string{"add", "https://github.com/owner/repo", "--resume", "--quiet"}); err != nil {
+		t.Fatalf("add --resume error = %v", err)
+	}
+	if got := installed(); !reflect.DeepEqual(got, []string{"alpha", "beta", "gamma"}) {
+		t.Errorf("installed after resume = %v, want [alpha beta gamma]", got)
+	}
+	if got := gh.Requests("/raw/owner/repo/skills/alpha/SKILL.md"); got != alphaRequests {
+		t.Errorf("resume downloaded the already installed skill again (%d requests, want %d)", got, alphaRequests)
+	}
+	if _, err := os.Stat(paths.BatchPath(dataDir)); !os.IsNotExist(err) {
+		t.Errorf("batch state should be removed once the batch completes: %v", err)
+	}
+
+	// Nothing is left to resume.
+	err = executeRoot(context.Background(), []string{"add", "https://github.com/owner/repo", "--resume", "--quiet"})
+	if got := exitCode(err); got != ExitUsage {
+		t.Errorf("exit code = %d, want %d (error: %v)", got, ExitUsage, err)
+	}
+}
+
+func TestAddCmd_PostInstall(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	defer func() { addPostInstall, addQuiet, addOverwrite = "", false, false }()
+
+	srcDir := filepath.Join(t.TempDir(), "hooked")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Hooked"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeRoot(context.Background(), []string{"add", srcDir, "--quiet", "--post-install", "pwd > hook.txt"}); err != nil {
+		t.Fatalf("add --post-install error = %v", err)
+	}
+
+	storePath, err := filepath.EvalSymlinks(filepath.Join(paths.SkillsDir(dataDir), "hooked"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(storePath, "hook.txt"))
+	if err != nil {
+		t.Fatalf("post-install hook did not run in the store directory: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != storePath {
+		t.Errorf("post-install ran in %q, want %q", got, storePath)
+	}
+
+	err = executeRoot(context.Background(), []string{"add", srcDir, "--quiet", "--overwrite", "--post-install", "exit 1"})
+	if err == nil || !strings.Contains(err.Error(), "added but") {
+		t.Errorf("failing hook error = %v, want the failure reported", err)
+	}
+	if _, err := registry.FindSkillByName("hooked"); err != nil {
+		t.Errorf("a failing hook must not roll the add back: %v", err)
+	}
+}
+
+func TestAddCmd_ConfiguredSkillsDir(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	skillsDir := filepath.Join(t.TempDir(), "big-disk", "skills")
+	viper.Set("skills_dir", skillsDir)
+	defer viper.Set("skills_dir", "")
+	defer func() { addQuiet = false }()
+
+	srcDir := filepath.Join(t.TempDir(), "roomy")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Roomy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeRoot(context.Background(), []string{"add", srcDir, "--quiet"}); err != nil {
+		t.Fatalf("add error = %v", err)
+	}
+
+	storePath := filepath.Join(skillsDir, "roomy")
+	if _, err := os.Stat(filepath.Join(storePath, "SKILL.md")); err != nil {
+		t.Fatalf("skill not stored in skills_dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.SkillsDir(dataDir), "roomy")); !os.IsNotExist(err) {
+		t.Errorf("skill should not be stored in the default store: %v", err)
+	}
+	if _, err := os.Stat(paths.RegistryPath(dataDir)); err != nil {
+		t.Errorf("registry should stay in the data directory: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := executeRoot(context.Background(), []string{"link", "roomy", projectDir}); err != nil {
+		t.Fatalf("link error = %v", err)
+	}
+	skill, err := registry.FindSkillByName("roomy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := skill.LinkedProjects[projectDir]
+	if !ok {
+		t.Fatalf("link not recorded: %+v", skill.LinkedProjects)
+	}
+	target, err := os.Readlink(info.SymlinkPath)
+	if err != nil {
+		t.Fatalf("link is not a symlink: %v", err)
+	}
+	if target != storePath {
+		t.Errorf("link points at %s, want %s", target, storePath)
+	}
+}
+
+func TestAddCmd_JSON(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	defer func() { addJSON = false }()
+
+	srcDir := filepath.Join(t.TempDir(), "jsony")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# Jsony"
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return executeRoot(context.Background(), []string{"add", srcDir, "--json"})
+	})
+	if err != nil {
+		t.Fatalf("add --json error = %v", err)
+	}
+
+	var result struct {
+		Skill struct {
+			Name      string `json:"name"`
+			StorePath string `json:"store_path"`
+		} `json:"skill"`
+		Stats struct {
+			Files    int    `json:"files"`
+			Bytes    int64  `json:"bytes"`
+			Location string `json:"location"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not a JSON object: %v\n%s", err, out)
+	}
+
+	storePath := filepath.Join(paths.SkillsDir(dataDir), "jsony")
+	if result.Stats.Location != storePath || result.Skill.StorePath != storePath {
+		t.Errorf("location = %q, store_path = %q, want %q", result.Stats.Location, result.Skill.StorePath, storePath)
+	}
+	if result.Stats.Bytes != int64(len(content)) || result.Stats.Files != 1 {
+		t.Errorf("stats = %+v, want 1 file of %d bytes", result.Stats, len(content))
+	}
+	if result.Skill.Name != "jsony" {
+		t.Errorf("skill name = %q, want jsony", result.Skill.Name)
+	}
+
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetErr(nil)
+	err = executeRoot(context.Background(), []string{"add", filepath.Join(t.TempDir(), "missing"), "--json"})
+	var reported *reportedError
+	if !errors.As(err, &reported) {
+		t.Fatalf("add --json failure = %v, want it reported", err)
+	}
+	var failure struct {
+		Error    string `json:"error"`
+		ExitCode int    `json:"exit_code"`
+	}
+	if err := json.Unmarshal(stderr.Bytes(), &failure); err != nil {
+		t.Fatalf("stderr is not a JSON error: %v\n%s", err, stderr.String())
+	}
+	if failure.Error == "" || failure.ExitCode != exitCode(err) {
+		t.Errorf("JSON error = %+v, want the message and exit code %d", failure, exitCode(err))
+	}
+}
+
+func TestAddCmd_EmptySkill(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:       "manifest only warns",
+			args:       []string{"https://github.com/owner/repo/tree/main/skills/lonely"},
+			wantOutput: "Warning: the skill has only 1 file(s)",
+		},
+		{
+			name:     "empty directory aborts",
+			args:     []string{"https://github.com/owner/repo/tree/main/skills/empty", "--skip-skill-check"},
+			wantCode: ExitError,
+			wantErr:  "--allow-empty",
+		},
+		{
+			name:       "empty directory added with --allow-empty",
+			args:       []string{"https://github.com/owner/repo/tree/main/skills/empty", "--skip-skill-check", "--allow-empty"},
+			wantOutput: "Warning: the skill has only 0 file(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.TempHome(t)
+
+			gh := testutil.NewFakeGitHub(t)
+			gh.SetBranch("owner", "repo", "main", "abc123")
+			gh.SetFile("owner", "repo", "skills/lonely/SKILL.md", "# Lonely")
+			// Hidden files are not downloaded, so this directory adds no files.
+			gh.SetFile("owner", "repo", "skills/empty/.gitkeep", "")
+
+			oldNewManager := newManager
+			newManager = func(token string) *add.Manager {
+				manager := add.NewManager("", token, nil)
+				manager.Client().SetBaseURL(gh.URL())
+				return manager
+			}
+			defer func() { newManager = oldNewManager }()
+			defer func() {
+				addSkipCheck, addAllowEmpty = false, false
+				addCmd.Flags().Lookup("skip-skill-check").Changed = false
+				addCmd.Flags().Lookup("allow-empty").Changed = false
+			}()
+
+			output, err := captureStdout(t, func() error {
+				return executeRoot(context.Background(), append([]string{"add"}, tt.args...))
+			})
+			if got := exitCode(err); got != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (error: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to mention %s", err, tt.wantErr)
+			}
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestAddCmd_ReplaceWithItself(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	defer func() { addReplace, addQuiet, addOverwrite = "", false, false }()
+
+	srcDir := filepath.Join(t.TempDir(), "same")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeRoot(context.Background(), []string{"add", srcDir, "--quiet"}); err != nil {
+		t.Fatalf("add error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# New"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := executeRoot(context.Background(), []string{"add", srcDir, "--quiet", "--overwrite", "--replace", "same"})
+	if got := exitCode(err); got != ExitUsage {
+		t.Fatalf("add --replace with the same name: exit code = %d, want %d (error: %v)", got, ExitUsage, err)
+	}
+	data, err := os.ReadFile(filepath.Join(paths.SkillsDir(dataDir), "same", "SKILL.md"))
+	if err != nil || string(data) != "# Old" {
+		t.Errorf("installed SKILL.md = %q (%v), want it untouched", data, err)
+	}
+}
+
+func TestAddSources_DeclinedOverwriteSkipsReplaceAndLink(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	ctx := context.Background()
+
+	newSkill := func(name string) string {
+		dir := filepath.Join(t.TempDir(), name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	oldSrc, newSrc := newSkill("old-skill"), newSkill("new-skill")
+	project, linkProject := t.TempDir(), t.TempDir()
+
+	for _, args := range [][]string{
+		{"add", oldSrc, "--quiet"},
+		{"link", "old-skill", project},
+		{"add", newSrc, "--quiet"},
+	} {
+		if err := executeRoot(ctx, args); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+	}
+
+	addReplace, addLinkProject = "old-skill", linkProject
+	defer func() { addReplace, addLinkProject = "", "" }()
+	decline := func() (bool, error) { return false, nil }
+	if err := addSources(ctx, newSrc, []string{newSrc}, nil, "", addOptions{quiet: true, confirmOverwrite: decline}); err != nil {
+		t.Fatalf("addSources() error = %v", err)
+	}
+
+	oldSkill, err := registry.FindSkillByName("old-skill")
+	if err != nil {
+		t.Fatalf("replaced skill was removed: %v", err)
+	}
+	oldStore := filepath.Join(paths.SkillsDir(dataDir), "old-skill")
+	if _, err := os.Stat(oldStore); err != nil {
+		t.Errorf("replaced skill's store was removed: %v", err)
+	}
+	absProject, _ := filepath.Abs(project)
+	linkInfo, ok := oldSkill.LinkedProjects[absProject]
+	if !ok {
+		t.Fatalf("replaced skill lost its link to %s: %v", absProject, oldSkill.LinkedProjects)
+	}
+	if target, err := os.Readlink(linkInfo.SymlinkPath); err != nil || target != oldStore {
+		t.Errorf("project link = %q (%v), want it to point at %s", target, err, oldStore)
+	}
+
+	skill, err := registry.FindSkillByName("new-skill")
+	if err != nil {
+		t.Fatalf("new-skill not in registry: %v", err)
+	}
+	if len(skill.LinkedProjects) != 0 {
+		t.Errorf("new-skill links = %v, want none after a declined add", skill.LinkedProjects)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smy-101/gskills/internal/clone"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+}
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <skill_name> <new_name>",
+	Short: "复制已安装的技能为一个新名称的本地副本",
+	Long: `复制已安装的技能为一个新名称的本地副本，便于在不影响原技能的情况下进行修改。
+
+命令格式: gskills clone <skill_name> <new_name>
+
+示例:
+  gskills clone prompt-engineer my-prompt-engineer
+
+副本存放于 ~/.gskills/skills/<new_name>，并作为独立的技能记录在注册表中。`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("用法: gskills clone <skill_name> <new_name>")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeClone(args[0], args[1])
+	},
+}
+
+func executeClone(skillName, newName string) error {
+	cloned, err := clone.CloneSkill(skillName, newName)
+	if err != nil {
+		return fmt.Errorf("failed to clone skill: %w", err)
+	}
+
+	fmt.Printf("Successfully cloned skill '%s' to '%s'\n", skillName, cloned.Name)
+	fmt.Printf("  Location: %s\n", cloned.StorePath)
+	return nil
+}
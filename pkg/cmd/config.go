@@ -1,24 +1,81 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/update"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 // configKeys 定义所有支持的配置项
-var configKeys = []string{"github_token", "proxy"}
+var configKeys = []string{"github_token", "proxy", "github_api_url", "max_files", "max_total_bytes", "check_timeout", "download_timeout", "skills_dir", "max_retries", "retry_wait"}
+
+// positiveIntConfigKeys 是取值必须为正整数的配置项
+var positiveIntConfigKeys = map[string]bool{"max_files": true, "max_total_bytes": true}
+
+// durationConfigKeys 是取值必须为正的时长 (如 30s、5m) 的配置项
+var durationConfigKeys = map[string]bool{"check_timeout": true, "download_timeout": true, "retry_wait": true}
+
+// absolutePathConfigKeys 是取值必须为绝对路径的配置项
+var absolutePathConfigKeys = map[string]bool{"skills_dir": true}
+
+// secretConfigKeys 是显示时默认隐藏取值的配置项，需 --show-secret 才显示
+var secretConfigKeys = map[string]bool{"github_token": true}
+
+// configDefaults 是 config migrate 为缺少的配置项写入的内置默认值。
+// github_token 和 proxy 没有默认值；skills_dir 的默认值随数据目录变化，
+// 写死会让 GSKILLS_HOME 不再移动技能存储目录，因此这三项写入空值
+var configDefaults = map[string]string{
+	"github_api_url":   add.DefaultAPIURL,
+	"max_files":        strconv.Itoa(add.DefaultMaxFiles),
+	"max_total_bytes":  strconv.FormatInt(add.DefaultMaxTotalBytes, 10),
+	"check_timeout":    update.DefaultCheckTimeout.String(),
+	"download_timeout": add.DefaultDownloadTimeout.String(),
+	"max_retries":      strconv.Itoa(add.DefaultMaxRetries),
+	"retry_wait":       add.DefaultRetryWait.String(),
+}
+
+// currentConfigVersion 是当前配置文件的结构版本，由 config migrate 写入 config_version
+const currentConfigVersion = 1
+
+// configShowSecret 为 true 时 config get/list 显示敏感配置的实际值
+var configShowSecret bool
 
 // validConfigKeys 用于验证配置键的有效性
 var validConfigKeys = map[string]bool{}
 
-// configMutex 保护 viper 并发访问的互斥锁（viper 不是并发安全的）
+// configMutex 保护对 viper 的访问，只能通过 configGuard 使用
 var configMutex sync.Mutex
 
+// configGuard 在持有 configMutex 时运行 fn。viper 不是并发安全的，
+// 本包中所有对 viper 的读写都必须通过 configGuard (或基于它的 configString)
+func configGuard(fn func() error) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	return fn()
+}
+
+// configString 在 configGuard 保护下读取配置项 key 的字符串值
+func configString(key string) (value string) {
+	configGuard(func() error {
+		value = viper.GetString(key)
+		return nil
+	})
+	return value
+}
+
 func init() {
 	for _, key := range configKeys {
 		validConfigKeys[key] = true
@@ -30,14 +87,17 @@ func init() {
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.PersistentFlags().BoolVar(&configShowSecret, "show-secret", false, "显示 github_token 等敏感配置的实际值 (get/list)")
 }
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "管理 gskills 配置",
-	Long:  "管理 gskills 配置文件 (~/.gskills/config.json)",
+	Long:  "管理 gskills 配置文件 (~/.gskills/config.json，设置 GSKILLS_HOME 时为 $GSKILLS_HOME/config.json)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeConfigList()
+		return executeConfigList(cmd.OutOrStdout(), configShowSecret)
 	},
 }
 
@@ -46,7 +106,7 @@ var configGetCmd = &cobra.Command{
 	Short: "获取指定配置项的值",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeConfigGet(args[0])
+		return executeConfigGet(cmd.OutOrStdout(), args[0], configShowSecret)
 	},
 }
 
@@ -64,100 +124,307 @@ var configListCmd = &cobra.Command{
 	Short: "列出所有配置项",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeConfigList()
+		return executeConfigList(cmd.OutOrStdout(), configShowSecret)
 	},
 }
 
-// executeConfigGet 获取并显示指定配置项的值
-// 对于敏感配置（如 github_token），显示时会隐藏实际值
-// 使用互斥锁保护 viper 并发访问
-func executeConfigGet(key string) error {
-	if !validConfigKeys[key] {
-		return fmt.Errorf("无效的配置项: %s (有效选项: github_token, proxy)", key)
-	}
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "显示配置文件路径",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeConfigPath(cmd.OutOrStdout())
+	},
+}
 
-	configMutex.Lock()
-	defer configMutex.Unlock()
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "将配置文件升级到当前结构",
+	Long: `读取现有配置文件，以内置默认值补全缺少的配置项，
+并写入 config_version 记录配置结构版本。已有的取值不会被修改。
+github_token、proxy 和 skills_dir 没有固定的默认值，补全为空值。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeConfigMigrate(cmd.OutOrStdout())
+	},
+}
 
-	value := viper.GetString(key)
-	if value == "" {
-		fmt.Printf("%s: (未设置)\n", key)
+// downloadLimits 返回配置的单个技能下载上限 (max_files、max_total_bytes)，
+// 未设置时为 0，即使用内置默认值
+func downloadLimits() (maxFiles int, maxTotalBytes int64) {
+	configGuard(func() error {
+		maxFiles, maxTotalBytes = viper.GetInt("max_files"), viper.GetInt64("max_total_bytes")
 		return nil
+	})
+	return maxFiles, maxTotalBytes
+}
+
+// configuredSkillsDir 返回配置的技能存储目录 (skills_dir)，未设置时为空，
+// 即使用数据目录下的 skills
+func configuredSkillsDir() string {
+	return configString("skills_dir")
+}
+
+// operationTimeouts 返回配置的检查超时 (check_timeout) 和单个技能的下载超时
+// (download_timeout)，未设置或无法解析时为 0，即使用内置默认值
+func operationTimeouts() (check, download time.Duration) {
+	parse := func(key string) time.Duration {
+		d, err := time.ParseDuration(configString(key))
+		if err != nil || d <= 0 {
+			return 0
+		}
+		return d
+	}
+	return parse("check_timeout"), parse("download_timeout")
+}
+
+// configuredRetries 返回配置的重试次数 (max_retries) 和首次重试前的等待时间
+// (retry_wait)，未设置或无效时分别为 -1 和 0，即使用内置默认值
+func configuredRetries() (maxRetries int, retryWait time.Duration) {
+	maxRetries = -1
+	if n, err := strconv.Atoi(configString("max_retries")); err == nil && checkRetries(n, 0) == nil {
+		maxRetries = n
+	}
+	if d, err := time.ParseDuration(configString("retry_wait")); err == nil && checkRetries(-1, d) == nil {
+		retryWait = d
 	}
+	return maxRetries, retryWait
+}
 
-	if key == "github_token" {
-		fmt.Printf("%s: ***\n", key)
-	} else {
-		fmt.Printf("%s: %s\n", key, value)
+// checkRetries 检查重试次数和等待时间是否在允许范围内；
+// 负的次数和为 0 的等待时间表示未指定，不检查
+func checkRetries(maxRetries int, retryWait time.Duration) error {
+	if maxRetries > add.MaxRetries {
+		return fmt.Errorf("重试次数必须在 0 到 %d 之间: %d", add.MaxRetries, maxRetries)
+	}
+	if retryWait != 0 && (retryWait < add.MinRetryWait || retryWait > add.MaxRetryWait) {
+		return fmt.Errorf("重试等待时间必须在 %v 到 %v 之间: %v", add.MinRetryWait, add.MaxRetryWait, retryWait)
 	}
 	return nil
 }
 
+// configureUpdater 依次应用配置的超时、--timeout、下载上限和重试设置；--timeout 优先于配置的超时
+func configureUpdater(updater *update.Updater) {
+	check, download := operationTimeouts()
+	updater.SetCheckTimeout(check)
+	updater.SetDownloadTimeout(download)
+	updater.SetTimeout(rootTimeout)
+	updater.SetDownloadLimits(downloadLimits())
+	updater.SetRetries(configuredRetries())
+}
+
+// resolveConfigPath 返回配置文件路径：优先使用 viper 已加载的文件，
+// 否则为配置目录（$GSKILLS_HOME、~/.gskills 或 $XDG_CONFIG_HOME/gskills）下的 config.json。
+// 调用方必须处于 configGuard 中
+func resolveConfigPath() (string, error) {
+	if configPath := viper.ConfigFileUsed(); configPath != "" {
+		return configPath, nil
+	}
+
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("无法获取配置目录: %w", err)
+	}
+	return paths.ConfigPath(configDir), nil
+}
+
+// executeConfigPath 输出配置文件路径
+func executeConfigPath(w io.Writer) error {
+	return configGuard(func() error {
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, configPath)
+		return nil
+	})
+}
+
+// displayConfigValue 返回配置项用于显示的值：未设置时为 "(未设置)"，
+// 敏感配置（如 github_token）除非 showSecret 为 true，否则显示为 "***"
+func displayConfigValue(key, value string, showSecret bool) string {
+	switch {
+	case value == "":
+		return "(未设置)"
+	case secretConfigKeys[key] && !showSecret:
+		return "***"
+	default:
+		return value
+	}
+}
+
+// executeConfigGet 获取并显示指定配置项的值
+// 对于敏感配置（如 github_token），除非指定 showSecret，显示时会隐藏实际值
+func executeConfigGet(w io.Writer, key string, showSecret bool) error {
+	if !validConfigKeys[key] {
+		return fmt.Errorf("无效的配置项: %s (有效选项: %s)", key, strings.Join(configKeys, ", "))
+	}
+
+	return configGuard(func() error {
+		fmt.Fprintf(w, "%s: %s\n", key, displayConfigValue(key, viper.GetString(key), showSecret))
+		return nil
+	})
+}
+
 // executeConfigSet 设置指定配置项的值并持久化到配置文件
 // 配置文件权限设置为 0600（仅所有者可读写）以保护敏感信息
-// 使用互斥锁保护 viper 并发访问（viper 不是并发安全的）
 func executeConfigSet(key, value string) error {
 	if !validConfigKeys[key] {
-		return fmt.Errorf("无效的配置项: %s (有效选项: github_token, proxy)", key)
+		return fmt.Errorf("无效的配置项: %s (有效选项: %s)", key, strings.Join(configKeys, ", "))
 	}
 
-	configMutex.Lock()
-	defer configMutex.Unlock()
-
-	viper.Set(key, value)
+	if positiveIntConfigKeys[key] {
+		if n, err := strconv.ParseInt(value, 10, 64); err != nil || n <= 0 {
+			return fmt.Errorf("配置项 %s 必须为正整数: %s", key, value)
+		}
+	}
 
-	configPath := viper.ConfigFileUsed()
-	if configPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("无法获取用户目录: %w", err)
+	if durationConfigKeys[key] {
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("配置项 %s 必须为正的时长 (如 30s、5m): %s", key, value)
 		}
-		configPath = filepath.Join(home, ".gskills", "config.json")
 	}
 
-	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("无法创建配置目录: %w", err)
+	if key == "max_retries" {
+		if n, err := strconv.Atoi(value); err != nil || n < 0 || checkRetries(n, 0) != nil {
+			return fmt.Errorf("配置项 %s 必须为 0 到 %d 之间的整数: %s", key, add.MaxRetries, value)
+		}
+	}
+	if key == "retry_wait" {
+		if d, _ := time.ParseDuration(value); checkRetries(-1, d) != nil {
+			return fmt.Errorf("配置项 %s 必须在 %v 到 %v 之间: %s", key, add.MinRetryWait, add.MaxRetryWait, value)
+		}
 	}
 
-	if err := viper.WriteConfigAs(configPath); err != nil {
-		return fmt.Errorf("写入配置文件失败: %w", err)
+	if absolutePathConfigKeys[key] && !filepath.IsAbs(value) {
+		return fmt.Errorf("配置项 %s 必须为绝对路径: %s", key, value)
 	}
 
-	// 设置配置文件权限为 0600（仅所有者可读写）
-	if err := os.Chmod(configPath, 0600); err != nil {
-		return fmt.Errorf("设置配置文件权限失败: %w", err)
+	if key == "github_api_url" {
+		if _, err := add.NewServer(value); err != nil {
+			return fmt.Errorf("配置项 %s 无效: %w", key, err)
+		}
 	}
 
-	fmt.Printf("已设置 %s = %s\n", key, value)
-	return nil
+	return configGuard(func() error {
+		viper.Set(key, value)
+
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		configDir := filepath.Dir(configPath)
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return fmt.Errorf("无法创建配置目录: %w", err)
+		}
+
+		if err := viper.WriteConfigAs(configPath); err != nil {
+			return fmt.Errorf("写入配置文件失败: %w", err)
+		}
+
+		// 设置配置文件权限为 0600（仅所有者可读写）
+		if err := os.Chmod(configPath, 0600); err != nil {
+			return fmt.Errorf("设置配置文件权限失败: %w", err)
+		}
+
+		fmt.Printf("已设置 %s = %s\n", key, value)
+		return nil
+	})
 }
 
 // executeConfigList 列出所有配置项的当前值
-// 对于敏感配置（如 github_token），显示时会隐藏实际值
-// 使用互斥锁保护 viper 并发访问
-func executeConfigList() error {
-	configMutex.Lock()
-	defer configMutex.Unlock()
+// 对于敏感配置（如 github_token），除非指定 showSecret，显示时会隐藏实际值
+func executeConfigList(w io.Writer, showSecret bool) error {
+	return configGuard(func() error {
+		fmt.Fprintln(w, "当前配置:")
+		for _, key := range configKeys {
+			fmt.Fprintf(w, "  %s: %s\n", key, displayConfigValue(key, viper.GetString(key), showSecret))
+		}
 
-	fmt.Println("当前配置:")
-	for _, key := range configKeys {
-		value := viper.GetString(key)
-		if value == "" {
-			fmt.Printf("  %s: (未设置)\n", key)
-		} else {
-			if key == "github_token" {
-				fmt.Printf("  %s: ***\n", key)
-			} else {
-				fmt.Printf("  %s: %s\n", key, value)
+		if configPath, err := resolveConfigPath(); err == nil {
+			fmt.Fprintf(w, "\n配置文件: %s\n", configPath)
+		}
+
+		return nil
+	})
+}
+
+// executeConfigMigrate 将配置文件升级到当前结构：以 configDefaults 中的默认值
+// 补全 configKeys 中缺少的配置项，保留已有取值和未知配置项，并写入 config_version。
+// 配置文件不存在时会创建；配置版本高于当前版本时报错，以免降级写坏配置。
+// 新内容先写入临时文件再重命名，中途失败不会留下写了一半的配置文件
+func executeConfigMigrate(w io.Writer) error {
+	return configGuard(func() error {
+		configPath, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		config := map[string]any{}
+		data, err := os.ReadFile(configPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("读取配置文件失败: %w", err)
+		case len(strings.TrimSpace(string(data))) > 0:
+			if err := json.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("解析配置文件失败: %w", err)
 			}
 		}
-	}
 
-	configPath := viper.ConfigFileUsed()
-	if configPath != "" {
-		fmt.Printf("\n配置文件: %s\n", configPath)
-	}
+		version := 0
+		if v, ok := config["config_version"].(float64); ok {
+			version = int(v)
+		}
+		if version > currentConfigVersion {
+			return fmt.Errorf("配置文件版本 %d 高于当前支持的版本 %d，请升级 gskills", version, currentConfigVersion)
+		}
 
-	return nil
+		var added []string
+		for _, key := range configKeys {
+			if _, ok := config[key]; !ok {
+				config[key] = configDefaults[key]
+				added = append(added, key)
+			}
+		}
+		config["config_version"] = currentConfigVersion
+
+		data, err = json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("生成配置文件失败: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+			return fmt.Errorf("无法创建配置目录: %w", err)
+		}
+		tmpPath := configPath + ".tmp"
+		if err := os.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("写入配置文件失败: %w", err)
+		}
+		// WriteFile 不会修改已存在文件的权限
+		if err := os.Chmod(tmpPath, 0600); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("设置配置文件权限失败: %w", err)
+		}
+		if err := os.Rename(tmpPath, configPath); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("写入配置文件失败: %w", err)
+		}
+
+		viper.SetConfigFile(configPath)
+		if err := viper.ReadInConfig(); err != nil {
+			return fmt.Errorf("重新加载配置文件失败: %w", err)
+		}
+
+		if len(added) == 0 && version == currentConfigVersion {
+			fmt.Fprintf(w, "配置文件已是最新 (config_version %d)\n", currentConfigVersion)
+			return nil
+		}
+		if len(added) > 0 {
+			fmt.Fprintf(w, "已补全配置项: %s\n", strings.Join(added, ", "))
+		}
+		fmt.Fprintf(w, "配置文件已升级到 config_version %d: %s\n", currentConfigVersion, configPath)
+		return nil
+	})
 }
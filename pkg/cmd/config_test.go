@@ -1,12 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/update"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -41,7 +55,7 @@ func TestExecuteConfigGet(t *testing.T) {
 
 		viper.Set("github_token", "test-token-123")
 
-		err := executeConfigGet("github_token")
+		err := executeConfigGet(io.Discard, "github_token", false)
 		if err != nil {
 			t.Errorf("executeConfigGet() error = %v", err)
 		}
@@ -53,7 +67,7 @@ func TestExecuteConfigGet(t *testing.T) {
 
 		viper.Set("proxy", "")
 
-		err := executeConfigGet("proxy")
+		err := executeConfigGet(io.Discard, "proxy", false)
 		if err != nil {
 			t.Errorf("executeConfigGet() error = %v", err)
 		}
@@ -63,13 +77,63 @@ func TestExecuteConfigGet(t *testing.T) {
 		cleanup, _ := setupConfigTest(t)
 		defer cleanup()
 
-		err := executeConfigGet("invalid_key")
+		err := executeConfigGet(io.Discard, "invalid_key", false)
 		if err == nil {
 			t.Error("executeConfigGet() expected error for invalid key, got nil")
 		}
 	})
 }
 
+func TestExecuteConfigGet_ShowSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		showSecret bool
+		want       string
+	}{
+		{name: "token masked by default", key: "github_token", want: "github_token: ***\n"},
+		{name: "token revealed", key: "github_token", showSecret: true, want: "github_token: test-token-123\n"},
+		{name: "non-secret unaffected", key: "proxy", want: "proxy: http://proxy.example.com\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+			viper.Set("github_token", "test-token-123")
+			viper.Set("proxy", "http://proxy.example.com")
+
+			var buf bytes.Buffer
+			if err := executeConfigGet(&buf, tt.key, tt.showSecret); err != nil {
+				t.Fatalf("executeConfigGet() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		cleanup, _ := setupConfigTest(t)
+		defer cleanup()
+		viper.Set("github_token", "test-token-123")
+
+		var masked, revealed bytes.Buffer
+		if err := executeConfigList(&masked, false); err != nil {
+			t.Fatalf("executeConfigList() error = %v", err)
+		}
+		if err := executeConfigList(&revealed, true); err != nil {
+			t.Fatalf("executeConfigList() error = %v", err)
+		}
+		if strings.Contains(masked.String(), "test-token-123") || !strings.Contains(masked.String(), "github_token: ***") {
+			t.Errorf("list should mask the token by default:\n%s", masked.String())
+		}
+		if !strings.Contains(revealed.String(), "github_token: test-token-123") {
+			t.Errorf("list --show-secret should reveal the token:\n%s", revealed.String())
+		}
+	})
+}
+
 func TestConcurrentConfigAccess(t *testing.T) {
 	cleanup, tempDir := setupConfigTest(t)
 	defer cleanup()
@@ -89,6 +153,21 @@ func TestConcurrentConfigAccess(t *testing.T) {
 			for j := 0; j < numOperations; j++ {
 				key := configKeys[index%len(configKeys)]
 				value := fmt.Sprintf("concurrent-value-%d-%d", index, j)
+				if positiveIntConfigKeys[key] {
+					value = fmt.Sprintf("%d", index*numOperations+j+1)
+				}
+				if durationConfigKeys[key] {
+					value = fmt.Sprintf("%ds", index*numOperations+j+1)
+				}
+				if absolutePathConfigKeys[key] {
+					value = filepath.Join(tempDir, value)
+				}
+				if key == "max_retries" {
+					value = fmt.Sprintf("%d", j)
+				}
+				if key == "github_api_url" {
+					value = fmt.Sprintf("https://ghe-%d-%d.example/api/v3", index, j)
+				}
 				if err := executeConfigSet(key, value); err != nil {
 					t.Errorf("concurrent set failed: %v", err)
 				}
@@ -104,6 +183,148 @@ func TestConcurrentConfigAccess(t *testing.T) {
 	}
 }
 
+func TestConcurrentConfigAccess_ConsistentFile(t *testing.T) {
+	cleanup, tempDir := setupConfigTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(tempDir, "config.json")
+	viper.SetConfigFile(configPath)
+
+	keys := []string{"github_token", "proxy", "github_api_url", "check_timeout"}
+	valueFor := func(key string, i int) string {
+		if durationConfigKeys[key] {
+			return fmt.Sprintf("%ds", i+1)
+		}
+		if key == "github_api_url" {
+			return fmt.Sprintf("https://ghe-%d.example/api/v3", i)
+		}
+		return fmt.Sprintf("%s-%d", key, i)
+	}
+
+	const writesPerKey = 20
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(2)
+		go func(key string) {
+			defer wg.Done()
+			for i := 0; i < writesPerKey; i++ {
+				if err := executeConfigSet(key, valueFor(key, i)); err != nil {
+					t.Errorf("executeConfigSet(%s) error = %v", key, err)
+				}
+			}
+		}(key)
+		// Readers run alongside the writers through the same guard.
+		go func(key string) {
+			defer wg.Done()
+			for i := 0; i < writesPerKey; i++ {
+				configString(key)
+				operationTimeouts()
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	var file map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("config file is not valid JSON after concurrent writes: %v\n%s", err, data)
+	}
+
+	// Each key was written in order by one goroutine, so both the file and
+	// viper must hold its last value.
+	for _, key := range keys {
+		want := valueFor(key, writesPerKey-1)
+		if got := file[key]; got != want {
+			t.Errorf("file %s = %v, want %q", key, got, want)
+		}
+		if got := configString(key); got != want {
+			t.Errorf("viper %s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestExecuteConfigSet_PositiveIntKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid max_files", key: "max_files", value: "100"},
+		{name: "valid max_total_bytes", key: "max_total_bytes", value: "1048576"},
+		{name: "zero", key: "max_files", value: "0", wantErr: true},
+		{name: "negative", key: "max_total_bytes", value: "-1", wantErr: true},
+		{name: "not a number", key: "max_files", value: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+
+			err := executeConfigSet(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executeConfigSet(%s, %s) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := viper.GetString(tt.key); got != tt.value {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestExecuteConfigSet_RetryKeys(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "max_retries", value: "0"},
+		{key: "max_retries", value: "10"},
+		{key: "max_retries", value: "11", wantErr: true},
+		{key: "max_retries", value: "-1", wantErr: true},
+		{key: "max_retries", value: "many", wantErr: true},
+		{key: "retry_wait", value: "500ms"},
+		{key: "retry_wait", value: "10ms", wantErr: true},
+		{key: "retry_wait", value: "2m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+
+			err := executeConfigSet(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executeConfigSet(%s, %s) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteConfigSet_SkillsDir(t *testing.T) {
+	cleanup, tempDir := setupConfigTest(t)
+	defer cleanup()
+
+	if err := executeConfigSet("skills_dir", "relative/skills"); err == nil {
+		t.Error("executeConfigSet() should reject a relative skills_dir")
+	}
+
+	skillsDir := filepath.Join(tempDir, "skills")
+	if err := executeConfigSet("skills_dir", skillsDir); err != nil {
+		t.Fatalf("executeConfigSet() error = %v", err)
+	}
+	if got := configuredSkillsDir(); got != skillsDir {
+		t.Errorf("configuredSkillsDir() = %q, want %q", got, skillsDir)
+	}
+}
+
 func TestExecuteConfigList(t *testing.T) {
 	t.Run("list all configs", func(t *testing.T) {
 		cleanup, tempDir := setupConfigTest(t)
@@ -114,9 +335,9 @@ func TestExecuteConfigList(t *testing.T) {
 		viper.Set("github_token", "test-token")
 		viper.Set("proxy", "http://proxy.example.com")
 
-		err := executeConfigList()
+		err := executeConfigList(io.Discard, false)
 		if err != nil {
-			t.Errorf("executeConfigList() error = %v", err)
+			t.Errorf("executeConfigList(io.Discard, false) error = %v", err)
 		}
 	})
 
@@ -127,9 +348,9 @@ func TestExecuteConfigList(t *testing.T) {
 		configPath := filepath.Join(tempDir, "config.json")
 		viper.SetConfigFile(configPath)
 
-		err := executeConfigList()
+		err := executeConfigList(io.Discard, false)
 		if err != nil {
-			t.Errorf("executeConfigList() error = %v", err)
+			t.Errorf("executeConfigList(io.Discard, false) error = %v", err)
 		}
 	})
 }
@@ -221,3 +442,234 @@ func TestConfigSetCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteConfigPath(t *testing.T) {
+	t.Run("loaded config file", func(t *testing.T) {
+		cleanup, tempDir := setupConfigTest(t)
+		defer cleanup()
+
+		var buf bytes.Buffer
+		if err := executeConfigPath(&buf); err != nil {
+			t.Fatalf("executeConfigPath() error = %v", err)
+		}
+		want := filepath.Join(tempDir, "config.json")
+		if got := strings.TrimSpace(buf.String()); got != want {
+			t.Errorf("config path = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("no config file loaded honors GSKILLS_HOME", func(t *testing.T) {
+		originalConfigFile := viper.ConfigFileUsed()
+		viper.Reset()
+		defer func() {
+			viper.Reset()
+			if originalConfigFile != "" {
+				viper.SetConfigFile(originalConfigFile)
+			}
+		}()
+
+		t.Setenv("HOME", t.TempDir())
+		dataDir := t.TempDir()
+		t.Setenv(paths.HomeEnv, dataDir)
+
+		var buf bytes.Buffer
+		if err := executeConfigPath(&buf); err != nil {
+			t.Fatalf("executeConfigPath() error = %v", err)
+		}
+		want := filepath.Join(dataDir, "config.json")
+		if got := strings.TrimSpace(buf.String()); got != want {
+			t.Errorf("config path = %s, want %s", got, want)
+		}
+		if _, err := os.Stat(want); !os.IsNotExist(err) {
+			t.Error("config path must not create the config file")
+		}
+	})
+}
+
+func TestExecuteConfigSet_DurationKeys(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "check_timeout", value: "45s"},
+		{key: "download_timeout", value: "10m"},
+		{key: "check_timeout", value: "0s", wantErr: true},
+		{key: "download_timeout", value: "-1m", wantErr: true},
+		{key: "download_timeout", value: "10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+
+			err := executeConfigSet(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executeConfigSet(%s, %s) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigureUpdater_AppliesOperationTimeouts(t *testing.T) {
+	// The slow endpoint answers only after the configured timeout has long
+	// expired, so a fast failure shows the timeout bounded the context.
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}
+	tests := []struct {
+		name       string
+		key        string
+		slowPrefix string
+	}{
+		{name: "check", key: "check_timeout", slowPrefix: "/repos/owner/repo/commits/"},
+		{name: "download", key: "download_timeout", slowPrefix: "/repos/owner/repo/contents/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup, _ := setupConfigTest(t)
+			defer cleanup()
+			testutil.TempHome(t)
+			viper.Set(tt.key, "200ms")
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, tt.slowPrefix):
+					slow(w, r)
+				case r.URL.Path == "/repos/owner/repo/commits/main":
+					w.Write([]byte(`{"sha":"2222222"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer ts.Close()
+
+			updater := update.NewUpdater("")
+			updater.SetBaseURL(ts.URL)
+			configureUpdater(updater)
+
+			skill := &types.SkillMetadata{
+				Name:      "demo",
+				Version:   "main",
+				CommitSHA: "1111111",
+				SourceURL: "https://github.com/owner/repo/tree/main/skills/demo",
+				StorePath: filepath.Join(t.TempDir(), "demo"),
+			}
+
+			start := time.Now()
+			err := updater.UpdateSkill(context.Background(), skill)
+			if err == nil {
+				t.Fatal("UpdateSkill() should fail once the configured timeout expires")
+			}
+			if !errors.Is(err, context.DeadlineExceeded) && !strings.Contains(err.Error(), "deadline exceeded") {
+				t.Errorf("UpdateSkill() error = %v, want a deadline error", err)
+			}
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("UpdateSkill() took %v, want it bounded by the configured %s", elapsed, tt.key)
+			}
+		})
+	}
+}
+
+func TestExecuteConfigMigrate(t *testing.T) {
+	cleanup, tempDir := setupConfigTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(tempDir, "config.json")
+	old := `{"github_token": "ghp_old", "proxy": "http://proxy:8080", "custom": "kept"}`
+	if err := os.WriteFile(configPath, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := executeConfigMigrate(&out); err != nil {
+		t.Fatalf("executeConfigMigrate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("migrated config is not valid JSON: %v", err)
+	}
+
+	for _, key := range configKeys {
+		if _, ok := config[key]; !ok {
+			t.Errorf("key %s was not added", key)
+		}
+	}
+	if config["github_token"] != "ghp_old" || config["proxy"] != "http://proxy:8080" {
+		t.Errorf("existing values were overwritten: %v", config)
+	}
+	if config["custom"] != "kept" {
+		t.Errorf("unknown key was dropped: %v", config)
+	}
+	for key, want := range map[string]string{"max_files": "5000", "check_timeout": "30s", "github_api_url": add.DefaultAPIURL, "skills_dir": ""} {
+		if config[key] != want {
+			t.Errorf("added %s = %v, want the default %q", key, config[key], want)
+		}
+	}
+	if _, err := os.Stat(configPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary config file was left behind: %v", err)
+	}
+	if config["config_version"] != float64(currentConfigVersion) {
+		t.Errorf("config_version = %v, want %d", config["config_version"], currentConfigVersion)
+	}
+	if !strings.Contains(out.String(), "max_files") {
+		t.Errorf("output should list added keys, got %q", out.String())
+	}
+	if info, err := os.Stat(configPath); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("config permissions = %o, want 0600", info.Mode().Perm())
+	}
+
+	out.Reset()
+	if err := executeConfigMigrate(&out); err != nil {
+		t.Fatalf("second executeConfigMigrate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "已是最新") {
+		t.Errorf("second run should report an up-to-date config, got %q", out.String())
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"config_version": 99}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeConfigMigrate(io.Discard); err == nil {
+		t.Error("executeConfigMigrate() should refuse a newer config_version")
+	}
+}
+
+func TestConfigSet_GitHubAPIURL(t *testing.T) {
+	cleanup, _ := setupConfigTest(t)
+	defer cleanup()
+	testutil.TempHome(t)
+
+	if err := executeConfigSet("github_api_url", "ftp://x"); err == nil {
+		t.Fatal("executeConfigSet() should reject a non-http(s) github_api_url")
+	}
+	if got := viper.GetString("github_api_url"); got != "" {
+		t.Errorf("rejected value was stored: %q", got)
+	}
+
+	// A value stored by hand must not lock the user out of config.
+	viper.Set("github_api_url", "ftp://x")
+	if _, err := captureStdout(t, func() error {
+		return executeRoot(context.Background(), []string{"config", "set", "github_api_url", "https://github.mycorp.com/api/v3"})
+	}); err != nil {
+		t.Fatalf("config set with an invalid stored github_api_url error = %v", err)
+	}
+
+	viper.Set("github_api_url", "ftp://x")
+	_, err := captureStdout(t, func() error {
+		return executeRoot(context.Background(), []string{"list"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "github_api_url") {
+		t.Errorf("list with an invalid github_api_url error = %v, want it rejected", err)
+	}
+}
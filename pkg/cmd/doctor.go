@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/smy-101/gskills/internal/doctor"
+	"github.com/smy-101/gskills/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFix  bool
+	doctorJSON bool
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "自动修复可安全修复的问题")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "以 JSON 格式输出检查报告")
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "检查技能注册表、存储目录和项目链接是否一致",
+	Long: `检查技能注册表、存储目录和项目链接是否一致。
+
+检查项:
+  1. 注册表中存储目录已不存在的技能
+  2. 符号链接缺失或失效的项目链接
+  3. 存储目录中存在但未登记到注册表的技能（使用 --registry 时不检查）
+
+使用 --fix 自动修复：移除失效的注册表项和符号链接，并在确认后重新登记未登记的技能（来源记为 local）。
+使用 --json 输出机器可读的报告。未使用 --fix 且发现问题时，退出码为 6，便于在 CI 中检查。
+
+示例:
+  gskills doctor
+  gskills doctor --json
+  gskills doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeDoctor(cmd.OutOrStdout(), doctorFix, doctorJSON)
+	},
+}
+
+// executeDoctor checks, or with fix repairs, the data directory and prints
+// the report to w as prose or, with asJSON, as JSON. Problems found without
+// fix are returned as a problemsError.
+func executeDoctor(w io.Writer, fix, asJSON bool) error {
+	d := doctor.New()
+
+	var report *doctor.Report
+	var err error
+	if fix {
+		d.SetConfirmReAdd(confirmReAdd)
+		report, err = d.Fix()
+	} else {
+		report, err = d.Diagnose()
+	}
+	if err != nil {
+		return fmt.Errorf("检查失败: %w", err)
+	}
+
+	if asJSON {
+		if report.Issues == nil {
+			report.Issues = []doctor.Issue{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		printDoctorReport(w, report, fix)
+	}
+
+	if !fix && len(report.Issues) > 0 {
+		return &problemsError{err: fmt.Errorf("发现 %d 个问题", len(report.Issues))}
+	}
+	return nil
+}
+
+func printDoctorReport(w io.Writer, report *doctor.Report, fix bool) {
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(w, "✓ 没有发现问题")
+		return
+	}
+
+	for _, issue := range report.Issues {
+		mark := "✗"
+		if issue.Fixed {
+			mark = "✓ 已修复"
+		}
+		fmt.Fprintf(w, "%s %s\n", mark, describeIssue(issue))
+	}
+
+	if fix {
+		fmt.Fprintf(w, "\n修复了 %d/%d 个问题\n", report.Fixed, len(report.Issues))
+	} else {
+		fmt.Fprintf(w, "\n发现 %d 个问题，运行 'gskills doctor --fix' 进行修复\n", len(report.Issues))
+	}
+}
+
+func describeIssue(issue doctor.Issue) string {
+	switch issue.Kind {
+	case doctor.IssueMissingStore:
+		return fmt.Sprintf("%s: 存储目录不存在 (%s)", issue.Skill, issue.Path)
+	case doctor.IssueBrokenLink:
+		return fmt.Sprintf("%s: 项目 %s 中的链接已失效 (%s)", issue.Skill, issue.Project, issue.Path)
+	case doctor.IssueUnregistered:
+		return fmt.Sprintf("%s: 存储目录未登记到注册表 (%s)", issue.Skill, issue.Path)
+	default:
+		return fmt.Sprintf("%s: %s", issue.Skill, issue.Kind)
+	}
+}
+
+// confirmReAdd asks whether an unregistered skill should be registered again.
+// Without a terminal the skill is left unregistered.
+func confirmReAdd(issue doctor.Issue) (bool, error) {
+	if !prompt.IsInteractive() {
+		return false, nil
+	}
+
+	fmt.Fprintf(promptOutput, "重新登记技能 '%s' (%s)? [y/N]: ", issue.Skill, issue.Path)
+	line, err := readPromptLine()
+	if err != nil {
+		return false, err
+	}
+	return prompt.IsYes(line), nil
+}
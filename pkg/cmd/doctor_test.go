@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestExecuteDoctor(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("GSKILLS_HOME", "")
+
+	skillsDir := filepath.Join(homeDir, ".gskills", "skills")
+	orphanDir := filepath.Join(skillsDir, "orphan")
+	if err := os.MkdirAll(orphanDir, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(orphanDir, "SKILL.md"), []byte("# orphan"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	if err := registry.SaveRegistry([]types.SkillMetadata{
+		{ID: "gone@main", Name: "gone", SourceURL: "https://github.com/o/r/tree/main/gone", StorePath: filepath.Join(skillsDir, "gone"), Version: "main", CommitSHA: "abc"},
+	}); err != nil {
+		t.Fatalf("failed to seed registry: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := executeDoctor(&out, false, false); err == nil {
+		t.Error("executeDoctor() without --fix should fail when issues are found")
+	}
+	if !strings.Contains(out.String(), "gskills doctor --fix") {
+		t.Errorf("output = %q, want a hint to run --fix", out.String())
+	}
+
+	t.Run("fix re-adds when confirmed", func(t *testing.T) {
+		withPromptInput(t, "y\n", true)
+
+		out.Reset()
+		if err := executeDoctor(&out, true, false); err != nil {
+			t.Fatalf("executeDoctor(fix) error = %v", err)
+		}
+		if !strings.Contains(out.String(), "修复了 2/2 个问题") {
+			t.Errorf("output = %q, want 2 of 2 fixed", out.String())
+		}
+
+		skills, err := registry.LoadRegistry()
+		if err != nil {
+			t.Fatalf("LoadRegistry() error = %v", err)
+		}
+		if len(skills) != 1 || skills[0].Name != "orphan" {
+			t.Errorf("registry = %+v, want only the re-added orphan", skills)
+		}
+
+		out.Reset()
+		if err := executeDoctor(&out, false, false); err != nil {
+			t.Errorf("executeDoctor() after fix error = %v, output %q", err, out.String())
+		}
+	})
+}
+
+func TestExecuteDoctor_JSON(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("GSKILLS_HOME", "")
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "gone")
+	if err := registry.SaveRegistry([]types.SkillMetadata{
+		{ID: "gone@main", Name: "gone", SourceURL: "https://github.com/o/r/tree/main/gone", StorePath: storePath, Version: "main", CommitSHA: "abc"},
+	}); err != nil {
+		t.Fatalf("failed to seed registry: %v", err)
+	}
+
+	var out bytes.Buffer
+	err := executeDoctor(&out, false, true)
+	if got := exitCode(err); got != ExitProblems {
+		t.Errorf("exit code = %d, want %d (error: %v)", got, ExitProblems, err)
+	}
+
+	var report struct {
+		Issues []struct {
+			Kind  string `json:"kind"`
+			Skill string `json:"skill"`
+			Path  string `json:"path"`
+			Fixed bool   `json:"fixed"`
+		} `json:"issues"`
+		Fixed int `json:"fixed"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if len(report.Issues) != 1 {
+		t.Fatalf("issues = %+v, want one", report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.Kind != "missing_store" || issue.Skill != "gone" || issue.Path != storePath || issue.Fixed {
+		t.Errorf("issue = %+v, want an unfixed missing_store for gone", issue)
+	}
+
+	if err := registry.SaveRegistry(nil); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := executeDoctor(&out, false, true); err != nil {
+		t.Fatalf("executeDoctor() on a healthy registry error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"issues": []`) {
+		t.Errorf("healthy report = %s, want an empty issues list", out.String())
+	}
+}
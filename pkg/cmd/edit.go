@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/clone"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var editDir bool
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().BoolVar(&editDir, "dir", false, "打开技能目录，而不是 SKILL.md")
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit <skill-name>",
+	Short: "用 $VISUAL 或 $EDITOR 打开技能进行修改",
+	Long: `用 $VISUAL 或 $EDITOR 指定的编辑器打开技能的 SKILL.md，使用 --dir 打开整个技能目录。
+
+编辑器可以带参数，例如 EDITOR="code --wait"。
+适合修改通过 clone 复制或从本地目录添加的技能；已链接的项目通过符号链接立即看到修改。
+注意：对来自 GitHub 或本地目录的技能执行 update 时，会重新下载或复制，覆盖这里的修改。
+
+示例:
+  gskills edit my-prompt-engineer
+  gskills edit my-prompt-engineer --dir`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeEdit(args[0], editDir)
+	},
+}
+
+// editorCommand returns the editor configured in $VISUAL or $EDITOR, split
+// into the program and its arguments. The program must be on PATH.
+func editorCommand() ([]string, error) {
+	name, editor := "VISUAL", os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		name, editor = "EDITOR", os.Getenv("EDITOR")
+	}
+
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, errors.New("未设置编辑器: 请设置 $VISUAL 或 $EDITOR 环境变量，例如 export EDITOR=vim")
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("$%s 指定的编辑器 '%s' 无法运行: %w", name, fields[0], err)
+	}
+	return fields, nil
+}
+
+// executeEdit opens the SKILL.md of skillName, or its directory when dir is
+// set, in the user's editor and waits for the editor to exit.
+func executeEdit(skillName string, dir bool) error {
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("failed to find skill: %w", err)
+	}
+
+	target := skill.StorePath
+	if !dir {
+		manifest, err := add.FindSkillManifest(skill.StorePath)
+		if err != nil {
+			return fmt.Errorf("failed to read skill directory '%s': %w", skill.StorePath, err)
+		}
+		if manifest == "" {
+			return fmt.Errorf("SKILL.md not found in '%s'; use --dir to open the directory", skill.StorePath)
+		}
+		target = manifest
+	}
+
+	editor, err := editorCommand()
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(editor[0], append(editor[1:], target)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("编辑器退出时出错: %w", err)
+	}
+
+	if !strings.HasPrefix(skill.SourceURL, clone.SourcePrefix) && skill.SourceURL != add.UntrackedSource {
+		fmt.Printf("注意: '%s' 有来源，执行 'gskills update %s' 会覆盖这里的修改；可先用 'gskills clone' 复制一份再修改\n", skillName, skillName)
+	}
+
+	if len(skill.LinkedProjects) > 0 {
+		projects := make([]string, 0, len(skill.LinkedProjects))
+		for projectPath := range skill.LinkedProjects {
+			projects = append(projects, projectPath)
+		}
+		sort.Strings(projects)
+		fmt.Printf("修改已通过符号链接在 %d 个项目中生效:\n", len(projects))
+		for _, projectPath := range projects {
+			fmt.Printf("  • %s\n", projectPath)
+		}
+	}
+	return nil
+}
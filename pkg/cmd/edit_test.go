@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/clone"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// installFakeEditor puts an executable named fake-editor on PATH that
+// records its arguments in the returned file.
+func installFakeEditor(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "fake-editor"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake editor: %v", err)
+	}
+
+	originalPath := os.Getenv("PATH")
+	os.Setenv("PATH", binDir+string(os.PathListSeparator)+originalPath)
+	t.Cleanup(func() { os.Setenv("PATH", originalPath) })
+	return argsFile
+}
+
+// setEditorEnv sets $VISUAL and $EDITOR for the duration of the test.
+func setEditorEnv(t *testing.T, visual, editor string) {
+	t.Helper()
+	originalVisual, originalEditor := os.Getenv("VISUAL"), os.Getenv("EDITOR")
+	os.Setenv("VISUAL", visual)
+	os.Setenv("EDITOR", editor)
+	t.Cleanup(func() {
+		os.Setenv("VISUAL", originalVisual)
+		os.Setenv("EDITOR", originalEditor)
+	})
+}
+
+func TestExecuteEdit(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "editable")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte("# Editable"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	skill := &types.SkillMetadata{
+		ID:        "editable@local",
+		Name:      "editable",
+		Version:   "local",
+		CommitSHA: "local",
+		SourceURL: clone.SourcePrefix + "original@main",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	argsFile := installFakeEditor(t)
+
+	tests := []struct {
+		name     string
+		visual   string
+		editor   string
+		dir      bool
+		wantArgs []string
+	}{
+		{
+			name:     "opens SKILL.md with $EDITOR",
+			editor:   "fake-editor",
+			wantArgs: []string{filepath.Join(storePath, "SKILL.md")},
+		},
+		{
+			name:     "--dir opens the skill directory",
+			editor:   "fake-editor",
+			dir:      true,
+			wantArgs: []string{storePath},
+		},
+		{
+			name:     "$VISUAL wins and keeps its arguments",
+			visual:   "fake-editor --wait",
+			editor:   "missing-editor",
+			wantArgs: []string{"--wait", filepath.Join(storePath, "SKILL.md")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(argsFile)
+			setEditorEnv(t, tt.visual, tt.editor)
+
+			if err := executeEdit("editable", tt.dir); err != nil {
+				t.Fatalf("executeEdit() error = %v", err)
+			}
+
+			data, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatalf("editor was not invoked: %v", err)
+			}
+			got := strings.Split(strings.TrimSpace(string(data)), "\n")
+			if strings.Join(got, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("editor invoked with %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestEditorCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		visual  string
+		editor  string
+		wantErr string
+	}{
+		{
+			name:    "no editor configured",
+			wantErr: "未设置编辑器",
+		},
+		{
+			name:    "editor not on PATH",
+			editor:  "definitely-not-an-editor-gskills",
+			wantErr: "$EDITOR 指定的编辑器 'definitely-not-an-editor-gskills' 无法运行",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEditorEnv(t, tt.visual, tt.editor)
+			_, err := editorCommand()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("editorCommand() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/tidy"
+	"github.com/smy-101/gskills/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by gskills, so scripts can tell failures apart.
+const (
+	ExitOK         = 0
+	ExitError      = 1 // any failure without a more specific code
+	ExitUsage      = 2 // invalid arguments, flags or commands
+	ExitNotFound   = 3 // the named skill is not installed
+	ExitNetwork    = 4 // GitHub API, network or rate-limit failures
+	ExitFilesystem = 5 // reading or writing the store, registry or projects
+	ExitProblems   = 6 // a health check such as doctor found problems
+)
+
+// usageError marks an error caused by how the command was invoked.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// problemsError marks the failure of a health check that ran but found
+// problems, as opposed to one that could not run.
+type problemsError struct {
+	err error
+}
+
+func (e *problemsError) Error() string { return e.err.Error() }
+func (e *problemsError) Unwrap() error { return e.err }
+
+// reportedError wraps an error the command already printed in its own
+// format, such as JSON, so that Execute only sets the exit code.
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// markUsageErrorsOnce wraps the argument validators and flag errors of every
+// command so their errors map to ExitUsage.
+var markUsageErrorsOnce sync.Once
+
+func markUsageErrors(c *cobra.Command) {
+	c.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+	if validate := c.Args; validate != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &usageError{err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range c.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// exitCode maps err to the exit code of the process.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) || strings.HasPrefix(err.Error(), "unknown command ") {
+		return ExitUsage
+	}
+
+	var problemsErr *problemsError
+	if errors.As(err, &problemsErr) {
+		return ExitProblems
+	}
+
+	var linkErr *link.LinkError
+	if errors.As(err, &linkErr) {
+		switch linkErr.Type {
+		case link.ErrorTypeInvalidPath:
+			return ExitUsage
+		case link.ErrorTypeSkillNotFound:
+			return ExitNotFound
+		case link.ErrorTypeFilesystem:
+			return ExitFilesystem
+		}
+	}
+
+	var downloadErr *add.DownloadError
+	if errors.As(err, &downloadErr) {
+		switch downloadErr.Type {
+		case add.ErrorTypeInvalidURL:
+			return ExitUsage
+		case add.ErrorTypeAPI, add.ErrorTypeRateLimit, add.ErrorTypeAccess:
+			return ExitNetwork
+		case add.ErrorTypeNotFound:
+			return ExitNotFound
+		case add.ErrorTypeFilesystem, add.ErrorTypeRegistry:
+			return ExitFilesystem
+		case add.ErrorTypeLimit, add.ErrorTypeChecksum:
+			// The download worked but the content was refused.
+			return ExitError
+		}
+	}
+
+	var updateErr *update.UpdateError
+	if errors.As(err, &updateErr) {
+		switch updateErr.Type {
+		case update.UpdateErrorTypeCheck, update.UpdateErrorTypeDownload:
+			// Downloads also write the files they fetch, so a local
+			// failure can surface as either type.
+			if isLocalFilesystemError(err) {
+				return ExitFilesystem
+			}
+			return ExitNetwork
+		case update.UpdateErrorTypeSource:
+			return ExitUsage
+		case update.UpdateErrorTypeFilesystem, update.UpdateErrorTypeRegistry:
+			return ExitFilesystem
+		case update.UpdateErrorTypeNotFound:
+			return ExitNotFound
+		case update.UpdateErrorTypeInvalid:
+			// Like adding a directory without a manifest.
+			return ExitError
+		}
+	}
+
+	var tidyErr *tidy.TidyError
+	if errors.As(err, &tidyErr) {
+		switch tidyErr.Type {
+		case tidy.ErrorTypeInvalidPath:
+			return ExitUsage
+		case tidy.ErrorTypeFilesystem, tidy.ErrorTypeRegistry:
+			return ExitFilesystem
+		}
+	}
+
+	if errors.Is(err, registry.ErrSkillNotFound) {
+		return ExitNotFound
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitNetwork
+	}
+
+	if isLocalFilesystemError(err) {
+		return ExitFilesystem
+	}
+
+	return ExitError
+}
+
+// isLocalFilesystemError reports whether err wraps a failed operation on a
+// local file or directory.
+func isLocalFilesystemError(err error) bool {
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	return errors.As(err, &pathErr) || errors.As(err, &linkErr)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/smy-101/gskills/internal/history"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySkill     string
+	historyOperation string
+	historyLimit     int
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historySkill, "skill", "", "只显示指定技能的记录")
+	historyCmd.Flags().StringVar(&historyOperation, "op", "", "只显示指定操作的记录 (add、update、remove、link)")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "最多显示的记录条数，0 表示全部")
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "显示技能的添加、更新、删除和链接记录",
+	Long: `显示 gskills 对技能执行过的操作，按时间从旧到新排列，默认显示最近 20 条。
+记录保存在 ~/.gskills/history.log 中，每行一条 JSON。
+
+示例:
+  gskills history
+  gskills history --skill prompt-engineer
+  gskills history --op update -n 50`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := history.Filter{Skill: historySkill, Limit: historyLimit}
+		if historyOperation != "" {
+			op, err := history.ParseOperation(historyOperation)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("--op: %w", err)}
+			}
+			filter.Operation = op
+		}
+		return executeHistory(cmd.OutOrStdout(), filter)
+	},
+}
+
+func executeHistory(w io.Writer, filter history.Filter) error {
+	if filter.Limit < 0 {
+		return &usageError{err: fmt.Errorf("--limit 不能为负数: %d", filter.Limit)}
+	}
+
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return err
+	}
+	entries, err := history.Read(dataDir)
+	if err != nil {
+		return err
+	}
+
+	entries = filter.Apply(entries)
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No history recorded.")
+		return nil
+	}
+
+	cnf := tablewriter.Config{
+		Header: tw.CellConfig{
+			Alignment: tw.CellAlignment{Global: tw.AlignCenter},
+		},
+		Row: tw.CellConfig{
+			Alignment: tw.CellAlignment{Global: tw.AlignLeft},
+		},
+	}
+
+	table := tablewriter.NewTable(w, tablewriter.WithConfig(cnf))
+	table.Header("Time", "Operation", colName, "Result", "Error")
+	for _, e := range entries {
+		table.Append(e.Time.Local().Format("2006-01-02 15:04:05"), string(e.Operation), e.Skill, e.Result, e.Error)
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/history"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/remove"
+	"github.com/smy-101/gskills/internal/testutil"
+)
+
+func TestHistory_RecordsEachOperation(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "audited-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Audited"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	projectDir := t.TempDir()
+
+	if err := executeAdd(context.Background(), srcDir, addOptions{}); err != nil {
+		t.Fatalf("executeAdd() error = %v", err)
+	}
+	if err := executeUpdate(context.Background(), "", []string{"audited-skill"}, updateOptions{assumeYes: true}); err != nil {
+		t.Fatalf("executeUpdate() error = %v", err)
+	}
+	if err := executeLink(context.Background(), "audited-skill", projectDir); err != nil {
+		t.Fatalf("executeLink() error = %v", err)
+	}
+	if err := executeLink(context.Background(), "missing-skill", projectDir); err == nil {
+		t.Fatal("executeLink() of a missing skill should fail")
+	}
+	if err := remove.RemoveSkillByName("audited-skill", true); err != nil {
+		t.Fatalf("RemoveSkillByName() error = %v", err)
+	}
+
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		t.Fatalf("DataDir() error = %v", err)
+	}
+	entries, err := history.Read(dataDir)
+	if err != nil {
+		t.Fatalf("history.Read() error = %v", err)
+	}
+
+	want := []struct {
+		op     history.Operation
+		skill  string
+		result string
+	}{
+		{history.OpAdd, "audited-skill", history.ResultSuccess},
+		{history.OpUpdate, "audited-skill", history.ResultSuccess},
+		{history.OpLink, "audited-skill", history.ResultSuccess},
+		{history.OpLink, "missing-skill", history.ResultFailure},
+		{history.OpRemove, "audited-skill", history.ResultSuccess},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("history has %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Operation != w.op || e.Skill != w.skill || e.Result != w.result {
+			t.Errorf("entry %d = %s %s %s, want %s %s %s", i, e.Operation, e.Skill, e.Result, w.op, w.skill, w.result)
+		}
+	}
+	if entries[3].Error == "" {
+		t.Error("failed link recorded without an error message")
+	}
+
+	var out bytes.Buffer
+	if err := executeHistory(&out, history.Filter{Operation: history.OpLink}); err != nil {
+		t.Fatalf("executeHistory() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "missing-skill") || strings.Contains(out.String(), "remove") {
+		t.Errorf("history --op link output:\n%s", out.String())
+	}
+}
+
+func TestHistory_Empty(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var out bytes.Buffer
+	if err := executeHistory(&out, history.Filter{}); err != nil {
+		t.Fatalf("executeHistory() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No history recorded.") {
+		t.Errorf("output = %q, want empty notice", out.String())
+	}
+}
+
+func TestHistoryCmd_InvalidFlagsAreUsageErrors(t *testing.T) {
+	testutil.TempHome(t)
+	defer func() { historyOperation, historyLimit = "", 20 }()
+
+	for _, args := range [][]string{
+		{"history", "--limit", "-1"},
+		{"history", "--op", "rename"},
+	} {
+		err := executeRoot(context.Background(), args)
+		if got := exitCode(err); got != ExitUsage {
+			t.Errorf("gskills %s: exit code = %d, want %d (error: %v)", strings.Join(args, " "), got, ExitUsage, err)
+		}
+		historyOperation, historyLimit = "", 20
+	}
+}
@@ -2,54 +2,76 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/smy-101/gskills/internal/initializer"
 	"github.com/spf13/cobra"
 )
 
+var initPrint bool
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().BoolVar(&initPrint, "print", false, "只安装二进制文件并输出 PATH 配置行，不修改 shell 配置文件")
 }
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "初始化 gskills，将二进制文件安装到 ~/.gskills/bin 并添加到 PATH",
+	Long: `初始化 gskills，将二进制文件安装到 ~/.gskills/bin 并将其添加到 shell 配置文件的 PATH 中。
+
+使用 --print 时不修改 shell 配置文件，只将 PATH 配置行输出到 stdout，
+由你自行加入 dotfiles，例如:
+  gskills init --print >> ~/.config/zsh/path.zsh`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if initPrint {
+			return executeInitPrint(os.Stdout, os.Stderr)
+		}
 		return executeInit()
 	},
 }
 
-func executeInit() error {
-	execPath, err := initializer.GetExecutablePath()
+// newInitializer returns the initializer shared by both init modes, together
+// with the path of the running gskills binary and the bin directory it is
+// installed into.
+func newInitializer() (initr *initializer.Initializer, execPath, binDir string, err error) {
+	execPath, err = initializer.GetExecutablePath()
 	if err != nil {
-		return fmt.Errorf("无法获取 gskills 可执行文件路径: %w", err)
+		return nil, "", "", fmt.Errorf("无法获取 gskills 可执行文件路径: %w", err)
 	}
+	initr = initializer.New()
+	return initr, execPath, initr.GetBinDir(), nil
+}
 
-	init := initializer.New()
-	binDir := init.GetBinDir()
+func executeInit() error {
+	initr, execPath, binDir, err := newInitializer()
+	if err != nil {
+		return err
+	}
 
-	if init.IsInPATH(binDir) {
+	if initr.IsInPATH(binDir) {
 		fmt.Println("✓ gskills 已经在 PATH 中，无需重复初始化")
 		return nil
 	}
 
 	fmt.Printf("✓ 检测到源路径: %s\n", execPath)
 
-	if err := init.InstallBinary(execPath); err != nil {
+	if err := initr.InstallBinary(execPath); err != nil {
 		return fmt.Errorf("无法安装二进制文件: %w", err)
 	}
 
 	fmt.Printf("✓ 复制二进制文件: %s/gskills\n", binDir)
 
-	shell, configPath, err := init.DetectShell()
+	shell, configPath, err := initr.DetectShell()
 	if err != nil {
 		return fmt.Errorf("无法检测 shell: %w", err)
 	}
 
 	fmt.Printf("✓ 检测到 shell: %s\n", shell)
 
-	if err := init.UpdatePATH(binDir, configPath, shell); err != nil {
+	if err := initr.UpdatePATH(binDir, configPath, shell); err != nil {
 		return fmt.Errorf("无法更新 PATH: %w", err)
 	}
 
@@ -75,3 +97,27 @@ func executeInit() error {
 	fmt.Println("\n或重新打开终端窗口。")
 	return nil
 }
+
+// executeInitPrint installs the binary like executeInit but leaves the shell
+// config alone: the PATH export for the detected shell is written to w and
+// progress goes to status, so w can be redirected into a dotfile.
+func executeInitPrint(w, status io.Writer) error {
+	initr, execPath, binDir, err := newInitializer()
+	if err != nil {
+		return err
+	}
+
+	if err := initr.InstallBinary(execPath); err != nil {
+		return fmt.Errorf("无法安装二进制文件: %w", err)
+	}
+	fmt.Fprintf(status, "✓ 复制二进制文件: %s/gskills\n", binDir)
+
+	shell, _, err := initr.DetectShell()
+	if err != nil {
+		return fmt.Errorf("无法检测 shell: %w", err)
+	}
+
+	fmt.Fprintln(w, strings.TrimSpace(initializer.GeneratePATHExport(binDir, shell)))
+	fmt.Fprintf(status, "✓ 请将以上内容加入 %s 的配置文件\n", shell)
+	return nil
+}
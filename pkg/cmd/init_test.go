@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+)
+
+func TestExecuteInitPrint(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv(paths.HomeEnv, "")
+	t.Setenv("SHELL", "/bin/zsh")
+
+	zshrc := filepath.Join(homeDir, ".zshrc")
+	if err := os.WriteFile(zshrc, []byte("# my dotfiles\n"), 0644); err != nil {
+		t.Fatalf("failed to write .zshrc: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := executeInitPrint(&out, io.Discard); err != nil {
+		t.Fatalf("executeInitPrint() error = %v", err)
+	}
+
+	binDir := filepath.Join(homeDir, ".gskills", "bin")
+	want := `export PATH="` + binDir + `:$PATH"`
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want it to contain %q", out.String(), want)
+	}
+	if _, err := os.Stat(filepath.Join(binDir, "gskills")); err != nil {
+		t.Errorf("binary not installed: %v", err)
+	}
+
+	data, err := os.ReadFile(zshrc)
+	if err != nil {
+		t.Fatalf("failed to read .zshrc: %v", err)
+	}
+	if string(data) != "# my dotfiles\n" {
+		t.Errorf(".zshrc was modified: %q", data)
+	}
+}
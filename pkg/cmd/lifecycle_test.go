@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/remove"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/update"
+)
+
+// useFakeGitHub points the manager and updater of the commands at gh.
+func useFakeGitHub(t *testing.T, gh *testutil.FakeGitHub) {
+	t.Helper()
+
+	oldNewManager, oldNewUpdater := newManager, newUpdater
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(gh.URL())
+		return manager
+	}
+	newUpdater = func(token string) *update.Updater {
+		updater := update.NewUpdater(token)
+		updater.SetBaseURL(gh.URL())
+		return updater
+	}
+	t.Cleanup(func() { newManager, newUpdater = oldNewManager, oldNewUpdater })
+}
+
+// captureStdout runs fn and returns what it printed to os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	runErr := fn()
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	r.Close()
+	return buf.String(), runErr
+}
+
+func TestLifecycle_AddListLinkUpdateRemove(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	gh := testutil.NewFakeGitHub(t)
+	useFakeGitHub(t, gh)
+
+	gh.SetBranch("owner", "repo", "main", "1111111aaaaaaa")
+	gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo v1\n")
+	gh.SetFile("owner", "repo", "skills/demo/scripts/run.sh", "echo v1\n")
+	gh.SetFile("owner", "repo", "README.md", "not part of the skill\n")
+
+	ctx := context.Background()
+	const source = "https://github.com/owner/repo/tree/main/skills/demo"
+
+	// add
+	if _, err := captureStdout(t, func() error {
+		return executeAdd(ctx, source, addOptions{})
+	}); err != nil {
+		t.Fatalf("add error = %v", err)
+	}
+	storePath := filepath.Join(dataDir, "skills", "demo")
+	if data, err := os.ReadFile(filepath.Join(storePath, "scripts", "run.sh")); err != nil || string(data) != "echo v1\n" {
+		t.Fatalf("stored run.sh = %q, %v; want %q", data, err, "echo v1\n")
+	}
+	if _, err := os.Stat(filepath.Join(storePath, "README.md")); !os.IsNotExist(err) {
+		t.Errorf("README.md outside the skill directory should not be downloaded")
+	}
+
+	// list
+	out, err := captureStdout(t, func() error { return executeList(nil, linkFilterAll, true) })
+	if err != nil {
+		t.Fatalf("list error = %v", err)
+	}
+	if !strings.Contains(out, "demo") {
+		t.Errorf("list output should contain the skill, got:\n%s", out)
+	}
+
+	// link
+	projectDir := t.TempDir()
+	var linkOut bytes.Buffer
+	if err := executeLinkTo(ctx, &linkOut, "demo", projectDir); err != nil {
+		t.Fatalf("link error = %v", err)
+	}
+	linkPath := filepath.Join(projectDir, ".opencode", "skills", "demo")
+	if data, err := os.ReadFile(filepath.Join(linkPath, "SKILL.md")); err != nil || string(data) != "# Demo v1\n" {
+		t.Fatalf("linked SKILL.md = %q, %v; want %q", data, err, "# Demo v1\n")
+	}
+
+	// update after an upstream commit
+	gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo v2\n")
+	gh.RemoveFile("owner", "repo", "skills/demo/scripts/run.sh")
+	gh.SetBranch("owner", "repo", "main", "2222222bbbbbbb")
+
+	out, err = captureStdout(t, func() error {
+		return executeUpdate(ctx, "", []string{"demo"}, updateOptions{assumeYes: true})
+	})
+	if err != nil {
+		t.Fatalf("update error = %v\n%s", err, out)
+	}
+	if data, err := os.ReadFile(filepath.Join(linkPath, "SKILL.md")); err != nil || string(data) != "# Demo v2\n" {
+		t.Errorf("linked SKILL.md after update = %q, %v; want %q", data, err, "# Demo v2\n")
+	}
+	if _, err := os.Stat(filepath.Join(storePath, "scripts", "run.sh")); !os.IsNotExist(err) {
+		t.Errorf("file deleted upstream should be gone after update")
+	}
+	skill, err := registry.FindSkillByName("demo")
+	if err != nil {
+		t.Fatalf("FindSkillByName() error = %v", err)
+	}
+	if skill.CommitSHA != "2222222bbbbbbb" {
+		t.Errorf("CommitSHA after update = %q, want %q", skill.CommitSHA, "2222222bbbbbbb")
+	}
+
+	// remove
+	if _, err := captureStdout(t, func() error { return remove.RemoveSkillByName("demo", true) }); err != nil {
+		t.Fatalf("remove error = %v", err)
+	}
+	if _, err := os.Stat(storePath); !os.IsNotExist(err) {
+		t.Errorf("store directory should be removed, stat err = %v", err)
+	}
+	if _, err := os.Lstat(linkPath); !os.IsNotExist(err) {
+		t.Errorf("project symlink should be removed, lstat err = %v", err)
+	}
+	if _, err := registry.FindSkillByName("demo"); err == nil {
+		t.Errorf("skill should no longer be registered")
+	}
+}
@@ -1,26 +1,109 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/update"
 	"github.com/spf13/cobra"
 )
 
+var (
+	infoFieldNames  []string
+	infoCheckUpdate bool
+)
+
 func init() {
 	rootCmd.AddCommand(linkInfoCmd)
+	linkInfoCmd.Flags().StringSliceVar(&infoFieldNames, "fields", nil,
+		fmt.Sprintf("只输出指定字段，每行一个 key=value，按指定顺序 (可选: %s)", strings.Join(infoFieldOrder, ",")))
+	linkInfoCmd.Flags().BoolVar(&infoCheckUpdate, "check-update", false, "同时检查该技能是否有更新，显示当前和最新的提交")
+	linkInfoCmd.MarkFlagsMutuallyExclusive("fields", "check-update")
 }
 
 var linkInfoCmd = &cobra.Command{
 	Use:   "info <skill_name>",
 	Short: "显示技能的详细链接信息",
-	Long:  `显示指定技能的详细链接信息，包括链接到的所有项目路径。`,
-	Args:  cobra.ExactArgs(1),
+	Long: `显示指定技能的详细链接信息，包括链接到的所有项目路径。
+
+使用 --fields 只输出指定字段，便于脚本读取，例如:
+  gskills info golang-pro --fields name,version,source,links
+
+使用 --check-update 在输出末尾显示该技能是否有更新；检查失败时只显示失败原因，不影响其余信息。`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeLinkInfo(args[0])
+		if len(infoFieldNames) > 0 {
+			if err := validateInfoFields(infoFieldNames); err != nil {
+				return &usageError{err: err}
+			}
+			return executeInfoFields(cmd.OutOrStdout(), args[0], infoFieldNames)
+		}
+		if err := executeLinkInfo(args[0]); err != nil {
+			return err
+		}
+		if infoCheckUpdate {
+			return executeInfoCheckUpdate(cmd.Context(), cmd.OutOrStdout(), args[0])
+		}
+		return nil
 	},
 }
 
+// infoFields are the fields info --fields can print, by name. Each returns
+// the value printed after "name=". Multi-valued fields are joined with
+// commas, in sorted order so the output is stable.
+var infoFields = map[string]func(skill *types.SkillMetadata) string{
+	"name":     func(s *types.SkillMetadata) string { return s.Name },
+	"version":  func(s *types.SkillMetadata) string { return s.Version },
+	"commit":   func(s *types.SkillMetadata) string { return s.CommitSHA },
+	"source":   func(s *types.SkillMetadata) string { return s.SourceURL },
+	"store":    func(s *types.SkillMetadata) string { return s.StorePath },
+	"updated":  func(s *types.SkillMetadata) string { return s.UpdatedAt.Format(time.RFC3339) },
+	"tags":     func(s *types.SkillMetadata) string { return strings.Join(s.Tags, ",") },
+	"checksum": func(s *types.SkillMetadata) string { return s.Checksum },
+	"policy":   func(s *types.SkillMetadata) string { return update.Policy(s) },
+	"links": func(s *types.SkillMetadata) string {
+		projects := make([]string, 0, len(s.LinkedProjects))
+		for projectPath := range s.LinkedProjects {
+			projects = append(projects, projectPath)
+		}
+		sort.Strings(projects)
+		return strings.Join(projects, ",")
+	},
+}
+
+// infoFieldOrder lists the names of infoFields for help and error messages.
+var infoFieldOrder = []string{"name", "version", "commit", "source", "store", "updated", "tags", "checksum", "policy", "links"}
+
+// validateInfoFields checks that every name in fields is a known field.
+func validateInfoFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := infoFields[field]; !ok {
+			return fmt.Errorf("unknown field '%s' (valid fields: %s)", field, strings.Join(infoFieldOrder, ", "))
+		}
+	}
+	return nil
+}
+
+// executeInfoFields prints the requested fields of skillName to w, one
+// key=value line per field in the order given. fields must have been
+// checked with validateInfoFields.
+func executeInfoFields(w io.Writer, skillName string, fields []string) error {
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("failed to find skill: %w", err)
+	}
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s=%s\n", field, infoFields[field](skill))
+	}
+	return nil
+}
+
 func executeLinkInfo(skillName string) error {
 	skill, err := registry.FindSkillByName(skillName)
 	if err != nil {
@@ -31,6 +114,19 @@ func executeLinkInfo(skillName string) error {
 	fmt.Printf("Version: %s\n", skill.Version)
 	fmt.Printf("Source: %s\n", skill.SourceURL)
 	fmt.Printf("Store Path: %s\n", skill.StorePath)
+	fmt.Printf("Tags: %s\n", formatTags(skill.Tags))
+	if skill.Unverified {
+		fmt.Println("Unverified: added with --skip-skill-check, SKILL.md was not checked")
+	}
+	if skill.IgnoreApplied {
+		fmt.Println("Ignore: files matched by .gskillsignore were not downloaded")
+	}
+	if skill.Checksum != "" {
+		fmt.Printf("Checksum: %s (verified on add)\n", skill.Checksum)
+	}
+	if skill.UpdatePolicy != "" {
+		fmt.Printf("Update Policy: %s\n", skill.UpdatePolicy)
+	}
 	fmt.Printf("\n")
 
 	if len(skill.LinkedProjects) == 0 {
@@ -41,10 +137,62 @@ func executeLinkInfo(skillName string) error {
 	fmt.Printf("Linked to %d project(s):\n", len(skill.LinkedProjects))
 	for projectPath, linkInfo := range skill.LinkedProjects {
 		fmt.Printf("  • %s\n", projectPath)
-		fmt.Printf("    Symlink: %s\n", linkInfo.SymlinkPath)
+		if linkInfo.Copy {
+			fmt.Printf("    Copy: %s\n", linkInfo.SymlinkPath)
+		} else {
+			fmt.Printf("    Symlink: %s\n", linkInfo.SymlinkPath)
+		}
+		if linkInfo.Alias != "" {
+			fmt.Printf("    Alias: %s\n", linkInfo.Alias)
+		}
 		fmt.Printf("    Linked: %s\n", linkInfo.LinkedAt.Format("2006-01-02 15:04"))
+		fmt.Printf("    Linked version: %s\n", formatLinkedVersion(skill, linkInfo))
 		fmt.Printf("\n")
 	}
 
 	return nil
 }
+
+// executeInfoCheckUpdate checks skillName for an update and prints the
+// result to w as one "Update:" line. A failed check is reported on that
+// line instead of failing info.
+func executeInfoCheckUpdate(ctx context.Context, w io.Writer, skillName string) error {
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("failed to find skill: %w", err)
+	}
+	if update.IsLocalSkill(skill) {
+		fmt.Fprintf(w, "Update: local skill, re-copied from its source by 'gskills update %s'\n", skill.Name)
+		return nil
+	}
+
+	updater := newUpdater(configString("github_token"))
+	configureUpdater(updater)
+	hasUpdate, newSHA, err := updater.CheckUpdate(ctx, skill)
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "Update: check failed - %v\n", err)
+	case hasUpdate:
+		fmt.Fprintf(w, "Update: available (current %s, latest %s), run 'gskills update %s'\n", shortSHA(skill.CommitSHA), shortSHA(newSHA), skill.Name)
+	default:
+		fmt.Fprintf(w, "Update: up to date (commit %s)\n", shortSHA(skill.CommitSHA))
+	}
+	return nil
+}
+
+// formatLinkedVersion describes the version a project was linked at and how
+// it relates to the version now in the store.
+func formatLinkedVersion(skill *types.SkillMetadata, linkInfo types.LinkedProjectInfo) string {
+	if linkInfo.CommitSHA == "" {
+		return "unknown"
+	}
+
+	linked := shortSHA(linkInfo.CommitSHA)
+	if linkInfo.Version != "" {
+		linked = linkInfo.Version + "@" + linked
+	}
+	if linkInfo.CommitSHA == skill.CommitSHA {
+		return linked + " (current)"
+	}
+	return fmt.Sprintf("%s (store updated to %s since linking)", linked, shortSHA(skill.CommitSHA))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/update"
+)
+
+func TestExecuteInfoFields(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "golang-pro@main",
+		Name:      "golang-pro",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/golang-pro",
+		StorePath: "/store/golang-pro",
+		UpdatedAt: time.Now(),
+		Tags:      []string{"backend", "go"},
+		LinkedProjects: map[string]types.LinkedProjectInfo{
+			"/work/b": {SymlinkPath: "/work/b/.opencode/skills/golang-pro"},
+			"/work/a": {SymlinkPath: "/work/a/.opencode/skills/golang-pro"},
+		},
+	}); err != nil {
+		t.Fatalf("failed to add skill: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "subset in requested order",
+			fields: []string{"version", "name", "links"},
+			want:   "version=main\nname=golang-pro\nlinks=/work/a,/work/b\n",
+		},
+		{
+			name:   "source and tags",
+			fields: []string{"source", "tags", "checksum"},
+			want:   "source=https://github.com/owner/repo/tree/main/skills/golang-pro\ntags=backend,go\nchecksum=\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateInfoFields(tt.fields); err != nil {
+				t.Fatalf("validateInfoFields() error = %v", err)
+			}
+			var buf bytes.Buffer
+			if err := executeInfoFields(&buf, "golang-pro", tt.fields); err != nil {
+				t.Fatalf("executeInfoFields() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if err := validateInfoFields([]string{"name", "author"}); err == nil {
+		t.Error("validateInfoFields() should reject an unknown field")
+	}
+}
+
+func TestInfoCmd_CheckUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		failure  int
+		wantLine string
+	}{
+		{name: "outdated", head: "def4567890", wantLine: "Update: available (current abc1234, latest def4567), run 'gskills update golang-pro'"},
+		{name: "up to date", head: "abc1234567", wantLine: "Update: up to date (commit abc1234)"},
+		{name: "check failed", head: "def4567890", failure: http.StatusInternalServerError, wantLine: "Update: check failed - "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.TempHome(t)
+			gh := testutil.NewFakeGitHub(t)
+			gh.SetBranch("owner", "repo", "main", tt.head)
+			if tt.failure != 0 {
+				gh.SetFailure("/repos/owner/repo/commits/main", tt.failure)
+			}
+
+			oldNewUpdater := newUpdater
+			newUpdater = func(token string) *update.Updater {
+				updater := update.NewUpdater(token)
+				updater.SetBaseURL(gh.URL())
+				return updater
+			}
+			defer func() { newUpdater = oldNewUpdater }()
+			defer func() { infoCheckUpdate = false }()
+
+			if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+				ID:        "golang-pro@main",
+				Name:      "golang-pro",
+				Version:   "main",
+				CommitSHA: "abc1234567",
+				SourceURL: "https://github.com/owner/repo/tree/main/skills/golang-pro",
+				StorePath: t.TempDir(),
+				UpdatedAt: time.Now(),
+			}); err != nil {
+				t.Fatalf("failed to add skill: %v", err)
+			}
+
+			output, err := captureStdout(t, func() error {
+				return executeRoot(context.Background(), []string{"info", "golang-pro", "--check-update"})
+			})
+			if err != nil {
+				t.Fatalf("info --check-update error = %v", err)
+			}
+			if !strings.Contains(output, "Skill: golang-pro") {
+				t.Errorf("output %q lost the skill details", output)
+			}
+			if !strings.Contains(output, tt.wantLine) {
+				t.Errorf("output %q does not contain %q", output, tt.wantLine)
+			}
+		})
+	}
+}
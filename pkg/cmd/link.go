@@ -4,13 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/smy-101/gskills/internal/link"
 	"github.com/spf13/cobra"
 )
 
+var (
+	linkDryRun bool
+	linkAlias  string
+	linkTarget string
+	linkCopy   bool
+)
+
 func init() {
 	rootCmd.AddCommand(linkCmd)
+	linkCmd.Flags().StringVar(&linkAlias, "as", "", "以指定名称创建符号链接 (.opencode/skills/<name>)，而不是技能名称")
+	linkCmd.Flags().StringVar(&linkTarget, "target", "", "在项目内的指定目录中创建符号链接 (如 .claude/skills)，而不是自动检测")
+	linkCmd.Flags().BoolVar(&linkCopy, "copy", false, "将技能复制到项目中，而不是创建符号链接 (更新技能时会重新复制)")
+	linkCmd.Flags().BoolVar(&linkDryRun, "dry-run", false, "只显示将要创建的符号链接，不实际创建，也不修改注册表")
 }
 
 var linkCmd = &cobra.Command{
@@ -23,8 +36,16 @@ var linkCmd = &cobra.Command{
 示例:
   gskills link prompt-engineer
   gskills link prompt-engineer /home/user/myproject
+  gskills link prompt-engineer --dry-run
+  gskills link my-prompt-engineer --as pe
+  gskills link prompt-engineer --target .cursor/skills
+  gskills link prompt-engineer --copy
 
-当不提供path_to_project时，默认使用当前目录。这将在项目的.opencode/skills/<skill_name>创建一个符号链接，指向~/.gskills/skills/<skill_name>。`,
+当不提供path_to_project时，默认使用当前目录。这将在项目的.opencode/skills/<skill_name>创建一个符号链接，指向~/.gskills/skills/<skill_name>。
+项目中存在 .claude 或 .cursor 目录（且没有 .opencode）时，改为链接到 .claude/skills 或 .cursor/skills；
+使用 --target 可显式指定链接目录。
+使用 --copy 时将技能复制到该位置而不是创建符号链接，适用于不支持符号链接的工具或需要同步到其他机器的项目；
+update 会重新复制，unlink 会删除复制的目录。`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 || len(args) > 2 {
 			return errors.New("用法: gskills link <skill_name> [path_to_project]")
@@ -37,21 +58,71 @@ var linkCmd = &cobra.Command{
 		if len(args) == 2 {
 			projectPath = args[1]
 		}
-		return executeLink(skillName, projectPath)
+		if linkDryRun {
+			return executeLinkDryRun(cmd.OutOrStdout(), skillName, linkAlias, linkTarget, projectPath, linkCopy)
+		}
+		return executeLinkAs(cmd.Context(), os.Stdout, skillName, linkAlias, linkTarget, projectPath, linkCopy)
 	},
 }
 
-func executeLink(skillName, projectPath string) error {
-	linker := link.NewLinker()
-	ctx := context.Background()
+func executeLink(ctx context.Context, skillName, projectPath string) error {
+	return executeLinkTo(ctx, os.Stdout, skillName, projectPath)
+}
 
-	fmt.Printf("Linking skill '%s' to project '%s'...\n", skillName, projectPath)
+// executeLinkTo links skillName into projectPath and prints progress to w.
+func executeLinkTo(ctx context.Context, w io.Writer, skillName, projectPath string) error {
+	return executeLinkAs(ctx, w, skillName, "", "", projectPath, false)
+}
+
+// executeLinkAs links skillName into the targetDir of projectPath under
+// alias, and prints progress to w. An empty alias uses the skill name and an
+// empty targetDir is detected from the project's agent framework. With
+// copyMode the skill is copied instead of symlinked.
+func executeLinkAs(ctx context.Context, w io.Writer, skillName, alias, targetDir, projectPath string, copyMode bool) error {
+	manager := newManager(configString("github_token"))
+	if targetDir == "" {
+		targetDir = link.DetectTargetDir(projectPath)
+	}
+	manager.SetLinkTargetDir(targetDir)
+	manager.SetLinkCopy(copyMode)
+
+	linkName := skillName
+	if alias != "" {
+		linkName = alias
+		fmt.Fprintf(w, "Linking skill '%s' as '%s' to project '%s'...\n", skillName, alias, projectPath)
+	} else {
+		fmt.Fprintf(w, "Linking skill '%s' to project '%s'...\n", skillName, projectPath)
+	}
 
-	if err := linker.LinkSkill(ctx, skillName, projectPath); err != nil {
+	if err := manager.LinkAs(ctx, skillName, projectPath, alias); err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully linked skill '%s' to project '%s'\n", skillName, projectPath)
-	fmt.Printf("Skill symlink created at: %s/.opencode/skills/%s\n", projectPath, skillName)
+	fmt.Fprintf(w, "Successfully linked skill '%s' to project '%s'\n", skillName, projectPath)
+	if copyMode {
+		fmt.Fprintf(w, "Skill copied to: %s/%s/%s\n", projectPath, targetDir, linkName)
+	} else {
+		fmt.Fprintf(w, "Skill symlink created at: %s/%s/%s\n", projectPath, targetDir, linkName)
+	}
+	return nil
+}
+
+// executeLinkDryRun prints the symlink, or with copyMode the copy, that
+// executeLinkAs would create, without creating it or touching the registry.
+func executeLinkDryRun(w io.Writer, skillName, alias, targetDir, projectPath string, copyMode bool) error {
+	manager := newManager(configString("github_token"))
+	manager.SetLinkTargetDir(targetDir)
+	plan, err := manager.PlanLink(skillName, projectPath, alias)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Dry run: would link skill '%s' to project '%s'\n", skillName, plan.ProjectPath)
+	if copyMode {
+		fmt.Fprintf(w, "  Copy:    %s\n", plan.TargetPath)
+	} else {
+		fmt.Fprintf(w, "  Symlink: %s\n", plan.TargetPath)
+	}
+	fmt.Fprintf(w, "  Target:  %s\n", plan.SkillPath)
 	return nil
 }
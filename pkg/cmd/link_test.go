@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -184,9 +185,9 @@ func TestExecuteLink(t *testing.T) {
 
 			var err error
 			if tt.projectPath == "" {
-				err = executeLink(tt.skillName, projectDir)
+				err = executeLink(context.Background(), tt.skillName, projectDir)
 			} else {
-				err = executeLink(tt.skillName, tt.projectPath)
+				err = executeLink(context.Background(), tt.skillName, tt.projectPath)
 			}
 
 			if (err != nil) != tt.wantErr {
@@ -247,7 +248,7 @@ func TestExecuteLink_DefaultToCurrentDirectory(t *testing.T) {
 	os.Chdir(projectDir)
 	defer os.Chdir(originalWd)
 
-	if err := executeLink("default-test-skill", "."); err != nil {
+	if err := executeLink(context.Background(), "default-test-skill", "."); err != nil {
 		t.Fatalf("executeLink() failed: %v", err)
 	}
 
@@ -258,3 +259,60 @@ func TestExecuteLink_DefaultToCurrentDirectory(t *testing.T) {
 		t.Error("target is not a symlink")
 	}
 }
+
+func TestExecuteLinkDryRun(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	skillDir := filepath.Join(homeDir, ".gskills", "skills", "dry-skill")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatalf("failed to create test skill directory: %v", err)
+	}
+	if err := registry.AddOrUpdateSkill(&types.SkillMetadata{
+		ID:        "dry-skill@main",
+		Name:      "dry-skill",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://example.com/dry",
+		StorePath: skillDir,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("failed to add test skill to registry: %v", err)
+	}
+	registryPath := filepath.Join(homeDir, ".gskills", "skills.json")
+	before, err := os.ReadFile(registryPath)
+	if err != nil {
+		t.Fatalf("failed to read registry: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	var buf bytes.Buffer
+	if err := executeLinkDryRun(&buf, "dry-skill", "", "", projectDir, false); err != nil {
+		t.Fatalf("executeLinkDryRun() error = %v", err)
+	}
+
+	targetPath := filepath.Join(projectDir, ".opencode", "skills", "dry-skill")
+	output := buf.String()
+	for _, want := range []string{targetPath, skillDir} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %s, got:\n%s", want, output)
+		}
+	}
+
+	if _, err := os.Lstat(filepath.Join(projectDir, ".opencode")); !os.IsNotExist(err) {
+		t.Errorf("dry run created files in the project: %v", err)
+	}
+	after, err := os.ReadFile(registryPath)
+	if err != nil {
+		t.Fatalf("failed to read registry: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("dry run changed the registry")
+	}
+
+	if err := executeLinkDryRun(&buf, "ghost", "", "", projectDir, false); err == nil {
+		t.Error("dry run of an unknown skill should fail")
+	}
+}
@@ -3,10 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/tw"
-	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/tag"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +17,47 @@ const (
 	colUpdatedAt = "Updated At"
 	colSourceURL = "Source URL"
 	colLinks     = "Links"
+	colTags      = "Tags"
 	emptyMsg     = "No skills installed yet."
 	usageHint    = "Use 'gskills add <url>' to install a skill."
+	missingMark  = " (missing)"
 )
 
+var (
+	listTags     []string
+	listNoVerify bool
+	listLinked   bool
+	listUnlinked bool
+)
+
+// linkFilter selects skills by whether they are linked into any project.
+type linkFilter int
+
+const (
+	linkFilterAll linkFilter = iota
+	linkFilterLinked
+	linkFilterUnlinked
+)
+
+// matches reports whether a skill with linkCount project links passes the filter.
+func (f linkFilter) matches(linkCount int) bool {
+	switch f {
+	case linkFilterLinked:
+		return linkCount > 0
+	case linkFilterUnlinked:
+		return linkCount == 0
+	default:
+		return true
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "只列出带有指定标签的技能 (可重复指定，需同时满足)")
+	listCmd.Flags().BoolVar(&listNoVerify, "no-verify", false, "不检查技能目录是否存在 (加快列出速度)")
+	listCmd.Flags().BoolVar(&listLinked, "linked", false, "只列出已链接到至少一个项目的技能")
+	listCmd.Flags().BoolVar(&listUnlinked, "unlinked", false, "只列出未链接到任何项目的技能")
+	listCmd.MarkFlagsMutuallyExclusive("linked", "unlinked")
 }
 
 var listCmd = &cobra.Command{
@@ -29,13 +65,24 @@ var listCmd = &cobra.Command{
 	Short: "列出所有已安装的技能",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeList()
+		links := linkFilterAll
+		switch {
+		case listLinked:
+			links = linkFilterLinked
+		case listUnlinked:
+			links = linkFilterUnlinked
+		}
+		return executeList(listTags, links, !listNoVerify)
 	},
 }
 
-// executeList loads the registry and displays a table of all installed skills.
-func executeList() error {
-	skills, err := registry.LoadRegistry()
+// executeList loads the registry and displays a table of the installed
+// skills. If tags is not empty, only skills carrying all of them are shown;
+// links further keeps only linked or only unlinked skills. With verify,
+// skills whose store directory no longer exists are marked as missing; they
+// are not removed from the registry.
+func executeList(tags []string, links linkFilter, verify bool) error {
+	skills, err := newManager(configString("github_token")).List()
 	if err != nil {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
@@ -46,6 +93,40 @@ func executeList() error {
 		return nil
 	}
 
+	if len(tags) > 0 {
+		filtered := skills[:0]
+		for i := range skills {
+			if tag.HasAll(&skills[i], tags) {
+				filtered = append(filtered, skills[i])
+			}
+		}
+		skills = filtered
+
+		if len(skills) == 0 {
+			fmt.Printf("No skills tagged with %s.\n", strings.Join(tags, ", "))
+			return nil
+		}
+	}
+
+	if links != linkFilterAll {
+		filtered := skills[:0]
+		for i := range skills {
+			if links.matches(len(skills[i].LinkedProjects)) {
+				filtered = append(filtered, skills[i])
+			}
+		}
+		skills = filtered
+
+		if len(skills) == 0 {
+			if links == linkFilterLinked {
+				fmt.Println("No linked skills.")
+			} else {
+				fmt.Println("No unlinked skills.")
+			}
+			return nil
+		}
+	}
+
 	cnf := tablewriter.Config{
 		Header: tw.CellConfig{
 			Alignment: tw.CellAlignment{Global: tw.AlignCenter},
@@ -56,11 +137,18 @@ func executeList() error {
 	}
 
 	table := tablewriter.NewTable(os.Stdout, tablewriter.WithConfig(cnf))
-	table.Header(colName, colUpdatedAt, colSourceURL, colLinks)
+	table.Header(colName, colUpdatedAt, colSourceURL, colLinks, colTags)
 
+	var missing int
 	for _, skill := range skills {
 		updatedAt := skill.UpdatedAt.Format(dateFormat)
 
+		name := skill.Name
+		if verify && storeMissing(skill.StorePath) {
+			name += missingMark
+			missing++
+		}
+
 		var linksInfo string
 		if len(skill.LinkedProjects) > 0 {
 			count := len(skill.LinkedProjects)
@@ -76,7 +164,7 @@ func executeList() error {
 			linksInfo = "-"
 		}
 
-		table.Append(skill.Name, updatedAt, skill.SourceURL, linksInfo)
+		table.Append(name, updatedAt, skill.SourceURL, linksInfo, formatTags(skill.Tags))
 	}
 
 	if err := table.Render(); err != nil {
@@ -84,6 +172,17 @@ func executeList() error {
 	}
 
 	fmt.Printf("\nTotal: %d skills\n", len(skills))
+	if missing > 0 {
+		fmt.Printf("%d skill(s) missing from the store. Run 'gskills doctor' for details, or 'gskills doctor --fix' to remove them from the registry.\n", missing)
+	}
 
 	return nil
 }
+
+// storeMissing reports whether the store directory of a skill is gone, e.g.
+// because it was deleted by hand. Errors other than non-existence are not
+// reported as missing.
+func storeMissing(storePath string) bool {
+	_, err := os.Stat(storePath)
+	return os.IsNotExist(err)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,7 +10,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
 	"github.com/smy-101/gskills/internal/types"
 )
 
@@ -164,7 +167,7 @@ func TestExecuteList(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := executeList()
+			err := executeList(nil, linkFilterAll, true)
 
 			w.Close()
 			os.Stdout = oldStdout
@@ -188,3 +191,226 @@ func TestExecuteList(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteList_FilterByTag(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	for _, s := range []types.SkillMetadata{
+		{ID: "react@main", Name: "react", SourceURL: "https://github.com/o/r/tree/main/react", StorePath: "/tmp/react", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), Tags: []string{"frontend"}},
+		{ID: "vue@main", Name: "vue", SourceURL: "https://github.com/o/r/tree/main/vue", StorePath: "/tmp/vue", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), Tags: []string{"frontend", "legacy"}},
+		{ID: "essay@main", Name: "essay", SourceURL: "https://github.com/o/r/tree/main/essay", StorePath: "/tmp/essay", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), Tags: []string{"writing"}},
+	} {
+		s := s
+		if err := registry.AddOrUpdateSkill(&s); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	capture := func(tags []string) string {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := executeList(tags, linkFilterAll, true)
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		r.Close()
+
+		if err != nil {
+			t.Fatalf("executeList(%v) error = %v", tags, err)
+		}
+		return buf.String()
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		notWant []string
+	}{
+		{name: "single tag", tags: []string{"frontend"}, want: []string{"react", "vue", "Total: 2 skills"}, notWant: []string{"essay"}},
+		{name: "all tags required", tags: []string{"frontend", "legacy"}, want: []string{"vue", "Total: 1 skills"}, notWant: []string{"react", "essay"}},
+		{name: "no match", tags: []string{"backend"}, want: []string{"No skills tagged with backend."}, notWant: []string{"react", "vue", "essay"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := capture(tt.tags)
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("output should contain %q, got:\n%s", w, out)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(out, "/tree/main/"+nw) {
+					t.Errorf("output should not contain skill %q, got:\n%s", nw, out)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteList_FilterByLinks(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	linked := map[string]types.LinkedProjectInfo{"/work/app": {SymlinkPath: "/work/app/.claude/skills/x", LinkedAt: time.Now()}}
+	for _, s := range []types.SkillMetadata{
+		{ID: "react@main", Name: "react", SourceURL: "https://github.com/o/r/tree/main/react", StorePath: "/tmp/react", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), Tags: []string{"frontend"}, LinkedProjects: linked},
+		{ID: "vue@main", Name: "vue", SourceURL: "https://github.com/o/r/tree/main/vue", StorePath: "/tmp/vue", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), Tags: []string{"frontend"}},
+		{ID: "essay@main", Name: "essay", SourceURL: "https://github.com/o/r/tree/main/essay", StorePath: "/tmp/essay", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now(), LinkedProjects: linked},
+	} {
+		s := s
+		if err := registry.AddOrUpdateSkill(&s); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		links   linkFilter
+		want    []string
+		notWant []string
+	}{
+		{name: "linked", links: linkFilterLinked, want: []string{"react", "essay", "Total: 2 skills"}, notWant: []string{"vue"}},
+		{name: "unlinked", links: linkFilterUnlinked, want: []string{"vue", "Total: 1 skills"}, notWant: []string{"react", "essay"}},
+		{name: "linked with tag", tags: []string{"frontend"}, links: linkFilterLinked, want: []string{"react", "Total: 1 skills"}, notWant: []string{"vue", "essay"}},
+		{name: "no match", tags: []string{"frontend", "legacy"}, links: linkFilterAll, want: []string{"No skills tagged"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := captureStdout(t, func() error { return executeList(tt.tags, tt.links, false) })
+			if err != nil {
+				t.Fatalf("executeList() error = %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("output should contain %q, got:\n%s", w, out)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(out, "/tree/main/"+nw) {
+					t.Errorf("output should not contain skill %q, got:\n%s", nw, out)
+				}
+			}
+		})
+	}
+}
+
+func TestListCmd_LinkedFlagsExclusive(t *testing.T) {
+	testutil.TempHome(t)
+	defer func() {
+		listLinked, listUnlinked = false, false
+		listCmd.Flags().Lookup("linked").Changed = false
+		listCmd.Flags().Lookup("unlinked").Changed = false
+	}()
+
+	err := executeRoot(context.Background(), []string{"list", "--linked", "--unlinked"})
+	if err == nil || !strings.Contains(err.Error(), "linked") {
+		t.Errorf("list --linked --unlinked error = %v, want the flags rejected together", err)
+	}
+}
+
+func TestListCmd_RegistryFlag(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// The default registry holds a different skill that must not be listed.
+	defaultRegistry := filepath.Join(homeDir, ".gskills", "skills.json")
+	if err := registry.SaveRegistryWithPath(defaultRegistry, []types.SkillMetadata{
+		{ID: "default-skill@main", Name: "default-skill", SourceURL: "https://github.com/o/r/tree/main/default-skill", StorePath: "/tmp/default-skill", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now()},
+	}); err != nil {
+		t.Fatalf("failed to write default registry: %v", err)
+	}
+	customRegistry := createTestRegistry(t, []types.SkillMetadata{
+		{ID: "client-skill@main", Name: "client-skill", SourceURL: "https://github.com/o/r/tree/main/client-skill", StorePath: "/tmp/client-skill", Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now()},
+	})
+	defer func() {
+		rootRegistry = ""
+		paths.SetRegistryPath("")
+	}()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := executeRoot(context.Background(), []string{"list", "--registry", customRegistry})
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	_ = r.Close()
+
+	if err != nil {
+		t.Fatalf("list --registry error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "client-skill") {
+		t.Errorf("output should list the skill from --registry, got:\n%s", output)
+	}
+	if strings.Contains(output, "default-skill") {
+		t.Errorf("output should not list the default registry, got:\n%s", output)
+	}
+}
+
+func TestExecuteList_MissingStore(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	presentPath := filepath.Join(homeDir, ".gskills", "skills", "present")
+	if err := os.MkdirAll(presentPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []types.SkillMetadata{
+		{ID: "present@main", Name: "present", SourceURL: "https://github.com/o/r/tree/main/present", StorePath: presentPath, Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now()},
+		{ID: "deleted@main", Name: "deleted", SourceURL: "https://github.com/o/r/tree/main/deleted", StorePath: filepath.Join(homeDir, ".gskills", "skills", "deleted"), Version: "main", CommitSHA: "abc123", UpdatedAt: time.Now()},
+	} {
+		s := s
+		if err := registry.AddOrUpdateSkill(&s); err != nil {
+			t.Fatalf("failed to add skill: %v", err)
+		}
+	}
+
+	out, err := captureStdout(t, func() error { return executeList(nil, linkFilterAll, true) })
+	if err != nil {
+		t.Fatalf("executeList() error = %v", err)
+	}
+	if !strings.Contains(out, "deleted (missing)") {
+		t.Errorf("missing skill should be marked, got:\n%s", out)
+	}
+	if strings.Contains(out, "present (missing)") {
+		t.Errorf("present skill should not be marked, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1 skill(s) missing") || !strings.Contains(out, "gskills doctor") {
+		t.Errorf("output should suggest doctor, got:\n%s", out)
+	}
+
+	out, err = captureStdout(t, func() error { return executeList(nil, linkFilterAll, false) })
+	if err != nil {
+		t.Fatalf("executeList() error = %v", err)
+	}
+	if strings.Contains(out, "(missing)") {
+		t.Errorf("--no-verify should skip the check, got:\n%s", out)
+	}
+
+	if _, err := registry.FindSkillByName("deleted"); err != nil {
+		t.Errorf("list must not remove the missing skill from the registry: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/smy-101/gskills/internal/relocate"
+	"github.com/spf13/cobra"
+)
+
+var migrateXDG bool
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateXDG, "xdg", false, "将 ~/.gskills 迁移到 $XDG_DATA_HOME/gskills 和 $XDG_CONFIG_HOME/gskills")
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "迁移旧版本的链接数据",
+	Long: `将旧版本写入注册表的 linked- 链接条目迁移为技能的 linked_projects 记录。
+
+无法匹配到已安装技能的条目会保留在注册表中并列出。
+
+使用 --xdg 时改为将 ~/.gskills 整体迁移到 XDG 目录: 配置文件移到
+$XDG_CONFIG_HOME/gskills（未设置时与数据放在一起），其余内容移到
+$XDG_DATA_HOME/gskills，并更新注册表中的存放路径和项目中的符号链接。
+
+示例:
+  gskills migrate
+  XDG_DATA_HOME=~/.local/share XDG_CONFIG_HOME=~/.config gskills migrate --xdg`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateXDG {
+			return executeMigrateXDG(cmd.OutOrStdout())
+		}
+		return executeMigrate(cmd.OutOrStdout())
+	},
+}
+
+func executeMigrate(w io.Writer) error {
+	linker := link.NewLinker()
+
+	legacy, err := linker.CheckLegacyLinks()
+	if err != nil {
+		return fmt.Errorf("迁移失败: %w", err)
+	}
+	if len(legacy) == 0 {
+		fmt.Fprintln(w, "没有需要迁移的旧版链接数据")
+		return nil
+	}
+
+	fmt.Fprintf(w, "发现 %d 个旧版链接条目，正在迁移...\n", len(legacy))
+
+	report, err := linker.MigrateLegacyLinks()
+	if err != nil {
+		return fmt.Errorf("迁移失败: %w", err)
+	}
+
+	fmt.Fprintln(w, "\n迁移完成！")
+	fmt.Fprintf(w, "• 迁移了 %d 个链接\n", report.Migrated)
+	if len(report.Unresolved) > 0 {
+		fmt.Fprintf(w, "• %d 个条目无法迁移（对应技能未安装或链接路径无效）:\n", len(report.Unresolved))
+		for _, id := range report.Unresolved {
+			fmt.Fprintf(w, "    %s\n", id)
+		}
+	}
+	return nil
+}
+
+// executeMigrateXDG moves ~/.gskills to the XDG base directories.
+func executeMigrateXDG(w io.Writer) error {
+	if os.Getenv(paths.HomeEnv) != "" {
+		return fmt.Errorf("已设置 %s，数据目录由它决定，无需迁移", paths.HomeEnv)
+	}
+
+	from, err := paths.LegacyDataDir()
+	if err != nil {
+		return fmt.Errorf("迁移失败: %w", err)
+	}
+
+	report, err := relocate.Migrate(from)
+	if errors.Is(err, relocate.ErrNoXDG) {
+		return fmt.Errorf("迁移失败: 请先设置 %s（可选设置 %s）", paths.XDGDataHomeEnv, paths.XDGConfigHomeEnv)
+	}
+	if report == nil && err == nil {
+		fmt.Fprintf(w, "没有需要迁移的 %s 目录\n", from)
+		return nil
+	}
+	if report != nil {
+		fmt.Fprintf(w, "已将 %s 移动到 %s\n", report.From, report.DataDir)
+	}
+	if err != nil {
+		return fmt.Errorf("迁移失败: %w", err)
+	}
+
+	fmt.Fprintln(w, "\n迁移完成！")
+	fmt.Fprintf(w, "• 数据目录: %s\n", report.DataDir)
+	fmt.Fprintf(w, "• 配置目录: %s\n", report.ConfigDir)
+	fmt.Fprintf(w, "• 更新了 %d 个技能的存放路径\n", report.Skills)
+	fmt.Fprintf(w, "• 重新指向了 %d 个项目链接\n", report.Links)
+	fmt.Fprintf(w, "\n提示: bin 目录已移动到 %s，请相应更新 shell 配置中的 PATH\n", filepath.Join(report.DataDir, "bin"))
+	return nil
+}
+
+// warnLegacyLinks prints a hint to run 'gskills migrate' when the registry
+// still holds legacy link entries. Failures are ignored so that a broken
+// registry is reported by the command itself rather than by this check.
+func warnLegacyLinks(w io.Writer) {
+	legacy, err := link.NewLinker().CheckLegacyLinks()
+	if err != nil || len(legacy) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "提示: 注册表中有 %d 个旧版链接条目，请运行 'gskills migrate' 进行迁移\n", len(legacy))
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestMigrateCmd_ConvertsLegacyEntry(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("GSKILLS_HOME", "")
+
+	projectDir := t.TempDir()
+	symlinkPath := filepath.Join(projectDir, ".opencode", "skills", "golang")
+	if err := registry.SaveRegistry([]types.SkillMetadata{
+		{
+			ID:        "owner/repo/golang",
+			Name:      "golang",
+			StorePath: filepath.Join(homeDir, ".gskills", "skills", "golang"),
+			Version:   "main",
+			CommitSHA: "abc123",
+		},
+		{
+			ID:        "linked-golang",
+			Name:      "golang",
+			StorePath: symlinkPath,
+		},
+	}); err != nil {
+		t.Fatalf("failed to seed registry: %v", err)
+	}
+
+	var warning bytes.Buffer
+	warnLegacyLinks(&warning)
+	if !strings.Contains(warning.String(), "gskills migrate") {
+		t.Errorf("warnLegacyLinks() = %q, want a hint to run gskills migrate", warning.String())
+	}
+
+	var out bytes.Buffer
+	migrateCmd.SetOut(&out)
+	defer migrateCmd.SetOut(nil)
+	rootCmd.SetArgs([]string{"migrate"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("migrate error = %v", err)
+	}
+	if !strings.Contains(out.String(), "迁移了 1 个链接") {
+		t.Errorf("output = %q, want a summary with 1 migrated link", out.String())
+	}
+
+	skills, err := registry.LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	if len(skills) != 1 || skills[0].ID != "owner/repo/golang" {
+		t.Fatalf("registry = %+v, want only the installed skill", skills)
+	}
+	if info, ok := skills[0].LinkedProjects[projectDir]; !ok || info.SymlinkPath != symlinkPath {
+		t.Errorf("LinkedProjects = %v, want %s linked at %s", skills[0].LinkedProjects, projectDir, symlinkPath)
+	}
+
+	warning.Reset()
+	warnLegacyLinks(&warning)
+	if warning.Len() != 0 {
+		t.Errorf("warnLegacyLinks() after migrate = %q, want no output", warning.String())
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/smy-101/gskills/internal/update"
+	"github.com/smy-101/gskills/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var outdatedJSON bool
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().BoolVar(&outdatedJSON, "json", false, "以 JSON 格式输出")
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "列出有可用更新的技能",
+	Long: `检查所有技能的更新，只列出有可用更新的技能，显示当前与最新的 commit SHA。
+当版本号为语义化版本时，还会显示版本差异 (major/minor/patch)。
+
+示例:
+  gskills outdated
+  gskills outdated --json
+
+本命令只做检查，不会修改任何技能；使用 'gskills update' 执行更新。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeOutdated(cmd.Context(), cmd.OutOrStdout(), configString("github_token"), outdatedJSON)
+	},
+}
+
+// outdatedEntry is one row of the outdated report.
+type outdatedEntry struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version,omitempty"`
+	LatestVersion  string `json:"latest_version,omitempty"`
+	VersionDelta   string `json:"version_delta,omitempty"`
+	CurrentSHA     string `json:"current_sha"`
+	LatestSHA      string `json:"latest_sha"`
+	SourceURL      string `json:"source_url"`
+}
+
+func executeOutdated(ctx context.Context, w io.Writer, token string, asJSON bool) error {
+	updater := newUpdater(token)
+	configureUpdater(updater)
+
+	infos, err := updater.CheckAllUpdates(ctx)
+	if err != nil {
+		return fmt.Errorf("检查更新失败: %w", err)
+	}
+
+	entries := outdatedEntries(infos)
+	if asJSON {
+		return writeOutdatedJSON(w, entries)
+	}
+	return writeOutdatedTable(w, entries)
+}
+
+// outdatedEntries keeps only the skills with an available update.
+func outdatedEntries(infos []update.SkillUpdateInfo) []outdatedEntry {
+	entries := []outdatedEntry{}
+	for _, info := range infos {
+		if info.Status != update.UpdateStatusAvailable {
+			continue
+		}
+		entries = append(entries, outdatedEntry{
+			Name:           info.Skill.Name,
+			CurrentVersion: info.Skill.Version,
+			LatestVersion:  info.NewVersion,
+			VersionDelta:   version.Delta(info.Skill.Version, info.NewVersion),
+			CurrentSHA:     info.Skill.CommitSHA,
+			LatestSHA:      info.NewCommitSHA,
+			SourceURL:      info.Skill.SourceURL,
+		})
+	}
+	return entries
+}
+
+func writeOutdatedJSON(w io.Writer, entries []outdatedEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+func writeOutdatedTable(w io.Writer, entries []outdatedEntry) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "All skills are up to date.")
+		return nil
+	}
+
+	cnf := tablewriter.Config{
+		Header: tw.CellConfig{
+			Alignment: tw.CellAlignment{Global: tw.AlignCenter},
+		},
+		Row: tw.CellConfig{
+			Alignment: tw.CellAlignment{Global: tw.AlignLeft},
+		},
+	}
+
+	table := tablewriter.NewTable(w, tablewriter.WithConfig(cnf))
+	table.Header(colName, "Version", "Current", "Latest", "Delta")
+
+	for _, e := range entries {
+		ver := e.CurrentVersion
+		if e.LatestVersion != "" && e.LatestVersion != e.CurrentVersion {
+			ver = fmt.Sprintf("%s → %s", e.CurrentVersion, e.LatestVersion)
+		}
+		delta := e.VersionDelta
+		if delta == "" {
+			delta = "-"
+		}
+		table.Append(e.Name, ver, shortSHA(e.CurrentSHA), shortSHA(e.LatestSHA), delta)
+	}
+
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%d skill(s) outdated. Run 'gskills update' to update them.\n", len(entries))
+	return nil
+}
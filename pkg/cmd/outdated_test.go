@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/update"
+)
+
+func mixedUpdateInfos() []update.SkillUpdateInfo {
+	return []update.SkillUpdateInfo{
+		{
+			Skill:        &types.SkillMetadata{Name: "branch-skill", Version: "main", CommitSHA: "aaaaaaa1111"},
+			Status:       update.UpdateStatusAvailable,
+			NewCommitSHA: "bbbbbbb2222",
+			NewVersion:   "main",
+		},
+		{
+			Skill:        &types.SkillMetadata{Name: "tagged-skill", Version: "v1.2.0", CommitSHA: "ccccccc3333"},
+			Status:       update.UpdateStatusAvailable,
+			NewCommitSHA: "ddddddd4444",
+			NewVersion:   "v1.3.0",
+		},
+		{
+			Skill:  &types.SkillMetadata{Name: "current-skill", Version: "main", CommitSHA: "eeeeeee5555"},
+			Status: update.UpdateStatusUpToDate,
+		},
+		{
+			Skill:  &types.SkillMetadata{Name: "broken-skill", Version: "main", CommitSHA: "fffffff6666"},
+			Status: update.UpdateStatusFailed,
+			Error:  errors.New("network down"),
+		},
+		{
+			Skill:  &types.SkillMetadata{Name: "local-skill", Version: "local", CommitSHA: "local"},
+			Status: update.UpdateStatusSkipped,
+		},
+	}
+}
+
+func TestOutdated_OnlyListsAvailableUpdates(t *testing.T) {
+	entries := outdatedEntries(mixedUpdateInfos())
+
+	var buf bytes.Buffer
+	if err := writeOutdatedTable(&buf, entries); err != nil {
+		t.Fatalf("writeOutdatedTable() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"branch-skill", "aaaaaaa", "bbbbbbb", "tagged-skill", "v1.2.0 → v1.3.0", "minor", "2 skill(s) outdated"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"current-skill", "broken-skill", "local-skill"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("table output should not contain %q:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestOutdated_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeOutdatedJSON(&buf, outdatedEntries(mixedUpdateInfos())); err != nil {
+		t.Fatalf("writeOutdatedJSON() error = %v", err)
+	}
+
+	var got []outdatedEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	if got[0].Name != "branch-skill" || got[0].VersionDelta != "" || got[0].LatestSHA != "bbbbbbb2222" {
+		t.Errorf("branch entry = %+v", got[0])
+	}
+	if got[1].Name != "tagged-skill" || got[1].VersionDelta != "minor" {
+		t.Errorf("tagged entry = %+v", got[1])
+	}
+}
+
+func TestOutdated_NoneOutdated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeOutdatedJSON(&buf, outdatedEntries(nil)); err != nil {
+		t.Fatalf("writeOutdatedJSON() error = %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("JSON output = %q, want []", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeOutdatedTable(&buf, nil); err != nil {
+		t.Fatalf("writeOutdatedTable() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "All skills are up to date.") {
+		t.Errorf("table output = %q", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/update"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy <skill_name> [auto|manual|pinned|latest-tag]",
+	Short: "查看或设置技能的更新策略",
+	Long: `查看或设置已安装技能的更新策略。不指定策略时显示当前策略。
+
+更新策略:
+  auto        默认策略，检查和更新所有技能时一并更新
+  manual      检查和更新所有技能时跳过，只在 'gskills update <skill_name>' 时更新
+  pinned      从不更新
+  latest-tag  更新到最新的发布标签 (有 --tag-pattern 时只考虑匹配的标签)，仅适用于从标签添加的技能
+
+添加技能时可使用 'gskills add <url> --policy <策略>' 直接设置。
+
+示例:
+  gskills policy golang-pro
+  gskills policy golang-pro pinned
+  gskills policy prompt-engineer latest-tag`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return errors.New("用法: gskills policy <skill_name> [auto|manual|pinned|latest-tag]")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 {
+			return executeSetPolicy(cmd.OutOrStdout(), args[0], args[1])
+		}
+		return executeShowPolicy(cmd.OutOrStdout(), args[0])
+	},
+}
+
+// executeShowPolicy prints the update policy of skillName to w.
+func executeShowPolicy(w io.Writer, skillName string) error {
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("failed to find skill: %w", err)
+	}
+	fmt.Fprintf(w, "Update policy of '%s': %s\n", skill.Name, update.Policy(skill))
+	return nil
+}
+
+// executeSetPolicy records policy as the update policy of skillName.
+func executeSetPolicy(w io.Writer, skillName, policy string) error {
+	if _, err := add.ParseUpdatePolicy(policy); err != nil {
+		return &usageError{err: err}
+	}
+	skill, err := update.SetPolicy(skillName, policy)
+	if err != nil {
+		return fmt.Errorf("failed to set update policy: %w", err)
+	}
+	fmt.Fprintf(w, "Update policy of '%s': %s\n", skill.Name, update.Policy(skill))
+	return nil
+}
+
+// policyFlagUsage is the help text of the --policy flag.
+var policyFlagUsage = "技能的更新策略: " + strings.Join(add.UpdatePolicies, "、") + " (见 'gskills policy --help')"
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestPolicyCmd(t *testing.T) {
+	testutil.TempHome(t)
+	skill := &types.SkillMetadata{
+		ID:        "demo@main",
+		Name:      "demo",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/skills/demo",
+		CommitSHA: "abc123",
+		RefType:   add.RefTypeBranch,
+		StorePath: t.TempDir(),
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput string
+		wantCode   int
+		wantPolicy string
+	}{
+		{name: "default", args: []string{"policy", "demo"}, wantOutput: "Update policy of 'demo': auto"},
+		{name: "set", args: []string{"policy", "demo", "manual"}, wantOutput: "Update policy of 'demo': manual", wantPolicy: add.UpdatePolicyManual},
+		{name: "show", args: []string{"policy", "demo"}, wantOutput: "Update policy of 'demo': manual", wantPolicy: add.UpdatePolicyManual},
+		{name: "invalid policy", args: []string{"policy", "demo", "nightly"}, wantCode: ExitUsage, wantPolicy: add.UpdatePolicyManual},
+		{name: "latest-tag on a branch", args: []string{"policy", "demo", "latest-tag"}, wantCode: 1, wantPolicy: add.UpdatePolicyManual},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := captureStdout(t, func() error {
+				return executeRoot(context.Background(), tt.args)
+			})
+			if got := exitCode(err); got != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (error: %v)", got, tt.wantCode, err)
+			}
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("output %q does not contain %q", output, tt.wantOutput)
+			}
+
+			skill, err := registry.FindSkillByName("demo")
+			if err != nil {
+				t.Fatalf("FindSkillByName() error = %v", err)
+			}
+			if skill.UpdatePolicy != tt.wantPolicy {
+				t.Errorf("UpdatePolicy = %q, want %q", skill.UpdatePolicy, tt.wantPolicy)
+			}
+		})
+	}
+}
+
+func TestAddCmd_Policy(t *testing.T) {
+	testutil.TempHome(t)
+	gh := testutil.NewFakeGitHub(t)
+	gh.SetBranch("owner", "repo", "main", "abc123")
+	gh.SetFile("owner", "repo", "skills/demo/SKILL.md", "# Demo")
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(gh.URL())
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+	defer func() { addPolicy, addQuiet = "", false }()
+
+	source := "https://github.com/owner/repo/tree/main/skills/demo"
+	if err := executeRoot(context.Background(), []string{"add", source, "--quiet", "--policy", "Pinned"}); err != nil {
+		t.Fatalf("add --policy error = %v", err)
+	}
+	skill, err := registry.FindSkillByName("demo")
+	if err != nil {
+		t.Fatalf("skill not registered: %v", err)
+	}
+	if skill.UpdatePolicy != add.UpdatePolicyPinned {
+		t.Errorf("UpdatePolicy = %q, want pinned", skill.UpdatePolicy)
+	}
+
+	err = executeRoot(context.Background(), []string{"add", source, "--quiet", "--policy", "latest-tag"})
+	if err == nil || !strings.Contains(err.Error(), "requires a tag URL") {
+		t.Errorf("add --policy latest-tag from a branch error = %v, want a tag URL error", err)
+	}
+
+	err = executeRoot(context.Background(), []string{"add", source, "--quiet", "--policy", "nightly"})
+	if got := exitCode(err); got != ExitUsage {
+		t.Errorf("add --policy nightly exit code = %d, want %d (error: %v)", got, ExitUsage, err)
+	}
+}
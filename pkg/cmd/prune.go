@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smy-101/gskills/internal/prompt"
+	"github.com/smy-101/gskills/internal/remove"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneUnlinked  bool
+	pruneOlderThan string
+	pruneYes       bool
+)
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().BoolVar(&pruneUnlinked, "unlinked", false, "只删除未链接到任何项目的技能")
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "只删除超过指定时间未更新的技能，例如 720h、30d、2w")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "不询问直接删除 (非交互环境中必须指定)")
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "删除未使用的技能",
+	Long: `列出并删除未使用的技能，以回收磁盘空间。
+
+至少需要指定一个过滤条件；同时指定时，技能需满足全部条件：
+  --unlinked            未链接到任何项目
+  --older-than <时长>   超过指定时间未更新 (支持 d 天、w 周，如 30d)
+
+删除前会列出候选技能并询问确认；非交互环境中需要 --yes。
+技能的删除方式与 'gskills remove' 相同，并记录在历史中。
+
+示例:
+  gskills prune --unlinked
+  gskills prune --older-than 90d
+  gskills prune --unlinked --older-than 30d --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := remove.PruneFilter{Unlinked: pruneUnlinked}
+		if pruneOlderThan != "" {
+			age, err := parseAge(pruneOlderThan)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("无效的 --older-than: %w", err)}
+			}
+			filter.OlderThan = age
+		}
+		if !filter.IsSet() {
+			return &usageError{err: fmt.Errorf("请至少指定 --unlinked 或 --older-than")}
+		}
+		return executePrune(cmd.OutOrStdout(), filter, pruneYes)
+	},
+}
+
+// parseAge parses a duration such as "720h", extended with whole days ("30d")
+// and weeks ("2w"). The age must be positive.
+func parseAge(s string) (time.Duration, error) {
+	var age time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return 0, fmt.Errorf("%q: expected a number of days or weeks", s)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			age *= 7
+		}
+	default:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+		age = d
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("%q: must be positive", s)
+	}
+	return age, nil
+}
+
+// executePrune lists the skills selected by filter and removes them once
+// confirmed. Removal goes through the same manager as 'gskills remove', so
+// the store directory, registry entry and history are handled alike.
+func executePrune(w io.Writer, filter remove.PruneFilter, assumeYes bool) error {
+	manager := newManager(configString("github_token"))
+	skills, err := manager.List()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	candidates := remove.PruneCandidates(skills, filter, time.Now())
+	if len(candidates) == 0 {
+		fmt.Fprintln(w, "没有需要清理的技能")
+		return nil
+	}
+
+	fmt.Fprintf(w, "以下 %d 个技能将被删除:\n", len(candidates))
+	for _, skill := range candidates {
+		links := "未链接"
+		if n := len(skill.LinkedProjects); n > 0 {
+			links = fmt.Sprintf("%d 个链接", n)
+		}
+		fmt.Fprintf(w, "  • %s (更新于 %s, %s)\n", skill.Name, skill.UpdatedAt.Format("2006-01-02"), links)
+	}
+
+	if !assumeYes {
+		if !prompt.IsInteractive() {
+			fmt.Fprintln(w, "非交互环境，未删除；使用 --yes 直接删除")
+			return nil
+		}
+		confirmed, err := prompt.Confirm(fmt.Sprintf("删除这 %d 个技能?", len(candidates)))
+		if err != nil {
+			return fmt.Errorf("读取输入失败: %w", err)
+		}
+		if !confirmed {
+			fmt.Fprintln(w, "已取消")
+			return nil
+		}
+	}
+
+	var failed int
+	for _, skill := range candidates {
+		if err := remove.RemoveSkill(manager, skill.Name, true); err != nil {
+			failed++
+			fmt.Fprintf(w, "  ✗ %s: %v\n", skill.Name, err)
+			continue
+		}
+		fmt.Fprintf(w, "  ✓ %s 已删除\n", skill.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 个技能删除失败", failed)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/remove"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30d", want: 30 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "36h", want: 36 * time.Hour},
+		{input: "0d", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExecutePrune(t *testing.T) {
+	seed := func(t *testing.T) string {
+		t.Helper()
+		dataDir := testutil.TempHome(t)
+		project := t.TempDir()
+		for _, s := range []struct {
+			name   string
+			age    time.Duration
+			linked bool
+		}{
+			{name: "stale", age: 90 * 24 * time.Hour},
+			{name: "fresh", age: time.Hour},
+			{name: "in-use", age: 90 * 24 * time.Hour, linked: true},
+		} {
+			storePath := filepath.Join(dataDir, "skills", s.name)
+			if err := os.MkdirAll(storePath, 0755); err != nil {
+				t.Fatal(err)
+			}
+			skill := &types.SkillMetadata{
+				ID:        s.name + "@main",
+				Name:      s.name,
+				Version:   "main",
+				CommitSHA: "abc123",
+				SourceURL: "https://github.com/owner/repo/tree/main/" + s.name,
+				StorePath: storePath,
+				UpdatedAt: time.Now().Add(-s.age),
+			}
+			if s.linked {
+				skill.LinkedProjects = map[string]types.LinkedProjectInfo{
+					project: {SymlinkPath: filepath.Join(project, ".opencode", "skills", s.name), LinkedAt: time.Now()},
+				}
+			}
+			if err := registry.AddOrUpdateSkill(skill); err != nil {
+				t.Fatalf("failed to add skill to registry: %v", err)
+			}
+		}
+		return dataDir
+	}
+
+	remaining := func(t *testing.T) []string {
+		t.Helper()
+		skills, err := registry.LoadRegistry()
+		if err != nil {
+			t.Fatalf("LoadRegistry() error = %v", err)
+		}
+		var names []string
+		for _, s := range skills {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name        string
+		filter      remove.PruneFilter
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{
+			name:        "unlinked",
+			filter:      remove.PruneFilter{Unlinked: true},
+			wantRemoved: []string{"fresh", "stale"},
+			wantKept:    []string{"in-use"},
+		},
+		{
+			name:        "unlinked and older than",
+			filter:      remove.PruneFilter{Unlinked: true, OlderThan: 30 * 24 * time.Hour},
+			wantRemoved: []string{"stale"},
+			wantKept:    []string{"fresh", "in-use"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := seed(t)
+
+			var out bytes.Buffer
+			if _, err := captureStdout(t, func() error { return executePrune(&out, tt.filter, true) }); err != nil {
+				t.Fatalf("executePrune() error = %v\n%s", err, out.String())
+			}
+
+			kept := strings.Join(remaining(t), ",")
+			for _, name := range tt.wantRemoved {
+				if strings.Contains(","+kept+",", ","+name+",") {
+					t.Errorf("%s should be pruned, registry still has %s", name, kept)
+				}
+				if _, err := os.Stat(filepath.Join(dataDir, "skills", name)); !os.IsNotExist(err) {
+					t.Errorf("store directory of %s should be removed", name)
+				}
+			}
+			for _, name := range tt.wantKept {
+				if !strings.Contains(","+kept+",", ","+name+",") {
+					t.Errorf("%s should be kept, registry has %s", name, kept)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/smy-101/gskills/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryPathCmd)
+}
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "查看技能注册表信息",
+}
+
+var registryPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "显示技能注册表文件路径",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeRegistryPath(cmd.OutOrStdout())
+	},
+}
+
+// executeRegistryPath prints the resolved path of the skills registry,
+// honoring GSKILLS_HOME and --registry. The file does not need to exist yet.
+func executeRegistryPath(w io.Writer) error {
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, paths.RegistryPath(dataDir))
+	return nil
+}
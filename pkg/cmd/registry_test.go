@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/paths"
+)
+
+func TestExecuteRegistryPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		gskillsHome bool
+	}{
+		{name: "default under HOME"},
+		{name: "GSKILLS_HOME override", gskillsHome: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			t.Setenv("HOME", homeDir)
+			t.Setenv(paths.HomeEnv, "")
+
+			want := filepath.Join(homeDir, ".gskills", "skills.json")
+			if tt.gskillsHome {
+				dataDir := t.TempDir()
+				t.Setenv(paths.HomeEnv, dataDir)
+				want = filepath.Join(dataDir, "skills.json")
+			}
+
+			var buf bytes.Buffer
+			if err := executeRegistryPath(&buf); err != nil {
+				t.Fatalf("executeRegistryPath() error = %v", err)
+			}
+
+			dataDir, err := paths.DataDir()
+			if err != nil {
+				t.Fatalf("DataDir() error = %v", err)
+			}
+			got := strings.TrimSpace(buf.String())
+			if got != paths.RegistryPath(dataDir) || got != want {
+				t.Errorf("registry path = %s, want %s", got, want)
+			}
+		})
+	}
+}
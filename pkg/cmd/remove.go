@@ -8,8 +8,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var removeYes bool
+
 func init() {
 	rootCmd.AddCommand(removeCmd)
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "不询问直接删除 (非交互环境中必须指定)")
 }
 
 var removeCmd = &cobra.Command{
@@ -23,7 +26,7 @@ var removeCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		skillName := args[0]
-		if err := remove.RemoveSkillByName(skillName); err != nil {
+		if err := remove.RemoveSkill(newManager(configString("github_token")), skillName, removeYes); err != nil {
 			if err.Error() == "operation cancelled" {
 				fmt.Println("Operation cancelled")
 				return nil
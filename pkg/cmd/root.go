@@ -1,12 +1,34 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/smy-101/gskills/internal/paths"
 	"github.com/spf13/cobra"
 )
 
+// rootTimeout bounds the total run time of a command when set via --timeout.
+// It also replaces the built-in per-operation timeouts of add and update.
+var rootTimeout time.Duration
+
+// rootRegistry points every command at an alternate registry file when set
+// via --registry.
+var rootRegistry string
+
+// rootCancel releases the --timeout context once the command has finished.
+var rootCancel context.CancelFunc = func() {}
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&rootTimeout, "timeout", 0, "命令的最长执行时间，例如 30s、5m；同时覆盖内置的请求与下载超时")
+	rootCmd.PersistentFlags().StringVar(&rootRegistry, "registry", "", "使用指定的技能注册表文件，而不是 ~/.gskills/skills.json")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "gskills",
 	Short: "gskills CLI",
@@ -19,11 +41,72 @@ var rootCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
+
+	// 所有子命令共享同一个根 context：信号处理和 --timeout 都作用于它
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("timeout") && rootTimeout <= 0 {
+			return fmt.Errorf("--timeout 必须为正数: %s", rootTimeout)
+		}
+		if err := paths.SetRegistryPath(rootRegistry); err != nil {
+			return fmt.Errorf("--registry 无效: %w", err)
+		}
+		if rootTimeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), rootTimeout)
+			cmd.SetContext(ctx)
+			rootCancel = cancel
+		}
+		// The config commands must keep working with an invalid value,
+		// so that it can be corrected with 'gskills config set'.
+		if !isConfigCommand(cmd) {
+			if _, err := githubServer(); err != nil {
+				return err
+			}
+		}
+		if cmd != migrateCmd {
+			warnLegacyLinks(cmd.ErrOrStderr())
+		}
+		return nil
+	},
+}
+
+// isConfigCommand reports whether cmd is 'gskills config' or one of its
+// subcommands.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd {
+			return true
+		}
+	}
+	return false
 }
 
+// Execute runs the root command with a context that is cancelled on SIGINT
+// or SIGTERM, so long-running commands such as add and update can stop
+// in-flight downloads and clean up their temporary directories.
+// Commands receive this context through cmd.Context(); --timeout further
+// bounds it with a deadline.
+//
+// On failure the error is printed to stderr, unless the command already
+// reported it, and the process exits with the code exitCode assigns to it.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := executeRoot(ctx, os.Args[1:])
+	stop()
+	if err != nil {
+		var reported *reportedError
+		if !errors.As(err, &reported) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
+
+// executeRoot runs the command line args with ctx.
+func executeRoot(ctx context.Context, args []string) error {
+	markUsageErrorsOnce.Do(func() { markUsageErrors(rootCmd) })
+	rootCmd.SetArgs(args)
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.ExecuteContext(ctx)
+	rootCancel()
+	return err
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/link"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/tidy"
+	"github.com/smy-101/gskills/internal/update"
+)
+
+func TestTimeoutFlag_AbortsSlowAdd(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+
+	defer resetTimeoutFlag()
+	rootCmd.SetArgs([]string{"add", "https://github.com/owner/repo/tree/main/skill", "--timeout", "100ms"})
+	defer rootCmd.SetArgs(nil)
+
+	start := time.Now()
+	err := rootCmd.ExecuteContext(context.Background())
+	rootCancel()
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("add with --timeout expected error against slow server, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("add took %v, --timeout did not abort the operation", elapsed)
+	}
+}
+
+// resetTimeoutFlag restores --timeout after a test so that later commands
+// run without it, including the flag's Changed state.
+func resetTimeoutFlag() {
+	rootTimeout = 0
+	rootCmd.PersistentFlags().Lookup("timeout").Changed = false
+}
+
+func TestTimeoutFlag_Validation(t *testing.T) {
+	defer resetTimeoutFlag()
+	rootCmd.SetArgs([]string{"list", "--timeout", "-1s"})
+	defer rootCmd.SetArgs(nil)
+
+	err := rootCmd.ExecuteContext(context.Background())
+	if err == nil {
+		t.Fatal("expected error for non-positive --timeout, got nil")
+	}
+	if !strings.Contains(err.Error(), "--timeout") {
+		t.Errorf("error = %v, want error mentioning --timeout", err)
+	}
+}
+
+func TestExecuteRoot_ExitCodes(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+
+	srcDir := filepath.Join(t.TempDir(), "stored-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Stored"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	// A regular file where --store expects a directory makes the copy fail.
+	blockedStore := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blockedStore, []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	defer func() { removeYes, addStore = false, "" }()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"missing argument", []string{"link"}, ExitUsage},
+		{"unknown flag", []string{"list", "--no-such-flag"}, ExitUsage},
+		{"unknown command", []string{"no-such-command"}, ExitUsage},
+		{"remove unknown skill", []string{"remove", "ghost", "--yes"}, ExitNotFound},
+		{"link unknown skill", []string{"link", "ghost", t.TempDir()}, ExitNotFound},
+		{"GitHub API failure", []string{"add", "https://github.com/owner/repo/tree/main/skill"}, ExitNetwork},
+		{"store is not a directory", []string{"add", srcDir, "--store", filepath.Join(blockedStore, "skills")}, ExitFilesystem},
+		{"success", []string{"list"}, ExitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			defer rootCmd.SetOut(nil)
+			defer rootCmd.SetErr(nil)
+
+			err := executeRoot(context.Background(), tt.args)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("gskills %s: exit code = %d, want %d (error: %v)", strings.Join(tt.args, " "), got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestExitCode_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"plain error", errors.New("boom"), ExitError},
+		{"invalid URL", &add.DownloadError{Type: add.ErrorTypeInvalidURL}, ExitUsage},
+		{"rate limit", fmt.Errorf("wrapped: %w", &add.DownloadError{Type: add.ErrorTypeRateLimit}), ExitNetwork},
+		{"download filesystem", &add.DownloadError{Type: add.ErrorTypeFilesystem}, ExitFilesystem},
+		{"repository access", &add.DownloadError{Type: add.ErrorTypeAccess}, ExitNetwork},
+		{"repository not found", &add.DownloadError{Type: add.ErrorTypeNotFound}, ExitNotFound},
+		{"download limit", &add.DownloadError{Type: add.ErrorTypeLimit}, ExitError},
+		{"checksum mismatch", &add.DownloadError{Type: add.ErrorTypeChecksum}, ExitError},
+		{"skill exists", &add.DownloadError{Type: add.ErrorTypeExists}, ExitError},
+		{"symlink path", &link.LinkError{Type: link.ErrorTypeInvalidPath}, ExitUsage},
+		{"link filesystem", &link.LinkError{Type: link.ErrorTypeFilesystem}, ExitFilesystem},
+		{"update check", &update.UpdateError{Type: update.UpdateErrorTypeCheck}, ExitNetwork},
+		{"update download write", &update.UpdateError{Type: update.UpdateErrorTypeDownload, Err: &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}}, ExitFilesystem},
+		{"update unparseable source", &update.UpdateError{Type: update.UpdateErrorTypeSource}, ExitUsage},
+		{"update filesystem", &update.UpdateError{Type: update.UpdateErrorTypeFilesystem}, ExitFilesystem},
+		{"update registry", &update.UpdateError{Type: update.UpdateErrorTypeRegistry}, ExitFilesystem},
+		{"update not found", &update.UpdateError{Type: update.UpdateErrorTypeNotFound}, ExitNotFound},
+		{"update without manifest", &update.UpdateError{Type: update.UpdateErrorTypeInvalid, Err: add.ErrSkillManifestMissing}, ExitError},
+		{"tidy registry", &tidy.TidyError{Type: tidy.ErrorTypeRegistry}, ExitFilesystem},
+		{"tidy path", &tidy.TidyError{Type: tidy.ErrorTypeInvalidPath}, ExitUsage},
+		{"registry lookup", fmt.Errorf("lookup: %w", registry.ErrSkillNotFound), ExitNotFound},
+		{"path error", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, ExitFilesystem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
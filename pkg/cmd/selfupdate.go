@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/smy-101/gskills/internal/initializer"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var selfUpdateForce bool
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "即使当前已是最新版本(或为开发版本)也重新安装最新 release")
+}
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "将 ~/.gskills/bin/gskills 更新到最新的 release 版本",
+	Long: `从 GitHub releases 下载适用于当前系统和架构的最新 gskills 二进制文件，
+校验 checksums.txt 中记录的 SHA-256 后替换 ~/.gskills/bin/gskills。
+
+示例:
+  gskills self-update
+  gskills self-update --force
+
+新文件先写入临时文件再重命名覆盖，因此可以安全地更新正在运行的 gskills。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeSelfUpdate(cmd.Context(), selfUpdateForce)
+	},
+}
+
+func executeSelfUpdate(ctx context.Context, force bool) error {
+	current := version.Get().Version
+	client := newGitHubClient()
+
+	fmt.Println("正在检查最新版本...")
+	release, err := client.GetLatestRelease(ctx, version.RepoOwner, version.RepoName)
+	if err != nil {
+		return fmt.Errorf("无法获取最新版本: %w", err)
+	}
+
+	if !force {
+		cmp, err := version.Compare(current, release.TagName)
+		if err != nil {
+			return fmt.Errorf("当前为开发版本 (%s)，无法与 %s 比较；如需安装请使用 --force", current, release.TagName)
+		}
+		if cmp >= 0 {
+			fmt.Printf("✓ gskills 已是最新版本 (%s)\n", current)
+			return nil
+		}
+	}
+
+	assetName := initializer.ReleaseAssetName(runtime.GOOS, runtime.GOARCH)
+	binaryAsset := findReleaseAsset(release, assetName)
+	if binaryAsset == nil {
+		return fmt.Errorf("release %s 中没有适用于 %s/%s 的文件 %s", release.TagName, runtime.GOOS, runtime.GOARCH, assetName)
+	}
+	checksumsAsset := findReleaseAsset(release, initializer.ChecksumsAssetName)
+	if checksumsAsset == nil {
+		return fmt.Errorf("release %s 中没有 %s，无法校验下载文件", release.TagName, initializer.ChecksumsAssetName)
+	}
+
+	fmt.Printf("正在下载 %s (%s)...\n", assetName, release.TagName)
+	binary, err := client.DownloadFile(ctx, binaryAsset.BrowserDownloadURL)
+	if err != nil {
+		return fmt.Errorf("无法下载 %s: %w", assetName, err)
+	}
+	checksums, err := client.DownloadFile(ctx, checksumsAsset.BrowserDownloadURL)
+	if err != nil {
+		return fmt.Errorf("无法下载 %s: %w", initializer.ChecksumsAssetName, err)
+	}
+
+	tmpFile, err := os.CreateTemp("", "gskills-update-*")
+	if err != nil {
+		return fmt.Errorf("无法创建临时文件: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(binary); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("无法写入临时文件: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("无法写入临时文件: %w", err)
+	}
+
+	want, err := initializer.LookupChecksum(checksums, assetName)
+	if err != nil {
+		return err
+	}
+	if err := initializer.VerifyChecksum(tmpPath, want); err != nil {
+		return err
+	}
+	fmt.Println("✓ 校验和验证通过")
+
+	initr := initializer.New()
+	if err := initr.InstallBinary(tmpPath); err != nil {
+		return fmt.Errorf("无法安装二进制文件: %w", err)
+	}
+
+	fmt.Printf("✓ gskills 已更新到 %s: %s/gskills\n", release.TagName, initr.GetBinDir())
+	return nil
+}
+
+// findReleaseAsset returns the asset of release with the given name, or nil.
+func findReleaseAsset(release *types.GitHubRelease, name string) *types.GitHubReleaseAsset {
+	for i := range release.Assets {
+		if release.Assets[i].Name == name {
+			return &release.Assets[i]
+		}
+	}
+	return nil
+}
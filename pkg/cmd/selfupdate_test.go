@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/initializer"
+	"github.com/smy-101/gskills/internal/version"
+)
+
+// setupReleaseServer serves a latest release v1.1.0 whose binary asset for
+// the current platform contains binary and whose checksums file records
+// checksum for it.
+func setupReleaseServer(t *testing.T, binary []byte, checksum string) {
+	t.Helper()
+
+	assetName := initializer.ReleaseAssetName(runtime.GOOS, runtime.GOARCH)
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/smy-101/gskills/releases/latest":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"tag_name": "v1.1.0",
+				"assets": []map[string]interface{}{
+					{"name": assetName, "browser_download_url": ts.URL + "/download/" + assetName},
+					{"name": "checksums.txt", "browser_download_url": ts.URL + "/download/checksums.txt"},
+				},
+			})
+		case "/download/" + assetName:
+			w.Write(binary)
+		case "/download/checksums.txt":
+			fmt.Fprintf(w, "%s  %s\n", checksum, assetName)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	t.Cleanup(func() { newManager = oldNewManager })
+}
+
+// setupInstalledBinary points HOME at a temp dir with an installed
+// ~/.gskills/bin/gskills and returns its path.
+func setupInstalledBinary(t *testing.T) string {
+	t.Helper()
+
+	homeDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	t.Cleanup(func() { os.Setenv("HOME", oldHome) })
+
+	oldVersion := version.Version
+	version.Version = "v1.0.0"
+	t.Cleanup(func() { version.Version = oldVersion })
+
+	binPath := filepath.Join(homeDir, ".gskills", "bin", "gskills")
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		t.Fatalf("failed to create bin dir: %v", err)
+	}
+	if err := os.WriteFile(binPath, []byte("old-binary"), 0755); err != nil {
+		t.Fatalf("failed to write binary: %v", err)
+	}
+	return binPath
+}
+
+func TestSelfUpdate_SwapsBinary(t *testing.T) {
+	binPath := setupInstalledBinary(t)
+
+	newBinary := []byte("new-binary")
+	sum := sha256.Sum256(newBinary)
+	setupReleaseServer(t, newBinary, hex.EncodeToString(sum[:]))
+
+	if err := executeSelfUpdate(context.Background(), false); err != nil {
+		t.Fatalf("executeSelfUpdate() error = %v", err)
+	}
+
+	got, err := os.ReadFile(binPath)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if string(got) != "new-binary" {
+		t.Errorf("binary = %q, want %q", string(got), "new-binary")
+	}
+
+	info, err := os.Stat(binPath)
+	if err != nil {
+		t.Fatalf("failed to stat binary: %v", err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("binary is not executable: %v", info.Mode())
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(binPath))
+	if err != nil {
+		t.Fatalf("failed to read bin dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("bin dir has %d entries, want only gskills", len(entries))
+	}
+}
+
+func TestSelfUpdate_ChecksumMismatchKeepsBinary(t *testing.T) {
+	binPath := setupInstalledBinary(t)
+	setupReleaseServer(t, []byte("tampered-binary"), "0000000000000000000000000000000000000000000000000000000000000000")
+
+	if err := executeSelfUpdate(context.Background(), false); err == nil {
+		t.Fatal("executeSelfUpdate() should fail on a checksum mismatch")
+	}
+
+	got, err := os.ReadFile(binPath)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if string(got) != "old-binary" {
+		t.Errorf("binary = %q, want it unchanged", string(got))
+	}
+}
+
+func TestSelfUpdate_AlreadyLatest(t *testing.T) {
+	binPath := setupInstalledBinary(t)
+	version.Version = "v1.1.0"
+	setupReleaseServer(t, []byte("new-binary"), "unused")
+
+	if err := executeSelfUpdate(context.Background(), false); err != nil {
+		t.Fatalf("executeSelfUpdate() error = %v", err)
+	}
+
+	got, err := os.ReadFile(binPath)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if string(got) != "old-binary" {
+		t.Errorf("binary = %q, want it unchanged", string(got))
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/markdown"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// defaultPager pages long output when $PAGER is not set. -R lets less pass
+// the ANSI styles through.
+const defaultPager = "less -R"
+
+var (
+	showRaw     bool
+	showNoPager bool
+)
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().BoolVar(&showRaw, "raw", false, "原样输出 SKILL.md，不渲染")
+	showCmd.Flags().BoolVar(&showNoPager, "no-pager", false, "内容较长时也不使用分页器")
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <skill-name>",
+	Short: "在终端中显示技能的 SKILL.md",
+	Long: `在终端中渲染并显示技能的 SKILL.md。
+
+内容超过一屏时使用 $PAGER (默认 less -R) 分页。输出不是终端（如管道、重定向）时原样输出。
+
+示例:
+  gskills show prompt-engineer
+  gskills show prompt-engineer --raw
+  gskills show prompt-engineer | grep name`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeShow(cmd.OutOrStdout(), args[0], showRaw, showNoPager)
+	},
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal. It is a variable so
+// tests can simulate one.
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// terminalHeight returns the number of rows of the terminal on stdout, or 0
+// if it is unknown.
+var terminalHeight = func() int {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// runPager pipes content through the pager command. It is a variable so
+// tests can capture paged output.
+var runPager = func(pager, content string) error {
+	fields := strings.Fields(pager)
+	c := exec.Command(fields[0], fields[1:]...)
+	c.Stdin = strings.NewReader(content)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// executeShow writes the SKILL.md of skillName to w. On a terminal the
+// Markdown is rendered unless raw is set, and content taller than the
+// terminal goes through the pager unless noPager is set.
+func executeShow(w io.Writer, skillName string, raw, noPager bool) error {
+	skill, err := registry.FindSkillByName(skillName)
+	if err != nil {
+		return fmt.Errorf("failed to find skill: %w", err)
+	}
+
+	manifest, err := add.FindSkillManifest(skill.StorePath)
+	if err != nil {
+		return fmt.Errorf("failed to read skill directory '%s': %w", skill.StorePath, err)
+	}
+	if manifest == "" {
+		return fmt.Errorf("SKILL.md not found in '%s'", skill.StorePath)
+	}
+
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", manifest, err)
+	}
+	content := string(data)
+
+	if !stdoutIsTerminal() {
+		_, err := io.WriteString(w, content)
+		return err
+	}
+
+	// SKILL.yaml and SKILL.json manifests are shown as they are.
+	if !raw && strings.EqualFold(filepath.Ext(manifest), ".md") {
+		content = markdown.RenderANSI(content)
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if height := terminalHeight(); !noPager && height > 0 && strings.Count(content, "\n") >= height {
+		pager := os.Getenv("PAGER")
+		if pager == "" {
+			pager = defaultPager
+		}
+		if strings.TrimSpace(pager) != "" {
+			if err := runPager(pager, content); err == nil {
+				return nil
+			}
+		}
+	}
+
+	_, err = io.WriteString(w, content)
+	return err
+}
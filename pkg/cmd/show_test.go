@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+// seedShowSkill registers a skill whose SKILL.md holds content.
+func seedShowSkill(t *testing.T, homeDir, content string) {
+	t.Helper()
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "shown")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatalf("failed to create store directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(storePath, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+	skill := &types.SkillMetadata{
+		ID:        "shown@main",
+		Name:      "shown",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/shown",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+}
+
+// simulateStdoutTerminal makes executeShow treat stdout as a terminal with
+// the given number of rows.
+func simulateStdoutTerminal(t *testing.T, height int) {
+	t.Helper()
+	oldIsTerminal, oldHeight := stdoutIsTerminal, terminalHeight
+	stdoutIsTerminal = func() bool { return true }
+	terminalHeight = func() int { return height }
+	t.Cleanup(func() {
+		stdoutIsTerminal, terminalHeight = oldIsTerminal, oldHeight
+	})
+}
+
+func TestExecuteShow(t *testing.T) {
+	const content = "# Shown Skill\n\nUse `gskills show` to read me.\n"
+
+	tests := []struct {
+		name     string
+		terminal bool
+		raw      bool
+		want     string
+	}{
+		{
+			name: "not a terminal prints the file unchanged",
+			want: content,
+		},
+		{
+			name:     "terminal renders the markdown",
+			terminal: true,
+			want:     "\x1b[1m\x1b[4mShown Skill\x1b[0m\n\nUse \x1b[36mgskills show\x1b[0m to read me.\n",
+		},
+		{
+			name:     "terminal with --raw skips rendering",
+			terminal: true,
+			raw:      true,
+			want:     content,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			originalHome := os.Getenv("HOME")
+			os.Setenv("HOME", homeDir)
+			defer os.Setenv("HOME", originalHome)
+
+			seedShowSkill(t, homeDir, content)
+			if tt.terminal {
+				simulateStdoutTerminal(t, 100)
+			} else {
+				old := stdoutIsTerminal
+				stdoutIsTerminal = func() bool { return false }
+				defer func() { stdoutIsTerminal = old }()
+			}
+
+			var out bytes.Buffer
+			if err := executeShow(&out, "shown", tt.raw, false); err != nil {
+				t.Fatalf("executeShow() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("executeShow() output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteShow_PagesLongContent(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("PAGER", "more")
+	defer os.Unsetenv("PAGER")
+
+	seedShowSkill(t, homeDir, strings.Repeat("line\n", 50))
+	simulateStdoutTerminal(t, 20)
+
+	var pagedWith, paged string
+	oldRunPager := runPager
+	runPager = func(pager, content string) error {
+		pagedWith, paged = pager, content
+		return nil
+	}
+	defer func() { runPager = oldRunPager }()
+
+	var out bytes.Buffer
+	if err := executeShow(&out, "shown", false, false); err != nil {
+		t.Fatalf("executeShow() error = %v", err)
+	}
+	if pagedWith != "more" || strings.Count(paged, "line") != 50 {
+		t.Errorf("pager %q got %d lines, want more with 50", pagedWith, strings.Count(paged, "line"))
+	}
+	if out.Len() != 0 {
+		t.Errorf("paged content also written to output: %q", out.String())
+	}
+
+	pagedWith = ""
+	if err := executeShow(&out, "shown", false, true); err != nil {
+		t.Fatalf("executeShow() --no-pager error = %v", err)
+	}
+	if pagedWith != "" || strings.Count(out.String(), "line") != 50 {
+		t.Errorf("--no-pager should print directly, pager = %q", pagedWith)
+	}
+}
+
+func TestExecuteShow_MissingManifest(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	seedShowSkill(t, homeDir, "")
+	os.Remove(filepath.Join(homeDir, ".gskills", "skills", "shown", "SKILL.md"))
+
+	if err := executeShow(&bytes.Buffer{}, "shown", false, false); err == nil {
+		t.Error("executeShow() without SKILL.md should fail")
+	}
+}
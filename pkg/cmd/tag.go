@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/smy-101/gskills/internal/tag"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(untagCmd)
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <skill_name> <tag...>",
+	Short: "为技能添加标签",
+	Long: `为已安装的技能添加一个或多个标签，便于分组管理。
+
+示例:
+  gskills tag prompt-engineer writing
+  gskills tag react-expert frontend mobile
+
+标签不区分大小写，不能包含空白或逗号。标签只保存在本地注册表中，更新技能时会保留。
+使用 'gskills list --tag <tag>' 按标签筛选技能。`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("用法: gskills tag <skill_name> <tag...>")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skill, err := tag.AddTags(args[0], args[1:])
+		if err != nil {
+			return fmt.Errorf("failed to tag skill: %w", err)
+		}
+		printSkillTags(skill)
+		return nil
+	},
+}
+
+var untagCmd = &cobra.Command{
+	Use:   "untag <skill_name> <tag...>",
+	Short: "移除技能的标签",
+	Long: `移除已安装技能的一个或多个标签。
+
+示例:
+  gskills untag react-expert mobile`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("用法: gskills untag <skill_name> <tag...>")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skill, err := tag.RemoveTags(args[0], args[1:])
+		if err != nil {
+			return fmt.Errorf("failed to untag skill: %w", err)
+		}
+		printSkillTags(skill)
+		return nil
+	},
+}
+
+// printSkillTags prints the tags of skill after a tag change.
+func printSkillTags(skill *types.SkillMetadata) {
+	fmt.Printf("Tags of '%s': %s\n", skill.Name, formatTags(skill.Tags))
+}
+
+// formatTags joins tags for display, or returns "-" if there are none.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	return strings.Join(tags, ", ")
+}
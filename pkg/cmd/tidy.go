@@ -2,14 +2,23 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/smy-101/gskills/internal/tidy"
 	"github.com/spf13/cobra"
 )
 
+var (
+	tidyProject string
+	tidyJSON    bool
+)
+
 func init() {
 	rootCmd.AddCommand(tidyCmd)
+	tidyCmd.Flags().StringVar(&tidyProject, "project", "", "只清理指定项目目录中的链接")
+	tidyCmd.Flags().BoolVar(&tidyJSON, "json", false, "以 JSON 格式输出清理报告，包括删除的注册表项和符号链接")
 }
 
 var tidyCmd = &cobra.Command{
@@ -21,40 +30,60 @@ var tidyCmd = &cobra.Command{
   1. 移除注册表中指向不存在符号链接的项目条目
   2. 删除指向已删除技能的孤立符号链接
 
+使用 --project 可只清理单个项目目录。
+使用 --json 可输出机器可读的清理报告。
+
 示例:
-  gskills tidy`,
+  gskills tidy
+  gskills tidy --project ~/myproject
+  gskills tidy --json`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executeTidy()
+		return executeTidy(cmd.Context(), cmd.OutOrStdout(), tidyProject, tidyJSON)
 	},
 }
 
-func executeTidy() error {
+// executeTidy cleans up projectPath, or every linked project when it is
+// empty, and prints the report to w as prose or, with asJSON, as JSON.
+func executeTidy(ctx context.Context, w io.Writer, projectPath string, asJSON bool) error {
 	tidier := tidy.NewTidier()
-	ctx := context.Background()
+	if err := tidier.SetProject(projectPath); err != nil {
+		return fmt.Errorf("清理失败: %w", err)
+	}
 
-	fmt.Println("正在清理无用的技能链接...")
+	if !asJSON {
+		fmt.Fprintln(w, "正在清理无用的技能链接...")
+	}
 
 	report, err := tidier.Tidy(ctx)
 	if err != nil {
 		return fmt.Errorf("清理失败: %w", err)
 	}
 
-	fmt.Println("\n清理完成！")
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(w, "\n清理完成！")
 
 	if report.StaleRegistryEntries > 0 {
-		fmt.Printf("• 移除了 %d 个无效的注册表项\n", report.StaleRegistryEntries)
+		fmt.Fprintf(w, "• 移除了 %d 个无效的注册表项\n", report.StaleRegistryEntries)
 	}
 
 	if report.OrphanedSymlinks > 0 {
-		fmt.Printf("• 删除了 %d 个孤立的符号链接\n", report.OrphanedSymlinks)
+		fmt.Fprintf(w, "• 删除了 %d 个孤立的符号链接\n", report.OrphanedSymlinks)
 	}
 
 	if report.StaleRegistryEntries == 0 && report.OrphanedSymlinks == 0 {
-		fmt.Println("• 没有发现需要清理的项目")
+		fmt.Fprintln(w, "• 没有发现需要清理的项目")
 	}
 
-	fmt.Printf("\n已检查 %d 个技能，扫描了 %d 个项目目录\n", report.SkillsChecked, report.ProjectsScanned)
+	fmt.Fprintf(w, "\n已检查 %d 个技能，扫描了 %d 个项目目录\n", report.SkillsChecked, report.ProjectsScanned)
 
 	return nil
 }
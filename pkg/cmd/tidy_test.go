@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/tidy"
+	"github.com/smy-101/gskills/internal/types"
+)
+
+func TestExecuteTidy_JSON(t *testing.T) {
+	dataDir := testutil.TempHome(t)
+	projectDir := t.TempDir()
+	skillsDir := filepath.Join(projectDir, ".opencode", "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	storePath := filepath.Join(dataDir, "skills", "alive")
+	if err := os.MkdirAll(storePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	staleLink := filepath.Join(skillsDir, "alive")
+	skill := &types.SkillMetadata{
+		ID:        "alive@main",
+		Name:      "alive",
+		Version:   "main",
+		CommitSHA: "abc123",
+		SourceURL: "https://github.com/owner/repo/tree/main/alive",
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+		LinkedProjects: map[string]types.LinkedProjectInfo{
+			projectDir: {SymlinkPath: staleLink, LinkedAt: time.Now()},
+		},
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	orphan := filepath.Join(skillsDir, "ghost")
+	if err := os.Symlink(filepath.Join(dataDir, "skills", "ghost"), orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := executeTidy(context.Background(), &out, projectDir, true); err != nil {
+		t.Fatalf("executeTidy() error = %v", err)
+	}
+
+	var report tidy.CleanupReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+
+	wantEntry := tidy.RemovedEntry{Skill: "alive", Project: projectDir, SymlinkPath: staleLink}
+	if len(report.RemovedEntries) != 1 || report.RemovedEntries[0] != wantEntry {
+		t.Errorf("removed_entries = %+v, want [%+v]", report.RemovedEntries, wantEntry)
+	}
+	if len(report.RemovedSymlinks) != 1 || report.RemovedSymlinks[0] != orphan {
+		t.Errorf("removed_symlinks = %v, want [%s]", report.RemovedSymlinks, orphan)
+	}
+	if report.StaleRegistryEntries != 1 || report.OrphanedSymlinks != 1 {
+		t.Errorf("counts = %d stale, %d orphaned; want 1 and 1", report.StaleRegistryEntries, report.OrphanedSymlinks)
+	}
+	if _, err := os.Lstat(orphan); !os.IsNotExist(err) {
+		t.Errorf("orphaned symlink should be removed")
+	}
+}
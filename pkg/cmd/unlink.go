@@ -8,7 +8,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	unlinkPruneEmptyDirs bool
+	unlinkNoPrune        bool
+)
+
 func init() {
+	unlinkCmd.Flags().BoolVar(&unlinkPruneEmptyDirs, "prune-empty-dirs", true, "删除链接后，移除因此变空的 .opencode/skills 等目录（不会删除项目根目录）")
+	unlinkCmd.Flags().BoolVar(&unlinkNoPrune, "no-prune", false, "保留变空的技能目录，等同于 --prune-empty-dirs=false")
+	unlinkCmd.MarkFlagsMutuallyExclusive("prune-empty-dirs", "no-prune")
 	rootCmd.AddCommand(unlinkCmd)
 }
 
@@ -22,8 +30,11 @@ var unlinkCmd = &cobra.Command{
 示例:
   gskills unlink prompt-engineer
   gskills unlink prompt-engineer /home/user/myproject
+  gskills unlink prompt-engineer --no-prune
 
-当不提供project_path时，默认使用当前目录。`,
+当不提供project_path时，默认使用当前目录。
+删除链接后，变空的技能目录（如 .opencode/skills 和 .opencode）会被一并删除，
+直到项目根目录为止；使用 --no-prune 保留它们。`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 || len(args) > 2 {
 			return errors.New("用法: gskills unlink <skill_name> [project_path]")
@@ -36,12 +47,13 @@ var unlinkCmd = &cobra.Command{
 		if len(args) == 2 {
 			projectPath = args[1]
 		}
-		return executeUnlink(skillName, projectPath)
+		return executeUnlink(skillName, projectPath, unlinkPruneEmptyDirs && !unlinkNoPrune)
 	},
 }
 
-func executeUnlink(skillName, projectPath string) error {
+func executeUnlink(skillName, projectPath string, pruneEmptyDirs bool) error {
 	linker := link.NewLinker()
+	linker.SetPruneEmptyDirs(pruneEmptyDirs)
 
 	fmt.Printf("Unlinking skill '%s' from project '%s'...\n", skillName, projectPath)
 
@@ -193,9 +193,9 @@ func TestExecuteUnlink(t *testing.T) {
 
 			var err error
 			if tt.projectPath == "" {
-				err = executeUnlink(tt.skillName, projectDir)
+				err = executeUnlink(tt.skillName, projectDir, true)
 			} else {
-				err = executeUnlink(tt.skillName, tt.projectPath)
+				err = executeUnlink(tt.skillName, tt.projectPath, true)
 			}
 
 			if (err != nil) != tt.wantErr {
@@ -267,7 +267,7 @@ func TestExecuteUnlink_DefaultToCurrentDirectory(t *testing.T) {
 		t.Fatalf("link not created: %v", err)
 	}
 
-	if err := executeUnlink("default-unlink-test-skill", "."); err != nil {
+	if err := executeUnlink("default-unlink-test-skill", ".", true); err != nil {
 		t.Fatalf("executeUnlink() failed: %v", err)
 	}
 
@@ -1,25 +1,63 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"os"
+	"time"
 
-	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/prompt"
 	"github.com/smy-101/gskills/internal/types"
 	"github.com/smy-101/gskills/internal/update"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+)
+
+// minWatchInterval is the shortest --interval accepted, to stay well inside
+// the GitHub API rate limit.
+const minWatchInterval = time.Minute
+
+var (
+	updateYes         bool
+	updatePrerelease  bool
+	updateWatch       bool
+	updateInterval    time.Duration
+	updateRetry       bool
+	updateConcurrency int
+	updateAskOnError  bool
 )
 
 func init() {
 	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "不询问直接更新 (非交互环境中必须指定)")
+	updateCmd.Flags().BoolVar(&updatePrerelease, "prerelease", false, "跟随标签模式的技能也可更新到预发布标签 (如 v2.0.0-rc.1)")
+	updateCmd.Flags().BoolVar(&updateAskOnError, "interactive-on-error", false, "更新所有技能时不询问直接开始，但每个技能更新失败后询问是否继续 (需要终端)")
+	updateCmd.Flags().BoolVar(&updateRetry, "retry-failed", false, "更新所有技能时，再重试一次更新失败的技能")
+	updateCmd.Flags().BoolVar(&updateWatch, "watch", false, "持续在前台定期检查更新并打印新发现的更新，不会自动更新；按 Ctrl+C 退出")
+	updateCmd.Flags().IntVar(&updateConcurrency, "concurrency", 0, fmt.Sprintf("所有技能共享的并行下载请求数 (1-%d)，默认 3", add.MaxConcurrency))
+	updateCmd.Flags().DurationVar(&updateInterval, "interval", 30*time.Minute, "--watch 模式下两次检查的间隔，最小 1m")
 }
 
 var updateCmd = &cobra.Command{
 	Use:   "update [skill-name]",
 	Short: "更新已安装的技能",
-	Long:  `更新已安装的技能。如果不指定技能名称，则检查并更新所有技能。`,
+	Long: `更新已安装的技能。如果不指定技能名称，则检查并更新所有技能。
+
+更新前会询问确认；非交互环境（如管道、CI）中默认不更新，使用 --yes 可跳过确认。
+
+使用 --interactive-on-error 时直接开始更新所有技能，每个技能更新失败后询问是否继续；
+选择不继续时，其余技能不再更新。此模式下技能按顺序逐个更新。
+
+使用 --watch 可持续检查所有技能的更新，每隔 --interval 检查一次，只打印新发现的更新。
+遇到 GitHub 限流时会自动延长检查间隔。
+
+示例:
+  gskills update
+  gskills update golang-pro --yes
+  gskills update --interactive-on-error
+  gskills update --watch --interval 1h`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
 			return fmt.Errorf("用法: gskills update [skill-name]")
@@ -27,65 +65,202 @@ var updateCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		token := viper.GetString("github_token")
-		return executeUpdate(token, args)
+		token := configString("github_token")
+		if cmd.Flags().Changed("concurrency") && updateConcurrency < 1 {
+			return &usageError{err: fmt.Errorf("--concurrency 必须至少为 1: %d", updateConcurrency)}
+		}
+		if cmd.Flags().Changed("interval") && !updateWatch {
+			return &usageError{err: fmt.Errorf("--interval 需要与 --watch 一起使用")}
+		}
+		if updateWatch {
+			if len(args) > 0 {
+				return &usageError{err: fmt.Errorf("--watch 会检查所有技能，不能指定技能名称")}
+			}
+			if updateInterval < minWatchInterval {
+				return &usageError{err: fmt.Errorf("--interval 不能小于 %v: %v", minWatchInterval, updateInterval)}
+			}
+			updater := newUpdater(token)
+			configureUpdater(updater)
+			updater.SetIncludePrerelease(updatePrerelease)
+			return watchUpdates(cmd.Context(), cmd.OutOrStdout(), updater, updateInterval)
+		}
+		opts := updateOptions{assumeYes: updateYes, retryFailed: updateRetry}
+		if updateAskOnError {
+			if len(args) > 0 {
+				return &usageError{err: fmt.Errorf("--interactive-on-error 只能在更新所有技能时使用")}
+			}
+			if !prompt.IsInteractive() {
+				return &usageError{err: fmt.Errorf("--interactive-on-error 需要在终端中使用")}
+			}
+			opts.assumeYes = true
+			opts.askOnError = true
+		}
+		return executeUpdate(cmd.Context(), token, args, opts)
 	},
 }
 
-func executeUpdate(token string, args []string) error {
+// updateOptions holds the flags of an update.
+type updateOptions struct {
+	// assumeYes skips the confirmation prompt.
+	assumeYes bool
+	// retryFailed re-attempts the skills whose update failed once when
+	// updating all skills.
+	retryFailed bool
+	// askOnError asks after each failed update of all skills whether to
+	// update the remaining ones.
+	askOnError bool
+}
+
+// newUpdater creates the updater used by the update and outdated commands.
+// It is a variable so tests can point the updater at a mock server.
+var newUpdater = func(token string) *update.Updater {
 	updater := update.NewUpdater(token)
+	updater.SetServer(configuredServer())
+	return updater
+}
+
+// executeUpdate updates the skill named in args, or every skill when args is
+// empty.
+func executeUpdate(ctx context.Context, token string, args []string, opts updateOptions) error {
+	updater := newUpdater(token)
+	configureUpdater(updater)
+	updater.SetIncludePrerelease(updatePrerelease)
+	updater.SetConcurrency(updateConcurrency)
+
+	manager := newManager(token)
+	manager.SetUpdater(updater)
 
 	if len(args) == 0 {
-		return updateAllSkills(updater)
+		return updateAllSkills(ctx, manager, updater, opts)
 	}
 
-	return updateSingleSkill(updater, args[0])
+	return updateSingleSkill(ctx, manager, updater, args[0], opts.assumeYes)
 }
 
-func updateSingleSkill(updater *update.Updater, skillName string) error {
-	skill, err := registry.FindSkillByName(skillName)
+// updateSingleSkill updates skillName through manager. updater is the one
+// manager delegates to; it is used directly only to report the new commit
+// before asking for confirmation.
+func updateSingleSkill(ctx context.Context, manager *add.Manager, updater *update.Updater, skillName string, assumeYes bool) error {
+	skill, err := manager.Find(skillName)
 	if err != nil {
 		return fmt.Errorf("技能 '%s' 未找到: %w", skillName, err)
 	}
 
+	if update.IsPolicyPinned(skill) {
+		fmt.Printf("  ✓ %s 的更新策略为 pinned，不会更新 (commit: %s)\n", skillName, shortSHA(skill.CommitSHA))
+		return nil
+	}
+
+	if update.IsLocalSkill(skill) {
+		fmt.Printf("从本地源重新复制: %s...\n", skillName)
+		if _, err := manager.Update(ctx, skillName); err != nil {
+			return fmt.Errorf("更新失败: %w", err)
+		}
+		fmt.Printf("  ✓ %s 更新成功\n", skillName)
+		return nil
+	}
+
 	fmt.Printf("检查更新: %s...\n", skillName)
 
-	hasUpdate, newSHA, err := updater.CheckUpdate(skill)
+	hasUpdate, newSHA, err := updater.CheckUpdate(ctx, skill)
 	if err != nil {
+		printUpdateHint(os.Stdout, "  ", err)
 		return fmt.Errorf("检查更新失败: %w", err)
 	}
 
 	if !hasUpdate {
+		if update.IsTagPinned(skill) {
+			fmt.Printf("  ✓ %s 固定在标签 %s，不会更新 (commit: %s)\n", skillName, skill.Version, shortSHA(skill.CommitSHA))
+			return nil
+		}
 		fmt.Printf("  ✓ %s 已是最新版本 (commit: %s)\n", skillName, shortSHA(skill.CommitSHA))
 		return nil
 	}
 
 	fmt.Printf("  → 发现更新: %s → %s\n", shortSHA(skill.CommitSHA), shortSHA(newSHA))
-	fmt.Printf("更新 '%s'? [y/N]: ", skillName)
-
-	response, err := readUserInput()
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("读取输入失败: %w", err)
+	confirmed, err := confirmUpdate(fmt.Sprintf("更新 '%s'?", skillName), assumeYes)
+	if err != nil {
+		return err
 	}
-
-	if !isYesResponse(response) {
+	if !confirmed {
 		fmt.Println("更新已取消")
 		return nil
 	}
 
 	fmt.Printf("正在更新 %s...\n", skillName)
-	if err := updater.UpdateSkill(skill); err != nil {
+	if _, err := manager.Update(ctx, skillName); err != nil {
+		printUpdateHint(os.Stdout, "  ", err)
 		return fmt.Errorf("更新失败: %w", err)
 	}
 
 	fmt.Printf("  ✓ %s 更新成功\n", skillName)
+	printLinksBehind(manager, skillName)
 	return nil
 }
 
-func updateAllSkills(updater *update.Updater) error {
+// printLinksBehind notes the projects that were linked to skillName at an
+// older version than the one now in the store.
+func printLinksBehind(manager *add.Manager, skillName string) {
+	skill, err := manager.Find(skillName)
+	if err != nil {
+		return
+	}
+
+	projects := update.LinksBehind(skill)
+	if len(projects) == 0 {
+		return
+	}
+
+	fmt.Printf("  注意: %d 个项目链接时为旧版本，现已指向 %s:\n", len(projects), shortSHA(skill.CommitSHA))
+	for _, projectPath := range projects {
+		fmt.Printf("    • %s (链接时: %s)\n", projectPath, shortSHA(skill.LinkedProjects[projectPath].CommitSHA))
+	}
+}
+
+// watchUpdates checks all skills every interval until ctx is cancelled and
+// prints each update the first time it is seen. An update that disappears,
+// e.g. because the skill was updated meanwhile, is printed again if it
+// comes back.
+func watchUpdates(ctx context.Context, w io.Writer, updater *update.Updater, interval time.Duration) error {
+	fmt.Fprintf(w, "每 %v 检查一次更新，按 Ctrl+C 退出\n", interval)
+
+	seen := make(map[string]string)
+	err := updater.Watch(ctx, interval, func(infos []update.SkillUpdateInfo) {
+		now := time.Now().Format("2006-01-02 15:04:05")
+		available := make(map[string]string)
+		for _, info := range infos {
+			switch info.Status {
+			case update.UpdateStatusAvailable:
+				key := info.NewVersion + "@" + info.NewCommitSHA
+				available[info.Skill.Name] = key
+				if seen[info.Skill.Name] == key {
+					continue
+				}
+				if info.NewVersion != info.Skill.Version {
+					fmt.Fprintf(w, "[%s] → %s: %s → %s (%s)\n", now, info.Skill.Name, info.Skill.Version, info.NewVersion, shortSHA(info.NewCommitSHA))
+				} else {
+					fmt.Fprintf(w, "[%s] → %s: %s → %s\n", now, info.Skill.Name, shortSHA(info.Skill.CommitSHA), shortSHA(info.NewCommitSHA))
+				}
+			case update.UpdateStatusFailed:
+				fmt.Fprintf(w, "[%s] ✗ %s: 检查失败 - %v\n", now, info.Skill.Name, info.Error)
+			}
+		}
+		seen = available
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "已停止检查更新")
+	return nil
+}
+
+// updateAllSkills checks every skill with updater and updates those with
+// updates. manager is used to look up the updated skills afterwards.
+func updateAllSkills(ctx context.Context, manager *add.Manager, updater *update.Updater, opts updateOptions) error {
 	fmt.Println("检查所有技能的更新...")
 
-	updates, err := updater.CheckAllUpdates()
+	updates, err := updater.CheckAllUpdates(ctx)
 	if err != nil {
 		return fmt.Errorf("检查更新失败: %w", err)
 	}
@@ -100,11 +275,18 @@ func updateAllSkills(updater *update.Updater) error {
 	for _, info := range updates {
 		if info.Status == update.UpdateStatusAvailable {
 			availableUpdates = append(availableUpdates, info.Skill)
-			fmt.Printf("  → %s: %s → %s\n", info.Skill.Name, shortSHA(info.Skill.CommitSHA), shortSHA(info.NewCommitSHA))
+			if info.NewVersion != info.Skill.Version {
+				fmt.Printf("  → %s: %s → %s (%s)\n", info.Skill.Name, info.Skill.Version, info.NewVersion, shortSHA(info.NewCommitSHA))
+			} else {
+				fmt.Printf("  → %s: %s → %s\n", info.Skill.Name, shortSHA(info.Skill.CommitSHA), shortSHA(info.NewCommitSHA))
+			}
 		} else if info.Status == update.UpdateStatusUpToDate {
 			fmt.Printf("  ✓ %s: 已是最新\n", info.Skill.Name)
 		} else if info.Status == update.UpdateStatusFailed {
 			fmt.Printf("  ✗ %s: 检查失败 - %v\n", info.Skill.Name, info.Error)
+			printUpdateHint(os.Stdout, "    ", info.Error)
+		} else if info.Status == update.UpdateStatusSkipped {
+			fmt.Printf("  - %s: %s\n", info.Skill.Name, skipReason(info.Skill))
 		}
 	}
 
@@ -114,36 +296,142 @@ func updateAllSkills(updater *update.Updater) error {
 	}
 
 	fmt.Printf("\n发现 %d 个技能有更新\n", len(availableUpdates))
-	fmt.Print("更新这些技能? [y/N]: ")
-
-	response, err := readUserInput()
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("读取输入失败: %w", err)
+	confirmed, err := confirmUpdate("更新这些技能?", opts.assumeYes)
+	if err != nil {
+		return err
 	}
-
-	if !isYesResponse(response) {
+	if !confirmed {
 		fmt.Println("更新已取消")
 		return nil
 	}
 
+	if opts.askOnError {
+		updater.SetOnFailure(askContinueAfterFailure)
+	}
+
 	fmt.Println("\n正在更新技能...")
-	stats, err := updater.UpdateAll(availableUpdates)
+	stats, results, err := updater.UpdateAll(ctx, availableUpdates)
 	if err != nil {
 		return fmt.Errorf("更新失败: %w", err)
 	}
 
+	failed := update.FailedSkills(results)
+	// Skills were skipped only because the user chose to stop, so the
+	// failures are not retried either.
+	if len(failed) > 0 && opts.retryFailed && stats.Skipped == 0 {
+		fmt.Printf("\n重试 %d 个更新失败的技能...\n", len(failed))
+		retryStats, retryResults, err := updater.UpdateAll(ctx, failed)
+		if err != nil {
+			return fmt.Errorf("更新失败: %w", err)
+		}
+		stats.Updated += retryStats.Updated
+		stats.Failed = retryStats.Failed
+		stats.Duration += retryStats.Duration
+		stats.BytesDownloaded += retryStats.BytesDownloaded
+		results = update.MergeRetryResults(results, retryResults)
+	}
+
+	fmt.Println()
+	printUpdateResults(os.Stdout, results)
+
 	fmt.Printf("\n更新完成:\n")
 	fmt.Printf("  成功: %d\n", stats.Updated)
 	fmt.Printf("  失败: %d\n", stats.Failed)
+	if stats.Skipped > 0 {
+		fmt.Printf("  跳过: %d\n", stats.Skipped)
+	}
 	fmt.Printf("  耗时: %v\n", stats.Duration)
+	fmt.Printf("  下载: %s (%s/s)\n", formatBytes(stats.BytesDownloaded), formatBytes(int64(stats.BytesPerSecond())))
+
+	for _, skill := range availableUpdates {
+		printLinksBehind(manager, skill.Name)
+	}
 
 	if stats.Failed > 0 {
+		if !opts.retryFailed {
+			fmt.Println("使用 'gskills update --retry-failed' 可自动重试失败的技能")
+		}
 		return fmt.Errorf("部分技能更新失败")
 	}
 
 	return nil
 }
 
+// printUpdateResults prints one line per skill of an UpdateAll batch in the
+// order of results, which is the order the skills were passed in, so the
+// output does not depend on which update finished first.
+func printUpdateResults(w io.Writer, results []update.SkillUpdateResult) {
+	for _, r := range results {
+		switch r.Status {
+		case update.UpdateStatusFailed:
+			fmt.Fprintf(w, "  ✗ %s: %v\n", r.Skill.Name, r.Error)
+			printUpdateHint(w, "    ", r.Error)
+		case update.UpdateStatusSkipped:
+			if update.SkipsBulkUpdate(r.Skill) {
+				fmt.Fprintf(w, "  - %s: %s\n", r.Skill.Name, skipReason(r.Skill))
+				continue
+			}
+			fmt.Fprintf(w, "  - %s: 未更新 (已在失败后停止)\n", r.Skill.Name)
+		default:
+			fmt.Fprintf(w, "  ✓ %s: 已更新\n", r.Skill.Name)
+		}
+	}
+}
+
+// skipReason explains why checking all skills skipped skill.
+func skipReason(skill *types.SkillMetadata) string {
+	if update.IsLocalSkill(skill) {
+		return "本地技能，已跳过"
+	}
+	if skill.UpdatePolicy == add.UpdatePolicyManual {
+		return "更新策略为 manual，已跳过 (使用 'gskills update " + skill.Name + "' 更新)"
+	}
+	return fmt.Sprintf("更新策略为 %s，已跳过", update.Policy(skill))
+}
+
+// askContinueAfterFailure reports a failed update of skill and asks whether
+// to update the remaining skills. A failure to read the answer stops.
+func askContinueAfterFailure(skill *types.SkillMetadata, err error) bool {
+	fmt.Printf("  ✗ %s: %v\n", skill.Name, err)
+	printUpdateHint(os.Stdout, "    ", err)
+	confirmed, promptErr := prompt.Confirm("继续更新其余技能?")
+	return promptErr == nil && confirmed
+}
+
+// updateErrorHint returns advice for the category of err, an *update.UpdateError,
+// or an empty string for other errors.
+func updateErrorHint(err error) string {
+	var updateErr *update.UpdateError
+	if !errors.As(err, &updateErr) {
+		return ""
+	}
+	switch updateErr.Type {
+	case update.UpdateErrorTypeCheck:
+		return "无法从 GitHub 获取最新版本，可能是网络问题或达到速率限制；请检查网络、proxy 和 github_token 配置"
+	case update.UpdateErrorTypeDownload:
+		return "下载或写入技能文件失败；请检查网络连接和磁盘空间 (磁盘已满?)"
+	case update.UpdateErrorTypeSource:
+		return "该技能记录的来源无法用于更新；请检查 'gskills info' 中的源地址，必要时用 'gskills add' 重新添加"
+	case update.UpdateErrorTypeFilesystem:
+		return "写入技能目录失败；请检查技能存储目录的权限和磁盘空间 (磁盘已满?)"
+	case update.UpdateErrorTypeRegistry:
+		return "技能文件已更新，但写入注册表失败；请检查注册表文件的权限，或运行 'gskills doctor'"
+	case update.UpdateErrorTypeNotFound:
+		return "该技能未安装；使用 'gskills list' 查看已安装的技能"
+	case update.UpdateErrorTypeInvalid:
+		return "上游的 SKILL.md 可能已被重命名或移动，已保留当前安装的版本；请检查技能的源地址，必要时用 'gskills add' 重新添加"
+	default:
+		return ""
+	}
+}
+
+// printUpdateHint prints the hint for err, if any, indented by indent.
+func printUpdateHint(w io.Writer, indent string, err error) {
+	if hint := updateErrorHint(err); hint != "" {
+		fmt.Fprintf(w, "%s提示: %s\n", indent, hint)
+	}
+}
+
 func shortSHA(sha string) string {
 	if len(sha) <= 7 {
 		return sha
@@ -151,19 +439,33 @@ func shortSHA(sha string) string {
 	return sha[:7]
 }
 
-// readUserInput reads a line of input from the user.
-// Returns io.EOF if no input is available (e.g., user pressed Ctrl+D).
-func readUserInput() (string, error) {
-	var response string
-	_, err := fmt.Scanln(&response)
+// confirmUpdate asks question unless assumeYes is set. Without a terminal
+// the answer is no and the user is pointed at --yes.
+func confirmUpdate(question string, assumeYes bool) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !prompt.IsInteractive() {
+		fmt.Println("非交互环境，未更新；使用 --yes 直接更新")
+		return false, nil
+	}
+	confirmed, err := prompt.Confirm(question)
 	if err != nil {
-		return "", err
+		return false, fmt.Errorf("读取输入失败: %w", err)
 	}
-	return strings.TrimSpace(response), nil
+	return confirmed, nil
 }
 
-// isYesResponse checks if the user response indicates agreement ("y" or "yes").
-func isYesResponse(response string) bool {
-	response = strings.ToLower(response)
-	return response == "y" || response == "yes"
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 KiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
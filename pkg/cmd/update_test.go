@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/registry"
+	"github.com/smy-101/gskills/internal/testutil"
+	"github.com/smy-101/gskills/internal/types"
+	"github.com/smy-101/gskills/internal/update"
+)
+
+func TestExecuteUpdate_AllWithLocalSkill(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	srcDir := filepath.Join(t.TempDir(), "local-skill")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create source directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Local"), 0644); err != nil {
+		t.Fatalf("failed to write SKILL.md: %v", err)
+	}
+
+	storePath := filepath.Join(homeDir, ".gskills", "skills", "local-skill")
+	if _, err := add.InstallLocal(srcDir, storePath); err != nil {
+		t.Fatalf("InstallLocal() error = %v", err)
+	}
+
+	skill := &types.SkillMetadata{
+		ID:        "local-skill@local",
+		Name:      "local-skill",
+		Version:   add.LocalVersion,
+		CommitSHA: add.LocalVersion,
+		SourceURL: "file://" + srcDir,
+		StorePath: storePath,
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	if err := executeUpdate(context.Background(), "", nil, updateOptions{}); err != nil {
+		t.Errorf("executeUpdate() with only local skills error = %v", err)
+	}
+}
+
+func TestLinkInfo_ReflectsLinkedVersionAfterUpdate(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var mu sync.Mutex
+	sha := "1111111aaaaaaa"
+	currentSHA := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return sha
+	}
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": currentSHA()})
+		case "/repos/owner/repo/contents/skill":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "skill/SKILL.md", DownloadURL: ts.URL + "/skillmd"},
+			})
+		case "/skillmd":
+			w.Write([]byte("# Skill " + currentSHA()))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+
+	ctx := context.Background()
+	manager := newManager("")
+	if _, err := manager.Add(ctx, "https://github.com/owner/repo/tree/main/skill"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	projectDir := t.TempDir()
+	if err := manager.Link(ctx, "skill", projectDir); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	captureInfo := func() string {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := executeLinkInfo("skill")
+
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		r.Close()
+
+		if err != nil {
+			t.Fatalf("executeLinkInfo() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	if out := captureInfo(); !strings.Contains(out, "Linked version: main@1111111 (current)") {
+		t.Errorf("info before update should show the link as current, got:\n%s", out)
+	}
+
+	mu.Lock()
+	sha = "2222222bbbbbbb"
+	mu.Unlock()
+
+	updater := update.NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+	manager.SetUpdater(updater)
+	if updated, err := manager.Update(ctx, "skill"); err != nil || !updated {
+		t.Fatalf("Update() = %v, %v; want true, nil", updated, err)
+	}
+
+	out := captureInfo()
+	if !strings.Contains(out, "Linked version: main@1111111 (store updated to 2222222 since linking)") {
+		t.Errorf("info after update should show the older linked version, got:\n%s", out)
+	}
+
+	skill, err := registry.FindSkillByName("skill")
+	if err != nil {
+		t.Fatalf("FindSkillByName() error = %v", err)
+	}
+	absProject, _ := filepath.Abs(projectDir)
+	if got := update.LinksBehind(skill); len(got) != 1 || got[0] != absProject {
+		t.Errorf("LinksBehind() = %v, want [%s]", got, absProject)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWatchUpdates_PrintsNewUpdatesOnce(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"sha": "2222222bbbbbbb"})
+	}))
+	defer ts.Close()
+
+	skill := &types.SkillMetadata{
+		ID:        "watched@main",
+		Name:      "watched",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/watched",
+		CommitSHA: "1111111aaaaaaa",
+		StorePath: t.TempDir(),
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := update.NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := watchUpdates(ctx, &out, updater, 20*time.Millisecond); err != nil {
+		t.Fatalf("watchUpdates() error = %v", err)
+	}
+
+	if got := strings.Count(out.String(), "watched: 1111111 → 2222222"); got != 1 {
+		t.Errorf("update printed %d times, want once:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "已停止检查更新") {
+		t.Errorf("missing stop message:\n%s", out.String())
+	}
+}
+
+func TestUpdateAllSkills_RetryFailed(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var mu sync.Mutex
+	downloads := 0
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "2222222bbbbbbb"})
+		case "/repos/owner/repo/contents/flaky":
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: "flaky/SKILL.md", DownloadURL: ts.URL + "/skillmd"},
+			})
+		case "/skillmd":
+			mu.Lock()
+			downloads++
+			first := downloads == 1
+			mu.Unlock()
+			if first {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte("# Flaky"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	skill := &types.SkillMetadata{
+		ID:        "flaky@main",
+		Name:      "flaky",
+		Version:   "main",
+		SourceURL: "https://github.com/owner/repo/tree/main/flaky",
+		CommitSHA: "1111111aaaaaaa",
+		StorePath: filepath.Join(homeDir, ".gskills", "skills", "flaky"),
+		UpdatedAt: time.Now(),
+	}
+	if err := registry.AddOrUpdateSkill(skill); err != nil {
+		t.Fatalf("failed to add skill to registry: %v", err)
+	}
+
+	updater := update.NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	if err := updateAllSkills(context.Background(), newManager(""), updater, updateOptions{assumeYes: true, retryFailed: true}); err != nil {
+		t.Fatalf("updateAllSkills() with --retry-failed error = %v", err)
+	}
+
+	mu.Lock()
+	if downloads != 2 {
+		t.Errorf("SKILL.md downloaded %d times, want 2 (failure and retry)", downloads)
+	}
+	mu.Unlock()
+
+	updated, err := registry.FindSkillByName("flaky")
+	if err != nil {
+		t.Fatalf("FindSkillByName() error = %v", err)
+	}
+	if updated.CommitSHA != "2222222bbbbbbb" {
+		t.Errorf("CommitSHA = %s, want the retried update", updated.CommitSHA)
+	}
+}
+
+func TestUpdateAllSkills_PrintsResultsInInputOrder(t *testing.T) {
+	homeDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/commits/main":
+			json.NewEncoder(w).Encode(map[string]string{"sha": "2222222bbbbbbb"})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/contents/"):
+			name := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/contents/")
+			json.NewEncoder(w).Encode([]types.GitHubContent{
+				{Type: "file", Name: "SKILL.md", Path: name + "/SKILL.md", DownloadURL: ts.URL + "/raw/" + name},
+			})
+		case r.URL.Path == "/raw/alpha":
+			// The first skill finishes last.
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte("# alpha"))
+		case r.URL.Path == "/raw/beta":
+			w.Write([]byte("# beta"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		skill := &types.SkillMetadata{
+			ID:        name + "@main",
+			Name:      name,
+			Version:   "main",
+			SourceURL: "https://github.com/owner/repo/tree/main/" + name,
+			CommitSHA: "1111111aaaaaaa",
+			StorePath: filepath.Join(homeDir, ".gskills", "skills", name),
+			UpdatedAt: time.Now(),
+		}
+		if err := registry.AddOrUpdateSkill(skill); err != nil {
+			t.Fatalf("failed to add skill to registry: %v", err)
+		}
+	}
+
+	updater := update.NewUpdater("")
+	updater.SetBaseURL(ts.URL)
+
+	out, err := captureStdout(t, func() error {
+		return updateAllSkills(context.Background(), newManager(""), updater, updateOptions{assumeYes: true})
+	})
+	if err == nil {
+		t.Fatal("updateAllSkills() should report the failed skill")
+	}
+
+	_, results, found := strings.Cut(out, "正在更新技能...")
+	if !found {
+		t.Fatalf("missing update section in output:\n%s", out)
+	}
+	var lines []string
+	for _, line := range strings.Split(results, "\n") {
+		if strings.HasPrefix(line, "  ✓ ") || strings.HasPrefix(line, "  ✗ ") {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{"  ✓ alpha: 已更新", "  ✓ beta: 已更新", "  ✗ gamma: "}
+	if len(lines) != len(want) {
+		t.Fatalf("result lines = %q, want %d lines in input order", lines, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("result line %d = %q, want prefix %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestUpdateErrorHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		errType update.UpdateErrorType
+		want    string
+	}{
+		{name: "check", errType: update.UpdateErrorTypeCheck, want: "github_token"},
+		{name: "download", errType: update.UpdateErrorTypeDownload, want: "磁盘已满?"},
+		{name: "registry", errType: update.UpdateErrorTypeRegistry, want: "gskills doctor"},
+		{name: "not found", errType: update.UpdateErrorTypeNotFound, want: "gskills list"},
+		{name: "invalid", errType: update.UpdateErrorTypeInvalid, want: "已保留当前安装的版本"},
+		{name: "source", errType: update.UpdateErrorTypeSource, want: "gskills info"},
+		{name: "filesystem", errType: update.UpdateErrorTypeFilesystem, want: "存储目录"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("更新失败: %w", &update.UpdateError{Type: tt.errType, Message: "failed", Skill: "demo"})
+			if got := updateErrorHint(err); !strings.Contains(got, tt.want) {
+				t.Errorf("updateErrorHint() = %q, want it to mention %q", got, tt.want)
+			}
+
+			var buf bytes.Buffer
+			printUpdateResults(&buf, []update.SkillUpdateResult{{
+				Skill:  &types.SkillMetadata{Name: "demo"},
+				Status: update.UpdateStatusFailed,
+				Error:  err,
+			}})
+			if !strings.Contains(buf.String(), "    提示: ") || !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("printUpdateResults() output lacks the hint:\n%s", buf.String())
+			}
+		})
+	}
+
+	if got := updateErrorHint(errors.New("plain")); got != "" {
+		t.Errorf("updateErrorHint(untyped) = %q, want no hint", got)
+	}
+}
+
+func TestUpdateCmd_WatchFlagsAreUsageErrors(t *testing.T) {
+	testutil.TempHome(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"--interval without --watch", []string{"update", "--interval", "1h"}},
+		{"--watch with a skill name", []string{"update", "skill", "--watch"}},
+		{"--interval below the minimum", []string{"update", "--watch", "--interval", "1s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				updateWatch, updateInterval = false, 30*time.Minute
+				updateCmd.Flags().Lookup("watch").Changed = false
+				updateCmd.Flags().Lookup("interval").Changed = false
+			}()
+
+			err := executeRoot(context.Background(), tt.args)
+			if got := exitCode(err); got != ExitUsage {
+				t.Errorf("gskills %s: exit code = %d, want %d (error: %v)", strings.Join(tt.args, " "), got, ExitUsage, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var versionCheck bool
+
+// versionCheckTimeout bounds the release check so that it never holds up the
+// command for long when GitHub is unreachable.
+var versionCheckTimeout = 10 * time.Second
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "检查 GitHub 上是否有更新的 gskills 版本")
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "显示 gskills 的版本信息",
+	Long: `显示 gskills 的版本、构建提交以及编译所用的 Go 版本。
+
+示例:
+  gskills version
+  gskills version --check
+
+使用 --check 时会查询 GitHub releases 判断是否有新版本；网络不可用时仅给出提示，不会报错。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := version.Get()
+		out := cmd.OutOrStdout()
+		printVersion(out, info)
+		if versionCheck {
+			checkLatestVersion(cmd.Context(), out, info.Version)
+		}
+		return nil
+	},
+}
+
+// printVersion writes the build information of info to w.
+func printVersion(w io.Writer, info version.Info) {
+	commit := info.Commit
+	if commit == "" {
+		commit = "unknown"
+	}
+
+	fmt.Fprintf(w, "gskills %s\n", info.Version)
+	fmt.Fprintf(w, "  Commit: %s\n", commit)
+	fmt.Fprintf(w, "  Go version: %s\n", info.GoVersion)
+}
+
+// newGitHubClient returns a GitHub client configured with the token, proxy
+// and --timeout settings of the CLI.
+func newGitHubClient() *add.Client {
+	client := newManager(configString("github_token")).Client()
+	client.SetProxy(configString("proxy"))
+	client.SetTimeout(rootTimeout)
+	return client
+}
+
+// checkLatestVersion reports whether a release newer than current exists.
+// Network and API failures are reported as a notice rather than an error so
+// that the command still succeeds offline.
+func checkLatestVersion(ctx context.Context, w io.Writer, current string) {
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+
+	release, err := newGitHubClient().GetLatestRelease(ctx, version.RepoOwner, version.RepoName)
+	if err != nil {
+		fmt.Fprintf(w, "\nCould not check for updates: %v\n", err)
+		return
+	}
+
+	cmp, err := version.Compare(current, release.TagName)
+	if err != nil {
+		fmt.Fprintf(w, "\nLatest release: %s (current build %s cannot be compared)\n", release.TagName, current)
+		return
+	}
+
+	if cmp < 0 {
+		fmt.Fprintf(w, "\nA new version of gskills is available: %s (current %s)\n", release.TagName, current)
+		if release.HTMLURL != "" {
+			fmt.Fprintf(w, "  %s\n", release.HTMLURL)
+		}
+		return
+	}
+	fmt.Fprintln(w, "\ngskills is up to date.")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smy-101/gskills/internal/add"
+	"github.com/smy-101/gskills/internal/version"
+)
+
+func TestPrintVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		info version.Info
+		want []string
+	}{
+		{
+			name: "release build",
+			info: version.Info{Version: "v1.2.0", Commit: "abc1234", GoVersion: "go1.25.0"},
+			want: []string{"gskills v1.2.0", "Commit: abc1234", "Go version: go1.25.0"},
+		},
+		{
+			name: "unknown commit",
+			info: version.Info{Version: "dev", GoVersion: "go1.25.0"},
+			want: []string{"gskills dev", "Commit: unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printVersion(&buf, tt.info)
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output %q does not contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckLatestVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		statusCode int
+		tag        string
+		want       string
+	}{
+		{name: "update available", current: "v1.2.0", statusCode: http.StatusOK, tag: "v1.10.0", want: "A new version of gskills is available: v1.10.0 (current v1.2.0)"},
+		{name: "up to date", current: "v1.2.0", statusCode: http.StatusOK, tag: "v1.2.0", want: "gskills is up to date."},
+		{name: "dev build", current: "dev", statusCode: http.StatusOK, tag: "v1.2.0", want: "Latest release: v1.2.0"},
+		{name: "API failure", current: "v1.2.0", statusCode: http.StatusInternalServerError, want: "Could not check for updates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/smy-101/gskills/releases/latest" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(tt.statusCode)
+				json.NewEncoder(w).Encode(map[string]string{
+					"tag_name": tt.tag,
+					"html_url": "https://github.com/smy-101/gskills/releases/tag/" + tt.tag,
+				})
+			}))
+			defer ts.Close()
+
+			oldNewManager := newManager
+			newManager = func(token string) *add.Manager {
+				manager := add.NewManager("", token, nil)
+				manager.Client().SetBaseURL(ts.URL)
+				return manager
+			}
+			defer func() { newManager = oldNewManager }()
+
+			var buf bytes.Buffer
+			checkLatestVersion(context.Background(), &buf, tt.current)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output %q does not contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLatestVersion_Offline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+
+	oldTimeout := versionCheckTimeout
+	versionCheckTimeout = 200 * time.Millisecond
+	defer func() { versionCheckTimeout = oldTimeout }()
+
+	oldNewManager := newManager
+	newManager = func(token string) *add.Manager {
+		manager := add.NewManager("", token, nil)
+		manager.Client().SetBaseURL(ts.URL)
+		return manager
+	}
+	defer func() { newManager = oldNewManager }()
+
+	var buf bytes.Buffer
+	checkLatestVersion(context.Background(), &buf, "v1.0.0")
+	if !strings.Contains(buf.String(), "Could not check for updates") {
+		t.Errorf("output %q should report the failed check", buf.String())
+	}
+}